@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/acceptpatch/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	return ctx.OK([]byte("Number 8"))
+}
+
+// Update runs the update action.
+func (c *BottleController) Update(ctx *app.UpdateBottleContext) error {
+	return ctx.NoContent()
+}
+
+func TestAcceptPatch(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	req, _ := http.NewRequest("PATCH", server.URL+"/bottles/1", strings.NewReader(`{"name":"Number 9"}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("patch request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("patch: expected status 204, got %d", resp.StatusCode)
+	}
+
+	for _, method := range []string{"PUT", "DELETE", "POST"} {
+		req, _ := http.NewRequest(method, server.URL+"/bottles/1", strings.NewReader(`{"name":"Number 9"}`))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s request failed: %s", method, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("%s: expected status 405, got %d", method, resp.StatusCode)
+		}
+		if ap := resp.Header.Get("Accept-Patch"); ap != "application/merge-patch+json" {
+			t.Errorf("%s: expected Accept-Patch header %q, got %q", method, "application/merge-patch+json", ap)
+		}
+		if allow := resp.Header.Get("Allow"); allow != "GET, HEAD, PATCH" {
+			t.Errorf("%s: expected Allow header %q, got %q", method, "GET, HEAD, PATCH", allow)
+		}
+	}
+}
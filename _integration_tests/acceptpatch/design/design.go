@@ -0,0 +1,35 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API whose bottles may only be updated with JSON merge patch documents")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(OK, "text/plain")
+	})
+	Action("update", func() {
+		Routing(PATCH("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		AcceptPatch("application/merge-patch+json")
+		Payload(func() {
+			Attribute("name", String, "Name of bottle")
+		})
+		Response(NoContent)
+	})
+})
@@ -0,0 +1,65 @@
+package anyof_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/anyof/app"
+	"golang.org/x/net/context"
+)
+
+// newListContext creates the list action context from the given query string.
+func newListContext(query string) (*app.ListBottleContext, error) {
+	req := httptest.NewRequest("GET", "/bottles?"+query, nil)
+	rw := httptest.NewRecorder()
+	params, _ := url.ParseQuery(query)
+	ctx := goa.NewContext(context.Background(), rw, req, params)
+	return app.NewListBottleContext(ctx, goa.New("cellar"))
+}
+
+func TestAnyOfIntFirst(t *testing.T) {
+	ctx, err := newListContext("vintage=2012")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v := app.MustListBottleVintageAsInt(ctx); v != 2012 {
+		t.Errorf("expected vintage 2012, got %d", v)
+	}
+	ctx, err = newListContext("vintage=old")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v := app.MustListBottleVintageAsString(ctx); v != "old" {
+		t.Errorf(`expected vintage "old", got %q`, v)
+	}
+}
+
+func TestAnyOfStringFirst(t *testing.T) {
+	ctx, err := newListContext("name=42")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v := app.MustListBottleNameAsString(ctx); v != "42" {
+		t.Errorf(`expected name "42", got %q`, v)
+	}
+}
+
+func TestAnyOfNoMatch(t *testing.T) {
+	ctx, err := newListContext("rating=3")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v := app.MustListBottleRatingAsInt(ctx); v != 3 {
+		t.Errorf("expected rating 3, got %d", v)
+	}
+	_, err = newListContext("rating=high")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if err.(goa.ServiceError).ResponseStatus() != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, err.(goa.ServiceError).ResponseStatus())
+	}
+}
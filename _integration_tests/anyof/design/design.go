@@ -0,0 +1,32 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API whose params accept values of several types")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("list", func() {
+		Routing(GET(""))
+		Params(func() {
+			Param("vintage", Any, "Vintage year or label", func() {
+				AnyOfTypes(IntegerKind, StringKind)
+			})
+			Param("name", Any, "Name or ID", func() {
+				AnyOfTypes(StringKind, IntegerKind)
+			})
+			Param("rating", Any, "Rating or sparkling flag", func() {
+				AnyOfTypes(IntegerKind, BooleanKind)
+			})
+		})
+		Response(OK, "text/plain")
+	})
+})
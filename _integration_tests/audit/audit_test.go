@@ -0,0 +1,76 @@
+package audit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/audit/app"
+	"golang.org/x/net/context"
+)
+
+// BottleController normalizes the rating of the updated bottles.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Update runs the update action.
+func (c *BottleController) Update(ctx *app.UpdateBottleContext) error {
+	if ctx.Payload.Rating != nil && *ctx.Payload.Rating > 5 {
+		rating := 5
+		ctx.Payload.Rating = &rating
+	}
+	return ctx.NoContent()
+}
+
+func TestAuditEvent(t *testing.T) {
+	var events []app.AuditEvent
+	app.AuditHandler = func(_ context.Context, event app.AuditEvent) {
+		events = append(events, event)
+	}
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	req, err := http.NewRequest("PUT", server.URL+"/bottles/42", strings.NewReader(`{"name":"Merlot","rating":7}`))
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "alice")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to send request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 204 {
+		t.Fatalf("invalid status, expected 204 got %d", resp.StatusCode)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("invalid number of audit events, expected 1 got %d", len(events))
+	}
+	event := events[0]
+	if event.Actor != "alice" {
+		t.Errorf("invalid actor, expected %q got %q", "alice", event.Actor)
+	}
+	if event.ResourceID != "42" {
+		t.Errorf("invalid resource ID, expected %q got %q", "42", event.ResourceID)
+	}
+	if string(event.Before) != `{"name":"Merlot","rating":7}` {
+		t.Errorf("invalid before state, got %s", event.Before)
+	}
+	if string(event.After) != `{"name":"Merlot","rating":5}` {
+		t.Errorf("invalid after state, got %s", event.After)
+	}
+	if !reflect.DeepEqual(event.Changed, []string{"rating"}) {
+		t.Errorf("invalid changed fields, expected [rating] got %v", event.Changed)
+	}
+	if event.Timestamp.IsZero() {
+		t.Error("missing timestamp")
+	}
+}
@@ -0,0 +1,30 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API recording audit events")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var BottlePayload = Type("BottlePayload", func() {
+	Description("BottlePayload is the type used to update bottles")
+	Attribute("name", String, "Name of bottle")
+	Attribute("rating", Integer, "Rating of bottle")
+	Required("name")
+})
+
+var _ = Resource("bottle", func() {
+	Action("update", func() {
+		Routing(PUT("/bottles/:id"))
+		Description("update changes a bottle, the controller normalizes the rating")
+		AuditLog("X-User-ID", "id")
+		Payload(BottlePayload)
+		Response(NoContent)
+	})
+})
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/goadesign/goa"
+)
+
+func TestLambdaHandler(t *testing.T) {
+	handler := NewLambdaHandler(goa.New("cellar"))
+
+	cases := []struct {
+		Path   string
+		Status int
+		Body   string
+	}{
+		{"/bottles/1", http.StatusOK, "Number 8"},
+		{"/bottles/2", http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		event := events.APIGatewayProxyRequest{
+			HTTPMethod: "GET",
+			Path:       c.Path,
+			Headers:    map[string]string{"Accept": "text/plain"},
+		}
+		resp, err := handler(context.Background(), event)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.Path, err)
+		}
+		if resp.StatusCode != c.Status {
+			t.Errorf("%s: expected status %d, got %d", c.Path, c.Status, resp.StatusCode)
+		}
+		if resp.Body != c.Body {
+			t.Errorf("%s: expected body %q, got %q", c.Path, c.Body, resp.Body)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/awslambda/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// NewBottleController creates a bottle controller.
+func NewBottleController(service *goa.Service) *BottleController {
+	return &BottleController{Controller: service.NewController("BottleController")}
+}
+
+// Show writes the bottle with ID 1 and responds with 404 otherwise.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID != 1 {
+		return ctx.NotFound()
+	}
+	return ctx.OK([]byte("Number 8"))
+}
+
+func main() {
+	StartLambda(goa.New("cellar"))
+}
@@ -0,0 +1,58 @@
+package bigint_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/bigint/app"
+	"golang.org/x/net/context"
+)
+
+// newShowContext creates the show action context for the given entry ID.
+func newShowContext(id string) (*app.ShowEntryContext, error) {
+	req := httptest.NewRequest("GET", "/entries/"+id, nil)
+	rw := httptest.NewRecorder()
+	ctx := goa.NewContext(context.Background(), rw, req, url.Values{"id": {id}})
+	return app.NewShowEntryContext(ctx, goa.New("ledger"))
+}
+
+func TestBigIntParam(t *testing.T) {
+	ctx, err := newShowContext("184467440737095516160")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if id := ctx.ID.String(); id != "184467440737095516160" {
+		t.Errorf("expected ID 184467440737095516160, got %s", id)
+	}
+	if _, err := newShowContext("42"); err == nil {
+		t.Error("expected an error for an ID lower than the minimum")
+	}
+	if _, err := newShowContext("4.2"); err == nil {
+		t.Error("expected an error for a non integer ID")
+	}
+}
+
+func TestBigIntPayload(t *testing.T) {
+	const body = `{"amount":"123456789012345678901234567890"}`
+	var payload app.EntryPayload
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := payload.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %s", err)
+	}
+	b, err := json.Marshal(&payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(b) != body {
+		t.Errorf("expected %s, got %s", body, b)
+	}
+	payload.Amount, _ = goa.ParseBigInt("-1")
+	if err := payload.Validate(); err == nil {
+		t.Error("expected an error for a negative amount")
+	}
+}
@@ -0,0 +1,38 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("ledger", func() {
+	Title("The ledger API")
+	Description("An API using arbitrary-precision integers")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var EntryPayload = Type("EntryPayload", func() {
+	Attribute("amount", BigIntType, "Amount in the smallest unit", func() {
+		Minimum(0)
+	})
+	Required("amount")
+})
+
+var _ = Resource("entry", func() {
+	BasePath("/entries")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", BigIntType, "Entry ID", func() {
+				Minimum("9223372036854775808")
+			})
+		})
+		Response(OK, "text/plain")
+	})
+	Action("create", func() {
+		Routing(POST(""))
+		Payload(EntryPayload)
+		Response(Created)
+	})
+})
@@ -0,0 +1,55 @@
+package cachecontrol_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/cachecontrol/app"
+)
+
+// bottleController returns the bottle with ID 1 and NotFound for any other ID.
+type bottleController struct {
+	*goa.Controller
+}
+
+// Show sends the cacheable bottle or a NotFound response.
+func (c *bottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID != 1 {
+		return ctx.NotFound()
+	}
+	name := "Number 8"
+	return ctx.OK(&app.GoaExampleBottle{ID: &ctx.ID, Name: &name})
+}
+
+func TestCacheControl(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &bottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/bottles/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != "max-age=300, public" {
+		t.Errorf("expected the joined cache directives, got %q", cc)
+	}
+
+	resp, err = http.Get(server.URL + "/bottles/2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", resp.StatusCode)
+	}
+	if cc, ok := resp.Header["Cache-Control"]; ok {
+		t.Errorf("expected no Cache-Control header on the NotFound response, got %q", cc)
+	}
+}
@@ -0,0 +1,39 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API setting the Cache-Control header of its responses")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var BottleMedia = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Attributes(func() {
+		Attribute("id", Integer)
+		Attribute("name", String)
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+	})
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer)
+		})
+		Response(OK, func() {
+			Media(BottleMedia)
+			CacheControl("max-age=300", "public")
+		})
+		Response(NotFound)
+	})
+})
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goadesign/goa/_integration_tests/clientcontext/client"
+)
+
+// server returns a test server that counts the requests it receives.
+func server(hits *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(hits, 1)
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func TestExpiredContext(t *testing.T) {
+	var hits int32
+	srv := server(&hits)
+	defer srv.Close()
+	c := client.NewCellarClient(srv.URL)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	start := time.Now()
+	_, err := c.ShowBottle(ctx, client.ShowBottlePath(1))
+	if err != client.ErrTimeout {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("expected the call to return immediately, took %s", d)
+	}
+	if n := atomic.LoadInt32(&hits); n != 0 {
+		t.Errorf("expected no request to reach the server, got %d", n)
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	var hits int32
+	srv := server(&hits)
+	defer srv.Close()
+	c := client.NewCellarClient(srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.ShowBottle(ctx, client.ShowBottlePath(1))
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 0 {
+		t.Errorf("expected no request to reach the server, got %d", n)
+	}
+}
+
+func TestLiveContext(t *testing.T) {
+	var hits int32
+	srv := server(&hits)
+	defer srv.Close()
+	c := client.NewCellarClient(srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := c.ShowBottle(ctx, client.ShowBottlePath(1))
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", resp.StatusCode)
+	}
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Errorf("expected one request to reach the server, got %d", n)
+	}
+}
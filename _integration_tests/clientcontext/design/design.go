@@ -0,0 +1,24 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API whose client requests honor the caller context")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(NoContent)
+	})
+})
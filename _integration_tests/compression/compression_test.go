@@ -0,0 +1,59 @@
+package compression_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/compression/app"
+)
+
+// bottleController returns the requested bottle.
+type bottleController struct {
+	*goa.Controller
+}
+
+// Show writes the bottle with the given ID.
+func (c *bottleController) Show(ctx *app.ShowBottleContext) error {
+	return ctx.OK(&app.GoaExampleBottle{ID: ctx.ID, Name: "Number 8"})
+}
+
+func TestCompression(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &bottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/bottles/1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// Setting the header explicitly disables the transparent decompression of the client.
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if ce := resp.Header.Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("expected gzip content encoding, got %q", ce)
+	}
+	gr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("response body is not gzip compressed: %s", err)
+	}
+	defer gr.Close()
+	var bottle app.GoaExampleBottle
+	if err := json.NewDecoder(gr).Decode(&bottle); err != nil {
+		t.Fatalf("failed to decode decompressed body: %s", err)
+	}
+	if bottle.ID != 1 || bottle.Name != "Number 8" {
+		t.Errorf("unexpected bottle %+v", bottle)
+	}
+}
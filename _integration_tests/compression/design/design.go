@@ -0,0 +1,40 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API compressing its JSON responses with gzip")
+	Host("localhost:8080")
+	Scheme("http")
+	Produces("application/json", func() {
+		Compression("gzip")
+	})
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(OK, BottleMedia)
+	})
+})
+
+// BottleMedia is the media type of the compressed responses.
+var BottleMedia = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Attributes(func() {
+		Attribute("id", Integer, "ID of bottle")
+		Attribute("name", String, "Name of wine")
+		Required("id", "name")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+	})
+})
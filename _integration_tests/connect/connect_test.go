@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/connect/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID != 1 {
+		return ctx.NotFound()
+	}
+	return ctx.OK(&app.GoaExampleBottle{ID: 1, Name: "Number 8"})
+}
+
+// Create runs the create action.
+func (c *BottleController) Create(ctx *app.CreateBottleContext) error {
+	return ctx.Created(&app.GoaExampleBottle{ID: 2, Name: ctx.Payload.Name})
+}
+
+func TestConnect(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, true))
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	MountConnectHandlers(service)
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+	ctx := context.Background()
+
+	show := connect.NewClient[ShowBottleRequest, json.RawMessage](http.DefaultClient, server.URL+BottleShowProcedure, connect.WithCodec(ConnectCodec{}))
+	resp, err := show.CallUnary(ctx, connect.NewRequest(&ShowBottleRequest{ID: 1}))
+	if err != nil {
+		t.Fatalf("show call failed: %s", err)
+	}
+	var bottle app.GoaExampleBottle
+	if err := json.Unmarshal(*resp.Msg, &bottle); err != nil {
+		t.Fatalf("failed to decode show response: %s", err)
+	}
+	if bottle.ID != 1 || bottle.Name != "Number 8" {
+		t.Errorf("unexpected show response %+v", bottle)
+	}
+
+	_, err = show.CallUnary(ctx, connect.NewRequest(&ShowBottleRequest{ID: 42}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	create := connect.NewClient[CreateBottleRequest, json.RawMessage](http.DefaultClient, server.URL+BottleCreateProcedure, connect.WithCodec(ConnectCodec{}))
+	resp, err = create.CallUnary(ctx, connect.NewRequest(&CreateBottleRequest{Payload: json.RawMessage(`{"name":"Merlot"}`)}))
+	if err != nil {
+		t.Fatalf("create call failed: %s", err)
+	}
+	if err := json.Unmarshal(*resp.Msg, &bottle); err != nil {
+		t.Fatalf("failed to decode create response: %s", err)
+	}
+	if bottle.ID != 2 || bottle.Name != "Merlot" {
+		t.Errorf("unexpected create response %+v", bottle)
+	}
+
+	_, err = create.CallUnary(ctx, connect.NewRequest(&CreateBottleRequest{Payload: json.RawMessage(`{}`)}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("expected invalid argument error, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package contenttype_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/contenttype/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// bottleController accepts any bottle.
+type bottleController struct {
+	*goa.Controller
+}
+
+// Create responds with Created.
+func (c *bottleController) Create(ctx *app.CreateBottleContext) error {
+	return ctx.Created()
+}
+
+func TestContentType(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, false))
+	app.MountBottleController(service, &bottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := []struct {
+		name, contentType, body string
+		status                  int
+	}{
+		{"json", "application/json", `{"name":"Number 8"}`, http.StatusCreated},
+		{"xml with a json content type", "application/json", `<payload><name>Number 8</name></payload>`, http.StatusUnsupportedMediaType},
+		{"xml", "application/xml", `<payload><name>Number 8</name></payload>`, http.StatusCreated},
+		{"invalid payload", "application/json", `{}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		resp, err := http.Post(server.URL+"/bottles", c.contentType, strings.NewReader(c.body))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Errorf("%s: expected status %d, got %d", c.name, c.status, resp.StatusCode)
+		}
+	}
+}
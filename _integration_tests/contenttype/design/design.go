@@ -0,0 +1,28 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API rejecting request bodies that do not match their content type")
+	Host("localhost:8080")
+	Scheme("http")
+	Consumes("application/json")
+	Consumes("application/xml")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("create", func() {
+		Routing(POST(""))
+		Payload(func() {
+			Attribute("name", String)
+			Required("name")
+		})
+		Response(Created)
+		Response(BadRequest, ErrorMedia)
+	})
+})
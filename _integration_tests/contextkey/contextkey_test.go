@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/contextkey/app"
+	"golang.org/x/net/context"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action, it writes the user ID read from the action context.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	return ctx.OK([]byte(ctx.UserID))
+}
+
+// Authenticate injects the user ID claim read from the Authorization header in the request
+// context.
+func Authenticate(h goa.Handler) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		if userID := req.Header.Get("Authorization"); userID != "" {
+			ctx = app.WithUserID(ctx, userID)
+		}
+		return h(ctx, rw, req)
+	}
+}
+
+func TestContextKey(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(Authenticate)
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := map[string]string{"with a claim": "42", "without a claim": ""}
+	for k, userID := range cases {
+		req, _ := http.NewRequest("GET", server.URL+"/bottles/1", nil)
+		if userID != "" {
+			req.Header.Set("Authorization", userID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %s", k, err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", k, resp.StatusCode)
+		}
+		if string(body) != userID {
+			t.Errorf("%s: expected user ID %q, got %q", k, userID, string(body))
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API exposing the user ID injected by middleware in the action contexts")
+	Host("localhost:8080")
+	Scheme("http")
+	ContextKey("userID", String)
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(OK, "text/plain")
+	})
+})
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/contract/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// NewBottleController creates a bottle controller.
+func NewBottleController(service *goa.Service) *BottleController {
+	return &BottleController{Controller: service.NewController("BottleController")}
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID != 1 {
+		return ctx.NotFound()
+	}
+	return ctx.OK(&app.GoaExampleBottle{ID: 1, Name: "Number 8"})
+}
+
+// Create runs the create action.
+func (c *BottleController) Create(ctx *app.CreateBottleContext) error {
+	ctx.ResponseData.Header().Set("Location", "/bottles/1")
+	return ctx.Created()
+}
+
+func TestCheckContract(t *testing.T) {
+	b, err := os.ReadFile(contractSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := loadContract(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := contractCase{Name: "bottle show", Method: "GET", Pattern: "/bottles/{id}", Path: "/bottles/1", Status: 200}
+	response := func(status int) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {"application/vnd.goa.example.bottle+json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id":1,"name":"Number 8"}`)),
+		}
+	}
+
+	if err := checkContract(doc, tc, response(http.StatusOK)); err != nil {
+		t.Errorf("compliant response: unexpected error %s", err)
+	}
+	if err := checkContract(doc, tc, response(http.StatusInternalServerError)); err == nil {
+		t.Error("response with the wrong status: expected an error")
+	}
+}
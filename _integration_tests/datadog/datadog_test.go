@@ -0,0 +1,55 @@
+package datadog_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/datadog/app"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/mocktracer"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+// bottleController checks that the request span is stored in the action context.
+type bottleController struct {
+	*goa.Controller
+	traced bool
+}
+
+// Show writes the bottle and records whether the context carries the request span.
+func (c *bottleController) Show(ctx *app.ShowBottleContext) error {
+	_, c.traced = tracer.SpanFromContext(ctx)
+	return ctx.OK([]byte("bottle"))
+}
+
+func TestDatadog(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	service := goa.New("cellar")
+	ctrl := &bottleController{Controller: service.NewController("BottleController")}
+	app.MountBottleController(service, ctrl)
+	server := httptest.NewServer(app.TraceService(service))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/bottles/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if !ctrl.traced {
+		t.Errorf("the action context does not carry the request span")
+	}
+	spans := mt.FinishedSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if r := spans[0].Tag(ext.ResourceName); r != "bottle.show" {
+		t.Errorf("expected resource name %q, got %v", "bottle.show", r)
+	}
+}
@@ -0,0 +1,54 @@
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/debug/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	return ctx.NoContent()
+}
+
+// AccountController implements the account resource.
+type AccountController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *AccountController) Show(ctx *app.ShowAccountContext) error {
+	return ctx.NoContent()
+}
+
+// newServer returns a test server for a service with both controllers mounted.
+func newServer() *httptest.Server {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	app.MountAccountController(service, &AccountController{Controller: service.NewController("account")})
+	return httptest.NewServer(service.Mux)
+}
+
+func TestProfilingHandlers(t *testing.T) {
+	// Each service mounts its own profiling handlers.
+	for i := 0; i < 2; i++ {
+		server := newServer()
+		resp, err := http.Get(server.URL + "/debug/pprof/cmdline")
+		server.Close()
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != profilingStatus {
+			t.Errorf("service %d: expected status %d, got %d", i, profilingStatus, resp.StatusCode)
+		}
+	}
+}
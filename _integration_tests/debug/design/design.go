@@ -0,0 +1,36 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API exposing the profiling handlers in debug builds")
+	Host("localhost:8080")
+	Scheme("http")
+	Debug()
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(NoContent)
+	})
+})
+
+var _ = Resource("account", func() {
+	BasePath("/accounts")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Account ID")
+		})
+		Response(NoContent)
+	})
+})
@@ -0,0 +1,9 @@
+//go:build !debug
+// +build !debug
+
+package debug
+
+import "net/http"
+
+// profilingStatus is the status of the responses to the profiling requests in release builds.
+const profilingStatus = http.StatusNotFound
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/defaults/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// List runs the list action, it writes the page parameters read from the action context.
+func (c *BottleController) List(ctx *app.ListBottleContext) error {
+	return ctx.OK([]byte(fmt.Sprintf("page=%d per_page=%d", ctx.Page, ctx.PerPage)))
+}
+
+func TestDefaults(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := map[string]string{
+		"":                    "page=1 per_page=20",
+		"?page=3":             "page=3 per_page=20",
+		"?page=3&per_page=50": "page=3 per_page=50",
+	}
+	for query, expected := range cases {
+		resp, err := http.Get(server.URL + "/bottles" + query)
+		if err != nil {
+			t.Fatalf("GET /bottles%s failed: %s", query, err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET /bottles%s: expected status 200, got %d", query, resp.StatusCode)
+		}
+		if string(body) != expected {
+			t.Errorf("GET /bottles%s: expected %q, got %q", query, expected, string(body))
+		}
+	}
+}
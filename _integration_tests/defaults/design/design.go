@@ -0,0 +1,29 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API using default values for the parameters omitted by the requests")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("list", func() {
+		Routing(GET(""))
+		Params(func() {
+			Param("page", Integer, "Page number", func() {
+				Default(1)
+			})
+			Param("per_page", Integer, "Number of bottles per page", func() {
+				Default(20)
+			})
+		})
+		Response(OK, "text/plain")
+	})
+})
@@ -0,0 +1,47 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("pets", func() {
+	Title("The pets API")
+	Description("An API whose payload type is selected by a discriminator field")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+// Dog is the dog payload.
+var Dog = Type("Dog", func() {
+	Attribute("type", String)
+	Attribute("name", String)
+	Attribute("barks", Boolean)
+	Required("name")
+})
+
+// Cat is the cat payload.
+var Cat = Type("Cat", func() {
+	Attribute("type", String)
+	Attribute("name", String)
+	Attribute("lives", Integer, func() {
+		Maximum(9)
+	})
+	Required("name")
+})
+
+// Pet is the polymorphic pet payload.
+var Pet = Type("Pet", func() {
+	Attribute("type", String)
+	Discriminate("type", map[string]DataType{"dog": Dog, "cat": Cat})
+})
+
+var _ = Resource("pet", func() {
+	BasePath("/pets")
+	Action("create", func() {
+		Routing(POST(""))
+		Description("create a pet")
+		Payload(Pet)
+		Response(OK, "text/plain")
+	})
+})
@@ -0,0 +1,72 @@
+package discriminate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/discriminate/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// PetController implements the pet resource.
+type PetController struct {
+	*goa.Controller
+}
+
+// Create describes the concrete payload type it receives.
+func (c *PetController) Create(ctx *app.CreatePetContext) error {
+	switch p := ctx.Payload.(type) {
+	case *app.Dog:
+		return ctx.OK([]byte(fmt.Sprintf("dog %s %v", p.Name, p.Barks != nil && *p.Barks)))
+	case *app.Cat:
+		lives := 0
+		if p.Lives != nil {
+			lives = *p.Lives
+		}
+		return ctx.OK([]byte(fmt.Sprintf("cat %s %d", p.Name, lives)))
+	}
+	return fmt.Errorf("unexpected payload type %T", ctx.Payload)
+}
+
+func TestDiscriminatedPayload(t *testing.T) {
+	service := goa.New("pets")
+	service.Use(middleware.ErrorHandler(service, true))
+	app.MountPetController(service, &PetController{Controller: service.NewController("pet")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := []struct {
+		body   string
+		status int
+		result string
+	}{
+		{`{"type":"dog","name":"rex","barks":true}`, http.StatusOK, "dog rex true"},
+		{`{"type":"cat","name":"tom","lives":7}`, http.StatusOK, "cat tom 7"},
+		{`{"type":"cat","name":"tom","lives":10}`, http.StatusBadRequest, ""},
+		{`{"type":"dog"}`, http.StatusBadRequest, ""},
+		{`{"type":"bird","name":"tweety"}`, http.StatusBadRequest, ""},
+	}
+	for _, c := range cases {
+		resp, err := http.Post(server.URL+"/pets", "application/json", strings.NewReader(c.body))
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to read response: %s", err)
+		}
+		if resp.StatusCode != c.status {
+			t.Errorf("%s: expected status %d, got %d: %s", c.body, c.status, resp.StatusCode, body)
+			continue
+		}
+		if c.result != "" && string(body) != c.result {
+			t.Errorf("%s: expected %q, got %q", c.body, c.result, body)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API tested end-to-end with the service running in a container")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var BottleMedia = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Description("A bottle of wine")
+	TypeName("Bottle")
+	Attributes(func() {
+		Attribute("id", Integer, "ID of bottle")
+		Attribute("name", String, "Name of bottle")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+	})
+})
+
+var _ = Resource("health", func() {
+	Action("check", func() {
+		Routing(GET("/health"))
+		Description("check responds with 200 once the service is ready")
+		Response(OK)
+	})
+})
+
+var _ = Resource("bottle", func() {
+	Action("show", func() {
+		Routing(GET("/bottles/:id"))
+		Description("show retrieves a bottle")
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(OK, BottleMedia)
+	})
+})
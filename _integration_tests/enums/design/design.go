@@ -0,0 +1,41 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API using string enum attributes")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("list", func() {
+		Routing(GET("/:color"))
+		Description("list the bottles of a color")
+		Params(func() {
+			Param("color", String, "Wine color", func() {
+				Enum("red", "white", "rose")
+			})
+			Param("sort", String, "Sort order", func() {
+				Enum("name", "vintage")
+			})
+		})
+		Response(OK, "text/plain")
+	})
+	Action("create", func() {
+		Routing(POST(""))
+		Description("create a bottle")
+		Payload(func() {
+			Attribute("color", String, "Wine color", func() {
+				Enum("red", "white", "rose")
+			})
+			Required("color")
+		})
+		Response(NoContent)
+	})
+})
@@ -0,0 +1,97 @@
+package enums
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/enums/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// List echoes the color and sort order.
+func (c *BottleController) List(ctx *app.ListBottleContext) error {
+	sort := "none"
+	if ctx.Sort != nil {
+		sort = *ctx.Sort
+	}
+	return ctx.OK([]byte(ctx.Color + " " + sort))
+}
+
+// Create accepts the bottle.
+func (c *BottleController) Create(ctx *app.CreateBottleContext) error {
+	return ctx.NoContent()
+}
+
+func TestEnumConstants(t *testing.T) {
+	var color app.ListBottleContextColor = app.ListBottleContextColorRose
+	if color != "rose" {
+		t.Errorf("expected rose, got %s", color)
+	}
+	if !app.IsValidListBottleContextColor(app.ListBottleContextColorRed) {
+		t.Error("expected red to be a valid color")
+	}
+	if app.IsValidListBottleContextSort("color") {
+		t.Error("expected color not to be a valid sort order")
+	}
+	if app.IsValidCreateBottlePayloadColor("blue") {
+		t.Error("expected blue not to be a valid payload color")
+	}
+}
+
+func TestEnumCoercion(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, true))
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/bottles/red", http.StatusOK, "red none"},
+		{"/bottles/white?sort=vintage", http.StatusOK, "white vintage"},
+		{"/bottles/blue", http.StatusBadRequest, "color"},
+		{"/bottles/red?sort=price", http.StatusBadRequest, "sort"},
+	}
+	for _, c := range cases {
+		resp, err := http.Get(server.URL + c.path)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to read response: %s", err)
+		}
+		if resp.StatusCode != c.status {
+			t.Errorf("%s: expected status %d, got %d: %s", c.path, c.status, resp.StatusCode, body)
+			continue
+		}
+		if !strings.Contains(string(body), c.body) {
+			t.Errorf("%s: expected response to contain %q, got %q", c.path, c.body, body)
+		}
+		if c.status == http.StatusBadRequest && strings.Count(string(body), "must be one of") != 1 {
+			t.Errorf("%s: expected a single enum error, got %s", c.path, body)
+		}
+	}
+
+	resp, err := http.Post(server.URL+"/bottles", "application/json", strings.NewReader(`{"color":"blue"}`))
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid payload color, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,77 @@
+package expvar
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/expvar/admin"
+	"github.com/goadesign/goa/_integration_tests/expvar/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	return ctx.NoContent()
+}
+
+// AdminController implements the bottle resource of the admin package.
+type AdminController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *AdminController) Show(ctx *admin.ShowBottleContext) error {
+	return ctx.NoContent()
+}
+
+func TestExpvar(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	// The admin package publishes its metrics in the same expvar maps.
+	adminService := goa.New("admin")
+	admin.MountBottleController(adminService, &AdminController{Controller: adminService.NewController("bottle")})
+
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(server.URL + "/bottles/1")
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected status 204, got %d", resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Get(server.URL + "/debug/vars")
+	if err != nil {
+		t.Fatalf("debug vars request failed: %s", err)
+	}
+	defer resp.Body.Close()
+	var vars struct {
+		Requests  map[string]int            `json:"goa.requests"`
+		Latencies map[string]map[string]int `json:"goa.latencies"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+		t.Fatalf("failed to decode debug vars: %s", err)
+	}
+	if c := vars.Requests["Bottle.Show.204"]; c != 3 {
+		t.Errorf("expected 3 requests, got %d", c)
+	}
+	var total int
+	for _, c := range vars.Latencies["Bottle.Show"] {
+		total += c
+	}
+	if total != 3 {
+		t.Errorf("expected 3 requests in the latency histogram, got %d (%v)", total, vars.Latencies)
+	}
+}
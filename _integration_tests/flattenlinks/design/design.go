@@ -0,0 +1,59 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API whose media types links are flattened")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var Account = MediaType("application/vnd.goa.example.account+json", func() {
+	Attributes(func() {
+		Attribute("id", Integer, "Account ID")
+		Attribute("href", String, "Account href")
+		Attribute("name", String, "Account name")
+		Required("id", "href")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("href")
+		Attribute("name")
+	})
+	View("link", func() {
+		Attribute("id")
+		Attribute("href")
+	})
+})
+
+var Bottle = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Attributes(func() {
+		Attribute("id", Integer, "Bottle ID")
+		Attribute("name", String, "Bottle name")
+		Attribute("account", Account, "Owner account")
+		Required("id", "name")
+	})
+	Links(func() {
+		Link("account")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+		Attribute("links")
+	})
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(OK, Bottle)
+	})
+})
@@ -0,0 +1,57 @@
+package flattenlinks_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/goadesign/goa/_integration_tests/flattenlinks/app"
+)
+
+func TestFlattenLinksMarshal(t *testing.T) {
+	bottle := &app.GoaExampleBottle{
+		ID:   1,
+		Name: "Chateau Margaux",
+		Links: &app.GoaExampleBottleLinks{
+			Account: &app.GoaExampleAccountLink{ID: 2, Href: "/accounts/2"},
+		},
+	}
+	b, err := json.Marshal(bottle)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := fields["links"]; ok {
+		t.Errorf("expected no links object, got %s", b)
+	}
+	if string(fields["account"]) != `{"href":"/accounts/2","id":2}` {
+		t.Errorf("expected the account link at the root, got %s", b)
+	}
+	if string(fields["name"]) != `"Chateau Margaux"` {
+		t.Errorf("expected the bottle name at the root, got %s", b)
+	}
+}
+
+func TestFlattenLinksUnmarshal(t *testing.T) {
+	var bottle app.GoaExampleBottle
+	body := `{"id":1,"name":"Chateau Margaux","account":{"id":2,"href":"/accounts/2"}}`
+	if err := json.Unmarshal([]byte(body), &bottle); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if bottle.Name != "Chateau Margaux" {
+		t.Errorf("unexpected name %q", bottle.Name)
+	}
+	if bottle.Links == nil || bottle.Links.Account == nil || bottle.Links.Account.ID != 2 {
+		t.Errorf("expected the account link to be read from the root, got %+v", bottle.Links)
+	}
+
+	bottle = app.GoaExampleBottle{}
+	if err := json.Unmarshal([]byte(`{"id":1,"name":"Chateau Margaux"}`), &bottle); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if bottle.Links != nil {
+		t.Errorf("expected no links, got %+v", bottle.Links)
+	}
+}
@@ -0,0 +1,27 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("gallery", func() {
+	Title("The gallery API")
+	Description("An API receiving multipart/form-data uploads")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("picture", func() {
+	Action("upload", func() {
+		Routing(POST("/pictures"))
+		Description("upload stores a picture")
+		FormData(func() {
+			Attribute("caption", String, "Picture caption")
+			Attribute("rating", Integer, "Picture rating")
+			Attribute("image", FileType, "Picture content")
+			Required("caption", "image")
+		})
+		Response(NoContent)
+	})
+})
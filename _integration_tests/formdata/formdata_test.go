@@ -0,0 +1,152 @@
+package formdata_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/formdata/app"
+	"github.com/goadesign/goa/_integration_tests/formdata/client"
+	"golang.org/x/net/context"
+)
+
+// PictureController implements the picture resource.
+type PictureController struct {
+	*goa.Controller
+	payload *app.UploadPicturePayload
+}
+
+// Upload runs the upload action.
+func (c *PictureController) Upload(ctx *app.UploadPictureContext) error {
+	c.payload = ctx.Payload
+	return ctx.NoContent()
+}
+
+func TestFormData(t *testing.T) {
+	service := goa.New("gallery")
+	ctrl := &PictureController{Controller: service.NewController("picture")}
+	app.MountPictureController(service, ctrl)
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("caption", "sunset"); err != nil {
+		t.Fatalf("failed to write caption field: %s", err)
+	}
+	if err := w.WriteField("rating", "4"); err != nil {
+		t.Fatalf("failed to write rating field: %s", err)
+	}
+	part, err := w.CreateFormFile("image", "sunset.png")
+	if err != nil {
+		t.Fatalf("failed to create image part: %s", err)
+	}
+	part.Write([]byte("PNG content"))
+	w.Close()
+
+	resp, err := http.Post(server.URL+"/pictures", w.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatalf("failed to send request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+	}
+	p := ctrl.payload
+	if p == nil {
+		t.Fatal("payload was not initialized")
+	}
+	if p.Caption != "sunset" {
+		t.Errorf(`expected caption "sunset", got %q`, p.Caption)
+	}
+	if p.Rating == nil || *p.Rating != 4 {
+		t.Errorf("expected rating 4, got %v", p.Rating)
+	}
+	if p.Image == nil {
+		t.Fatal("image was not initialized")
+	}
+	if p.Image.Filename != "sunset.png" {
+		t.Errorf(`expected image filename "sunset.png", got %q`, p.Image.Filename)
+	}
+	f, err := p.Image.Open()
+	if err != nil {
+		t.Fatalf("failed to open image: %s", err)
+	}
+	defer f.Close()
+	content, _ := ioutil.ReadAll(f)
+	if string(content) != "PNG content" {
+		t.Errorf(`expected image content "PNG content", got %q`, content)
+	}
+}
+
+func TestFormDataClient(t *testing.T) {
+	service := goa.New("gallery")
+	ctrl := &PictureController{Controller: service.NewController("picture")}
+	app.MountPictureController(service, ctrl)
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	image, err := fileHeader("image", "sunset.png", "PNG content")
+	if err != nil {
+		t.Fatalf("failed to create image file header: %s", err)
+	}
+	rating := 4
+	c := client.New(nil)
+	c.Host = strings.TrimPrefix(server.URL, "http://")
+	payload := &client.UploadPicturePayload{Caption: "sunset", Rating: &rating, Image: image}
+	resp, err := c.UploadPicture(context.Background(), client.UploadPicturePath(), payload)
+	if err != nil {
+		t.Fatalf("failed to send request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+	}
+	p := ctrl.payload
+	if p == nil {
+		t.Fatal("payload was not initialized")
+	}
+	if p.Caption != "sunset" {
+		t.Errorf(`expected caption "sunset", got %q`, p.Caption)
+	}
+	if p.Rating == nil || *p.Rating != 4 {
+		t.Errorf("expected rating 4, got %v", p.Rating)
+	}
+	if p.Image == nil {
+		t.Fatal("image was not initialized")
+	}
+	if p.Image.Filename != "sunset.png" {
+		t.Errorf(`expected image filename "sunset.png", got %q`, p.Image.Filename)
+	}
+	f, err := p.Image.Open()
+	if err != nil {
+		t.Fatalf("failed to open image: %s", err)
+	}
+	defer f.Close()
+	content, _ := ioutil.ReadAll(f)
+	if string(content) != "PNG content" {
+		t.Errorf(`expected image content "PNG content", got %q`, content)
+	}
+}
+
+// fileHeader returns the header of a file part with the given name, filename and content.
+func fileHeader(name, filename, content string) (*multipart.FileHeader, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile(name, filename)
+	if err != nil {
+		return nil, err
+	}
+	part.Write([]byte(content))
+	w.Close()
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		return nil, err
+	}
+	return form.File[name][0], nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/fx/app"
+	"go.uber.org/fx/fxtest"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// NewBottleController creates a bottle controller.
+func NewBottleController(service *goa.Service) *BottleController {
+	return &BottleController{Controller: service.NewController("BottleController")}
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	return ctx.NoContent()
+}
+
+func TestFx(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var called bool
+	tag := func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			rw.Header().Set("X-Fx", "true")
+			return h(ctx, rw, req)
+		}
+	}
+	application := fxtest.New(t,
+		ServiceModule(goa.New("cellar"), addr),
+		Middleware(tag),
+		Modules,
+	)
+	application.RequireStart()
+
+	resp, err := http.Get("http://" + addr + "/bottles/1")
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", resp.StatusCode)
+	}
+	if !called || resp.Header.Get("X-Fx") != "true" {
+		t.Errorf("middleware was not used")
+	}
+
+	application.RequireStop()
+	if _, err := http.Get("http://" + addr + "/bottles/1"); err == nil {
+		t.Errorf("expected server to be stopped")
+	}
+}
@@ -0,0 +1,39 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API serving HEAD requests with the GET handlers")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+// Bottle is the bottle resource media type.
+var Bottle = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Description("A bottle of wine")
+	Attributes(func() {
+		Attribute("id", Integer, "ID of bottle")
+		Attribute("name", String, "Name of wine")
+		Required("id", "name")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+	})
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(OK, Bottle)
+		Response(NotFound)
+	})
+})
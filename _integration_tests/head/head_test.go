@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/head/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID != 1 {
+		return ctx.NotFound()
+	}
+	ctx.ResponseData.Header().Set("ETag", `"1"`)
+	return ctx.OK(&app.GoaExampleBottle{ID: 1, Name: "Number 8"})
+}
+
+func TestHead(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	for _, path := range []string{"/bottles/1", "/bottles/2"} {
+		get, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %s", path, err)
+		}
+		getBody, _ := ioutil.ReadAll(get.Body)
+		get.Body.Close()
+		head, err := http.Head(server.URL + path)
+		if err != nil {
+			t.Fatalf("HEAD %s failed: %s", path, err)
+		}
+		headBody, _ := ioutil.ReadAll(head.Body)
+		head.Body.Close()
+
+		if head.StatusCode != get.StatusCode {
+			t.Errorf("HEAD %s: expected status %d, got %d", path, get.StatusCode, head.StatusCode)
+		}
+		for _, h := range []string{"Content-Type", "ETag"} {
+			if head.Header.Get(h) != get.Header.Get(h) {
+				t.Errorf("HEAD %s: expected %s header %q, got %q", path, h, get.Header.Get(h), head.Header.Get(h))
+			}
+		}
+		if len(headBody) != 0 {
+			t.Errorf("HEAD %s: expected empty body, got %q", path, headBody)
+		}
+		if get.StatusCode == http.StatusOK && len(getBody) == 0 {
+			t.Errorf("GET %s: expected a body", path)
+		}
+	}
+}
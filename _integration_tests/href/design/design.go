@@ -0,0 +1,26 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("repos", func() {
+	Title("The repos API")
+	Description("An API whose canonical hrefs have optional segments")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("repo", func() {
+	OptionalSegment("/orgs/:orgID")
+	Action("show", func() {
+		Routing(GET("/orgs/:orgID/repos/:id"))
+		Description("show retrieves a repository")
+		Params(func() {
+			Param("orgID", Integer, "Organization ID")
+			Param("id", Integer, "Repository ID")
+		})
+		Response(NoContent)
+	})
+})
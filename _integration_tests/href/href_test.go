@@ -0,0 +1,27 @@
+package href_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/goadesign/goa/_integration_tests/href/app"
+)
+
+func TestRepoHref(t *testing.T) {
+	cases := []struct {
+		Href     string
+		Expected string
+	}{
+		{app.RepoHref(1), "/repos/1"},
+		{app.RepoHref(1, nil), "/repos/1"},
+		{app.RepoHref(1, map[string]interface{}{"orgID": 2}), "/orgs/2/repos/1"},
+	}
+	for _, c := range cases {
+		if c.Href != c.Expected {
+			t.Errorf("invalid href, expected %s got %s", c.Expected, c.Href)
+		}
+		if u, err := url.Parse(c.Href); err != nil || u.Path != c.Href {
+			t.Errorf("invalid href path %s", c.Href)
+		}
+	}
+}
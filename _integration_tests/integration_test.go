@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"strings"
 	"testing"
 )
 
@@ -20,6 +21,646 @@ func TestBootstrapReadme(t *testing.T) {
 	}
 }
 
+func TestWebSocket(t *testing.T) {
+	defer os.RemoveAll("./websocket/main.go")
+	defer os.RemoveAll("./websocket/chat.go")
+	defer os.RemoveAll("./websocket/app")
+	defer os.RemoveAll("./websocket/client")
+	defer os.RemoveAll("./websocket/tool")
+	if err := goagen("./websocket", "app", "-d", "github.com/goadesign/goa/_integration_tests/websocket/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := goagen("./websocket", "client", "-d", "github.com/goadesign/goa/_integration_tests/websocket/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := goagen("./websocket", "main", "-d", "github.com/goadesign/goa/_integration_tests/websocket/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./websocket"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSQLType(t *testing.T) {
+	defer os.RemoveAll("./sqltype/app")
+	if err := goagen("./sqltype", "app", "-d", "github.com/goadesign/goa/_integration_tests/sqltype/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./sqltype"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestAudit(t *testing.T) {
+	defer os.RemoveAll("./audit/app")
+	if err := goagen("./audit", "app", "-d", "github.com/goadesign/goa/_integration_tests/audit/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./audit"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestOptionalSegments(t *testing.T) {
+	defer os.RemoveAll("./href/app")
+	if err := goagen("./href", "app", "-d", "github.com/goadesign/goa/_integration_tests/href/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./href"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestNetworkTypes(t *testing.T) {
+	defer os.RemoveAll("./network/app")
+	if err := goagen("./network", "app", "-d", "github.com/goadesign/goa/_integration_tests/network/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./network"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestStdMux(t *testing.T) {
+	defer os.RemoveAll("./stdmux/app")
+	if err := goagen("./stdmux", "app", "-d", "github.com/goadesign/goa/_integration_tests/stdmux/design", "--router", "stdlib"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./stdmux"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestExpvar(t *testing.T) {
+	defer os.RemoveAll("./expvar/app")
+	defer os.RemoveAll("./expvar/admin")
+	for _, pkg := range []string{"app", "admin"} {
+		if err := goagen("./expvar", "app", "-d", "github.com/goadesign/goa/_integration_tests/expvar/design", "--pkg", pkg, "--expvar"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./expvar"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestRequiredNullable(t *testing.T) {
+	defer os.RemoveAll("./nullable/app")
+	if err := goagen("./nullable", "app", "-d", "github.com/goadesign/goa/_integration_tests/nullable/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./nullable"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestVisibleTo(t *testing.T) {
+	defer os.RemoveAll("./visibility/app")
+	if err := goagen("./visibility", "app", "-d", "github.com/goadesign/goa/_integration_tests/visibility/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./visibility"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSunset(t *testing.T) {
+	defer os.RemoveAll("./sunset/app")
+	if err := goagen("./sunset", "app", "-d", "github.com/goadesign/goa/_integration_tests/sunset/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./sunset"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestFormData(t *testing.T) {
+	defer os.RemoveAll("./formdata/app")
+	defer os.RemoveAll("./formdata/client")
+	defer os.RemoveAll("./formdata/tool")
+	if err := goagen("./formdata", "app", "-d", "github.com/goadesign/goa/_integration_tests/formdata/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := goagen("./formdata", "client", "-d", "github.com/goadesign/goa/_integration_tests/formdata/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./formdata"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestConnect(t *testing.T) {
+	defer os.RemoveAll("./connect/connect.go")
+	defer os.RemoveAll("./connect/app")
+	for _, gen := range []string{"app", "connect"} {
+		if err := goagen("./connect", gen, "-d", "github.com/goadesign/goa/_integration_tests/connect/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./connect"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestWireInject(t *testing.T) {
+	defer os.RemoveAll("./wire/main.go")
+	defer os.RemoveAll("./wire/bottle.go")
+	defer os.RemoveAll("./wire/wire.go")
+	defer os.RemoveAll("./wire/wire_gen.go")
+	defer os.RemoveAll("./wire/app")
+	for _, gen := range []string{"app", "main"} {
+		if err := goagen("./wire", gen, "-d", "github.com/goadesign/goa/_integration_tests/wire/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./wire"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := govet("./wire", "-tags", "wireinject"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestFx(t *testing.T) {
+	defer os.RemoveAll("./fx/fx.go")
+	defer os.RemoveAll("./fx/app")
+	for _, gen := range []string{"app", "fx"} {
+		if err := goagen("./fx", gen, "-d", "github.com/goadesign/goa/_integration_tests/fx/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./fx"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestZerolog(t *testing.T) {
+	defer os.RemoveAll("./zerolog/log_middleware.go")
+	defer os.RemoveAll("./zerolog/app")
+	if err := goagen("./zerolog", "app", "-d", "github.com/goadesign/goa/_integration_tests/zerolog/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := goagen("./zerolog", "middleware", "-d", "github.com/goadesign/goa/_integration_tests/zerolog/design", "--logger", "zerolog"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./zerolog"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestHead(t *testing.T) {
+	defer os.RemoveAll("./head/app")
+	if err := goagen("./head", "app", "-d", "github.com/goadesign/goa/_integration_tests/head/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./head"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestContextKey(t *testing.T) {
+	defer os.RemoveAll("./contextkey/app")
+	if err := goagen("./contextkey", "app", "-d", "github.com/goadesign/goa/_integration_tests/contextkey/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./contextkey"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	defer os.RemoveAll("./shutdown/server.go")
+	defer os.RemoveAll("./shutdown/app")
+	for _, gen := range []string{"app", "server"} {
+		if err := goagen("./shutdown", gen, "-d", "github.com/goadesign/goa/_integration_tests/shutdown/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./shutdown"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestContract(t *testing.T) {
+	defer os.RemoveAll("./contract/contract_test.go")
+	defer os.RemoveAll("./contract/swagger")
+	defer os.RemoveAll("./contract/app")
+	for _, gen := range []string{"app", "swagger", "contract"} {
+		if err := goagen("./contract", gen, "-d", "github.com/goadesign/goa/_integration_tests/contract/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./contract"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestPact(t *testing.T) {
+	if _, err := exec.LookPath("pact-provider-verifier"); err != nil {
+		t.Skip("pact-provider-verifier is not installed")
+	}
+	defer os.RemoveAll("./pact/bottle_pact_test.go")
+	defer os.RemoveAll("./pact/app")
+	for _, gen := range []string{"app", "pact"} {
+		if err := goagen("./pact", gen, "-d", "github.com/goadesign/goa/_integration_tests/pact/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./pact"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestTransport(t *testing.T) {
+	defer os.RemoveAll("./transport/client")
+	defer os.RemoveAll("./transport/tool")
+	if err := goagen("./transport", "client", "-d", "github.com/goadesign/goa/_integration_tests/transport/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./transport"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestClientContext(t *testing.T) {
+	defer os.RemoveAll("./clientcontext/client")
+	defer os.RemoveAll("./clientcontext/tool")
+	if err := goagen("./clientcontext", "client", "-d", "github.com/goadesign/goa/_integration_tests/clientcontext/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./clientcontext"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestDiscriminate(t *testing.T) {
+	defer os.RemoveAll("./discriminate/app")
+	if err := goagen("./discriminate", "app", "-d", "github.com/goadesign/goa/_integration_tests/discriminate/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./discriminate"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestEnums(t *testing.T) {
+	defer os.RemoveAll("./enums/app")
+	if err := goagen("./enums", "app", "-d", "github.com/goadesign/goa/_integration_tests/enums/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./enums"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestUint(t *testing.T) {
+	defer os.RemoveAll("./uint/app")
+	if err := goagen("./uint", "app", "-d", "github.com/goadesign/goa/_integration_tests/uint/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./uint"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	defer os.RemoveAll("./maxdepth/app")
+	if err := goagen("./maxdepth", "app", "-d", "github.com/goadesign/goa/_integration_tests/maxdepth/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./maxdepth"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestDebug(t *testing.T) {
+	defer os.RemoveAll("./debug/app")
+	if err := goagen("./debug", "app", "-d", "github.com/goadesign/goa/_integration_tests/debug/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./debug"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./debug", "-tags", "debug"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	defer os.RemoveAll("./defaults/app")
+	if err := goagen("./defaults", "app", "-d", "github.com/goadesign/goa/_integration_tests/defaults/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./defaults"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestUnionResult(t *testing.T) {
+	defer os.RemoveAll("./unionresult/app")
+	if err := goagen("./unionresult", "app", "-d", "github.com/goadesign/goa/_integration_tests/unionresult/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./unionresult"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestAcceptPatch(t *testing.T) {
+	defer os.RemoveAll("./acceptpatch/app")
+	if err := goagen("./acceptpatch", "app", "-d", "github.com/goadesign/goa/_integration_tests/acceptpatch/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./acceptpatch"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestStaticAssets(t *testing.T) {
+	defer os.RemoveAll("./staticassets/app")
+	if err := goagen("./staticassets", "app", "-d", "github.com/goadesign/goa/_integration_tests/staticassets/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./staticassets"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestJWTAuth(t *testing.T) {
+	defer os.RemoveAll("./jwtauth/app")
+	if err := goagen("./jwtauth", "app", "-d", "github.com/goadesign/goa/_integration_tests/jwtauth/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./jwtauth"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestMountChain(t *testing.T) {
+	defer os.RemoveAll("./mountchain/app")
+	if err := goagen("./mountchain", "app", "-d", "github.com/goadesign/goa/_integration_tests/mountchain/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./mountchain"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	defer os.RemoveAll("./coverage/app")
+	defer os.RemoveAll("./coverage/api_version_test.go")
+	if err := goagen("./coverage", "app", "-d", "github.com/goadesign/goa/_integration_tests/coverage/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := goagen("./coverage", "coverage", "-d", "github.com/goadesign/goa/_integration_tests/coverage/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./coverage"); err != nil {
+		t.Error(err.Error())
+	}
+	// The version 2.0 of the design adds an action not handled by the generated controller.
+	if err := goagen("./coverage", "coverage", "-d", "github.com/goadesign/goa/_integration_tests/coverage/design/v2"); err != nil {
+		t.Error(err.Error())
+	}
+	err := gotest("./coverage")
+	if err == nil {
+		t.Error("expected the coverage test to fail")
+	} else if !strings.Contains(err.Error(), "app.BottleController does not handle the Delete action") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	defer os.RemoveAll("./anyof/app")
+	if err := goagen("./anyof", "app", "-d", "github.com/goadesign/goa/_integration_tests/anyof/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./anyof"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	defer os.RemoveAll("./bigint/app")
+	if err := goagen("./bigint", "app", "-d", "github.com/goadesign/goa/_integration_tests/bigint/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./bigint"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestStandalone(t *testing.T) {
+	defer os.RemoveAll("./standalone/app")
+	if err := goagen("./standalone", "app", "-d", "github.com/goadesign/goa/_integration_tests/standalone/design", "--standalone"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./standalone"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestRecover(t *testing.T) {
+	defer os.RemoveAll("./recover/app")
+	if err := goagen("./recover", "app", "-d", "github.com/goadesign/goa/_integration_tests/recover/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./recover"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestFlattenLinks(t *testing.T) {
+	defer os.RemoveAll("./flattenlinks/app")
+	if err := goagen("./flattenlinks", "app", "-d", "github.com/goadesign/goa/_integration_tests/flattenlinks/design", "--flatten-links"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./flattenlinks"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestLongPoll(t *testing.T) {
+	defer os.RemoveAll("./longpoll/app")
+	if err := goagen("./longpoll", "app", "-d", "github.com/goadesign/goa/_integration_tests/longpoll/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./longpoll"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestDatadog(t *testing.T) {
+	defer os.RemoveAll("./datadog/app")
+	if err := goagen("./datadog", "app", "-d", "github.com/goadesign/goa/_integration_tests/datadog/design", "--tracer=datadog"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./datadog"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestAWSLambda(t *testing.T) {
+	defer os.RemoveAll("./awslambda/lambda_handler.go")
+	defer os.RemoveAll("./awslambda/app")
+	for _, gen := range []string{"app", "aws_lambda"} {
+		if err := goagen("./awslambda", gen, "-d", "github.com/goadesign/goa/_integration_tests/awslambda/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./awslambda"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	defer os.RemoveAll("./mergepatch/app")
+	if err := goagen("./mergepatch", "app", "-d", "github.com/goadesign/goa/_integration_tests/mergepatch/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./mergepatch"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	defer os.RemoveAll("./cachecontrol/app")
+	if err := goagen("./cachecontrol", "app", "-d", "github.com/goadesign/goa/_integration_tests/cachecontrol/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./cachecontrol"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestContentType(t *testing.T) {
+	defer os.RemoveAll("./contenttype/app")
+	if err := goagen("./contenttype", "app", "-d", "github.com/goadesign/goa/_integration_tests/contenttype/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./contenttype"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestMultipartResponse(t *testing.T) {
+	defer os.RemoveAll("./multipartresponse/app")
+	if err := goagen("./multipartresponse", "app", "-d", "github.com/goadesign/goa/_integration_tests/multipartresponse/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./multipartresponse"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestCompression(t *testing.T) {
+	defer os.RemoveAll("./compression/app")
+	if err := goagen("./compression", "app", "-d", "github.com/goadesign/goa/_integration_tests/compression/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./compression"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	defer os.RemoveAll("./retryafter/app")
+	if err := goagen("./retryafter", "app", "-d", "github.com/goadesign/goa/_integration_tests/retryafter/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./retryafter"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestMultipartIngest(t *testing.T) {
+	defer os.RemoveAll("./multipartingest/app")
+	if err := goagen("./multipartingest", "app", "-d", "github.com/goadesign/goa/_integration_tests/multipartingest/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./multipartingest"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestKeyset(t *testing.T) {
+	defer os.RemoveAll("./keyset/sqlc")
+	if err := goagen("./keyset", "sqlc", "-d", "github.com/goadesign/goa/_integration_tests/keyset/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./keyset"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSQLMigration(t *testing.T) {
+	defer os.RemoveAll("./sqlmigration/migrations")
+	if err := goagen("./sqlmigration", "sqlmigration", "-d", "github.com/goadesign/goa/_integration_tests/sqlmigration/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./sqlmigration"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestE2E(t *testing.T) {
+	if os.Getenv("DOCKER_HOST") == "" {
+		t.Skip("DOCKER_HOST is not set")
+	}
+	defer os.RemoveAll("./e2e/main.go")
+	defer os.RemoveAll("./e2e/bottle.go")
+	defer os.RemoveAll("./e2e/health.go")
+	defer os.RemoveAll("./e2e/e2e_test.go")
+	defer os.RemoveAll("./e2e/app")
+	for _, gen := range []string{"app", "main", "e2e"} {
+		if err := goagen("./e2e", gen, "-d", "github.com/goadesign/goa/_integration_tests/e2e/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./e2e", "-tags", "e2e"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestRapid(t *testing.T) {
+	defer os.RemoveAll("./rapid/main.go")
+	defer os.RemoveAll("./rapid/bottle.go")
+	defer os.RemoveAll("./rapid/bottle_rapid_test.go")
+	defer os.RemoveAll("./rapid/app")
+	for _, gen := range []string{"app", "main", "rapid"} {
+		if err := goagen("./rapid", gen, "-d", "github.com/goadesign/goa/_integration_tests/rapid/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./rapid", "-rapid.checks=100"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	defer os.RemoveAll("./roundtrip/bottle_integration_test.go")
+	defer os.RemoveAll("./roundtrip/health_integration_test.go")
+	defer os.RemoveAll("./roundtrip/app")
+	for _, gen := range []string{"app", "integration_test"} {
+		if err := goagen("./roundtrip", gen, "-d", "github.com/goadesign/goa/_integration_tests/roundtrip/design"); err != nil {
+			t.Error(err.Error())
+		}
+	}
+	if err := gotest("./roundtrip"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	defer os.RemoveAll("./schema/app")
+	if err := goagen("./schema", "app", "-d", "github.com/goadesign/goa/_integration_tests/schema/design", "--schema-validate"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./schema"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestCellar(t *testing.T) {
 	if err := os.MkdirAll("./goa-cellar", 0755); err != nil {
 		t.Error(err.Error())
@@ -64,3 +705,23 @@ func gobuild(dir string) error {
 	}
 	return nil
 }
+
+func gotest(dir string, args ...string) error {
+	cmd := exec.Command("go", append([]string{"test", "."}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s\n%s", err.Error(), out)
+	}
+	return nil
+}
+
+func govet(dir string, args ...string) error {
+	cmd := exec.Command("go", append(append([]string{"vet"}, args...), ".")...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s\n%s", err.Error(), out)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API whose requests are authenticated with HS256 signed JSON Web Tokens")
+	Host("localhost:8080")
+	Scheme("http")
+	JWTAuth("cellar-2026", "HS256", map[string]DataType{
+		"sub":   String,
+		"admin": Boolean,
+		"level": Integer,
+	})
+})
+
+var _ = Resource("account", func() {
+	BasePath("/accounts")
+	Action("show", func() {
+		Routing(GET("/me"))
+		Response(OK, "text/plain")
+	})
+})
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/jwtauth/app"
+	"github.com/goadesign/goa/middleware"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+var key = []byte("cellar secret")
+
+// AccountController implements the account resource.
+type AccountController struct {
+	*goa.Controller
+}
+
+// Show runs the show action, it writes the claims read from the action context.
+func (c *AccountController) Show(ctx *app.ShowAccountContext) error {
+	return ctx.OK([]byte(fmt.Sprintf("%s %t %d", ctx.Sub, ctx.Admin, ctx.Level)))
+}
+
+// sign returns a HS256 token carrying the given claims signed with k.
+func sign(t *testing.T, kid string, k []byte, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(k)
+	if err != nil {
+		t.Fatalf("failed to sign token: %s", err)
+	}
+	return s
+}
+
+func TestJWTAuth(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, false))
+	service.Use(app.NewJWTMiddleware(key))
+	app.MountAccountController(service, &AccountController{Controller: service.NewController("account")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	valid := jwt.MapClaims{"sub": "alice", "admin": true, "level": 3}
+	cases := []struct {
+		Name   string
+		Token  string
+		Status int
+		Body   string
+	}{
+		{"valid token", sign(t, "cellar-2026", key, valid), http.StatusOK, "alice true 3"},
+		{"partial claims", sign(t, "cellar-2026", key, jwt.MapClaims{"sub": "bob"}), http.StatusOK, "bob false 0"},
+		{"missing token", "", http.StatusUnauthorized, ""},
+		{"invalid signature", sign(t, "cellar-2026", []byte("other secret"), valid), http.StatusUnauthorized, ""},
+		{"unknown key ID", sign(t, "cellar-2025", key, valid), http.StatusUnauthorized, ""},
+		{"invalid claim type", sign(t, "cellar-2026", key, jwt.MapClaims{"level": "high"}), http.StatusUnauthorized, ""},
+		{"malformed token", "not.a.token", http.StatusUnauthorized, ""},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", server.URL+"/accounts/me", nil)
+		if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %s", c.Name, err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != c.Status {
+			t.Errorf("%s: expected status %d, got %d", c.Name, c.Status, resp.StatusCode)
+		}
+		if c.Status == http.StatusOK && string(body) != c.Body {
+			t.Errorf("%s: expected body %q, got %q", c.Name, c.Body, string(body))
+		}
+	}
+}
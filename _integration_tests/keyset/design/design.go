@@ -0,0 +1,37 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API listing the bottles one keyset page at a time")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	DefaultMedia(BottleMedia)
+	Table("bottles")
+	Action("list", func() {
+		Routing(GET(""))
+		KeysetPage("id", "asc")
+		Response(OK, CollectionOf(BottleMedia))
+	})
+})
+
+// BottleMedia is the bottle resource media type.
+var BottleMedia = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Attributes(func() {
+		Attribute("id", Integer, "ID of bottle")
+		Attribute("name", String, "Name of bottle")
+		Required("id", "name")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+	})
+})
@@ -0,0 +1,97 @@
+package keyset_test
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// listQuery returns the generated ListBottle query.
+func listQuery(t *testing.T) string {
+	b, err := ioutil.ReadFile("sqlc/bottles.sql")
+	if err != nil {
+		t.Fatalf("failed to read queries: %s", err)
+	}
+	queries := string(b)
+	start := strings.Index(queries, "-- name: ListBottle :many\n")
+	if start < 0 {
+		t.Fatalf("ListBottle query not found in:\n%s", queries)
+	}
+	query := queries[start+len("-- name: ListBottle :many\n"):]
+	return query[:strings.Index(query, ";")+1]
+}
+
+func TestKeysetPage(t *testing.T) {
+	query := listQuery(t)
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE bottles (id INTEGER PRIMARY KEY, name TEXT NOT NULL)"); err != nil {
+		t.Fatalf("failed to create table: %s", err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("failed to start transaction: %s", err)
+	}
+	for i := 1; i <= 1000; i++ {
+		if _, err := tx.Exec("INSERT INTO bottles (id, name) VALUES (?, ?)", i, fmt.Sprintf("bottle %d", i)); err != nil {
+			t.Fatalf("failed to insert bottle %d: %s", i, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit bottles: %s", err)
+	}
+	args := []interface{}{sql.Named("last_key", 500), sql.Named("limit", 10)}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		t.Fatalf("failed to list bottles: %s", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatalf("failed to scan bottle: %s", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if len(ids) != 10 {
+		t.Fatalf("expected a page of 10 bottles, got %v", ids)
+	}
+	for i, id := range ids {
+		if id != 501+i {
+			t.Errorf("expected bottle %d at index %d, got %d", 501+i, i, id)
+		}
+	}
+
+	plan, err := db.Query("EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		t.Fatalf("failed to explain query: %s", err)
+	}
+	defer plan.Close()
+	var details []string
+	for plan.Next() {
+		var id, parent, notused int
+		var detail string
+		if err := plan.Scan(&id, &parent, &notused, &detail); err != nil {
+			t.Fatalf("failed to scan query plan: %s", err)
+		}
+		details = append(details, detail)
+	}
+	if len(details) == 0 || !strings.HasPrefix(details[0], "SEARCH bottles USING INTEGER PRIMARY KEY") {
+		t.Errorf("expected the query to search the primary key index, got plan %v", details)
+	}
+	for _, d := range details {
+		if strings.HasPrefix(d, "SCAN") {
+			t.Errorf("expected no table scan, got plan %v", details)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("events", func() {
+	Title("The events API")
+	Description("An API streaming events to long-poll clients")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("event", func() {
+	BasePath("/events")
+	Action("watch", func() {
+		Routing(GET(""))
+		LongPoll()
+		Response(OK)
+	})
+})
@@ -0,0 +1,78 @@
+package longpoll_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/longpoll/app"
+)
+
+// eventController streams the events received on its channel.
+type eventController struct {
+	*goa.Controller
+	events chan int
+}
+
+// Watch sends each event as a chunk and ends the stream once the channel is closed.
+func (c *eventController) Watch(ctx *app.WatchEventContext) error {
+	for e := range c.events {
+		if err := ctx.OKChunk(map[string]int{"event": e}); err != nil {
+			return err
+		}
+	}
+	return ctx.OKDone()
+}
+
+func TestLongPoll(t *testing.T) {
+	service := goa.New("events")
+	ctrl := &eventController{Controller: service.NewController("event"), events: make(chan int)}
+	app.MountEventController(service, ctrl)
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	// The goroutine only sends the next event once the client read the previous chunk so that
+	// the test fails if the chunks are not flushed as they are written.
+	received := make(chan struct{})
+	go func() {
+		defer close(ctrl.events)
+		for i := 1; i <= 3; i++ {
+			ctrl.events <- i
+			<-received
+		}
+	}()
+
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected ndjson content type, got %q", ct)
+	}
+	reader := bufio.NewReader(resp.Body)
+	for i := 1; i <= 3; i++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("failed to read chunk %d: %s", i, err)
+		}
+		var chunk map[string]int
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			t.Fatalf("invalid chunk %q: %s", line, err)
+		}
+		if chunk["event"] != i {
+			t.Errorf("expected event %d, got %q", i, line)
+		}
+		received <- struct{}{}
+	}
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("failed to read the done sentinel: %s", err)
+	}
+	if string(line) != "{\"done\":true}\n" {
+		t.Errorf("expected the done sentinel, got %q", line)
+	}
+}
@@ -0,0 +1,44 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("forest", func() {
+	Title("The forest API")
+	Description("An API limiting the nesting of the request payloads")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+// Node is a recursive type with two self references.
+var Node = Type("node", func() {
+	Attribute("value", Integer, "Node value")
+	Attribute("left", "node", "Left child")
+	Attribute("right", "node", "Right child")
+})
+
+var _ = Resource("tree", func() {
+	Action("create", func() {
+		Routing(POST("/trees"))
+		Description("create a tree")
+		Payload(func() {
+			Attribute("root", Node, "Tree root", func() {
+				MaxDepth(16)
+			})
+			Attribute("labels", HashOf(String, HashOf(String, String)), "Tree labels", func() {
+				MaxDepth(2)
+			})
+			Attribute("filter", func() {
+				MaxDepth(2)
+				Attribute("range", func() {
+					Attribute("bounds", func() {
+						Attribute("min", Integer)
+					})
+				})
+			})
+		})
+		Response(NoContent)
+	})
+})
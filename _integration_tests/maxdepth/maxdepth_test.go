@@ -0,0 +1,75 @@
+package maxdepth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/maxdepth/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// TreeController implements the tree resource.
+type TreeController struct {
+	*goa.Controller
+}
+
+// Create accepts the tree.
+func (c *TreeController) Create(ctx *app.CreateTreeContext) error {
+	return ctx.NoContent()
+}
+
+// tree returns the JSON representation of a tree of the given depth where each node has two
+// children.
+func tree(depth int) string {
+	if depth == 1 {
+		return `{"value":1}`
+	}
+	child := tree(depth - 1)
+	return `{"value":1,"left":` + child + `,"right":` + child + `}`
+}
+
+func TestMaxDepth(t *testing.T) {
+	service := goa.New("forest")
+	service.Use(middleware.ErrorHandler(service, true))
+	app.MountTreeController(service, &TreeController{Controller: service.NewController("tree")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := []struct {
+		name   string
+		body   string
+		status int
+		attr   string
+	}{
+		{"tree at max depth", `{"root":` + tree(16) + `}`, http.StatusNoContent, ""},
+		{"tree past max depth", `{"root":` + tree(17) + `}`, http.StatusBadRequest, "root"},
+		{"labels at max depth", `{"labels":{"a":{"b":"c"}}}`, http.StatusNoContent, ""},
+		{"filter at max depth", `{"filter":{"range":{}}}`, http.StatusNoContent, ""},
+		{"filter past max depth", `{"filter":{"range":{"bounds":{"min":1}}}}`, http.StatusBadRequest, "filter"},
+	}
+	for _, c := range cases {
+		resp, err := http.Post(server.URL+"/trees", "application/json", strings.NewReader(c.body))
+		if err != nil {
+			t.Fatalf("%s: request failed: %s", c.name, err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s: failed to read response: %s", c.name, err)
+		}
+		if resp.StatusCode != c.status {
+			t.Errorf("%s: expected status %d, got %d: %s", c.name, c.status, resp.StatusCode, body)
+			continue
+		}
+		if c.attr != "" && !strings.Contains(string(body), "exceeds the maximum nesting depth") {
+			t.Errorf("%s: expected a max depth error, got %s", c.name, body)
+		}
+		if c.attr != "" && !strings.Contains(string(body), c.attr) {
+			t.Errorf("%s: expected the error to mention %q, got %s", c.name, c.attr, body)
+		}
+	}
+}
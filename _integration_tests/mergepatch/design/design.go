@@ -0,0 +1,35 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("bottles", func() {
+	Title("The bottles API")
+	Description("An API updating bottles with JSON merge patch payloads")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var BottlePayload = Type("BottlePayload", func() {
+	Attribute("name", String)
+	Attribute("vintage", Integer, func() {
+		Minimum(1900)
+	})
+	Attribute("color", String)
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("update", func() {
+		Routing(PATCH("/:id"))
+		Params(func() {
+			Param("id", Integer)
+		})
+		Payload(BottlePayload)
+		JSONMergePatch()
+		Response(OK, "application/json")
+		Response(BadRequest, ErrorMedia)
+	})
+})
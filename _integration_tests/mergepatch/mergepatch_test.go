@@ -0,0 +1,79 @@
+package mergepatch_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/mergepatch/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// bottleController applies the merge patch payloads to a single stored bottle.
+type bottleController struct {
+	*goa.Controller
+	bottle *app.BottlePayload
+}
+
+// Update merges the request payload into the stored bottle.
+func (c *bottleController) Update(ctx *app.UpdateBottleContext) error {
+	bottle, err := c.bottle.MergePatch(ctx.Payload)
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+	c.bottle = bottle
+	return ctx.OK(nil)
+}
+
+func TestMergePatch(t *testing.T) {
+	name, vintage, color := "Number 8", 2012, "red"
+	service := goa.New("bottles")
+	service.Use(middleware.ErrorHandler(service, false))
+	ctrl := &bottleController{
+		Controller: service.NewController("bottle"),
+		bottle:     &app.BottlePayload{Name: &name, Vintage: &vintage, Color: &color},
+	}
+	app.MountBottleController(service, ctrl)
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	patch := func(body string) int {
+		req, err := http.NewRequest("PATCH", server.URL+"/bottles/1", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := patch(`{"vintage":2015,"color":null}`); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if ctrl.bottle.Name == nil || *ctrl.bottle.Name != name {
+		t.Errorf("expected the omitted name to be left unchanged, got %v", ctrl.bottle.Name)
+	}
+	if ctrl.bottle.Vintage == nil || *ctrl.bottle.Vintage != 2015 {
+		t.Errorf("expected the vintage to be replaced, got %v", ctrl.bottle.Vintage)
+	}
+	if ctrl.bottle.Color != nil {
+		t.Errorf("expected the null color to be removed, got %q", *ctrl.bottle.Color)
+	}
+
+	if code := patch(`{"vintage":1800}`); code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid vintage, got %d", code)
+	}
+	if code := patch(`[1, 2]`); code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a non object patch, got %d", code)
+	}
+	if b, _ := json.Marshal(ctrl.bottle); string(b) != `{"name":"Number 8","vintage":2015}` {
+		t.Errorf("expected the invalid patches to be rejected, got %s", b)
+	}
+}
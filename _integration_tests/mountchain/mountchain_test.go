@@ -0,0 +1,128 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/mountchain/app"
+	"golang.org/x/net/context"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID != 1 {
+		return ctx.NotFound()
+	}
+	return ctx.OK([]byte(strconv.Itoa(ctx.ID)))
+}
+
+func TestMountChain(t *testing.T) {
+	var count int64
+	counter := func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			atomic.AddInt64(&count, 1)
+			h.ServeHTTP(rw, req)
+		})
+	}
+	tagger := func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("X-Chain", strconv.FormatInt(atomic.LoadInt64(&count), 10))
+			h.ServeHTTP(rw, req)
+		})
+	}
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")}, counter, tagger)
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := []struct {
+		path   string
+		status int
+	}{
+		{"/bottles/1", http.StatusOK},
+		{"/bottles/2", http.StatusNotFound},
+		{"/bottles/1", http.StatusOK},
+	}
+	for i, c := range cases {
+		resp, err := http.Get(server.URL + c.path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %s", c.path, err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Errorf("GET %s: expected status %d, got %d", c.path, c.status, resp.StatusCode)
+		}
+		if c.status == http.StatusOK && string(body) != "1" {
+			t.Errorf("GET %s: expected body %q, got %q", c.path, "1", body)
+		}
+		if got := atomic.LoadInt64(&count); got != int64(i+1) {
+			t.Errorf("GET %s: expected counter %d, got %d", c.path, i+1, got)
+		}
+		// The counter runs before the tagger: the first middleware is the outermost.
+		if tag := resp.Header.Get("X-Chain"); tag != strconv.Itoa(i+1) {
+			t.Errorf("GET %s: expected X-Chain header %d, got %q", c.path, i+1, tag)
+		}
+	}
+}
+
+func TestMountNoChain(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/bottles/1")
+	if err != nil {
+		t.Fatalf("GET failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestMountService(t *testing.T) {
+	service := goa.New("cellar")
+	var fromChain, fromMiddleware *goa.Service
+	recorder := func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			fromChain = goa.ServiceFromContext(req.Context())
+			h.ServeHTTP(rw, req)
+		})
+	}
+	service.Use(func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			fromMiddleware = goa.ServiceFromContext(ctx)
+			return h(ctx, rw, req)
+		}
+	})
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")}, recorder)
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/bottles/1")
+	if err != nil {
+		t.Fatalf("GET failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if fromChain != service {
+		t.Errorf("net/http middleware: expected the mounted service, got %v", fromChain)
+	}
+	if fromMiddleware != service {
+		t.Errorf("goa middleware: expected the mounted service, got %v", fromMiddleware)
+	}
+}
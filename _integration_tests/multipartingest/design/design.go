@@ -0,0 +1,45 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API ingesting batches of bottles sent as multipart/mixed request bodies")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("ingest", func() {
+		Routing(POST(""))
+		MultipartIngest(Bottle)
+		Response(OK, func() {
+			Media(BatchMedia)
+		})
+		Response(BadRequest, ErrorMedia)
+	})
+})
+
+// Bottle is the type of the ingested documents.
+var Bottle = Type("bottle", func() {
+	Attribute("name", String, "Name of bottle")
+	Attribute("vintage", Integer, "Vintage of bottle")
+	Required("name")
+})
+
+// BatchMedia describes the result of an ingest.
+var BatchMedia = MediaType("application/vnd.goa.example.batch+json", func() {
+	Attributes(func() {
+		Attribute("names", ArrayOf(String), "Names of the ingested bottles")
+		Attribute("vintages", ArrayOf(Integer), "Vintages of the ingested bottles")
+		Required("names", "vintages")
+	})
+	View("default", func() {
+		Attribute("names")
+		Attribute("vintages")
+	})
+})
@@ -0,0 +1,105 @@
+package multipartingest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"reflect"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/multipartingest/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// bottleController responds with the names and vintages of the ingested bottles.
+type bottleController struct {
+	*goa.Controller
+}
+
+// Ingest lists the ingested bottles.
+func (c *bottleController) Ingest(ctx *app.IngestBottleContext) error {
+	res := &app.GoaExampleBatch{Names: []string{}, Vintages: []int{}}
+	for _, b := range ctx.Payload.Parts {
+		res.Names = append(res.Names, b.Name)
+		vintage := 0
+		if b.Vintage != nil {
+			vintage = *b.Vintage
+		}
+		res.Vintages = append(res.Vintages, vintage)
+	}
+	return ctx.OK(res)
+}
+
+// ingest posts a multipart/mixed body made of the given JSON parts.
+func ingest(t *testing.T, url string, parts ...string) *http.Response {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, p := range parts {
+		pw, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json"}})
+		if err != nil {
+			t.Fatalf("failed to create part: %s", err)
+		}
+		pw.Write([]byte(p))
+	}
+	w.Close()
+	resp, err := http.Post(url, "multipart/mixed; boundary="+w.Boundary(), &body)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return resp
+}
+
+func TestMultipartIngest(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, false))
+	app.MountBottleController(service, &bottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	resp := ingest(t, server.URL+"/bottles",
+		`{"name":"Number 8","vintage":2012}`,
+		`{"name":"Number 9","vintage":2013}`,
+		`{"name":"Number 10"}`,
+	)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	var batch app.GoaExampleBatch
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		t.Fatalf("failed to decode response: %s", err)
+	}
+	if len(batch.Names) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(batch.Names))
+	}
+	if expected := []string{"Number 8", "Number 9", "Number 10"}; !reflect.DeepEqual(batch.Names, expected) {
+		t.Errorf("expected names %v, got %v", expected, batch.Names)
+	}
+	if expected := []int{2012, 2013, 0}; !reflect.DeepEqual(batch.Vintages, expected) {
+		t.Errorf("expected vintages %v, got %v", expected, batch.Vintages)
+	}
+}
+
+func TestMultipartIngestInvalidPart(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, false))
+	app.MountBottleController(service, &bottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := map[string][]string{
+		"malformed part": {`{"name":"Number 8"}`, `not json`},
+		"invalid part":   {`{"name":"Number 8"}`, `{"vintage":2012}`},
+	}
+	for name, parts := range cases {
+		resp := ingest(t, server.URL+"/bottles", parts...)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", name, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API exporting reports made of a JSON part and a PNG chart")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("report", func() {
+	BasePath("/reports")
+	Action("export", func() {
+		Routing(GET("/export"))
+		Response(OK, func() {
+			MultipartResponse([]*PartDefinition{
+				{Name: "report", ContentType: "application/json", Type: ReportMedia},
+				{Name: "chart", ContentType: "image/png", Type: BinaryType},
+			})
+		})
+	})
+})
+
+// ReportMedia is the media type of the JSON part of the export.
+var ReportMedia = MediaType("application/vnd.goa.example.report+json", func() {
+	Attributes(func() {
+		Attribute("bottles", Integer, "Number of bottles in the cellar")
+		Required("bottles")
+	})
+	View("default", func() {
+		Attribute("bottles")
+	})
+})
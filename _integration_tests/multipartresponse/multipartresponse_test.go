@@ -0,0 +1,95 @@
+package multipartresponse_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/multipartresponse/app"
+)
+
+// report is the content of the JSON part.
+const report = `{"bottles":42}`
+
+// reportController exports the report and its chart.
+type reportController struct {
+	*goa.Controller
+	chart []byte
+}
+
+// Export writes the JSON and PNG parts.
+func (c *reportController) Export(ctx *app.ExportReportContext) error {
+	return ctx.OKMultipart(map[string]io.Reader{
+		"report": bytes.NewBufferString(report),
+		"chart":  bytes.NewReader(c.chart),
+	})
+}
+
+func TestMultipartResponse(t *testing.T) {
+	var chart bytes.Buffer
+	if err := png.Encode(&chart, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("failed to encode chart: %s", err)
+	}
+	service := goa.New("cellar")
+	app.MountReportController(service, &reportController{
+		Controller: service.NewController("report"),
+		chart:      chart.Bytes(),
+	})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/reports/export")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("invalid content type: %s", err)
+	}
+	if mediaType != "multipart/form-data" {
+		t.Fatalf("expected multipart/form-data content type, got %s", mediaType)
+	}
+
+	expected := []struct {
+		Name, ContentType string
+		Body              []byte
+	}{
+		{"report", "application/json", []byte(report)},
+		{"chart", "image/png", chart.Bytes()},
+	}
+	mr := multipart.NewReader(resp.Body, params["boundary"])
+	for _, e := range expected {
+		p, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("failed to read part %s: %s", e.Name, err)
+		}
+		if p.FormName() != e.Name {
+			t.Errorf("expected part %s, got %s", e.Name, p.FormName())
+		}
+		if ct := p.Header.Get("Content-Type"); ct != e.ContentType {
+			t.Errorf("%s: expected content type %s, got %s", e.Name, e.ContentType, ct)
+		}
+		body, err := ioutil.ReadAll(p)
+		if err != nil {
+			t.Fatalf("failed to read part %s body: %s", e.Name, err)
+		}
+		if !bytes.Equal(body, e.Body) {
+			t.Errorf("%s: expected body %q, got %q", e.Name, e.Body, body)
+		}
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("expected two parts, got more (err: %v)", err)
+	}
+}
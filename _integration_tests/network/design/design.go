@@ -0,0 +1,47 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("network", func() {
+	Title("The network API")
+	Description("An API using IP and CIDR attributes")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+// Route is the route media type.
+var Route = MediaType("application/vnd.goa.example.route+json", func() {
+	Description("A network route")
+	Attributes(func() {
+		Attribute("network", CIDR, "Destination network")
+		Attribute("gateway", IP, "Gateway address")
+		Attribute("zone", CIDR, "Network zone")
+		Required("network", "gateway", "zone")
+	})
+	View("default", func() {
+		Attribute("network")
+		Attribute("gateway")
+		Attribute("zone")
+	})
+})
+
+var _ = Resource("route", func() {
+	BasePath("/routes")
+	Action("create", func() {
+		Routing(POST(""))
+		Description("create a route")
+		Params(func() {
+			Param("zone", CIDR, "Network zone")
+			Required("zone")
+		})
+		Payload(func() {
+			Attribute("network", CIDR, "Destination network")
+			Attribute("gateway", IP, "Gateway address")
+			Required("network", "gateway")
+		})
+		Response(OK, Route)
+	})
+})
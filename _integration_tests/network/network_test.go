@@ -0,0 +1,75 @@
+package network
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/network/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// RouteController implements the route resource.
+type RouteController struct {
+	*goa.Controller
+}
+
+// Create runs the create action.
+func (c *RouteController) Create(ctx *app.CreateRouteContext) error {
+	return ctx.OK(&app.GoaExampleRoute{
+		Network: ctx.Payload.Network,
+		Gateway: ctx.Payload.Gateway,
+		Zone:    ctx.Zone,
+	})
+}
+
+func TestCIDRRoundTrip(t *testing.T) {
+	service := goa.New("network")
+	service.Use(middleware.ErrorHandler(service, true))
+	app.MountRouteController(service, &RouteController{Controller: service.NewController("route")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	body := `{"network":"2001:db8::/32","gateway":"192.0.2.1"}`
+	u := server.URL + "/routes?zone=" + url.QueryEscape("10.1.0.0/16")
+	resp, err := http.Post(u, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	var route map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&route); err != nil {
+		t.Fatalf("failed to decode response: %s", err)
+	}
+	expected := map[string]string{"network": "2001:db8::/32", "gateway": "192.0.2.1", "zone": "10.1.0.0/16"}
+	for k, v := range expected {
+		if route[k] != v {
+			t.Errorf("expected %s %q, got %q", k, v, route[k])
+		}
+	}
+
+	resp, err = http.Post(u, "application/json", strings.NewReader(`{"network":"192.0.2.1","gateway":"192.0.2.1"}`))
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid network, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(server.URL+"/routes?zone=nope", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid zone, got %d", resp.StatusCode)
+	}
+}
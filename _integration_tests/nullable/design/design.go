@@ -0,0 +1,47 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API with nullable attributes")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+// Review is the payload of the review action.
+var Review = Type("Review", func() {
+	Attribute("comment", String)
+	Attribute("score", Integer, func() {
+		Nullable()
+	})
+	Required("comment", "score")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("create", func() {
+		Routing(POST(""))
+		Description("create a bottle")
+		Payload(func() {
+			Attribute("name", String)
+			Attribute("rating", Integer, func() {
+				Nullable()
+			})
+			Required("name", "rating")
+		})
+		Response(NoContent)
+	})
+	Action("review", func() {
+		Routing(POST("/:id/review"))
+		Description("review a bottle")
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Payload(Review)
+		Response(NoContent)
+	})
+})
@@ -0,0 +1,57 @@
+package nullable
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/nullable/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Create runs the create action.
+func (c *BottleController) Create(ctx *app.CreateBottleContext) error {
+	return ctx.NoContent()
+}
+
+// Review runs the review action.
+func (c *BottleController) Review(ctx *app.ReviewBottleContext) error {
+	return ctx.NoContent()
+}
+
+func TestRequiredNullable(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, true))
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := []struct {
+		name, path, body string
+		status           int
+	}{
+		{"payload null", "/bottles", `{"name":"merlot","rating":null}`, http.StatusNoContent},
+		{"payload value", "/bottles", `{"name":"merlot","rating":4}`, http.StatusNoContent},
+		{"payload missing", "/bottles", `{"name":"merlot"}`, http.StatusBadRequest},
+		{"type null", "/bottles/1/review", `{"comment":"great","score":null}`, http.StatusNoContent},
+		{"type value", "/bottles/1/review", `{"comment":"great","score":5}`, http.StatusNoContent},
+		{"type missing", "/bottles/1/review", `{"comment":"great"}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		resp, err := http.Post(server.URL+c.path, "application/json", strings.NewReader(c.body))
+		if err != nil {
+			t.Fatalf("%s: request failed: %s", c.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Errorf("%s: expected status %d, got %d", c.name, c.status, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// contract is the pact published by the cellar-cli consumer, the interactions use the provider
+// states and descriptions generated by goagen pact.
+const contract = `{
+  "consumer": {"name": "cellar-cli"},
+  "provider": {"name": "cellar"},
+  "interactions": [
+    {
+      "description": "show bottle responds with OK",
+      "providerState": "bottle show",
+      "request": {"method": "GET", "path": "/bottles/1"},
+      "response": {
+        "status": 200,
+        "headers": {"Content-Type": "application/vnd.goa.example.bottle+json"},
+        "body": {"id": 1, "name": "Number 8"}
+      }
+    },
+    {
+      "description": "show bottle responds with NotFound",
+      "providerState": "bottle show",
+      "request": {"method": "GET", "path": "/bottles/2"},
+      "response": {"status": 404}
+    }
+  ],
+  "metadata": {"pactSpecification": {"version": "2.0.0"}}
+}`
+
+// TestMain starts a mock Pact broker serving the cellar-cli contract and points the generated
+// provider tests to it.
+func TestMain(m *testing.M) {
+	mux := http.NewServeMux()
+	broker := httptest.NewServer(mux)
+	hal := func(rw http.ResponseWriter, body string) {
+		rw.Header().Set("Content-Type", "application/hal+json")
+		fmt.Fprint(rw, body)
+	}
+	mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/" {
+			http.NotFound(rw, req)
+			return
+		}
+		hal(rw, fmt.Sprintf(`{"_links": {"pb:latest-provider-pacts": {"href": "%s/pacts/provider/{provider}/latest", "templated": true}}}`, broker.URL))
+	})
+	mux.HandleFunc("/pacts/provider/cellar/latest", func(rw http.ResponseWriter, req *http.Request) {
+		link := fmt.Sprintf(`{"href": "%s/pacts/provider/cellar/consumer/cellar-cli/latest", "name": "cellar-cli"}`, broker.URL)
+		hal(rw, fmt.Sprintf(`{"_links": {"pb:pacts": [%s], "pacts": [%s]}}`, link, link))
+	})
+	mux.HandleFunc("/pacts/provider/cellar/consumer/cellar-cli/latest", func(rw http.ResponseWriter, req *http.Request) {
+		hal(rw, contract)
+	})
+	os.Setenv("PACT_BROKER_URL", broker.URL)
+	code := m.Run()
+	broker.Close()
+	os.Exit(code)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/pact/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// NewBottleController creates a bottle controller.
+func NewBottleController(service *goa.Service) *BottleController {
+	return &BottleController{Controller: service.NewController("BottleController")}
+}
+
+// Show returns the bottle with ID 1 and NotFound for any other ID.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID != 1 {
+		return ctx.NotFound()
+	}
+	return ctx.OK(&app.GoaExampleBottle{ID: 1, Name: "Number 8"})
+}
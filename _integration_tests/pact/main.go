@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/pact/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+func main() {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, true))
+	app.MountBottleController(service, NewBottleController(service))
+	if err := service.ListenAndServe(":8080"); err != nil {
+		service.LogError("startup", "err", err)
+	}
+}
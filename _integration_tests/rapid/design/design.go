@@ -0,0 +1,39 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API whose context constructors are tested with rapid")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	Action("list", func() {
+		Routing(GET("/bottles"))
+		Description("list lists the bottles matching the query")
+		Params(func() {
+			Param("sort", String, "Sort order", func() {
+				Enum("asc", "desc")
+			})
+			Param("limit", Integer, "Maximum number of bottles", func() {
+				Minimum(1)
+				Maximum(100)
+			})
+			Param("name", String, "Name prefix", func() {
+				MinLength(2)
+			})
+			Param("years", ArrayOf(Integer), "Vintages", func() {
+				MaxLength(3)
+			})
+			Param("sparkling", Boolean, "Sparkling wines only")
+			Param("since", DateTime, "Bottles added after")
+			Required("sort")
+		})
+		Response(NoContent)
+	})
+})
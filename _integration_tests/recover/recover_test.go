@@ -0,0 +1,59 @@
+package recover_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/recover/app"
+)
+
+// bottleController panics when showing the bottle with ID 0.
+type bottleController struct {
+	*goa.Controller
+}
+
+// Show panics if the ID is 0 and writes the ID otherwise.
+func (c *bottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID == 0 {
+		panic("boom")
+	}
+	return ctx.OK([]byte("bottle"))
+}
+
+func TestRecover(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &bottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/bottles/0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("expected problem+json content type, got %q", ct)
+	}
+	var problem map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed to decode problem: %s", err)
+	}
+	if problem["status"] != float64(500) || problem["id"] == "" {
+		t.Errorf("unexpected problem %v", problem)
+	}
+
+	resp, err = http.Get(server.URL + "/bottles/1")
+	if err != nil {
+		t.Fatalf("the server stopped after the panic: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200 after the panic, got %d", resp.StatusCode)
+	}
+}
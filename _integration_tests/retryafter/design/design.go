@@ -0,0 +1,29 @@
+package design
+
+import (
+	"time"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API asking clients to retry later with a Retry-After header")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("list", func() {
+		Routing(GET(""))
+		Params(func() {
+			Param("at", DateTime, "Date after which the client may retry")
+		})
+		Response(OK)
+		Response(ServiceUnavailable, func() {
+			RetryAfter(30 * time.Second)
+		})
+	})
+})
@@ -0,0 +1,55 @@
+package retryafter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/retryafter/app"
+)
+
+// bottleController always asks clients to retry later.
+type bottleController struct {
+	*goa.Controller
+}
+
+// List sends the Retry-After header as an HTTP date if the at parameter is set, as the default
+// delay otherwise.
+func (c *bottleController) List(ctx *app.ListBottleContext) error {
+	if ctx.At != nil {
+		return ctx.ServiceUnavailableAt(*ctx.At)
+	}
+	return ctx.ServiceUnavailable(0)
+}
+
+func TestRetryAfter(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &bottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	at := time.Date(2026, time.October, 15, 13, 30, 0, 0, time.FixedZone("CEST", 2*3600))
+	cases := map[string]struct {
+		Query    string
+		Expected string
+	}{
+		"delay-seconds": {"", "30"},
+		"HTTP-date":     {"?at=" + url.QueryEscape(at.Format(time.RFC3339)), "Thu, 15 Oct 2026 11:30:00 GMT"},
+	}
+	for name, c := range cases {
+		resp, err := http.Get(server.URL + "/bottles" + c.Query)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("%s: expected status 503, got %d", name, resp.StatusCode)
+		}
+		if got := resp.Header.Get("Retry-After"); got != c.Expected {
+			t.Errorf("%s: expected Retry-After %q, got %q", name, c.Expected, got)
+		}
+	}
+}
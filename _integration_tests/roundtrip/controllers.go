@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/roundtrip/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// NewBottleController creates a bottle controller.
+func NewBottleController(service *goa.Service) *BottleController {
+	return &BottleController{Controller: service.NewController("BottleController")}
+}
+
+// Show returns the bottle with the given ID.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	return ctx.OK(&app.GoaExampleBottle{ID: ctx.ID, Name: "Number 8"})
+}
+
+// List returns the bottles of the given vintage.
+func (c *BottleController) List(ctx *app.ListBottleContext) error {
+	return ctx.OK(app.GoaExampleBottleCollection{{ID: 1, Name: "Number 8"}})
+}
+
+// Create creates a bottle.
+func (c *BottleController) Create(ctx *app.CreateBottleContext) error {
+	return ctx.Created()
+}
+
+// HealthController implements the health resource.
+type HealthController struct {
+	*goa.Controller
+}
+
+// NewHealthController creates a health controller.
+func NewHealthController(service *goa.Service) *HealthController {
+	return &HealthController{Controller: service.NewController("HealthController")}
+}
+
+// Check responds with NoContent.
+func (c *HealthController) Check(ctx *app.CheckHealthContext) error {
+	return ctx.NoContent()
+}
@@ -0,0 +1,71 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API exercised by the generated integration tests")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	DefaultMedia(BottleMedia)
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "ID of bottle", func() {
+				Enum(1)
+			})
+		})
+		Response(OK)
+		Response(NotFound)
+	})
+	Action("list", func() {
+		Routing(GET(""))
+		Params(func() {
+			Param("vintage", Integer, "Vintage of bottles", func() {
+				Minimum(1900)
+				Maximum(2100)
+			})
+			Required("vintage")
+		})
+		Response(OK, CollectionOf(BottleMedia))
+	})
+	Action("create", func() {
+		Routing(POST(""))
+		Payload(func() {
+			Attribute("name", String, "Name of bottle", func() {
+				MinLength(1)
+				Example("Number 8")
+			})
+			Required("name")
+		})
+		Response(Created)
+		Response(BadRequest, ErrorMedia)
+	})
+})
+
+var _ = Resource("health", func() {
+	Action("check", func() {
+		Routing(GET("/health"))
+		Response(NoContent)
+	})
+})
+
+// BottleMedia is the bottle resource media type.
+var BottleMedia = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Attributes(func() {
+		Attribute("id", Integer, "ID of bottle")
+		Attribute("name", String, "Name of bottle")
+		Required("id", "name")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+	})
+})
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/roundtrip/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+func main() {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, true))
+	service.Use(middleware.Recover())
+	app.MountBottleController(service, NewBottleController(service))
+	app.MountHealthController(service, NewHealthController(service))
+	if err := service.ListenAndServe(":8080"); err != nil {
+		service.LogError("startup", "err", err)
+	}
+}
@@ -0,0 +1,29 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API validating the request bodies against the payload JSON schemas")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var BottlePayload = Type("BottlePayload", func() {
+	Description("BottlePayload is the type used to create bottles")
+	Attribute("name", String, "Name of bottle")
+	Attribute("vintage", Integer, "Vintage of bottle")
+	Required("name")
+})
+
+var _ = Resource("bottle", func() {
+	Action("create", func() {
+		Routing(POST("/bottles"))
+		Description("create records a new bottle")
+		Payload(BottlePayload)
+		Response(Created)
+	})
+})
@@ -0,0 +1,50 @@
+package schema_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/schema/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Create runs the create action.
+func (c *BottleController) Create(ctx *app.CreateBottleContext) error {
+	return ctx.Created()
+}
+
+func TestWrongFieldType(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, true))
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/bottles", "application/json", strings.NewReader(`{"name":42,"vintage":2010}`))
+	if err != nil {
+		t.Fatalf("failed to send request: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 400 {
+		t.Errorf("invalid status, expected 400 got %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %s", err)
+	}
+	if !strings.Contains(string(body), "name") {
+		t.Errorf("error does not reference the field name: %s", body)
+	}
+	if !strings.Contains(string(body), "Expected: string") {
+		t.Errorf("error does not reference the expected type: %s", body)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/shutdown/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+	started   chan struct{}
+	completed int32
+}
+
+// Show runs the show action, it takes long enough for the shutdown to start while it runs.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	close(c.started)
+	time.Sleep(500 * time.Millisecond)
+	err := ctx.OK([]byte("Number 8"))
+	atomic.StoreInt32(&c.completed, 1)
+	return err
+}
+
+func TestRunServer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	service := goa.New("cellar")
+	ctrl := &BottleController{Controller: service.NewController("bottle"), started: make(chan struct{})}
+	app.MountBottleController(service, ctrl)
+
+	done := make(chan error, 1)
+	go func() { done <- RunServer(service, addr, WithDrainTimeout(5*time.Second)) }()
+	for i := 0; ; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if i == 100 {
+			t.Fatalf("server did not start: %s", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	resc := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/bottles/1")
+		if err != nil {
+			resc <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		resc <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+
+	select {
+	case <-ctrl.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request did not reach the controller")
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("RunServer returned an error: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunServer did not return")
+	}
+	if atomic.LoadInt32(&ctrl.completed) != 1 {
+		t.Error("RunServer returned before the in-flight request completed")
+	}
+	res := <-resc
+	if res.err != nil {
+		t.Fatalf("in-flight request failed: %s", res.err)
+	}
+	if res.status != http.StatusOK || res.body != "Number 8" {
+		t.Errorf("expected 200 \"Number 8\", got %d %q", res.status, res.body)
+	}
+	if _, err := net.Dial("tcp", addr); err == nil {
+		t.Error("server still accepts connections after RunServer returned")
+	}
+}
@@ -0,0 +1,41 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API whose bottles are stored in a table created by the generated migrations")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	DefaultMedia(BottleMedia)
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "ID of bottle")
+		})
+		Response(OK)
+	})
+})
+
+// BottleMedia is the bottle resource media type.
+var BottleMedia = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Table("bottles")
+	Attributes(func() {
+		Attribute("id", Integer, "ID of bottle")
+		Attribute("name", String, "Name of bottle")
+		Attribute("rating", Number, "Rating of bottle")
+		Attribute("created_at", DateTime, "Date of creation")
+		Required("id", "name")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+	})
+})
@@ -0,0 +1,62 @@
+package sqlmigration_test
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// migration returns the content of the generated migration with the given suffix.
+func migration(t *testing.T, suffix string) string {
+	files, err := filepath.Glob(filepath.Join("migrations", "*_create_bottles"+suffix))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one %s migration, got %v (%v)", suffix, files, err)
+	}
+	b, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("failed to read migration: %s", err)
+	}
+	return string(b)
+}
+
+// tableExists returns true if the database contains the given table.
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	var count int
+	err := db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count)
+	if err != nil {
+		t.Fatalf("failed to look up table: %s", err)
+	}
+	return count == 1
+}
+
+func TestMigrations(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(migration(t, ".up.sql")); err != nil {
+		t.Fatalf("failed to run up migration: %s", err)
+	}
+	if !tableExists(t, db, "bottles") {
+		t.Fatal("up migration did not create the bottles table")
+	}
+	if _, err := db.Exec("INSERT INTO bottles (id, name, rating, created_at) VALUES (1, 'bottle', 4.5, '2017-01-01T00:00:00Z')"); err != nil {
+		t.Errorf("failed to insert bottle: %s", err)
+	}
+	if _, err := db.Exec("INSERT INTO bottles (id) VALUES (2)"); err == nil {
+		t.Error("expected insert of bottle with no name to fail")
+	}
+
+	if _, err := db.Exec(migration(t, ".down.sql")); err != nil {
+		t.Fatalf("failed to run down migration: %s", err)
+	}
+	if tableExists(t, db, "bottles") {
+		t.Error("down migration did not drop the bottles table")
+	}
+}
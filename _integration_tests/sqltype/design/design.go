@@ -0,0 +1,31 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("addresses", func() {
+	Title("The addresses API")
+	Description("An API storing addresses in a SQL database")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var Address = Type("Address", func() {
+	Description("Address stored as JSON in a SQL column")
+	SQLType("json")
+	Attribute("street", String, "Street name and number")
+	Attribute("city", String, "City name")
+	Attribute("zip", Integer, "ZIP code")
+	Required("city")
+})
+
+var _ = Resource("address", func() {
+	Action("create", func() {
+		Routing(POST("/addresses"))
+		Description("create stores an address")
+		Payload(Address)
+		Response(Created)
+	})
+})
@@ -0,0 +1,41 @@
+package sqltype_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/goadesign/goa/_integration_tests/sqltype/app"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestRoundTrip(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE addresses (id INTEGER PRIMARY KEY, address JSON)"); err != nil {
+		t.Fatalf("failed to create table: %s", err)
+	}
+
+	street := "1 Infinite Loop"
+	zip := 95014
+	address := app.Address{Street: &street, City: "Cupertino", Zip: &zip}
+	if _, err := db.Exec("INSERT INTO addresses (id, address) VALUES (1, ?)", address); err != nil {
+		t.Fatalf("failed to insert address: %s", err)
+	}
+
+	stmt, err := db.Prepare("SELECT address FROM addresses WHERE id = ?")
+	if err != nil {
+		t.Fatalf("failed to prepare statement: %s", err)
+	}
+	defer stmt.Close()
+	var actual app.Address
+	if err := stmt.QueryRow(1).Scan(&actual); err != nil {
+		t.Fatalf("failed to query address: %s", err)
+	}
+	if !reflect.DeepEqual(actual, address) {
+		t.Errorf("invalid address, expected %+v got %+v", address, actual)
+	}
+}
@@ -0,0 +1,37 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API whose controllers are mounted on a net/http server")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var BottleMedia = MediaType("application/vnd.goa.bottle+json", func() {
+	Attributes(func() {
+		Attribute("id", Integer, "Bottle ID")
+		Attribute("name", String, "Bottle name")
+		Required("id", "name")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+	})
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/api/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(OK, BottleMedia)
+		Response(NotFound)
+	})
+})
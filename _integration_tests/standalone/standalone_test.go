@@ -0,0 +1,69 @@
+package standalone_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/standalone/app"
+)
+
+// bottleController implements the bottle resource actions.
+type bottleController struct {
+	*goa.Controller
+}
+
+// Show returns the bottle with ID 1.
+func (c *bottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID != 1 {
+		return ctx.NotFound()
+	}
+	return ctx.OK(&app.GoaBottle{ID: 1, Name: "Chateau Margaux"})
+}
+
+func TestStandaloneService(t *testing.T) {
+	service := goa.New("cellar")
+	ctrl := &bottleController{Controller: service.NewController("bottle")}
+	mux := http.NewServeMux()
+	mux.Handle("/api/", app.NewBottleService(service, ctrl))
+	mux.HandleFunc("/health", func(rw http.ResponseWriter, _ *http.Request) { rw.Write([]byte("ok")) })
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/bottles/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(body), `"name":"Chateau Margaux"`) {
+		t.Errorf("unexpected body %s", body)
+	}
+
+	resp, err = http.Get(server.URL + "/api/bottles/2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "/health")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200 for the vanilla handler, got %d", resp.StatusCode)
+	}
+}
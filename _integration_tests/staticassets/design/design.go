@@ -0,0 +1,16 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API serving its single page application alongside the REST endpoints")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("ui", func() {
+	StaticAssets("/ui", "public")
+})
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/staticassets/app"
+)
+
+// UIController implements the ui resource.
+type UIController struct {
+	*goa.Controller
+}
+
+func TestStaticAssets(t *testing.T) {
+	// The design serves the "public" directory relative to the working directory.
+	dir, err := ioutil.TempDir("", "staticassets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, "public"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	const index = "<html><body>Cellar</body></html>"
+	if err := ioutil.WriteFile(filepath.Join(dir, "public", "index.html"), []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	service := goa.New("cellar")
+	app.MountUIController(service, &UIController{Controller: service.NewController("ui")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	// http.FileServer redirects the requests for index.html files to their directory.
+	resp, err := http.Get(server.URL + "/ui/")
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if string(body) != index {
+		t.Errorf("expected body %q, got %q", index, string(body))
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("expected Cache-Control header %q, got %q", "no-cache", cc)
+	}
+
+	resp, err = http.Get(server.URL + "/ui/missing.js")
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing file: expected status 404, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,33 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("stdmux", func() {
+	Title("The stdmux API")
+	Description("An API served with the standard library router")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("home", func() {
+	Action("show", func() {
+		Routing(GET("/"))
+		Description("show the home page")
+		Response(NoContent)
+	})
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Description("show a bottle")
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(NoContent)
+	})
+})
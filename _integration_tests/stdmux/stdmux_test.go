@@ -0,0 +1,69 @@
+// The enhanced http.ServeMux patterns are disabled by default for code that is not built from a
+// Go 1.22 or later module, e.g. in GOPATH mode.
+//go:debug httpmuxgo121=0
+
+package stdmux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/stdmux/app"
+)
+
+// HomeController implements the home resource.
+type HomeController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *HomeController) Show(ctx *app.ShowHomeContext) error {
+	return ctx.NoContent()
+}
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	return ctx.NoContent()
+}
+
+func TestStdMuxRoutes(t *testing.T) {
+	service := app.NewService("stdmux")
+	app.MountHomeController(service, &HomeController{Controller: service.NewController("home")})
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := map[string]int{
+		"/":          http.StatusNoContent,
+		"/bottles/1": http.StatusNoContent,
+		"/other":     http.StatusNotFound,
+		"/bottles/":  http.StatusNotFound,
+	}
+	for path, status := range cases {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("request to %s failed: %s", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("%s: expected status %d, got %d", path, status, resp.StatusCode)
+		}
+	}
+}
+
+func TestStdMuxRequiresStdService(t *testing.T) {
+	service := goa.New("stdmux")
+	defer func() {
+		if recover() == nil {
+			t.Error("expected mounting on a service using another router to panic")
+		}
+	}()
+	app.MountHomeController(service, &HomeController{Controller: service.NewController("home")})
+}
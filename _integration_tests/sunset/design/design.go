@@ -0,0 +1,32 @@
+package design
+
+import (
+	"time"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+// SunsetDate is the date after which the version 1 of the API is no longer served.
+var SunsetDate = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("A sunsetted API version with no successor")
+	Version("1.0")
+	BasePath("/v1")
+	Host("localhost:8080")
+	Scheme("http")
+	Sunset(SunsetDate, "")
+})
+
+var _ = Resource("bottle", func() {
+	Action("show", func() {
+		Routing(GET("/bottles/:id"))
+		Description("show retrieves a bottle")
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Response(NoContent)
+	})
+})
@@ -0,0 +1,44 @@
+package sunset_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/sunset/app"
+	"github.com/goadesign/goa/_integration_tests/sunset/design"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	return ctx.NoContent()
+}
+
+func TestSunset(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v1/bottles/1")
+	if err != nil {
+		t.Fatalf("failed to send request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusGone {
+		t.Errorf("expected status %d, got %d", http.StatusGone, resp.StatusCode)
+	}
+	if d := resp.Header.Get("Deprecation"); d != "true" {
+		t.Errorf(`expected Deprecation header "true", got %q`, d)
+	}
+	expected := design.SunsetDate.Format(http.TimeFormat)
+	if s := resp.Header.Get("Sunset"); s != expected {
+		t.Errorf("expected Sunset header %q, got %q", expected, s)
+	}
+}
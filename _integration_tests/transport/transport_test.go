@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goadesign/goa/_integration_tests/transport/client"
+)
+
+// transport returns the transport of the HTTP client created by client.NewCellarClient.
+func transport(t *testing.T, c *client.Client) reflect.Value {
+	hc := reflect.ValueOf(c).Elem().FieldByName("httpClient")
+	if !hc.IsValid() || hc.IsNil() {
+		t.Fatal("client has no HTTP client")
+	}
+	return hc.Elem().FieldByName("Transport").Elem()
+}
+
+func TestDefaultTransport(t *testing.T) {
+	c := client.NewCellarClient("https://cellar.example.com")
+	if c.Scheme != "https" || c.Host != "cellar.example.com" {
+		t.Errorf("expected scheme https and host cellar.example.com, got %s and %s", c.Scheme, c.Host)
+	}
+	tr := transport(t, c)
+	if tr.Type() != reflect.TypeOf(&http.Transport{}) {
+		t.Fatalf("expected *http.Transport, got %s", tr.Type())
+	}
+	tr = tr.Elem()
+	if n := tr.FieldByName("MaxIdleConns").Int(); n != 100 {
+		t.Errorf("expected 100 max idle connections, got %d", n)
+	}
+	if n := tr.FieldByName("MaxIdleConnsPerHost").Int(); n != 100 {
+		t.Errorf("expected 100 max idle connections per host, got %d", n)
+	}
+	if d := time.Duration(tr.FieldByName("IdleConnTimeout").Int()); d != 90*time.Second {
+		t.Errorf("expected 90s idle connection timeout, got %s", d)
+	}
+}
+
+func TestTransportOptions(t *testing.T) {
+	tlsConfig := &tls.Config{ServerName: "cellar"}
+	c := client.NewCellarClient("https://cellar.example.com",
+		client.WithMaxIdleConns(7),
+		client.WithIdleConnTimeout(time.Minute),
+		client.WithTLSConfig(tlsConfig),
+	)
+	tr := transport(t, c).Elem()
+	if n := tr.FieldByName("MaxIdleConns").Int(); n != 7 {
+		t.Errorf("expected 7 max idle connections, got %d", n)
+	}
+	if n := tr.FieldByName("MaxIdleConnsPerHost").Int(); n != 7 {
+		t.Errorf("expected 7 max idle connections per host, got %d", n)
+	}
+	if d := time.Duration(tr.FieldByName("IdleConnTimeout").Int()); d != time.Minute {
+		t.Errorf("expected 1m idle connection timeout, got %s", d)
+	}
+	if p := tr.FieldByName("TLSClientConfig").Pointer(); p != reflect.ValueOf(tlsConfig).Pointer() {
+		t.Error("TLS configuration not applied")
+	}
+}
+
+// roundTripper is a custom transport.
+type roundTripper struct{}
+
+func (roundTripper) RoundTrip(*http.Request) (*http.Response, error) { return nil, nil }
+
+func TestCustomTransport(t *testing.T) {
+	c := client.NewCellarClient("http://localhost:8080", client.WithTransport(roundTripper{}), client.WithMaxIdleConns(7))
+	if tr := transport(t, c); tr.Type() != reflect.TypeOf(roundTripper{}) {
+		t.Errorf("expected the custom transport, got %s", tr.Type())
+	}
+}
@@ -0,0 +1,28 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API using unsigned integer parameters")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("list", func() {
+		Routing(GET("/:count"))
+		Description("list a number of bottles")
+		Params(func() {
+			Param("count", UintType, "Number of bottles")
+			Param("rating", Uint8Type, "Minimum rating")
+			Param("vintage", Uint16Type, "Vintage year")
+			Param("offset", Uint32Type, "Listing offset")
+		})
+		Response(OK, "text/plain")
+	})
+})
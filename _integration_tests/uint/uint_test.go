@@ -0,0 +1,79 @@
+package uint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/uint/app"
+	"github.com/goadesign/goa/middleware"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// List echoes the coerced parameters.
+func (c *BottleController) List(ctx *app.ListBottleContext) error {
+	var rating uint8
+	if ctx.Rating != nil {
+		rating = *ctx.Rating
+	}
+	var vintage uint16
+	if ctx.Vintage != nil {
+		vintage = *ctx.Vintage
+	}
+	var offset uint32
+	if ctx.Offset != nil {
+		offset = *ctx.Offset
+	}
+	return ctx.OK([]byte(fmt.Sprintf("%d %d %d %d", ctx.Count, rating, vintage, offset)))
+}
+
+func TestUintCoercion(t *testing.T) {
+	service := goa.New("cellar")
+	service.Use(middleware.ErrorHandler(service, true))
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/bottles/12", http.StatusOK, "12 0 0 0"},
+		{"/bottles/12?rating=255&vintage=1999&offset=4294967295", http.StatusOK, "12 255 1999 4294967295"},
+		{"/bottles/-1", http.StatusBadRequest, "count"},
+		{"/bottles/12?rating=-3", http.StatusBadRequest, "rating"},
+		{"/bottles/12?rating=256", http.StatusBadRequest, "rating"},
+		{"/bottles/12?vintage=65536", http.StatusBadRequest, "vintage"},
+		{"/bottles/12?offset=4294967296", http.StatusBadRequest, "offset"},
+	}
+	for _, c := range cases {
+		resp, err := http.Get(server.URL + c.path)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to read response: %s", err)
+		}
+		if resp.StatusCode != c.status {
+			t.Errorf("%s: expected status %d, got %d: %s", c.path, c.status, resp.StatusCode, body)
+			continue
+		}
+		if !strings.Contains(string(body), c.body) {
+			t.Errorf("%s: expected response to contain %q, got %q", c.path, c.body, body)
+		}
+		if c.status == http.StatusBadRequest && !strings.Contains(string(body), "unsigned integer") {
+			t.Errorf("%s: expected an invalid unsigned integer error, got %s", c.path, body)
+		}
+	}
+}
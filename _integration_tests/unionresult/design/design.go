@@ -0,0 +1,51 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API whose show action returns either a bottle or the box that contains it")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("bottle", func() {
+	BasePath("/bottles")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		UnionResult()
+		Response(OK, BottleMedia)
+		Response(Accepted, BoxMedia)
+		Response(NotFound)
+	})
+})
+
+// BottleMedia is the bottle resource media type.
+var BottleMedia = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Attributes(func() {
+		Attribute("id", Integer, "ID of bottle")
+		Attribute("name", String, "Name of bottle")
+		Required("id", "name")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+	})
+})
+
+// BoxMedia describes the box that contains a bottle being shipped.
+var BoxMedia = MediaType("application/vnd.goa.example.box+json", func() {
+	Attributes(func() {
+		Attribute("tracking", String, "Shipment tracking number")
+		Required("tracking")
+	})
+	View("default", func() {
+		Attribute("tracking")
+	})
+})
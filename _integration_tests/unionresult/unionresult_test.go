@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/unionresult/app"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action, bottle 1 is in the cellar, bottle 2 is being shipped and any other
+// bottle does not exist.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) (app.ShowBottleResult, error) {
+	switch ctx.ID {
+	case 1:
+		return &app.GoaExampleBottle{ID: 1, Name: "Number 8"}, nil
+	case 2:
+		return &app.GoaExampleBox{Tracking: "1Z999"}, nil
+	}
+	return nil, ctx.NotFound()
+}
+
+func TestUnionResult(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := []struct {
+		ID     string
+		Status int
+		Body   map[string]interface{}
+	}{
+		{"1", http.StatusOK, map[string]interface{}{"id": 1.0, "name": "Number 8"}},
+		{"2", http.StatusAccepted, map[string]interface{}{"tracking": "1Z999"}},
+		{"3", http.StatusNotFound, nil},
+	}
+	for _, c := range cases {
+		resp, err := http.Get(server.URL + "/bottles/" + c.ID)
+		if err != nil {
+			t.Fatalf("bottle %s: request failed: %s", c.ID, err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != c.Status {
+			t.Errorf("bottle %s: expected status %d, got %d", c.ID, c.Status, resp.StatusCode)
+		}
+		if c.Body == nil {
+			continue
+		}
+		var actual map[string]interface{}
+		if err := json.Unmarshal(body, &actual); err != nil {
+			t.Fatalf("bottle %s: invalid response body %q: %s", c.ID, string(body), err)
+		}
+		for k, v := range c.Body {
+			if actual[k] != v {
+				t.Errorf("bottle %s: expected %s to be %v, got %v", c.ID, k, v, actual[k])
+			}
+		}
+	}
+}
+
+// wrongResult is a controller that returns a value which is not one of the show action success
+// response types, it must not compile.
+const wrongResult = `package main
+
+import "github.com/goadesign/goa/_integration_tests/unionresult/app"
+
+type Corkscrew struct{}
+
+func Show(ctx *app.ShowBottleContext) (app.ShowBottleResult, error) {
+	return &Corkscrew{}, nil
+}
+
+func main() {}
+`
+
+func TestWrongResultType(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "wrongresult")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(wrongResult), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "build", "-o", os.DevNull, ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("expected the build of a controller returning a wrong result type to fail")
+	}
+	if !strings.Contains(string(out), "does not implement app.ShowBottleResult") {
+		t.Errorf("unexpected build error: %s", out)
+	}
+}
@@ -0,0 +1,46 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("cellar", func() {
+	Title("The cellar API")
+	Description("An API filtering response fields based on the user roles")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var BottleMedia = MediaType("application/vnd.goa.example.bottle+json", func() {
+	Description("A bottle of wine")
+	TypeName("Bottle")
+	Attributes(func() {
+		Attribute("id", Integer, "ID of bottle")
+		Attribute("name", String, "Name of bottle")
+		Attribute("internal_notes", String, "Notes only visible to administrators", func() {
+			VisibleTo("admin")
+		})
+		Required("id", "name")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+		Attribute("internal_notes")
+	})
+})
+
+var _ = Resource("bottle", func() {
+	DefaultMedia(BottleMedia)
+	Action("show", func() {
+		Routing(GET("/bottles/:id"))
+		Description("show retrieves a bottle, the controller filters the fields using the X-Roles header")
+		Params(func() {
+			Param("id", Integer, "Bottle ID")
+		})
+		Headers(func() {
+			Header("X-Roles", String, "Comma separated list of the user roles")
+		})
+		Response(OK)
+	})
+})
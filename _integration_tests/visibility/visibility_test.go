@@ -0,0 +1,74 @@
+package visibility_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/visibility/app"
+)
+
+// BottleController filters the bottle fields using the roles listed in the X-Roles header.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	notes := "corked"
+	bottle := &app.Bottle{ID: ctx.ID, Name: "Number 8", InternalNotes: &notes}
+	var roles []string
+	if ctx.XRoles != nil {
+		roles = strings.Split(*ctx.XRoles, ",")
+	}
+	return ctx.OKFiltered(bottle, roles)
+}
+
+func TestVisibleTo(t *testing.T) {
+	service := goa.New("cellar")
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	cases := []struct {
+		Roles   string
+		Visible bool
+	}{
+		{"", false},
+		{"user", false},
+		{"user,admin", true},
+		{"admin", true},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("GET", server.URL+"/bottles/1", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %s", err)
+		}
+		if c.Roles != "" {
+			req.Header.Set("X-Roles", c.Roles)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to send request: %s", err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to read response: %s", err)
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(body, &fields); err != nil {
+			t.Fatalf("invalid response body %s: %s", body, err)
+		}
+		if _, ok := fields["name"]; !ok {
+			t.Errorf("roles %q: name is missing from %s", c.Roles, body)
+		}
+		if _, ok := fields["internal_notes"]; ok != c.Visible {
+			t.Errorf("roles %q: expected internal_notes visibility to be %v, got %s", c.Roles, c.Visible, body)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("chat", func() {
+	Title("The chat API")
+	Description("An echo WebSocket server")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var _ = Resource("chat", func() {
+	Action("echo", func() {
+		Routing(GET("/echo"))
+		Description("echo sends back the messages received on the WebSocket connection")
+		WebSocket()
+		Response(SwitchingProtocols)
+	})
+})
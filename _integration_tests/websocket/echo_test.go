@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/websocket/app"
+	"github.com/goadesign/goa/_integration_tests/websocket/client"
+)
+
+func TestEcho(t *testing.T) {
+	service := goa.New("chat")
+	app.MountChatController(service, NewChatController(service))
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	c := client.NewChatClient(server.URL)
+	c.Scheme = "ws"
+	conn, err := c.EchoChat(context.Background(), client.EchoChatPath())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatalf("failed to write message: %s", err)
+	}
+	msg := make([]byte, 16)
+	n, err := conn.Read(msg)
+	if err != nil {
+		t.Fatalf("failed to read message: %s", err)
+	}
+	if string(msg[:n]) != "hello" {
+		t.Errorf("invalid message, expected %q got %q", "hello", string(msg[:n]))
+	}
+}
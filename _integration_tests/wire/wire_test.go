@@ -0,0 +1,20 @@
+//go:build !wireinject
+// +build !wireinject
+
+package main
+
+import (
+	"testing"
+
+	"github.com/goadesign/goa"
+)
+
+func TestPlaceholderInjector(t *testing.T) {
+	ctrl, err := InitializeBottleController(goa.New("cellar"))
+	if err != ErrNotImplemented {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+	if ctrl != nil {
+		t.Errorf("expected no controller, got %v", ctrl)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/zerolog/app"
+	"github.com/rs/zerolog"
+)
+
+// BottleController implements the bottle resource.
+type BottleController struct {
+	*goa.Controller
+}
+
+// Show runs the show action.
+func (c *BottleController) Show(ctx *app.ShowBottleContext) error {
+	if ctx.ID != 1 {
+		return ctx.NotFound()
+	}
+	return ctx.NoContent()
+}
+
+func TestLogRequest(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	service := goa.New("cellar")
+	service.Use(LogRequest())
+	app.MountBottleController(service, &BottleController{Controller: service.NewController("bottle")})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		service.Mux.ServeHTTP(rw, req.WithContext(logger.WithContext(req.Context())))
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		Path   string
+		Status int
+	}{
+		{"/bottles/1", http.StatusNoContent},
+		{"/bottles/2", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		buf.Reset()
+		resp, err := http.Get(server.URL + tc.Path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %s", tc.Path, err)
+		}
+		resp.Body.Close()
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("GET %s: invalid log entry %q: %s", tc.Path, buf.String(), err)
+		}
+		if msg, ok := entry["message"].(string); !ok || msg != "request" {
+			t.Errorf("GET %s: expected message \"request\", got %#v", tc.Path, entry["message"])
+		}
+		if method, ok := entry["method"].(string); !ok || method != "GET" {
+			t.Errorf("GET %s: expected method \"GET\", got %#v", tc.Path, entry["method"])
+		}
+		if path, ok := entry["path"].(string); !ok || path != tc.Path {
+			t.Errorf("GET %s: expected path %q, got %#v", tc.Path, tc.Path, entry["path"])
+		}
+		if status, ok := entry["status"].(float64); !ok || int(status) != tc.Status {
+			t.Errorf("GET %s: expected status %d, got %#v", tc.Path, tc.Status, entry["status"])
+		}
+		if latency, ok := entry["latency"].(float64); !ok || latency < 0 {
+			t.Errorf("GET %s: expected a numeric latency, got %#v", tc.Path, entry["latency"])
+		}
+	}
+}
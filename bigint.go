@@ -0,0 +1,95 @@
+package goa
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// BigInt is the Go representation of the BigIntType design type: an arbitrary-precision integer
+// whose text representation is its decimal representation, e.g. "9223372036854775808". BigInt
+// values are serialized as JSON strings so that clients do not lose precision. The zero value
+// represents 0. BigInt values are immutable so that copies may safely share the underlying
+// integer.
+type BigInt struct {
+	i *big.Int
+}
+
+// knownBigIntBounds records the parsed validation bounds.
+var knownBigIntBounds = make(map[string]*big.Int)
+
+// knownBigIntBoundsLock is the mutex used to access knownBigIntBounds.
+var knownBigIntBoundsLock = &sync.RWMutex{}
+
+// NewBigInt returns a BigInt holding a copy of i.
+func NewBigInt(i *big.Int) BigInt {
+	return BigInt{i: new(big.Int).Set(i)}
+}
+
+// ParseBigInt parses the decimal representation of an integer.
+func ParseBigInt(s string) (BigInt, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return BigInt{}, fmt.Errorf("invalid integer %#v", s)
+	}
+	return BigInt{i: i}, nil
+}
+
+// BigIntBound returns the integer whose decimal representation is s. It is used by the generated
+// code to validate the minimum and maximum of BigInt values and parses each bound only once. The
+// returned value is shared and must not be modified.
+func BigIntBound(s string) *big.Int {
+	knownBigIntBoundsLock.RLock()
+	i, ok := knownBigIntBounds[s]
+	knownBigIntBoundsLock.RUnlock()
+	if !ok {
+		i, ok = new(big.Int).SetString(s, 10)
+		if !ok {
+			panic(fmt.Sprintf("goa: invalid integer bound %#v", s)) // bug
+		}
+		knownBigIntBoundsLock.Lock()
+		knownBigIntBounds[s] = i
+		knownBigIntBoundsLock.Unlock()
+	}
+	return i
+}
+
+// Int returns a copy of b as a *big.Int.
+func (b BigInt) Int() *big.Int {
+	if b.i == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(b.i)
+}
+
+// Cmp compares b and y and returns -1 if b < y, 0 if b == y and +1 if b > y.
+func (b BigInt) Cmp(y *big.Int) int {
+	if b.i == nil {
+		return -y.Sign()
+	}
+	return b.i.Cmp(y)
+}
+
+// String returns the decimal representation of b.
+func (b BigInt) String() string {
+	if b.i == nil {
+		return "0"
+	}
+	return b.i.String()
+}
+
+// MarshalText implements encoding.TextMarshaler so that big integers are serialized as decimal
+// strings.
+func (b BigInt) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *BigInt) UnmarshalText(text []byte) error {
+	parsed, err := ParseBigInt(string(text))
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
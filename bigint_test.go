@@ -0,0 +1,75 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BigInt", func() {
+	// beyond is math.MaxInt64 + 1.
+	const beyond = "9223372036854775808"
+
+	It("parses integers exceeding math.MaxInt64", func() {
+		b, err := goa.ParseBigInt(beyond)
+		Ω(err).ShouldNot(HaveOccurred())
+		expected := new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1))
+		Ω(b.Int().Cmp(expected)).Should(Equal(0))
+		Ω(b.String()).Should(Equal(beyond))
+	})
+
+	It("fails to parse non integer values", func() {
+		_, err := goa.ParseBigInt("1.5")
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("round-trips through JSON as a decimal string", func() {
+		type payload struct {
+			Amount  goa.BigInt  `json:"amount"`
+			Balance *goa.BigInt `json:"balance,omitempty"`
+		}
+		b, err := goa.ParseBigInt(beyond)
+		Ω(err).ShouldNot(HaveOccurred())
+		js, err := json.Marshal(payload{Amount: b, Balance: &b})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(js)).Should(Equal(`{"amount":"` + beyond + `","balance":"` + beyond + `"}`))
+		var p payload
+		Ω(json.Unmarshal(js, &p)).Should(Succeed())
+		Ω(p.Amount.String()).Should(Equal(beyond))
+		Ω(p.Balance).ShouldNot(BeNil())
+		Ω(p.Balance.String()).Should(Equal(beyond))
+	})
+
+	It("returns copies of the integer", func() {
+		b := goa.NewBigInt(big.NewInt(42))
+		b.Int().SetInt64(0)
+		Ω(b.String()).Should(Equal("42"))
+	})
+
+	It("represents 0 with the zero value", func() {
+		var b goa.BigInt
+		Ω(b.String()).Should(Equal("0"))
+		Ω(b.Int().Sign()).Should(Equal(0))
+		Ω(b.Cmp(big.NewInt(-1))).Should(Equal(1))
+		Ω(b.Cmp(big.NewInt(1))).Should(Equal(-1))
+	})
+
+	It("compares with big integers", func() {
+		b, err := goa.ParseBigInt(beyond)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(b.Cmp(big.NewInt(math.MaxInt64))).Should(Equal(1))
+		Ω(b.Cmp(b.Int())).Should(Equal(0))
+	})
+})
+
+var _ = Describe("BigIntBound", func() {
+	It("parses each bound once", func() {
+		bound := goa.BigIntBound("18446744073709551616")
+		Ω(bound.String()).Should(Equal("18446744073709551616"))
+		Ω(goa.BigIntBound("18446744073709551616") == bound).Should(BeTrue())
+	})
+})
@@ -0,0 +1,41 @@
+package goa
+
+import "net"
+
+// CIDR is an IP network expressed in the RFC4632 or RFC4291 CIDR notation, e.g. "192.0.2.0/24".
+// Unlike net.IPNet it implements encoding.TextMarshaler and encoding.TextUnmarshaler so that
+// values round-trip through JSON.
+type CIDR struct {
+	net.IPNet
+}
+
+// ParseCIDR parses s as a CIDR notation IP network. The address is masked so that "10.1.2.3/16"
+// produces the "10.1.0.0/16" network.
+func ParseCIDR(s string) (CIDR, error) {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return CIDR{}, err
+	}
+	return CIDR{IPNet: *n}, nil
+}
+
+// String returns the CIDR notation of the network.
+func (c CIDR) String() string {
+	return c.IPNet.String()
+}
+
+// MarshalText implements encoding.TextMarshaler so that networks are serialized in the CIDR
+// notation.
+func (c CIDR) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *CIDR) UnmarshalText(text []byte) error {
+	parsed, err := ParseCIDR(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
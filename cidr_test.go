@@ -0,0 +1,47 @@
+package goa_test
+
+import (
+	"encoding/json"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseCIDR", func() {
+	It("parses IPv4 networks", func() {
+		c, err := goa.ParseCIDR("10.1.2.3/16")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(c.String()).Should(Equal("10.1.0.0/16"))
+	})
+
+	It("parses IPv6 networks", func() {
+		c, err := goa.ParseCIDR("2001:db8::/32")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(c.String()).Should(Equal("2001:db8::/32"))
+	})
+
+	It("rejects malformed values", func() {
+		_, err := goa.ParseCIDR("10.1.2.3")
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("CIDR", func() {
+	It("round-trips through JSON", func() {
+		var v struct {
+			Network goa.CIDR `json:"network"`
+		}
+		Ω(json.Unmarshal([]byte(`{"network":"192.0.2.0/24"}`), &v)).Should(Succeed())
+		Ω(v.Network.Contains([]byte{192, 0, 2, 42})).Should(BeTrue())
+
+		b, err := json.Marshal(v)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`{"network":"192.0.2.0/24"}`))
+	})
+
+	It("rejects invalid JSON values", func() {
+		var c goa.CIDR
+		Ω(json.Unmarshal([]byte(`"not a network"`), &c)).ShouldNot(Succeed())
+	})
+})
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Load balancing strategies supported by Gateway.
+const (
+	// RoundRobin sends the requests to each server in turn.
+	RoundRobin = "round-robin"
+	// Random sends each request to a server selected at random.
+	Random = "random"
+)
+
+type (
+	// Gateway is a Doer that load-balances requests across multiple servers. The scheme and
+	// host of the request URLs are replaced with the ones of the selected server before the
+	// requests are sent using the underlying Doer.
+	Gateway struct {
+		// Doer is the underlying http client.
+		Doer
+		// Strategy is the server selection strategy, RoundRobin or Random.
+		Strategy string
+		// HealthAware is the number of most recent requests considered when selecting a
+		// server. Servers that failed or returned a 5xx response to any of these requests are
+		// skipped as long as another server is available. Zero disables health tracking.
+		HealthAware int
+
+		urls    []*url.URL
+		mu      sync.Mutex
+		next    int
+		results []gatewayResult
+		rand    *rand.Rand
+	}
+
+	// gatewayResult records the outcome of a request sent by a gateway.
+	gatewayResult struct {
+		server int
+		failed bool
+	}
+)
+
+// NewGateway creates a gateway that load-balances requests across the servers located at the
+// given base URLs using the given strategy. If c is nil, the gateway wraps http.DefaultClient.
+func NewGateway(c Doer, strategy string, urls ...string) (*Gateway, error) {
+	if strategy != RoundRobin && strategy != Random {
+		return nil, fmt.Errorf("invalid load balancing strategy %#v, must be %#v or %#v", strategy, RoundRobin, Random)
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("gateway requires at least one server URL")
+	}
+	if c == nil {
+		c = HTTPClientDoer(http.DefaultClient)
+	}
+	g := &Gateway{
+		Doer:     c,
+		Strategy: strategy,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server URL %#v: %s", u, err)
+		}
+		if parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid server URL %#v: missing scheme or host", u)
+		}
+		g.urls = append(g.urls, parsed)
+	}
+	return g, nil
+}
+
+// Do sends the request to the server selected by the gateway strategy.
+func (g *Gateway) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	server := g.pick()
+	base := g.urls[server]
+	r := new(http.Request)
+	*r = *req
+	u := *req.URL
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	if p := strings.TrimSuffix(base.Path, "/"); p != "" {
+		u.Path = p + u.Path
+	}
+	r.URL = &u
+	r.Host = base.Host
+	resp, err := g.Doer.Do(ctx, r)
+	g.record(server, err != nil || resp.StatusCode >= 500)
+	return resp, err
+}
+
+// pick returns the index of the server the next request should be sent to.
+func (g *Gateway) pick() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var candidates []int
+	for i := range g.urls {
+		if g.healthy(i) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		for i := range g.urls {
+			candidates = append(candidates, i)
+		}
+	}
+	if g.Strategy == Random {
+		return candidates[g.rand.Intn(len(candidates))]
+	}
+	for _, i := range candidates {
+		if i >= g.next {
+			g.next = i + 1
+			return i
+		}
+	}
+	g.next = candidates[0] + 1
+	return candidates[0]
+}
+
+// healthy returns true if the server did not fail any of the recorded requests.
+func (g *Gateway) healthy(server int) bool {
+	for _, r := range g.results {
+		if r.server == server && r.failed {
+			return false
+		}
+	}
+	return true
+}
+
+// record keeps the outcome of the last HealthAware requests.
+func (g *Gateway) record(server int, failed bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.HealthAware <= 0 {
+		g.results = nil
+		return
+	}
+	g.results = append(g.results, gatewayResult{server: server, failed: failed})
+	if len(g.results) > g.HealthAware {
+		g.results = g.results[len(g.results)-g.HealthAware:]
+	}
+}
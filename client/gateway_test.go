@@ -0,0 +1,97 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goadesign/goa/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Gateway", func() {
+	var servers []*httptest.Server
+	var hits []int
+	var strategy string
+	var healthAware int
+	var gateway *client.Gateway
+
+	BeforeEach(func() {
+		hits = make([]int, 3)
+		servers = nil
+		for i := 0; i < 3; i++ {
+			i := i
+			servers = append(servers, httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				hits[i]++
+				if i == 1 {
+					rw.WriteHeader(500)
+				}
+			})))
+		}
+		strategy = client.RoundRobin
+		healthAware = 0
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		gateway, err = client.NewGateway(nil, strategy, servers[0].URL, servers[1].URL, servers[2].URL)
+		Ω(err).ShouldNot(HaveOccurred())
+		gateway.HealthAware = healthAware
+	})
+
+	AfterEach(func() {
+		for _, s := range servers {
+			s.Close()
+		}
+	})
+
+	send := func(n int) {
+		for i := 0; i < n; i++ {
+			req, err := http.NewRequest("GET", "http://example.com/bottles", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			resp, err := gateway.Do(context.Background(), req)
+			Ω(err).ShouldNot(HaveOccurred())
+			resp.Body.Close()
+		}
+	}
+
+	Context("using the round-robin strategy", func() {
+		It("sends the requests to each server in turn", func() {
+			send(6)
+			Ω(hits).Should(Equal([]int{2, 2, 2}))
+		})
+	})
+
+	Context("using the random strategy", func() {
+		BeforeEach(func() {
+			strategy = client.Random
+		})
+
+		It("sends the requests to the servers", func() {
+			send(30)
+			Ω(hits[0] + hits[1] + hits[2]).Should(Equal(30))
+		})
+	})
+
+	Context("in health aware mode", func() {
+		BeforeEach(func() {
+			healthAware = 10
+		})
+
+		It("stops routing to the failing server", func() {
+			send(3)
+			Ω(hits[1]).Should(Equal(1))
+			send(9)
+			Ω(hits[1]).Should(Equal(1))
+			Ω(hits[0] + hits[2]).Should(Equal(11))
+		})
+	})
+
+	Context("with an invalid strategy", func() {
+		It("fails", func() {
+			_, err := client.NewGateway(nil, "fastest", servers[0].URL)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
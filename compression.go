@@ -0,0 +1,148 @@
+package goa
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type (
+	// CompressorFunc instantiates a writer that compresses the data written to it and writes the
+	// result to w. Closing the writer flushes any pending data but does not close w.
+	CompressorFunc func(w io.Writer) (io.WriteCloser, error)
+
+	// CompressResponseWriter is a http.ResponseWriter that compresses the response body with a
+	// compressor. The Content-Encoding header is set when the response header is written, the
+	// header is left untouched if nothing is written to the response.
+	CompressResponseWriter struct {
+		http.ResponseWriter
+
+		encoding    string
+		compressor  CompressorFunc
+		cw          io.WriteCloser
+		wroteHeader bool
+		passthrough bool
+	}
+)
+
+// NewGzipCompressor is a CompressorFunc that uses the compress/gzip package.
+func NewGzipCompressor(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }
+
+// SetCompressor registers the compressor used to encode responses with the given content
+// encoding, e.g. "gzip". Compressors registered first are preferred when the client accepts
+// multiple encodings with the same quality.
+func (service *Service) SetCompressor(encoding string, fn CompressorFunc) {
+	if service.compressors == nil {
+		service.compressors = make(map[string]CompressorFunc)
+	}
+	if _, ok := service.compressors[encoding]; !ok {
+		service.encodings = append(service.encodings, encoding)
+	}
+	service.compressors[encoding] = fn
+}
+
+// Compressor returns the content encoding and compressor to use given the value of a request
+// Accept-Encoding header. It returns an empty string and a nil compressor if the client does not
+// accept any of the encodings registered with SetCompressor.
+func (service *Service) Compressor(acceptEncoding string) (string, CompressorFunc) {
+	var (
+		best     string
+		bestQ    float64
+		accepted = make(map[string]float64)
+	)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		enc, q := parseEncoding(part)
+		if enc != "" {
+			accepted[enc] = q
+		}
+	}
+	for _, enc := range service.encodings {
+		q, ok := accepted[enc]
+		if !ok {
+			if q, ok = accepted["*"]; !ok {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	if best == "" {
+		return "", nil
+	}
+	return best, service.compressors[best]
+}
+
+// parseEncoding parses a single element of an Accept-Encoding header, e.g. "gzip;q=0.8", and
+// returns the encoding and its quality.
+func parseEncoding(s string) (string, float64) {
+	elems := strings.Split(s, ";")
+	enc := strings.ToLower(strings.TrimSpace(elems[0]))
+	q := 1.0
+	for _, param := range elems[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+		if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+			q = v
+		}
+	}
+	return enc, q
+}
+
+// NewCompressResponseWriter returns a response writer that compresses the data written to rw
+// using the given compressor. The encoding is used to set the Content-Encoding header.
+func NewCompressResponseWriter(rw http.ResponseWriter, encoding string, fn CompressorFunc) *CompressResponseWriter {
+	return &CompressResponseWriter{ResponseWriter: rw, encoding: encoding, compressor: fn}
+}
+
+// WriteHeader sets the Content-Encoding and Vary headers and writes the response header. Responses
+// that may not have a body are not compressed.
+func (w *CompressResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	h := w.ResponseWriter.Header()
+	if status < 200 || status == http.StatusNoContent || status == http.StatusNotModified ||
+		h.Get("Content-Encoding") != "" {
+		w.passthrough = true
+	} else {
+		h.Set("Content-Encoding", w.encoding)
+		h.Add("Vary", "Accept-Encoding")
+		h.Del("Content-Length")
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write compresses b and writes the result to the underlying response writer.
+func (w *CompressResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.ResponseWriter.Header().Get("Content-Type") == "" {
+			w.ResponseWriter.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.cw == nil {
+		cw, err := w.compressor(w.ResponseWriter)
+		if err != nil {
+			return 0, err
+		}
+		w.cw = cw
+	}
+	return w.cw.Write(b)
+}
+
+// Close flushes the compressed data to the underlying response writer.
+func (w *CompressResponseWriter) Close() error {
+	if w.cw == nil {
+		return nil
+	}
+	return w.cw.Close()
+}
@@ -0,0 +1,125 @@
+package goa_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compressor", func() {
+	var service *goa.Service
+	var acceptEncoding string
+
+	var encoding string
+	var compressor goa.CompressorFunc
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		service.SetCompressor("gzip", goa.NewGzipCompressor)
+		service.SetCompressor("deflate", goa.NewGzipCompressor)
+		acceptEncoding = ""
+	})
+
+	JustBeforeEach(func() {
+		encoding, compressor = service.Compressor(acceptEncoding)
+	})
+
+	Context("with no Accept-Encoding header", func() {
+		It("does not compress", func() {
+			Ω(encoding).Should(BeEmpty())
+			Ω(compressor).Should(BeNil())
+		})
+	})
+
+	Context("with an accepted encoding", func() {
+		BeforeEach(func() {
+			acceptEncoding = "br, deflate"
+		})
+
+		It("returns the compressor", func() {
+			Ω(encoding).Should(Equal("deflate"))
+			Ω(compressor).ShouldNot(BeNil())
+		})
+	})
+
+	Context("with multiple accepted encodings", func() {
+		BeforeEach(func() {
+			acceptEncoding = "deflate, gzip"
+		})
+
+		It("prefers the compressor registered first", func() {
+			Ω(encoding).Should(Equal("gzip"))
+		})
+	})
+
+	Context("with quality values", func() {
+		BeforeEach(func() {
+			acceptEncoding = "gzip;q=0.5, deflate;q=0.8"
+		})
+
+		It("prefers the encoding with the highest quality", func() {
+			Ω(encoding).Should(Equal("deflate"))
+		})
+	})
+
+	Context("with a refused encoding", func() {
+		BeforeEach(func() {
+			acceptEncoding = "gzip;q=0"
+		})
+
+		It("does not compress", func() {
+			Ω(compressor).Should(BeNil())
+		})
+	})
+})
+
+var _ = Describe("CompressResponseWriter", func() {
+	const content = `{"name":"a bottle of wine"}`
+
+	var rw *httptest.ResponseRecorder
+	var status int
+
+	BeforeEach(func() {
+		rw = httptest.NewRecorder()
+		status = http.StatusOK
+	})
+
+	JustBeforeEach(func() {
+		service := goa.New("test")
+		service.SetCompressor("gzip", goa.NewGzipCompressor)
+		encoding, compressor := service.Compressor("gzip")
+		w := goa.NewCompressResponseWriter(rw, encoding, compressor)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status != http.StatusNoContent {
+			w.Write([]byte(content))
+		}
+		Ω(w.Close()).ShouldNot(HaveOccurred())
+	})
+
+	It("compresses the response body", func() {
+		Ω(rw.Header().Get("Content-Encoding")).Should(Equal("gzip"))
+		Ω(rw.Header().Get("Vary")).Should(Equal("Accept-Encoding"))
+		r, err := gzip.NewReader(rw.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		b, err := ioutil.ReadAll(r)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(content))
+	})
+
+	Context("with a response that has no body", func() {
+		BeforeEach(func() {
+			status = http.StatusNoContent
+		})
+
+		It("does not set the Content-Encoding header", func() {
+			Ω(rw.Code).Should(Equal(http.StatusNoContent))
+			Ω(rw.Header().Get("Content-Encoding")).Should(BeEmpty())
+		})
+	})
+})
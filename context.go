@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
+	"github.com/satori/go.uuid"
 	"golang.org/x/net/context"
 )
 
@@ -19,6 +21,11 @@ const (
 	logContextKey
 	errKey
 	securityScopesKey
+
+	// ServiceKey is the context key used to store the service that handles the request. The
+	// generated Mount functions set it in the context of the requests handled by the mounted
+	// controllers, see ServiceFromContext.
+	ServiceKey
 )
 
 type (
@@ -116,6 +123,22 @@ func ContextRequest(ctx context.Context) *RequestData {
 	return nil
 }
 
+// ServiceFromContext extracts the service that handles the request from the given context. It
+// looks up the context of the underlying HTTP request if the service is not set in ctx so that it
+// works with both the net/http middleware and goa middleware contexts. It returns nil if the
+// request is not handled by a mounted controller.
+func ServiceFromContext(ctx context.Context) *Service {
+	if s, ok := ctx.Value(ServiceKey).(*Service); ok {
+		return s
+	}
+	if r := ContextRequest(ctx); r != nil && r.Request != nil {
+		if s, ok := r.Request.Context().Value(ServiceKey).(*Service); ok {
+			return s
+		}
+	}
+	return nil
+}
+
 // ContextResponse extracts the response data from the given context.
 func ContextResponse(ctx context.Context) *ResponseData {
 	if r := ctx.Value(respKey); r != nil {
@@ -140,6 +163,28 @@ func ContextError(ctx context.Context) error {
 	return nil
 }
 
+// ExtractRequestID returns the ID of the given request. It uses the value of the X-Request-ID
+// header if set, then the value of the X-Correlation-ID header and finally the trace-id segment of
+// the W3C traceparent header. ExtractRequestID generates a random UUID if none of these headers is
+// set and stores it in the X-Request-ID header of the request so that subsequent calls return the
+// same value.
+func ExtractRequestID(req *http.Request) string {
+	if id := req.Header.Get("X-Request-ID"); id != "" {
+		return id
+	}
+	if id := req.Header.Get("X-Correlation-ID"); id != "" {
+		return id
+	}
+	if parts := strings.Split(req.Header.Get("Traceparent"), "-"); len(parts) == 4 {
+		if id := parts[1]; len(id) == 32 && strings.Trim(id, "0") != "" {
+			return id
+		}
+	}
+	id := uuid.NewV4().String()
+	req.Header.Set("X-Request-ID", id)
+	return id
+}
+
 // SwitchWriter overrides the underlying response writer. It returns the response
 // writer that was previously set.
 func (r *ResponseData) SwitchWriter(rw http.ResponseWriter) http.ResponseWriter {
@@ -9,6 +9,7 @@ import (
 	"github.com/goadesign/goa"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
 )
 
 var _ = Describe("ResponseData", func() {
@@ -40,3 +41,86 @@ var _ = Describe("ResponseData", func() {
 		})
 	})
 })
+
+var _ = Describe("ExtractRequestID", func() {
+	var req *http.Request
+	var reqID string
+
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest("GET", "google.com", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		reqID = goa.ExtractRequestID(req)
+	})
+
+	Context("with a X-Request-ID header", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-Request-ID", "abc123")
+			req.Header.Set("X-Correlation-ID", "def456")
+		})
+
+		It("returns the header value", func() {
+			Ω(reqID).Should(Equal("abc123"))
+		})
+	})
+
+	Context("with a X-Correlation-ID header", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-Correlation-ID", "def456")
+		})
+
+		It("returns the header value", func() {
+			Ω(reqID).Should(Equal("def456"))
+		})
+	})
+
+	Context("with a traceparent header", func() {
+		BeforeEach(func() {
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		})
+
+		It("returns the trace ID", func() {
+			Ω(reqID).Should(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+		})
+	})
+
+	Context("with no header", func() {
+		It("returns a random UUID", func() {
+			Ω(reqID).ShouldNot(BeEmpty())
+			_, err := uuid.FromString(reqID)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("returns the same value on subsequent calls", func() {
+			Ω(goa.ExtractRequestID(req)).Should(Equal(reqID))
+		})
+	})
+})
+
+var _ = Describe("ServiceFromContext", func() {
+	var service *goa.Service
+
+	BeforeEach(func() {
+		service = goa.New("test")
+	})
+
+	It("returns the service stored in the context", func() {
+		ctx := context.WithValue(context.Background(), goa.ServiceKey, service)
+		Ω(goa.ServiceFromContext(ctx)).Should(Equal(service))
+	})
+
+	It("returns the service stored in the request context", func() {
+		req, err := http.NewRequest("GET", "google.com", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		req = req.WithContext(context.WithValue(req.Context(), goa.ServiceKey, service))
+		ctx := goa.NewContext(context.Background(), &TestResponseWriter{}, req, nil)
+		Ω(goa.ServiceFromContext(ctx)).Should(Equal(service))
+	})
+
+	It("returns nil if no service is stored", func() {
+		Ω(goa.ServiceFromContext(context.Background())).Should(BeNil())
+	})
+})
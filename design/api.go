@@ -63,6 +63,11 @@ const (
 	HTTPVersionNotSupported = "HTTPVersionNotSupported"
 )
 
+// DefaultStaticAssetsCacheControl is the value of the Cache-Control header of the responses sent
+// by the static assets endpoints, clients must revalidate the files with the server which uses the
+// file modification times.
+const DefaultStaticAssetsCacheControl = "no-cache"
+
 var (
 	// Design being built by DSL.
 	Design *APIDefinition
@@ -150,6 +155,16 @@ var (
 		Views:      map[string]*ViewDefinition{"default": errorMediaView},
 	}
 
+	// BinaryType is the type of multipart response parts whose content is written as is, see
+	// PartDefinition.
+	BinaryType = &UserTypeDefinition{
+		AttributeDefinition: &AttributeDefinition{
+			Type:        String,
+			Description: "Binary content",
+		},
+		TypeName: "Binary",
+	}
+
 	errorMediaType = Object{
 		"id": &AttributeDefinition{
 			Type:        String,
@@ -51,6 +51,25 @@ func Files(path, filename string, dsls ...func()) {
 	}
 }
 
+// StaticAssets defines an endpoint that serves the files of the directory fsPath under the
+// urlPath request path prefix using http.FileServer:
+//
+//	var _ = Resource("ui", func() {
+//		StaticAssets("/ui", "public/ui") // Serves public/ui/app.js at /ui/app.js
+//	})
+//
+// Requests for a directory are served the index.html file of the directory if any. The responses
+// set the Cache-Control header to "no-cache" so that clients revalidate the files on each use.
+func StaticAssets(urlPath, fsPath string) {
+	if r, ok := resourceDefinition(); ok {
+		r.StaticAssets = append(r.StaticAssets, &design.StaticAssetsDefinition{
+			Parent:      r,
+			RequestPath: urlPath,
+			FilePath:    fsPath,
+		})
+	}
+}
+
 // Action implements the action definition DSL. Action definitions describe specific API endpoints
 // including the URL, HTTP method and request parameters (via path wildcards or query strings) and
 // payload (data structure describing the request HTTP body). An action belongs to a resource and
@@ -169,6 +188,232 @@ func PATCH(path string) *design.RouteDefinition {
 	return &design.RouteDefinition{Verb: "PATCH", Path: path}
 }
 
+// WebhookReceiver marks the action as a webhook receiver. The generated code verifies the
+// HMAC-SHA256 signature of the request body computed with secret against the value of the
+// sigHeader request header and responds with 403 if they do not match. Webhook receiver actions
+// must use POST routes:
+//
+//	Action("push", func() {
+//		Routing(POST("/hooks/push"))
+//		WebhookReceiver("X-Hub-Signature-256", "secret")
+//	})
+func WebhookReceiver(sigHeader, secret string) {
+	if a, ok := actionDefinition(); ok {
+		if sigHeader == "" {
+			dslengine.ReportError("webhook signature header name cannot be empty")
+			return
+		}
+		a.Webhook = &design.WebhookDefinition{SignatureHeader: sigHeader, Secret: secret}
+	}
+}
+
+// WebSocket marks the action as a WebSocket endpoint, it is equivalent to setting the action
+// scheme to "ws" with Scheme. The generated code upgrades the request connection using the
+// golang.org/x/net/websocket package and calls the controller action method with a context that
+// embeds the connection. WebSocket actions must use GET routes:
+//
+//	Action("echo", func() {
+//		Routing(GET("/echo"))
+//		WebSocket()
+//		Response(SwitchingProtocols)
+//	})
+func WebSocket() {
+	if a, ok := actionDefinition(); ok {
+		for _, s := range a.Schemes {
+			if s == "ws" || s == "wss" {
+				return
+			}
+		}
+		a.Schemes = append(a.Schemes, "ws")
+	}
+}
+
+// UnionResult causes the controller action method to return the action result instead of sending
+// the response. The generated code defines a result interface implemented by the media types of
+// the action success responses and sends the response whose media type matches the type of the
+// returned value. Returning a value of any other type is a compile error. The success responses
+// must use distinct media types:
+//
+//	Action("show", func() {
+//		Routing(GET("/:id"))
+//		UnionResult()
+//		Response(OK, BottleMedia)
+//		Response(Created, BoxMedia)
+//	})
+//
+// The controller method of the example above has the signature:
+//
+//	Show(*app.ShowBottleContext) (app.ShowBottleResult, error)
+//
+// A nil result indicates that the action already sent the response, e.g. using ctx.NotFound().
+func UnionResult() {
+	if a, ok := actionDefinition(); ok {
+		a.UnionResult = true
+	}
+}
+
+// LongPoll marks the action as a long-poll endpoint that streams its 200 response. The generated
+// action context exposes an OKChunk method that writes a chunk of data encoded in JSON and followed
+// by a newline and flushes the response so that the client receives it right away, and an OKDone
+// method that writes the {"done":true} sentinel line ending the stream. LongPoll actions must use
+// GET routes:
+//
+//	Action("watch", func() {
+//		Routing(GET("/:id/events"))
+//		LongPoll()
+//		Response(OK)
+//	})
+func LongPoll() {
+	if a, ok := actionDefinition(); ok {
+		a.LongPoll = true
+	}
+}
+
+// AcceptPatch lists the content types of the patch documents accepted by the PATCH routes of the
+// action. The generated code responds to the requests made with a method that the route path does
+// not allow with a 405 response whose Accept-Patch header lists the content types:
+//
+//	Action("update", func() {
+//		Routing(PATCH("/:id"))
+//		AcceptPatch("application/merge-patch+json")
+//		Payload(BottlePatch)
+//	})
+//
+// The content types default to the MIME types of the API decoders.
+func AcceptPatch(contentTypes ...string) {
+	if a, ok := actionDefinition(); ok {
+		a.PatchFormats = append(a.PatchFormats, contentTypes...)
+	}
+}
+
+// JSONMergePatch causes the PATCH action to accept JSON merge patch (RFC 7396) documents instead of
+// the complete payload. The action context Payload field holds the members of the patch document
+// indexed by name and the generated MergePatch method of the payload type applies the patch to an
+// existing value: the attributes absent from the patch keep their value and the attributes set to
+// null are removed. The payload validations apply to the result of the merge. The action must use
+// PATCH routes and define an object payload with Type or inline, media types are not supported:
+//
+//	Action("update", func() {
+//		Routing(PATCH("/:id"))
+//		JSONMergePatch()
+//		AcceptPatch("application/merge-patch+json")
+//		Payload(BottlePayload)
+//	})
+//
+// The controller method of the example above may apply the patch with:
+//
+//	bottle, err := existing.MergePatch(ctx.Payload)
+func JSONMergePatch() {
+	if a, ok := actionDefinition(); ok {
+		a.JSONMergePatch = true
+	}
+}
+
+// KeysetPage causes the sqlc generator to paginate the list query of the resource table using the
+// keyset pattern: the query returns the rows whose keyField column follows the last key of the
+// previous page in the given direction, "asc" or "desc", instead of skipping an offset number of
+// rows. keyField must be an attribute of the resource default media type:
+//
+//	Action("list", func() {
+//		Routing(GET(""))
+//		KeysetPage("id", "asc")
+//		Response(OK, CollectionOf(BottleMedia))
+//	})
+//
+// The generated query is:
+//
+//	SELECT * FROM bottles
+//	WHERE id > @last_key
+//	ORDER BY id
+//	LIMIT @limit;
+//
+// KeysetPage is a shortcut for Metadata("sql:keyset", keyField, direction).
+func KeysetPage(keyField, direction string) {
+	if a, ok := actionDefinition(); ok {
+		if direction != "asc" && direction != "desc" {
+			dslengine.ReportError(`invalid keyset page direction %#v, must be "asc" or "desc"`, direction)
+			return
+		}
+		if a.Metadata == nil {
+			a.Metadata = make(map[string][]string)
+		}
+		a.Metadata["sql:keyset"] = []string{keyField, direction}
+	}
+}
+
+// AuditLog causes the generated code to record an audit event each time the action completes
+// successfully. The event identifies the caller with the value of the actor request header and
+// the changed resource with the value of the resource parameter. It also contains the JSON
+// representations of the request payload before and after the action ran and the list of changed
+// fields. Audited actions must use state-changing routes:
+//
+//	Action("update", func() {
+//		Routing(PUT("/:id"))
+//		AuditLog("X-User-ID", "id")
+//		Payload(BottlePayload)
+//	})
+//
+// The events are given to the AuditHandler function of the generated app package which logs them
+// by default.
+func AuditLog(actor, resource string) {
+	if a, ok := actionDefinition(); ok {
+		if actor == "" {
+			dslengine.ReportError("audit actor header name cannot be empty")
+			return
+		}
+		a.Audit = &design.AuditDefinition{Actor: actor, Resource: resource}
+	}
+}
+
+// ContextKey describes a value injected in the request context by middleware, for example the
+// claims of an authentication token. The generated action contexts expose the value in a typed
+// field initialized from the request context. The app package defines the With<Name> function used
+// by middleware to inject the value and the Context<Name> function that retrieves it:
+//
+//	var _ = API("cellar", func() {
+//		ContextKey("userID", String)
+//	})
+//
+//	func Authenticate(h goa.Handler) goa.Handler {
+//		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+//			return h(app.WithUserID(ctx, subject(req)), rw, req)
+//		}
+//	}
+//
+// ContextKey can be used inside API, Resource or Action to expose the value in all the action
+// contexts, in the resource action contexts or in the action context only.
+func ContextKey(name string, typ design.DataType) {
+	var keys **design.AttributeDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		keys = &def.ContextKeys
+	case *design.ResourceDefinition:
+		keys = &def.ContextKeys
+	case *design.ActionDefinition:
+		keys = &def.ContextKeys
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if name == "" {
+		dslengine.ReportError("context key name cannot be empty")
+		return
+	}
+	if typ == nil {
+		dslengine.ReportError("context key %q type cannot be nil", name)
+		return
+	}
+	if *keys == nil {
+		*keys = &design.AttributeDefinition{Type: design.Object{}}
+	}
+	obj := (*keys).Type.ToObject()
+	index := len(obj)
+	if existing, ok := obj[name]; ok {
+		index = existing.Index
+	}
+	obj[name] = &design.AttributeDefinition{Type: typ, Index: index}
+}
+
 // Headers implements the DSL for describing HTTP headers. The DSL syntax is identical to the one
 // of Attribute. Here is an example defining a couple of headers with validations:
 //
@@ -332,6 +577,48 @@ func OptionalPayload(p interface{}, dsls ...func()) {
 	payload(true, p, dsls...)
 }
 
+// FormData implements the action multipart/form-data payload DSL. The DSL lists the form fields and
+// the file parts of the request body, file parts use the FileType type. The generated code parses
+// the multipart request body and initializes the payload fields with the form values and files.
+// Example:
+//
+//	Action("upload", func() {
+//		Routing(POST("/bottles/:id/label"))
+//		FormData(func() {
+//			Attribute("caption", String, "Label caption")
+//			Attribute("label", FileType, "Label image")
+//			Required("label")
+//		})
+//	})
+func FormData(dsl func()) {
+	if a, ok := actionDefinition(); ok {
+		payload(false, dsl)
+		a.FormData = true
+	}
+}
+
+// MultipartIngest implements the action batch ingest payload DSL. The action accepts
+// multipart/mixed request bodies where each part is a document of type partType encoded using the
+// part Content-Type header. The generated code decodes the parts in order and appends them to the
+// Parts field of the payload. Example:
+//
+//	Action("ingest", func() {
+//		Routing(POST("/documents"))
+//		MultipartIngest(Document)
+//		Response(NoContent)
+//	})
+//
+// The controller method of the example above receives the documents in ctx.Payload.Parts.
+func MultipartIngest(partType design.DataType) {
+	if a, ok := actionDefinition(); ok {
+		payload(false, func() {
+			Attribute("parts", ArrayOf(partType), "Documents read from the request body parts")
+			Required("parts")
+		})
+		a.MultipartIngest = true
+	}
+}
+
 func payload(isOptional bool, p interface{}, dsls ...func()) {
 	if len(dsls) > 1 {
 		dslengine.ReportError("too many arguments given to Payload")
@@ -356,6 +643,7 @@ func payload(isOptional bool, p interface{}, dsls ...func()) {
 			att = design.DupAtt(actual.Definition())
 		case *design.MediaTypeDefinition:
 			att = design.DupAtt(actual.AttributeDefinition)
+			a.PayloadMediaType = actual
 		case string:
 			ut, ok := design.Design.Types[actual]
 			if !ok {
@@ -73,6 +73,400 @@ var _ = Describe("Action", func() {
 
 	})
 
+	Context("with a webhook receiver", func() {
+		var route = POST("/hooks")
+
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(route)
+				WebhookReceiver("X-Hub-Signature-256", "secret")
+			}
+		})
+
+		It("records the signature verification", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.Webhook).Should(Equal(&WebhookDefinition{SignatureHeader: "X-Hub-Signature-256", Secret: "secret"}))
+		})
+
+		Context("using a GET route", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/hooks"))
+					WebhookReceiver("X-Hub-Signature-256", "secret")
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a WebSocket action", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/echo"))
+				WebSocket()
+				Response(SwitchingProtocols)
+			}
+		})
+
+		It("marks the action as a WebSocket action", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.Schemes).Should(Equal([]string{"ws"}))
+			Ω(action.WebSocket()).Should(BeTrue())
+		})
+
+		Context("using the wss scheme", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/echo"))
+					Scheme("wss")
+					WebSocket()
+				}
+			})
+
+			It("keeps the action scheme", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.Schemes).Should(Equal([]string{"wss"}))
+			})
+		})
+
+		Context("using a POST route", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(POST("/echo"))
+					WebSocket()
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a long-poll action", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/events"))
+				LongPoll()
+				Response(OK)
+			}
+		})
+
+		It("marks the action as a long-poll action", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.LongPoll).Should(BeTrue())
+		})
+
+		Context("using a POST route", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(POST("/events"))
+					LongPoll()
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with an audit log", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(PUT("/:id"))
+				AuditLog("X-User-ID", "id")
+			}
+		})
+
+		It("records the audit definition", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.Audit).Should(Equal(&AuditDefinition{Actor: "X-User-ID", Resource: "id"}))
+		})
+
+		Context("using a GET route", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					AuditLog("X-User-ID", "id")
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
+		Context("with an unknown resource parameter", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(PUT("/:id"))
+					AuditLog("X-User-ID", "bottleID")
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a context key", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				ContextKey("userID", String)
+			}
+		})
+
+		It("records the context key", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.ContextKeys).ShouldNot(BeNil())
+			Ω(action.ContextKeys.Type.ToObject()).Should(HaveKey("userID"))
+			Ω(action.AllContextKeys().Type.ToObject()["userID"].Type).Should(Equal(String))
+		})
+
+		Context("conflicting with a parameter", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:userID"))
+					ContextKey("userID", String)
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
+		Context("defined with a different type by the API", func() {
+			BeforeEach(func() {
+				API("test", func() {
+					ContextKey("userID", Integer)
+				})
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with accepted patch formats", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(PATCH("/:id"))
+				AcceptPatch("application/merge-patch+json", "application/json-patch+json")
+			}
+		})
+
+		It("records the patch formats", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.PatchFormats).Should(Equal([]string{"application/merge-patch+json", "application/json-patch+json"}))
+		})
+
+		Context("without a PATCH route", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(PUT("/:id"))
+					AcceptPatch("application/merge-patch+json")
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a JSON merge patch payload", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(PATCH("/:id"))
+				JSONMergePatch()
+				Payload(func() {
+					Attribute("name", String)
+				})
+			}
+		})
+
+		It("marks the action payload as a merge patch", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.JSONMergePatch).Should(BeTrue())
+		})
+
+		Context("without a PATCH route", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(PUT("/:id"))
+					JSONMergePatch()
+					Payload(func() {
+						Attribute("name", String)
+					})
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
+		Context("without a payload", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(PATCH("/:id"))
+					JSONMergePatch()
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a media type payload", func() {
+			BeforeEach(func() {
+				mt := MediaType("application/vnd.bottle", func() {
+					Attributes(func() {
+						Attribute("name", String)
+					})
+					View("default", func() {
+						Attribute("name")
+					})
+				})
+				dsl = func() {
+					Routing(PATCH("/:id"))
+					JSONMergePatch()
+					Payload(mt)
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("cannot be the media type application/vnd.bottle"))
+			})
+		})
+	})
+
+	Context("with a keyset page", func() {
+		var direction string
+
+		BeforeEach(func() {
+			name = "foo"
+			direction = "desc"
+			dsl = func() {
+				Routing(GET(""))
+				KeysetPage("createdAt", direction)
+			}
+		})
+
+		It("records the keyset metadata", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.Metadata["sql:keyset"]).Should(Equal([]string{"createdAt", "desc"}))
+		})
+
+		Context("with an invalid direction", func() {
+			BeforeEach(func() {
+				direction = "up"
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a union result", func() {
+		var bottle, box *MediaTypeDefinition
+
+		BeforeEach(func() {
+			name = "foo"
+			mediaType := func(id string) *MediaTypeDefinition {
+				return MediaType(id, func() {
+					Attributes(func() {
+						Attribute("name", String)
+					})
+					View("default", func() {
+						Attribute("name")
+					})
+				})
+			}
+			bottle = mediaType("application/vnd.goa.bottle")
+			box = mediaType("application/vnd.goa.box")
+			dsl = func() {
+				Routing(GET("/:id"))
+				UnionResult()
+				Response(OK, bottle)
+				Response(Accepted, box)
+				Response(NotFound)
+			}
+		})
+
+		It("marks the action as returning a union result", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.UnionResult).Should(BeTrue())
+		})
+
+		Context("with a single success response", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					UnionResult()
+					Response(OK, bottle)
+					Response(NotFound)
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
+		Context("with success responses using the same media type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					UnionResult()
+					Response(OK, bottle)
+					Response(Accepted, bottle)
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with typed path params", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id/:slug"))
+				Params(func() {
+					Param("id", Integer)
+				})
+			}
+		})
+
+		It("records the path param types on the route", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Routes).Should(HaveLen(1))
+			Ω(action.Routes[0].ParamTypes).Should(Equal(map[string]Kind{"id": IntegerKind, "slug": StringKind}))
+		})
+	})
+
 	Context("with a string payload", func() {
 		BeforeEach(func() {
 			name = "foo"
@@ -244,6 +638,86 @@ var _ = Describe("Payload", func() {
 		})
 	})
 
+	Context("with a form data definition", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					FormData(func() {
+						Attribute("caption", String)
+						Attribute("label", FileType)
+						Required("label")
+					})
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("generates the payload type and records the form data encoding", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			action := Design.Resources["foo"].Actions["bar"]
+			Ω(action.FormData).Should(BeTrue())
+			Ω(action.Payload).ShouldNot(BeNil())
+			Ω(action.Payload.Type.ToObject()).Should(HaveKey("caption"))
+			Ω(action.Payload.Type.ToObject()["label"].Type).Should(Equal(FileType))
+		})
+	})
+
+	Context("with a multipart ingest definition", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					MultipartIngest(String)
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("generates the payload type with the parts attribute", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			action := Design.Resources["foo"].Actions["bar"]
+			Ω(action.MultipartIngest).Should(BeTrue())
+			Ω(action.Payload).ShouldNot(BeNil())
+			Ω(action.Payload.Type.ToObject()).Should(HaveKey("parts"))
+			Ω(action.Payload.Type.ToObject()["parts"].Type.ToArray().ElemType.Type).Should(Equal(String))
+			Ω(action.Payload.IsRequired("parts")).Should(BeTrue())
+		})
+	})
+
+	Context("with a file attribute in a payload that is not form data", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					Payload(func() {
+						Attribute("label", FileType)
+					})
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with an array", func() {
 		BeforeEach(func() {
 			dslengine.Reset()
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
@@ -95,6 +97,62 @@ func Version(ver string) {
 	}
 }
 
+// Sunset marks the API version as deprecated and sets the date after which it is no longer
+// served. The generated handlers set the Deprecation and Sunset headers on all the responses.
+// After the sunset date requests are redirected with 301 Moved Permanently to redirectTo, the
+// base path or URL of the successor version, or get a 410 Gone response if redirectTo is empty:
+//
+//	var _ = API("cellar", func() {
+//		Version("1.0")
+//		BasePath("/v1")
+//		Sunset(time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), "/v2")
+//	})
+func Sunset(date time.Time, redirectTo string) {
+	if api, ok := apiDefinition(); ok {
+		api.Sunset = &design.SunsetDefinition{Date: date, RedirectTo: redirectTo}
+	}
+}
+
+// JWTAuth generates the NewJWTMiddleware function that validates the JSON Web Token sent in the
+// request Authorization header and copies the given claims into the request context. The token
+// must be signed with algorithm, e.g. "HS256". keyID is the identifier of the signing key, the
+// token "kid" header must match it unless keyID is empty. keyID is not the key itself, the key is
+// given to NewJWTMiddleware. Each claim is also defined as an API context key (see ContextKey) so
+// the action contexts expose the claim values. The claim types must be String, Integer, Number or
+// Boolean:
+//
+//	var _ = API("cellar", func() {
+//		JWTAuth("cellar-2026", "HS256", map[string]DataType{
+//			"sub":   String,
+//			"admin": Boolean,
+//		})
+//	})
+func JWTAuth(keyID, algorithm string, claims map[string]design.DataType) {
+	api, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	if algorithm == "" {
+		dslengine.ReportError("JWT signing algorithm cannot be empty")
+		return
+	}
+	names := make([]string, 0, len(claims))
+	for n, t := range claims {
+		switch t {
+		case design.String, design.Integer, design.Number, design.Boolean:
+		default:
+			dslengine.ReportError("invalid type for JWT claim %q, must be String, Integer, Number or Boolean", n)
+			return
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		ContextKey(n, claims[n])
+	}
+	api.JWTAuth = &design.JWTAuthDefinition{KeyID: keyID, Algorithm: algorithm, Claims: names}
+}
+
 // Description sets the definition description.
 // Description can be called inside API, Resource, Action or MediaType.
 func Description(d string) {
@@ -419,6 +477,32 @@ func Function(fn string) {
 	}
 }
 
+// Compression sets the algorithms used to compress the responses encoded with the encoder. It must
+// be used inside a Produces DSL. The supported algorithms are "gzip", "br" (Brotli) and "zstd"
+// (Zstandard). The generated code negotiates the algorithm with the request Accept-Encoding header,
+// algorithms listed first are preferred:
+//
+//	Produces("application/json", func() {
+//		Compression("br", "gzip")
+//	})
+//
+func Compression(algs ...string) {
+	if e, ok := encodingDefinition(); ok {
+		if !e.Encoder {
+			dslengine.ReportError("Compression may only be used in Produces")
+			return
+		}
+		for _, alg := range algs {
+			switch alg {
+			case "gzip", "br", "zstd":
+				e.Compression = append(e.Compression, alg)
+			default:
+				dslengine.ReportError(`invalid compression algorithm %#v, must be one of "gzip", "br" or "zstd"`, alg)
+			}
+		}
+	}
+}
+
 // ResponseTemplate defines a response template that action definitions can use to describe their
 // responses. The template may specify the HTTP response status, header specification and body media
 // type. The template consists of a name and an anonymous function. The function is called when an
@@ -529,6 +613,77 @@ func Title(val string) {
 	}
 }
 
+// WireInject causes the main generator to produce a wire.go file containing google/wire
+// (https://github.com/google/wire) injectors for the controller constructors. The file is only
+// compiled with the wireinject build tag, a companion wire_gen.go file provides placeholder
+// implementations until the wire tool is run:
+//
+//	var _ = API("cellar", func() {
+//		WireInject()
+//	})
+func WireInject() {
+	if a, ok := apiDefinition(); ok {
+		a.WireInject = true
+	}
+}
+
+// LoadBalanced causes the client generator to produce a NewGateway function that creates a
+// client Doer distributing the requests across multiple servers. The strategy is either
+// "round-robin" or "random":
+//
+//	var _ = API("cellar", func() {
+//		LoadBalanced("round-robin")
+//	})
+func LoadBalanced(strategy string) {
+	if strategy != "round-robin" && strategy != "random" {
+		dslengine.ReportError(`invalid load balancing strategy "%s", must be one of "round-robin" or "random"`, strategy)
+		return
+	}
+	if a, ok := apiDefinition(); ok {
+		a.LoadBalancing = strategy
+	}
+}
+
+// Debug causes the application generator to produce a debug.go file that is only compiled with
+// the "debug" build tag. The file defines the RegisterDebugHandlers function which attaches the
+// net/http/pprof handlers to a http.ServeMux and mounts these handlers under "/debug/pprof/" on
+// the service so that profiling endpoints never appear in production builds:
+//
+//	var _ = API("cellar", func() {
+//		Debug()
+//	})
+//
+// Build with "go build -tags debug" to enable the profiling endpoints.
+func Debug() {
+	if a, ok := apiDefinition(); ok {
+		a.Debug = true
+	}
+}
+
+// Requires declares an external service such as a database that the API depends on and the Docker
+// image that provides it. The testcontainers generator uses the declarations to produce a TestMain
+// function that starts the corresponding containers before running the integration tests. The
+// service is one of "postgres", "mysql", "redis" or "mongo":
+//
+//	var _ = API("cellar", func() {
+//		Requires("postgres", "postgres:16-alpine")
+//		Requires("redis", "redis:7")
+//	})
+//
+// Requires sets the "testcontainers:requires" metadata of the API.
+func Requires(service, image string) {
+	if service == "" || image == "" {
+		dslengine.ReportError("Requires service and image must not be empty")
+		return
+	}
+	if a, ok := apiDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(map[string][]string)
+		}
+		a.Metadata["testcontainers:requires"] = append(a.Metadata["testcontainers:requires"], service+"="+image)
+	}
+}
+
 // Trait defines an API trait. A trait encapsulates arbitrary DSL that gets executed wherever the
 // trait is called via the UseTrait function.
 func Trait(name string, val ...func()) {
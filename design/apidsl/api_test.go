@@ -1,6 +1,8 @@
 package apidsl_test
 
 import (
+	"time"
+
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
@@ -56,6 +58,60 @@ var _ = Describe("API", func() {
 		})
 	})
 
+	Context("with an invalid compression algorithm", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Produces("application/json", func() {
+					Compression("lzma")
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an invalid load balancing strategy", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				LoadBalanced("fastest")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a sunset redirecting to an invalid location", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Sunset(time.Now(), "v2")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a JWT claim of invalid type", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				JWTAuth("", "HS256", map[string]DataType{"roles": &Array{ElemType: &AttributeDefinition{Type: String}}})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with valid DSL", func() {
 		JustBeforeEach(func() {
 			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
@@ -118,6 +174,81 @@ var _ = Describe("API", func() {
 			})
 		})
 
+		Context("with a load balancing strategy", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					LoadBalanced("random")
+				}
+			})
+
+			It("sets the API load balancing strategy", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.LoadBalancing).Should(Equal("random"))
+			})
+		})
+
+		Context("with debug enabled", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Debug()
+				}
+			})
+
+			It("enables the profiling handlers", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.Debug).Should(BeTrue())
+			})
+		})
+
+		Context("with a sunset", func() {
+			var date = time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+			BeforeEach(func() {
+				dsl = func() {
+					Sunset(date, "/v2")
+				}
+			})
+
+			It("sets the API sunset", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.Sunset).ShouldNot(BeNil())
+				Ω(Design.Sunset.Date).Should(Equal(date))
+				Ω(Design.Sunset.RedirectTo).Should(Equal("/v2"))
+			})
+		})
+
+		Context("with JWT authentication", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					JWTAuth("cellar-2026", "HS256", map[string]DataType{"sub": String, "level": Integer})
+				}
+			})
+
+			It("sets the JWT middleware and defines the claims context keys", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.JWTAuth).ShouldNot(BeNil())
+				Ω(Design.JWTAuth.KeyID).Should(Equal("cellar-2026"))
+				Ω(Design.JWTAuth.Algorithm).Should(Equal("HS256"))
+				Ω(Design.JWTAuth.Claims).Should(Equal([]string{"level", "sub"}))
+				keys := Design.ContextKeys.Type.ToObject()
+				Ω(keys).Should(HaveLen(2))
+				Ω(keys["level"].Type).Should(Equal(Integer))
+			})
+		})
+
+		Context("with required services", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Requires("postgres", "postgres:16-alpine")
+					Requires("redis", "redis:7")
+				}
+			})
+
+			It("sets the testcontainers metadata", func() {
+				Ω(Design.Metadata["testcontainers:requires"]).Should(Equal([]string{"postgres=postgres:16-alpine", "redis=redis:7"}))
+			})
+		})
+
 		Context("with contact information", func() {
 			const contactName = "contactName"
 			const contactEmail = "contactEmail"
@@ -200,6 +331,21 @@ var _ = Describe("API", func() {
 			})
 		})
 
+		Context("with compression", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Produces("application/json", func() {
+						Compression("br", "gzip")
+					})
+				}
+			})
+
+			It("sets the encoder compression algorithms", func() {
+				Ω(Design.Produces).Should(HaveLen(1))
+				Ω(Design.Produces[0].Compression).Should(Equal([]string{"br", "gzip"}))
+			})
+		})
+
 		Context("with a BasePath", func() {
 			const basePath = "basePath"
 
@@ -2,6 +2,7 @@ package apidsl
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -132,6 +133,16 @@ func Attribute(name string, args ...interface{}) {
 			}
 		}
 		baseAttr.Reference = parent.Reference
+		if baseAttr.Type != nil && baseAttr.Type.Kind().IsUnsigned() {
+			// Unsigned integers cannot be negative, the DSL may override the minimum.
+			if baseAttr.Validation == nil {
+				baseAttr.Validation = &dslengine.ValidationDefinition{}
+			}
+			if baseAttr.Validation.Minimum == nil {
+				min := 0.0
+				baseAttr.Validation.Minimum = &min
+			}
+		}
 		if dsl != nil {
 			dslengine.Execute(dsl, baseAttr)
 		}
@@ -139,7 +150,13 @@ func Attribute(name string, args ...interface{}) {
 			// DSL did not contain an "Attribute" declaration
 			baseAttr.Type = design.String
 		}
-		parent.Type.(design.Object)[name] = baseAttr
+		obj := parent.Type.(design.Object)
+		if existing, ok := obj[name]; ok {
+			baseAttr.Index = existing.Index
+		} else {
+			baseAttr.Index = len(obj)
+		}
+		obj[name] = baseAttr
 	}
 }
 
@@ -277,6 +294,122 @@ func Example(exp interface{}) {
 	}
 }
 
+// Nullable marks the attribute as accepting explicit null values. The Go struct fields generated
+// for nullable attributes are always pointers - even for required attributes of primitive types -
+// and their JSON tags omit "omitempty" so that nil values get serialized as null:
+//
+//	Attribute("count", Integer, func() {
+//		Nullable()
+//	})
+//
+// Required nullable attributes must be present in JSON requests but may be set to null.
+//
+// Nullable is a shortcut for Metadata("struct:field:nullable").
+func Nullable() {
+	Metadata("struct:field:nullable")
+}
+
+// FreeForm marks an object attribute as holding arbitrary key-value pairs. The Go struct fields
+// generated for free-form attributes are of type map[string]json.RawMessage so that the values
+// are kept as is when decoding and encoding and are not validated:
+//
+//	Attribute("metadata", func() {
+//		FreeForm()
+//	})
+//
+// FreeForm is a shortcut for Metadata("struct:field:freeform").
+func FreeForm() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type == nil {
+			a.Type = make(design.Object)
+		} else if !a.Type.IsObject() {
+			dslengine.ReportError("free-form attribute must be an object, got %s", a.Type.Name())
+			return
+		}
+		Metadata("struct:field:freeform")
+	}
+}
+
+// TZ sets the time zone used to normalize the values of a DateTime attribute. The generated code
+// converts the values parsed from request params and headers to UTC by default. Use "local" to
+// convert them to the local time zone of the service instead:
+//
+//	Param("since", DateTime, func() {
+//		TZ("local")
+//	})
+//
+// TZ is a shortcut for Metadata("datetime:tz", zone).
+func TZ(zone string) {
+	if a, ok := attributeDefinition(); ok {
+		if zone != "UTC" && zone != "local" {
+			dslengine.ReportError(`invalid time zone %#v, must be "UTC" or "local"`, zone)
+			return
+		}
+		if a.Type == nil || a.Type.Kind() != design.DateTimeKind {
+			dslengine.ReportError("TZ may only be used with DateTime attributes")
+			return
+		}
+		delete(a.Metadata, "datetime:tz")
+		Metadata("datetime:tz", zone)
+	}
+}
+
+// AnyOfTypes restricts the values of an Any param or header to the given types. The generated code
+// coerces the raw value to each type in order and keeps the first successful coercion, it returns
+// an error if no type matches:
+//
+//	Param("filter", Any, func() {
+//		AnyOfTypes(IntegerKind, StringKind)
+//	})
+//
+// The accepted kinds are BooleanKind, IntegerKind, NumberKind and StringKind. The generated
+// contexts provide MustXxxAsYyy helper functions that return the value as one of the types.
+// AnyOfTypes is a shortcut for Metadata("anyof:types", names...).
+func AnyOfTypes(kinds ...design.Kind) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type == nil || a.Type.Kind() != design.AnyKind {
+			dslengine.ReportError("AnyOfTypes may only be used with Any attributes")
+			return
+		}
+		if len(kinds) == 0 {
+			dslengine.ReportError("AnyOfTypes requires at least one kind")
+			return
+		}
+		names := make([]string, len(kinds))
+		for i, k := range kinds {
+			switch k {
+			case design.BooleanKind, design.IntegerKind, design.NumberKind, design.StringKind:
+				names[i] = design.Primitive(k).Name()
+			default:
+				dslengine.ReportError("invalid AnyOfTypes kind %d, must be BooleanKind, IntegerKind, NumberKind or StringKind", k)
+				return
+			}
+		}
+		delete(a.Metadata, "anyof:types")
+		Metadata("anyof:types", names...)
+	}
+}
+
+// VisibleTo restricts the roles allowed to see the attribute in response bodies. The response
+// helpers generated for media types that define such attributes have a "Filtered" variant that
+// removes the attributes not visible to any of the given roles before sending the response:
+//
+//	Attribute("internal_notes", String, func() {
+//		VisibleTo("admin", "support")
+//	})
+//
+// Filtered attributes must be optional, they may not be required, have a default value or be
+// nullable. VisibleTo is a shortcut for Metadata("visible:roles", roles...).
+func VisibleTo(roles ...string) {
+	if _, ok := attributeDefinition(); ok {
+		if len(roles) == 0 {
+			dslengine.ReportError("VisibleTo requires at least one role")
+			return
+		}
+		Metadata("visible:roles", roles...)
+	}
+}
+
 // NoExample sets the example of an attribute to be blank for the documentation. It is used when
 // users don't want any custom or auto-generated example
 func NoExample() {
@@ -331,6 +464,7 @@ func Enum(val ...interface{}) {
 var SupportedValidationFormats = []string{
 	"cidr",
 	"date-time",
+	"e164",
 	"email",
 	"hostname",
 	"ipv4",
@@ -360,6 +494,8 @@ var SupportedValidationFormats = []string{
 // "cidr": RFC4632 or RFC4291 CIDR notation IP address
 //
 // "regexp": RE2 regular expression
+//
+// "e164": ITU-T E.164 international phone number, e.g. "+12125551234"
 func Format(f string) {
 	if a, ok := attributeDefinition(); ok {
 		if a.Type != nil && a.Type.Kind() != design.StringKind {
@@ -407,9 +543,17 @@ func Pattern(p string) {
 
 // Minimum adds a "minimum" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor21.
+// The minimum of BigIntType attributes may be given as a *big.Int, an integer or a decimal string.
 func Minimum(val interface{}) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
+		if a.Type != nil && a.Type.Kind() == design.BigIntKind {
+			if b, ok := bigIntValue(val); ok {
+				if a.Validation == nil {
+					a.Validation = &dslengine.ValidationDefinition{}
+				}
+				a.Validation.BigMinimum = b
+			}
+		} else if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind && !a.Type.Kind().IsUnsigned() {
 			incompatibleAttributeType("minimum", a.Type.Name(), "an integer or a number")
 		} else {
 			var f float64
@@ -437,9 +581,17 @@ func Minimum(val interface{}) {
 
 // Maximum adds a "maximum" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor17.
+// The maximum of BigIntType attributes may be given as a *big.Int, an integer or a decimal string.
 func Maximum(val interface{}) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
+		if a.Type != nil && a.Type.Kind() == design.BigIntKind {
+			if b, ok := bigIntValue(val); ok {
+				if a.Validation == nil {
+					a.Validation = &dslengine.ValidationDefinition{}
+				}
+				a.Validation.BigMaximum = b
+			}
+		} else if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind && !a.Type.Kind().IsUnsigned() {
 			incompatibleAttributeType("maximum", a.Type.Name(), "an integer or a number")
 		} else {
 			var f float64
@@ -465,6 +617,25 @@ func Maximum(val interface{}) {
 	}
 }
 
+// bigIntValue converts the minimum or maximum of a BigIntType attribute to a *big.Int, it reports
+// an error if val is not an integer.
+func bigIntValue(val interface{}) (*big.Int, bool) {
+	switch v := val.(type) {
+	case *big.Int:
+		return new(big.Int).Set(v), true
+	case int, int8, int16, int32, int64:
+		return big.NewInt(reflect.ValueOf(v).Int()), true
+	case uint, uint8, uint16, uint32, uint64:
+		return new(big.Int).SetUint64(reflect.ValueOf(v).Uint()), true
+	case string:
+		if b, ok := new(big.Int).SetString(v, 10); ok {
+			return b, true
+		}
+	}
+	dslengine.ReportError("invalid big integer value %#v", val)
+	return nil, false
+}
+
 // MinLength adss a "minItems" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor45.
 func MinLength(val int) {
@@ -495,6 +666,111 @@ func MaxLength(val int) {
 	}
 }
 
+// IPVersion restricts the values of an IP attribute to IPv4 or IPv6 addresses:
+//
+//	Attribute("gateway", IP, func() {
+//		IPVersion(4)
+//	})
+//
+// The only valid values are 4 and 6.
+func IPVersion(version int) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.IPKind {
+			incompatibleAttributeType("IP version", qualifiedTypeName(a.Type), "an IP")
+		} else if version != 4 && version != 6 {
+			dslengine.ReportError("invalid IP version %d, must be 4 or 6", version)
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.IPVersion = version
+		}
+	}
+}
+
+// BoundingBox restricts the values of a LatLon attribute to the area delimited by the given
+// latitudes and longitudes expressed in decimal degrees:
+//
+//	Attribute("location", LatLon, func() {
+//		BoundingBox(45.8, 47.8, 5.9, 10.5)
+//	})
+func BoundingBox(minLat, maxLat, minLon, maxLon float64) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.LatLonKind {
+			incompatibleAttributeType("bounding box", qualifiedTypeName(a.Type), "a latlon")
+		} else if minLat < -90 || maxLat > 90 || minLat > maxLat {
+			dslengine.ReportError("invalid bounding box latitudes [%v, %v]", minLat, maxLat)
+		} else if minLon < -180 || maxLon > 180 || minLon > maxLon {
+			dslengine.ReportError("invalid bounding box longitudes [%v, %v]", minLon, maxLon)
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.BoundingBox = &dslengine.BoundingBoxDefinition{
+				MinLat: minLat,
+				MaxLat: maxLat,
+				MinLon: minLon,
+				MaxLon: maxLon,
+			}
+		}
+	}
+}
+
+// MaxDepth limits the nesting of the values of an object, array or map attribute. The attribute
+// value counts as one level and each nested object, array or map adds a level:
+//
+//	Attribute("filter", func() {
+//		MaxDepth(2)
+//		Attribute("tags", ArrayOf(String))
+//		Attribute("range", Range)
+//	})
+//
+// MaxDepth protects the services from deeply nested user input.
+func MaxDepth(n int) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && !a.Type.IsObject() && !a.Type.IsArray() && !a.Type.IsHash() {
+			incompatibleAttributeType("max depth", qualifiedTypeName(a.Type), "an object, an array or a map")
+		} else if n < 1 {
+			dslengine.ReportError("invalid max depth %d, must be greater than 0", n)
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.MaxDepth = n
+		}
+	}
+}
+
+// Discriminate defines a polymorphic payload whose concrete type is selected by the value of the
+// given field. mapping lists the user types corresponding to each field value:
+//
+//	var Pet = Type("Pet", func() {
+//		Attribute("type", String)
+//		Discriminate("type", map[string]DataType{"dog": Dog, "cat": Cat})
+//	})
+//
+// The generated code decodes the request bodies of actions using the type as payload into the
+// user type matching the field value and returns an error if the value is unknown.
+func Discriminate(field string, mapping map[string]design.DataType) {
+	if a, ok := attributeDefinition(); ok {
+		if field == "" {
+			dslengine.ReportError("discriminator field name cannot be empty")
+			return
+		}
+		if len(mapping) == 0 {
+			dslengine.ReportError("discriminator mapping cannot be empty")
+			return
+		}
+		for v, t := range mapping {
+			if _, ok := t.(*design.UserTypeDefinition); !ok {
+				dslengine.ReportError("invalid type for discriminator value %#v, must be a user type", v)
+				return
+			}
+		}
+		a.Discriminator = &design.DiscriminatorDefinition{Field: field, Mapping: mapping}
+	}
+}
+
 // Required adds a "required" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor61.
 func Required(names ...string) {
@@ -533,6 +809,24 @@ func qualifiedTypeName(t design.DataType) string {
 	switch t.Kind() {
 	case design.DateTimeKind:
 		return "datetime"
+	case design.IPKind:
+		return "ip"
+	case design.CIDRKind:
+		return "cidr"
+	case design.LatLonKind:
+		return "latlon"
+	case design.SemVerKind:
+		return "semver"
+	case design.UintKind:
+		return "uint"
+	case design.Uint8Kind:
+		return "uint8"
+	case design.Uint16Kind:
+		return "uint16"
+	case design.Uint32Kind:
+		return "uint32"
+	case design.BigIntKind:
+		return "bigint"
 	case design.ArrayKind:
 		return fmt.Sprintf("%s<%s>", t.Name(), qualifiedTypeName(t.ToArray().ElemType.Type))
 	case design.HashKind:
@@ -1,6 +1,8 @@
 package apidsl_test
 
 import (
+	"math/big"
+
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
@@ -124,6 +126,311 @@ var _ = Describe("Attribute", func() {
 		})
 	})
 
+	Context("with a nullable attribute", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = Integer
+			dsl = func() {
+				Nullable()
+			}
+		})
+
+		It("marks the attribute as nullable", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].IsNullable()).Should(BeTrue())
+			Ω(parent.IsPrimitivePointer(name)).Should(BeTrue())
+		})
+	})
+
+	Context("with a free-form attribute", func() {
+		BeforeEach(func() {
+			name = "metadata"
+			dsl = func() {
+				FreeForm()
+			}
+		})
+
+		It("marks the attribute as a free-form object", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].Type).Should(BeAssignableToTypeOf(Object{}))
+			Ω(o[name].IsFreeForm()).Should(BeTrue())
+		})
+	})
+
+	Context("with a free-form string attribute", func() {
+		BeforeEach(func() {
+			name = "metadata"
+			dataType = String
+			dsl = func() {
+				FreeForm()
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an attribute visible to specific roles", func() {
+		BeforeEach(func() {
+			name = "internal_notes"
+			dataType = String
+			dsl = func() {
+				VisibleTo("admin", "support")
+			}
+		})
+
+		It("records the roles", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o[name].VisibleTo()).Should(Equal([]string{"admin", "support"}))
+		})
+	})
+
+	Context("with VisibleTo and no role", func() {
+		BeforeEach(func() {
+			name = "internal_notes"
+			dataType = String
+			dsl = func() {
+				VisibleTo()
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a datetime attribute and no time zone", func() {
+		BeforeEach(func() {
+			name = "since"
+			dataType = DateTime
+		})
+
+		It("uses UTC", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o[name].TimeZone()).Should(Equal("UTC"))
+		})
+	})
+
+	Context("with a datetime attribute using the local time zone", func() {
+		BeforeEach(func() {
+			name = "since"
+			dataType = DateTime
+			dsl = func() {
+				TZ("local")
+			}
+		})
+
+		It("sets the time zone", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o[name].TimeZone()).Should(Equal("local"))
+		})
+	})
+
+	Context("with an invalid time zone", func() {
+		BeforeEach(func() {
+			name = "since"
+			dataType = DateTime
+			dsl = func() {
+				TZ("Europe/Paris")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a time zone on a string attribute", func() {
+		BeforeEach(func() {
+			name = "since"
+			dataType = String
+			dsl = func() {
+				TZ("UTC")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an any attribute restricted to several types", func() {
+		BeforeEach(func() {
+			name = "filter"
+			dataType = Any
+			dsl = func() {
+				AnyOfTypes(StringKind, IntegerKind)
+			}
+		})
+
+		It("sets the types in order", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o[name].AnyOfTypes()).Should(Equal([]Primitive{String, Integer}))
+		})
+	})
+
+	Context("with an invalid any of kind", func() {
+		BeforeEach(func() {
+			name = "filter"
+			dataType = Any
+			dsl = func() {
+				AnyOfTypes(IntegerKind, DateTimeKind)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with any of types on a string attribute", func() {
+		BeforeEach(func() {
+			name = "filter"
+			dataType = String
+			dsl = func() {
+				AnyOfTypes(IntegerKind)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an IP attribute and an IP version validation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = IP
+			dsl = func() {
+				IPVersion(6)
+			}
+		})
+
+		It("records the validation", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o[name].Type).Should(Equal(IP))
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(o[name].Validation.IPVersion).Should(Equal(6))
+		})
+	})
+
+	Context("with an invalid IP version", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = IP
+			dsl = func() {
+				IPVersion(5)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an IP version validation on a string attribute", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = String
+			dsl = func() {
+				IPVersion(4)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a LatLon attribute and a bounding box validation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = LatLon
+			dsl = func() {
+				BoundingBox(45.8, 47.8, 5.9, 10.5)
+			}
+		})
+
+		It("records the validation", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o[name].Type).Should(Equal(LatLon))
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(o[name].Validation.BoundingBox).Should(Equal(&dslengine.BoundingBoxDefinition{
+				MinLat: 45.8, MaxLat: 47.8, MinLon: 5.9, MaxLon: 10.5,
+			}))
+		})
+	})
+
+	Context("with an out of range bounding box", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = LatLon
+			dsl = func() {
+				BoundingBox(-95, 47.8, 5.9, 10.5)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a bounding box validation on a string attribute", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = String
+			dsl = func() {
+				BoundingBox(45.8, 47.8, 5.9, 10.5)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an array attribute and a max depth validation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = ArrayOf(ArrayOf(String))
+			dsl = func() {
+				MaxDepth(2)
+			}
+		})
+
+		It("records the validation", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(o[name].Validation.MaxDepth).Should(Equal(2))
+		})
+	})
+
+	Context("with a max depth validation on a string attribute", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = String
+			dsl = func() {
+				MaxDepth(2)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with a name and datatype", func() {
 		BeforeEach(func() {
 			name = "foo"
@@ -253,6 +560,68 @@ var _ = Describe("Attribute", func() {
 		})
 	})
 
+	Context("with a name and an unsigned integer type", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = Uint16Type
+		})
+
+		It("produces an attribute with an implicit minimum of 0", func() {
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].Type).Should(Equal(Uint16Type))
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(o[name].Validation.Minimum).ShouldNot(BeNil())
+			Ω(*o[name].Validation.Minimum).Should(Equal(0.0))
+		})
+	})
+
+	Context("with a name, an unsigned integer type and a DSL defining a minimum", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = UintType
+			dsl = func() { Minimum(10) }
+		})
+
+		It("keeps the minimum defined by the DSL", func() {
+			o := parent.Type.(Object)
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(*o[name].Validation.Minimum).Should(Equal(10.0))
+		})
+	})
+
+	Context("with a name, a big integer type and a DSL defining a minimum and a maximum", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = BigIntType
+			dsl = func() {
+				Minimum(new(big.Int).Lsh(big.NewInt(1), 64))
+				Maximum("100000000000000000000")
+			}
+		})
+
+		It("produces an attribute with big integer bounds", func() {
+			o := parent.Type.(Object)
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(o[name].Validation.Minimum).Should(BeNil())
+			Ω(o[name].Validation.BigMinimum.String()).Should(Equal("18446744073709551616"))
+			Ω(o[name].Validation.BigMaximum.String()).Should(Equal("100000000000000000000"))
+		})
+	})
+
+	Context("with a name, a big integer type and a DSL defining an invalid minimum", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = BigIntType
+			dsl = func() { Minimum("1.5") }
+		})
+
+		It("records an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with a name, type integer, a description and a DSL defining an enum validation", func() {
 		BeforeEach(func() {
 			name = "foo"
@@ -381,3 +750,58 @@ var _ = Describe("Attribute", func() {
 		})
 	})
 })
+
+var _ = Describe("Discriminate", func() {
+	var mapping func(dog, cat *UserTypeDefinition) map[string]DataType
+	var pet *UserTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		mapping = nil
+	})
+
+	JustBeforeEach(func() {
+		Dog := Type("Dog", func() {
+			Attribute("type", String)
+			Attribute("barks", Boolean)
+		})
+		Cat := Type("Cat", func() {
+			Attribute("type", String)
+			Attribute("lives", Integer)
+		})
+		pet = Type("Pet", func() {
+			Attribute("type", String)
+			Discriminate("type", mapping(Dog, Cat))
+		})
+		dslengine.Run()
+	})
+
+	Context("with user types", func() {
+		BeforeEach(func() {
+			mapping = func(dog, cat *UserTypeDefinition) map[string]DataType {
+				return map[string]DataType{"dog": dog, "cat": cat}
+			}
+		})
+
+		It("records the discriminator", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(pet.Discriminator).ShouldNot(BeNil())
+			Ω(pet.Discriminator.Field).Should(Equal("type"))
+			Ω(pet.Discriminator.Mapping).Should(HaveLen(2))
+			Ω(pet.Discriminator.Mapping["dog"].(*UserTypeDefinition).TypeName).Should(Equal("Dog"))
+			Ω(pet.Discriminator.Mapping["cat"].(*UserTypeDefinition).TypeName).Should(Equal("Cat"))
+		})
+	})
+
+	Context("with a primitive type", func() {
+		BeforeEach(func() {
+			mapping = func(dog, _ *UserTypeDefinition) map[string]DataType {
+				return map[string]DataType{"dog": dog, "cat": String}
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -110,6 +110,25 @@ var _ = Describe("MediaType", func() {
 		})
 	})
 
+	Context("with a table", func() {
+		BeforeEach(func() {
+			name = "application/foo"
+			dslFunc = func() {
+				Table("foos")
+				Attributes(func() {
+					Attribute("attName")
+				})
+				View("default", func() { Attribute("attName") })
+			}
+		})
+
+		It("sets the sql:table metadata", func() {
+			Ω(mt).ShouldNot(BeNil())
+			Ω(mt.Validate()).ShouldNot(HaveOccurred())
+			Ω(mt.Metadata).Should(HaveKeyWithValue("sql:table", []string{"foos"}))
+		})
+	})
+
 	Context("with links", func() {
 		const linkName = "link"
 		var link1Name, link2Name string
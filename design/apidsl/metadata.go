@@ -18,6 +18,30 @@ import (
 //
 //        Metadata("struct:field:name", "MyName")
 //
+// `struct:field:nullable`: makes the generated Go struct field a pointer and removes "omitempty"
+// from its default tags so that nil values are serialized as null, see Nullable.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:nullable")
+//
+// `struct:field:freeform`: generates a map[string]json.RawMessage field for an object attribute
+// holding arbitrary key-value pairs and skips its validation, see FreeForm.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:freeform")
+//
+// `datetime:tz`: sets the time zone used to normalize the DateTime values parsed from request
+// params and headers, "UTC" (default) or "local", see TZ.
+// Applicable to attributes only.
+//
+//        Metadata("datetime:tz", "local")
+//
+// `visible:roles`: restricts the roles allowed to see the attribute in the response bodies sent
+// with the generated "Filtered" response helpers, see VisibleTo.
+// Applicable to attributes only.
+//
+//        Metadata("visible:roles", "admin")
+//
 // `struct:tag:xxx`: sets the struct field tag xxx on generated Go structs.  Overrides tags that
 // goagen would otherwise set.  If the metadata value is a slice then the strings are joined with
 // the space character as separator.
@@ -39,6 +63,18 @@ import (
 //
 //        Metadata("swagger:summary", "Short summary of what action does")
 //
+// `sql:type`: implements the database/sql Scanner and database/sql/driver Valuer interfaces on
+// the generated user type storing values as JSON in columns of the given type, see SQLType.
+// Applicable to user types only.
+//
+//        Metadata("sql:type", "jsonb")
+//
+// `testcontainers:requires`: declares the external services started by the TestMain function
+// produced by the testcontainers generator as "service=image" values, see Requires.
+// Applicable to API definitions only.
+//
+//        Metadata("testcontainers:requires", "postgres=postgres:16-alpine")
+//
 // The special key names listed above may be used as follows:
 //
 //        var Account = Type("Account", func() {
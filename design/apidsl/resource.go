@@ -120,3 +120,61 @@ func CanonicalActionName(a string) {
 		r.CanonicalActionName = a
 	}
 }
+
+// OptionalSegment marks a segment of the canonical action path as optional. The generated resource
+// href function only includes optional segments when the parameters they contain are given, for
+// example with a canonical path of "/orgs/:orgID/repos/:id":
+//
+//	Resource("repo", func() {
+//		OptionalSegment("/orgs/:orgID")
+//		Action("show", func() {
+//			Routing(GET("/orgs/:orgID/repos/:id"))
+//		})
+//	})
+//
+// produces a RepoHref function returning "/repos/1" when called with RepoHref(1) and
+// "/orgs/2/repos/1" when called with RepoHref(1, map[string]interface{}{"orgID": 2}).
+// OptionalSegment may appear more than once.
+func OptionalSegment(segment string) {
+	if r, ok := resourceDefinition(); ok {
+		r.OptionalSegments = append(r.OptionalSegments, segment)
+	}
+}
+
+// Table sets the name of the database table that stores the resource or media type. The sqlc
+// generator uses the table name and the resource default media type attributes to produce the SQL
+// queries that implement the resource CRUD operations:
+//
+//	Resource("bottle", func() {
+//		DefaultMedia(BottleMedia)
+//		Table("bottles")
+//	})
+//
+// The sqlmigration generator uses the table name and the attributes of the media types that use
+// Table to produce the migrations that create the tables:
+//
+//	var BottleMedia = MediaType("application/vnd.bottle+json", func() {
+//		Table("bottles")
+//		Attributes(func() {
+//			Attribute("id", Integer)
+//			Attribute("name", String)
+//		})
+//	})
+//
+// Table is a shortcut for Metadata("sql:table", name).
+func Table(name string) {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ResourceDefinition:
+		if def.Metadata == nil {
+			def.Metadata = make(map[string][]string)
+		}
+		def.Metadata["sql:table"] = []string{name}
+	case *design.MediaTypeDefinition:
+		if def.Metadata == nil {
+			def.Metadata = make(map[string][]string)
+		}
+		def.Metadata["sql:table"] = []string{name}
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
@@ -131,6 +131,39 @@ var _ = Describe("Resource", func() {
 		})
 	})
 
+	Context("with an optional segment", func() {
+		const segment = "/orgs/:orgID"
+
+		BeforeEach(func() {
+			name = "repo"
+			dsl = func() {
+				OptionalSegment(segment)
+				Action("show", func() { Routing(GET("/orgs/:orgID/repos/:id")) })
+			}
+		})
+
+		It("sets the optional segments and produces a valid resource definition", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.OptionalSegments).Should(Equal([]string{segment}))
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with an optional segment that is not part of the canonical path", func() {
+		BeforeEach(func() {
+			name = "repo"
+			dsl = func() {
+				OptionalSegment("/teams/:teamID")
+				Action("show", func() { Routing(GET("/orgs/:orgID/repos/:id")) })
+			}
+		})
+
+		It("produces an invalid resource definition", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).Should(HaveOccurred())
+		})
+	})
+
 	Context("with a base path", func() {
 		const basePath = "basePath"
 
@@ -1,6 +1,8 @@
 package apidsl
 
 import (
+	"time"
+
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 )
@@ -118,6 +120,58 @@ func Status(status int) {
 	}
 }
 
+// RetryAfter sets the default delay sent to clients in the Retry-After header, typically used with
+// TooManyRequests and ServiceUnavailable responses. The generated response helper accepts the
+// delay as additional argument and uses the default when the argument is 0. goagen also generates
+// a helper suffixed with "At" that accepts a time.Time and sends it as an HTTP date instead:
+//
+//	Response(ServiceUnavailable, func() {
+//		RetryAfter(30 * time.Second)
+//	})
+func RetryAfter(d time.Duration) {
+	if r, ok := responseDefinition(); ok {
+		if d < time.Second {
+			dslengine.ReportError("invalid Retry-After delay %s, must be at least one second", d)
+			return
+		}
+		r.RetryAfter = d
+	}
+}
+
+// CacheControl sets the directives sent to clients and caches in the Cache-Control header of the
+// response. The generated response helper sets the header to the directives joined with ", ":
+//
+//	Response(OK, func() {
+//		Media(BottleMedia)
+//		CacheControl("max-age=300", "public")
+//	})
+func CacheControl(directives ...string) {
+	if r, ok := responseDefinition(); ok {
+		if len(directives) == 0 {
+			dslengine.ReportError("CacheControl requires at least one directive")
+			return
+		}
+		r.CacheControl = append(r.CacheControl, directives...)
+	}
+}
+
+// MultipartResponse defines the parts of a multipart/form-data response. Each part has a name,
+// a content type and a type which is either a media type or design.BinaryType for parts whose
+// content is written as is. goagen generates an additional response helper method for responses
+// that define parts, the method accepts the content of each part indexed by name:
+//
+//	Response(OK, func() {
+//		MultipartResponse([]*design.PartDefinition{
+//			{Name: "report", ContentType: "application/json", Type: ReportMedia},
+//			{Name: "chart", ContentType: "image/png", Type: design.BinaryType},
+//		})
+//	})
+func MultipartResponse(parts []*design.PartDefinition) {
+	if r, ok := responseDefinition(); ok {
+		r.Parts = append(r.Parts, parts...)
+	}
+}
+
 func executeResponseDSL(name string, paramsAndDSL ...interface{}) *design.ResponseDefinition {
 	var params []string
 	var dsl func()
@@ -1,6 +1,8 @@
 package apidsl_test
 
 import (
+	"time"
+
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
@@ -177,6 +179,114 @@ var _ = Describe("Response", func() {
 		})
 	})
 
+	Context("with multipart parts", func() {
+		const status = 200
+		var reportMedia *MediaTypeDefinition
+
+		BeforeEach(func() {
+			name = "foo"
+			reportMedia = &MediaTypeDefinition{
+				UserTypeDefinition: &UserTypeDefinition{
+					AttributeDefinition: &AttributeDefinition{Type: Object{}},
+					TypeName:            "Report",
+				},
+				Identifier: "application/vnd.report+json",
+			}
+			dsl = func() {
+				Status(status)
+				MultipartResponse([]*PartDefinition{
+					{Name: "report", ContentType: "application/json", Type: reportMedia},
+					{Name: "chart", ContentType: "image/png", Type: BinaryType},
+				})
+			}
+		})
+
+		It("sets the response parts", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.Parts).Should(HaveLen(2))
+			Ω(res.Parts[0].Name).Should(Equal("report"))
+			Ω(res.Parts[0].Type).Should(Equal(reportMedia))
+			Ω(res.Parts[1].Name).Should(Equal("chart"))
+			Ω(res.Parts[1].ContentType).Should(Equal("image/png"))
+			Ω(res.Parts[1].Type).Should(Equal(BinaryType))
+		})
+
+		Context("using a type that is not a media type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Status(status)
+					MultipartResponse([]*PartDefinition{
+						{Name: "report", ContentType: "application/json", Type: String},
+					})
+				}
+			})
+
+			It("produces an invalid response definition", func() {
+				Ω(res).ShouldNot(BeNil())
+				Ω(res.Validate()).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a retry delay", func() {
+		const delay = 30 * time.Second
+
+		BeforeEach(func() {
+			name = "ServiceUnavailable"
+			dsl = func() {
+				RetryAfter(delay)
+			}
+		})
+
+		It("sets the response retry delay", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.Status).Should(Equal(503))
+			Ω(res.RetryAfter).Should(Equal(delay))
+		})
+	})
+
+	Context("with a retry delay shorter than a second", func() {
+		BeforeEach(func() {
+			name = "ServiceUnavailable"
+			dsl = func() {
+				RetryAfter(time.Millisecond)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with cache directives", func() {
+		BeforeEach(func() {
+			name = "OK"
+			dsl = func() {
+				CacheControl("max-age=300", "public")
+			}
+		})
+
+		It("sets the response cache directives", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.CacheControl).Should(Equal([]string{"max-age=300", "public"}))
+		})
+	})
+
+	Context("with no cache directive", func() {
+		BeforeEach(func() {
+			name = "OK"
+			dsl = func() {
+				CacheControl()
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("not from the goa default definitions", func() {
 		BeforeEach(func() {
 			name = "foo"
@@ -94,3 +94,40 @@ func HashOf(k, v design.DataType) *design.Hash {
 	vat := design.AttributeDefinition{Type: v}
 	return &design.Hash{KeyType: &kat, ElemType: &vat}
 }
+
+// SQLType causes the generated user type to implement the database/sql Scanner and
+// database/sql/driver Valuer interfaces so that its values can be read from and written to columns
+// of the given type directly. The values are stored as JSON so that columnType should be a JSON or
+// text column type:
+//
+//	var Address = Type("address", func() {
+//		SQLType("jsonb")
+//		Attribute("street", String)
+//		Attribute("city", String)
+//	})
+//
+// SQLType may only be used in Type definitions, it sets the "sql:type" metadata.
+func SQLType(columnType string) {
+	if a, ok := attributeDefinition(); ok {
+		if !isUserTypeAttribute(a) {
+			dslengine.ReportError("SQLType may only be used in Type definitions")
+			return
+		}
+		if columnType == "" {
+			dslengine.ReportError("SQLType column type must not be empty")
+			return
+		}
+		delete(a.Metadata, "sql:type")
+		Metadata("sql:type", columnType)
+	}
+}
+
+// isUserTypeAttribute returns true if att is the attribute of a user type definition.
+func isUserTypeAttribute(att *design.AttributeDefinition) bool {
+	for _, t := range design.Design.Types {
+		if t.AttributeDefinition == att {
+			return true
+		}
+	}
+	return false
+}
@@ -65,6 +65,37 @@ var _ = Describe("Type", func() {
 		})
 	})
 
+	Context("with a SQL column type", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				SQLType("jsonb")
+				Attribute("att")
+			}
+		})
+
+		It("sets the sql:type metadata", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(ut).ShouldNot(BeNil())
+			Ω(ut.SQLType()).Should(Equal("jsonb"))
+		})
+	})
+
+	Context("with a SQL column type on an attribute", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Attribute("att", func() {
+					SQLType("jsonb")
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with a name and uuid datatype", func() {
 		const attName = "att"
 		BeforeEach(func() {
@@ -2,13 +2,18 @@ package design
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dimfeld/httppath"
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/dslengine"
+	"github.com/satori/go.uuid"
 )
 
 type (
@@ -73,6 +78,23 @@ type (
 		Security *SecurityDefinition
 		// NoExamples indicates whether to bypass automatic example generation.
 		NoExamples bool
+		// WireInject indicates whether the main generator produces google/wire injectors
+		// for the controller constructors.
+		WireInject bool
+		// LoadBalancing is the strategy used by the generated client gateway to distribute
+		// requests across multiple servers, "round-robin" or "random". The gateway is only
+		// generated when the strategy is set.
+		LoadBalancing string
+		// Debug is true if the generated application registers the net/http/pprof handlers
+		// when built with the "debug" build tag.
+		Debug bool
+		// Sunset describes the retirement of the API version if any.
+		Sunset *SunsetDefinition
+		// ContextKeys describes the values injected in the request context by middleware
+		// that are exposed by all the action contexts, see the ContextKey DSL.
+		ContextKeys *AttributeDefinition
+		// JWTAuth describes the generated JWT authentication middleware if any.
+		JWTAuth *JWTAuthDefinition
 
 		// rand is the random generator used to generate examples.
 		rand *RandomGenerator
@@ -128,8 +150,13 @@ type (
 		Actions map[string]*ActionDefinition
 		// FileServers is the list of static asset serving endpoints
 		FileServers []*FileServerDefinition
+		// StaticAssets is the list of endpoints serving directories with http.FileServer.
+		StaticAssets []*StaticAssetsDefinition
 		// Action with canonical resource path
 		CanonicalActionName string
+		// OptionalSegments lists the segments of the canonical path that may be omitted
+		// from the resource href.
+		OptionalSegments []string
 		// Map of response definitions that apply to all actions indexed by name.
 		Responses map[string]*ResponseDefinition
 		// Request headers that apply to all actions.
@@ -143,6 +170,9 @@ type (
 		// Security defines security requirements for the Resource,
 		// for actions that don't define one themselves.
 		Security *SecurityDefinition
+		// ContextKeys describes the values injected in the request context by middleware
+		// that are exposed by the resource action contexts.
+		ContextKeys *AttributeDefinition
 	}
 
 	// CORSDefinition contains the definition for a specific origin CORS policy.
@@ -179,6 +209,9 @@ type (
 		Function string
 		// Encoder is true if the definition is for a encoder, false if it's for a decoder.
 		Encoder bool
+		// Compression lists the algorithms used to compress the encoded responses, e.g.
+		// "gzip", "br" or "zstd".
+		Compression []string
 	}
 
 	// ResponseDefinition defines a HTTP response status and optional validation rules.
@@ -197,6 +230,14 @@ type (
 		ViewName string
 		// Response header definitions
 		Headers *AttributeDefinition
+		// Parts lists the parts of multipart responses if any, see MultipartResponse.
+		Parts []*PartDefinition
+		// RetryAfter is the default delay sent in the Retry-After header, the generated
+		// response helper accepts a retry delay when set.
+		RetryAfter time.Duration
+		// CacheControl lists the directives sent in the Cache-Control header, see the
+		// CacheControl DSL.
+		CacheControl []string
 		// Parent action or resource
 		Parent dslengine.Definition
 		// Metadata is a list of key/value pairs
@@ -205,6 +246,16 @@ type (
 		Standard bool
 	}
 
+	// PartDefinition describes a single part of a multipart response.
+	PartDefinition struct {
+		// Part name, used as form field name in the part Content-Disposition header
+		Name string
+		// Part Content-Type header value
+		ContentType string
+		// Part body type, either a media type or BinaryType
+		Type DataType
+	}
+
 	// ResponseTemplateDefinition defines a response template.
 	// A response template is a function that takes an arbitrary number
 	// of strings and returns a response definition.
@@ -244,12 +295,80 @@ type (
 		Payload *UserTypeDefinition
 		// PayloadOptional is true if the request payload is optional, false otherwise.
 		PayloadOptional bool
+		// PayloadMediaType is the media type the payload attributes were copied from if
+		// the Payload DSL was given a media type.
+		PayloadMediaType *MediaTypeDefinition
+		// FormData is true if the payload is read from the parts of a multipart/form-data
+		// request body, see the FormData DSL.
+		FormData bool
 		// Request headers that need to be made available to action
 		Headers *AttributeDefinition
 		// Metadata is a list of key/value pairs
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the action
 		Security *SecurityDefinition
+		// Webhook defines the signature verification of webhook receiver actions if any.
+		Webhook *WebhookDefinition
+		// Audit defines how the audit events of the action are recorded if any, see the
+		// AuditLog DSL.
+		Audit *AuditDefinition
+		// ContextKeys describes the values injected in the request context by middleware
+		// that are exposed by the action context.
+		ContextKeys *AttributeDefinition
+		// UnionResult is true if the controller action method returns the result of the
+		// action instead of sending the response, see the UnionResult DSL.
+		UnionResult bool
+		// PatchFormats lists the content types of the patch documents accepted by the
+		// action PATCH routes, see the AcceptPatch DSL.
+		PatchFormats []string
+		// LongPoll is true if the action streams its 200 response in chunks, see the
+		// LongPoll DSL.
+		LongPoll bool
+		// JSONMergePatch is true if the action payload is a JSON merge patch document, see
+		// the JSONMergePatch DSL.
+		JSONMergePatch bool
+		// MultipartIngest is true if the action payload parts are read from the parts of a
+		// multipart/mixed request body, see the MultipartIngest DSL.
+		MultipartIngest bool
+	}
+
+	// AuditDefinition describes the audit events recorded by state-changing actions.
+	AuditDefinition struct {
+		// Actor is the name of the request header that identifies the caller.
+		Actor string
+		// Resource is the name of the parameter that holds the ID of the changed resource.
+		Resource string
+	}
+
+	// WebhookDefinition describes how the signature of webhook requests is verified.
+	WebhookDefinition struct {
+		// SignatureHeader is the name of the request header containing the signature.
+		SignatureHeader string
+		// Secret is the key used to compute the HMAC-SHA256 signature of the request body.
+		Secret string
+	}
+
+	// SunsetDefinition describes the retirement of an API version.
+	SunsetDefinition struct {
+		// Date is the date after which the API version is no longer served.
+		Date time.Time
+		// RedirectTo is the base path of the successor version, requests made after the
+		// sunset date are redirected to it. The API version responds with 410 Gone after the
+		// sunset date if RedirectTo is empty.
+		RedirectTo string
+	}
+
+	// JWTAuthDefinition describes the middleware that validates the JSON Web Tokens sent in the
+	// request Authorization header and copies the token claims into the request context.
+	JWTAuthDefinition struct {
+		// KeyID is the value of the "kid" header of the accepted tokens, the tokens may use
+		// any key ID if empty.
+		KeyID string
+		// Algorithm is the name of the algorithm used to sign the tokens, e.g. "HS256".
+		Algorithm string
+		// Claims lists the names of the claims copied into the request context sorted
+		// alphabetically. The claims are also defined as API context keys.
+		Claims []string
 	}
 
 	// FileServerDefinition defines an endpoint that servers static assets.
@@ -270,6 +389,20 @@ type (
 		Security *SecurityDefinition
 	}
 
+	// StaticAssetsDefinition defines an endpoint that serves the content of a directory with
+	// http.FileServer.
+	StaticAssetsDefinition struct {
+		// Parent resource
+		Parent *ResourceDefinition
+		// RequestPath is the HTTP path prefix stripped from the request paths before looking
+		// up the files.
+		RequestPath string
+		// FilePath is the path to the directory containing the files.
+		FilePath string
+		// CacheControl is the value of the Cache-Control header of the responses.
+		CacheControl string
+	}
+
 	// LinkDefinition defines a media type link, it specifies a URL to a related resource.
 	LinkDefinition struct {
 		// Link name
@@ -304,6 +437,9 @@ type (
 		Path string
 		// Parent is the action this route applies to.
 		Parent *ActionDefinition
+		// ParamTypes lists the kinds of the path parameters indexed by name, it is
+		// initialized from the action parameters when the action is finalized.
+		ParamTypes map[string]Kind
 	}
 
 	// AttributeDefinition defines a JSON object member with optional description, default
@@ -330,6 +466,20 @@ type (
 		NonZeroAttributes map[string]bool
 		// DSLFunc contains the initialization DSL. This is used for user types.
 		DSLFunc func()
+		// Discriminator describes how to select the concrete type of polymorphic payloads.
+		Discriminator *DiscriminatorDefinition
+		// Index is the position of the attribute in the parent object definition in order
+		// of declaration in the design.
+		Index int
+	}
+
+	// DiscriminatorDefinition maps the values of a discriminator field to the concrete types
+	// of polymorphic payloads.
+	DiscriminatorDefinition struct {
+		// Field is the name of the discriminator field, e.g. "type".
+		Field string
+		// Mapping lists the concrete user types indexed by discriminator value.
+		Mapping map[string]DataType
 	}
 
 	// ContainerDefinition defines a generic container definition that contains attributes.
@@ -590,10 +740,102 @@ func (a *APIDefinition) MediaTypeWithIdentifier(id string) *MediaTypeDefinition
 	return nil
 }
 
-// IterateResources calls the given iterator passing in each resource sorted in alphabetical order.
-// Iteration stops if an iterator returns an error and in this case IterateResources returns that
-// error.
-func (a *APIDefinition) IterateResources(it ResourceIterator) error {
+// AllContextKeys returns the context keys defined by the API, the resources and the actions merged
+// together, nil if there are none.
+func (a *APIDefinition) AllContextKeys() *AttributeDefinition {
+	res := &AttributeDefinition{Type: Object{}}
+	res = res.Merge(a.ContextKeys)
+	a.IterateResources(func(r *ResourceDefinition) error {
+		res = res.Merge(r.ContextKeys)
+		return r.IterateActions(func(ac *ActionDefinition) error {
+			res = res.Merge(ac.ContextKeys)
+			return nil
+		})
+	})
+	if len(res.Type.ToObject()) == 0 {
+		return nil
+	}
+	return res
+}
+
+// MergePolicy defines how APIDefinition.Merge resolves the conflicts between the resources, media
+// types or user types that share the same name in both designs.
+type MergePolicy int
+
+const (
+	// FailOnConflict causes Merge to return an error listing the conflicting definitions and
+	// to leave the receiver unchanged.
+	FailOnConflict MergePolicy = iota
+	// LastWins causes Merge to replace the receiver definitions with the conflicting ones.
+	LastWins
+	// FirstWins causes Merge to keep the receiver definitions and to ignore the conflicting
+	// ones.
+	FirstWins
+)
+
+// Merge adds the resources, media types and user types of other to the API so that large APIs can
+// be composed from designs defined in separate packages. The policy argument controls how
+// definitions defined in both designs are merged and defaults to FailOnConflict. Definitions
+// shared by both designs, that is pointing to the same value, are not conflicts.
+func (a *APIDefinition) Merge(other *APIDefinition, policy ...MergePolicy) error {
+	p := FailOnConflict
+	if len(policy) > 0 {
+		p = policy[0]
+	}
+	if p == FailOnConflict {
+		var conflicts []string
+		for n, r := range other.Resources {
+			if ar, ok := a.Resources[n]; ok && ar != r {
+				conflicts = append(conflicts, fmt.Sprintf("resource %#v", n))
+			}
+		}
+		for id, mt := range other.MediaTypes {
+			if amt, ok := a.MediaTypes[id]; ok && amt != mt {
+				conflicts = append(conflicts, fmt.Sprintf("media type %#v", mt.Identifier))
+			}
+		}
+		for n, t := range other.Types {
+			if at, ok := a.Types[n]; ok && at != t {
+				conflicts = append(conflicts, fmt.Sprintf("type %#v", n))
+			}
+		}
+		if len(conflicts) > 0 {
+			sort.Strings(conflicts)
+			return fmt.Errorf("cannot merge API %#v into API %#v, conflicting definitions: %s",
+				other.Name, a.Name, strings.Join(conflicts, ", "))
+		}
+	}
+	if a.Resources == nil && len(other.Resources) > 0 {
+		a.Resources = make(map[string]*ResourceDefinition)
+	}
+	for n, r := range other.Resources {
+		if _, ok := a.Resources[n]; !ok || p == LastWins {
+			a.Resources[n] = r
+		}
+	}
+	if a.MediaTypes == nil && len(other.MediaTypes) > 0 {
+		a.MediaTypes = make(map[string]*MediaTypeDefinition)
+	}
+	for id, mt := range other.MediaTypes {
+		if _, ok := a.MediaTypes[id]; !ok || p == LastWins {
+			a.MediaTypes[id] = mt
+		}
+	}
+	if a.Types == nil && len(other.Types) > 0 {
+		a.Types = make(map[string]*UserTypeDefinition)
+	}
+	for n, t := range other.Types {
+		if _, ok := a.Types[n]; !ok || p == LastWins {
+			a.Types[n] = t
+		}
+	}
+	return nil
+}
+
+// SortedResources returns the API resources sorted by name. Code generators should use it rather
+// than ranging over the Resources map so that the generated code is the same from one run to the
+// next.
+func (a *APIDefinition) SortedResources() []*ResourceDefinition {
 	names := make([]string, len(a.Resources))
 	i := 0
 	for n := range a.Resources {
@@ -601,8 +843,19 @@ func (a *APIDefinition) IterateResources(it ResourceIterator) error {
 		i++
 	}
 	sort.Strings(names)
-	for _, n := range names {
-		if err := it(a.Resources[n]); err != nil {
+	resources := make([]*ResourceDefinition, len(names))
+	for i, n := range names {
+		resources[i] = a.Resources[n]
+	}
+	return resources
+}
+
+// IterateResources calls the given iterator passing in each resource sorted in alphabetical order.
+// Iteration stops if an iterator returns an error and in this case IterateResources returns that
+// error.
+func (a *APIDefinition) IterateResources(it ResourceIterator) error {
+	for _, res := range a.SortedResources() {
+		if err := it(res); err != nil {
 			return err
 		}
 	}
@@ -669,6 +922,17 @@ func (r *ResourceDefinition) Context() string {
 // Iteration stops if an iterator returns an error and in this case IterateActions returns that
 // error.
 func (r *ResourceDefinition) IterateActions(it ActionIterator) error {
+	for _, a := range r.SortedActions() {
+		if err := it(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SortedActions returns the resource actions sorted by name so that the code generated from the
+// actions does not depend on the map iteration order.
+func (r *ResourceDefinition) SortedActions() []*ActionDefinition {
 	names := make([]string, len(r.Actions))
 	i := 0
 	for n := range r.Actions {
@@ -676,12 +940,11 @@ func (r *ResourceDefinition) IterateActions(it ActionIterator) error {
 		i++
 	}
 	sort.Strings(names)
-	for _, n := range names {
-		if err := it(r.Actions[n]); err != nil {
-			return err
-		}
+	actions := make([]*ActionDefinition, len(names))
+	for i, n := range names {
+		actions[i] = r.Actions[n]
 	}
-	return nil
+	return actions
 }
 
 // IterateFileServers calls the given iterator passing each resource file server sorted by file
@@ -834,6 +1097,9 @@ func (r *ResourceDefinition) Finalize() {
 		f.Finalize()
 		return nil
 	})
+	for _, sa := range r.StaticAssets {
+		sa.Finalize()
+	}
 	r.IterateActions(func(a *ActionDefinition) error {
 		a.Finalize()
 		return nil
@@ -895,6 +1161,59 @@ func (a *AttributeDefinition) IsRequired(attName string) bool {
 	return false
 }
 
+// RequireAll adds the given attributes to the list of required attributes of the object. It makes
+// it possible to require attributes of types or inline attributes built or modified
+// programmatically, the generated validation code checks the attributes like attributes listed
+// with the Required DSL.
+func (a *AttributeDefinition) RequireAll(fields ...string) error {
+	if a.Type == nil || !a.Type.IsObject() {
+		return fmt.Errorf("cannot require attributes of %s, attribute must be an object", a.typeName())
+	}
+	obj := a.Type.ToObject()
+	for _, f := range fields {
+		if _, ok := obj[f]; !ok {
+			return fmt.Errorf("cannot require attribute %#v, %s has no such attribute", f, a.typeName())
+		}
+	}
+	if a.Validation == nil {
+		a.Validation = &dslengine.ValidationDefinition{}
+	}
+	a.Validation.AddRequired(fields)
+	return nil
+}
+
+// RequireAllExcept requires all the attributes of the object except the given ones, see
+// RequireAll.
+func (a *AttributeDefinition) RequireAllExcept(fields ...string) error {
+	if a.Type == nil || !a.Type.IsObject() {
+		return fmt.Errorf("cannot require attributes of %s, attribute must be an object", a.typeName())
+	}
+	obj := a.Type.ToObject()
+	except := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if _, ok := obj[f]; !ok {
+			return fmt.Errorf("cannot exclude attribute %#v, %s has no such attribute", f, a.typeName())
+		}
+		except[f] = true
+	}
+	var names []string
+	for n := range obj {
+		if !except[n] {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return a.RequireAll(names...)
+}
+
+// typeName returns the name of the attribute type used in error messages.
+func (a *AttributeDefinition) typeName() string {
+	if a.Type == nil {
+		return "untyped attribute"
+	}
+	return "type " + a.Type.Name()
+}
+
 // HasDefaultValue returns true if the given attribute has a default value.
 func (a *AttributeDefinition) HasDefaultValue(attName string) bool {
 	if a.Type.IsObject() {
@@ -963,12 +1282,177 @@ func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if att == nil {
 		return false
 	}
-	if att.Type.IsPrimitive() {
+	if att.Type.IsPrimitive() && att.Type.Kind() != FileKind {
+		if att.IsNullable() {
+			return true
+		}
 		return !a.IsRequired(attName) && !a.HasDefaultValue(attName) && !a.IsNonZero(attName)
 	}
 	return false
 }
 
+// IsNullable returns true if the attribute accepts explicit null values, see the Nullable DSL.
+// The Go fields generated for nullable attributes are always pointers and are serialized as null
+// when nil.
+func (a *AttributeDefinition) IsNullable() bool {
+	_, ok := a.Metadata["struct:field:nullable"]
+	return ok
+}
+
+// IsFreeForm returns true if the attribute holds arbitrary key-value pairs, see the FreeForm DSL.
+// The Go fields generated for free-form attributes are of type map[string]json.RawMessage.
+func (a *AttributeDefinition) IsFreeForm() bool {
+	_, ok := a.Metadata["struct:field:freeform"]
+	return ok
+}
+
+// TimeZone returns the time zone used to normalize the values of a DateTime attribute, see the TZ
+// DSL. TimeZone returns "UTC" unless the attribute time zone is set to "local".
+func (a *AttributeDefinition) TimeZone() string {
+	if tz, ok := a.Metadata["datetime:tz"]; ok && len(tz) > 0 && tz[0] == "local" {
+		return "local"
+	}
+	return "UTC"
+}
+
+// AnyOfTypes returns the types the values of an Any attribute are coerced to in order, see the
+// AnyOfTypes DSL. AnyOfTypes returns nil if the metadata is not set.
+func (a *AttributeDefinition) AnyOfTypes() []Primitive {
+	var types []Primitive
+	for _, name := range a.Metadata["anyof:types"] {
+		for _, p := range []Primitive{Boolean, Integer, Number, String} {
+			if p.Name() == name {
+				types = append(types, p)
+			}
+		}
+	}
+	return types
+}
+
+// Coerce converts the raw string value of a parameter of type a.Type to the Go value the generated
+// code initializes the action context field with, e.g. an int for Integer attributes or a
+// uuid.UUID for UUID attributes. Coerce returns an error if raw is not a valid value of the type or
+// if the type cannot be read from a single string such as arrays, objects and files.
+func (a *AttributeDefinition) Coerce(raw string) (interface{}, error) {
+	invalid := func(expected string) error {
+		return fmt.Errorf("invalid value %#v, must be a %s", raw, expected)
+	}
+	k := a.Type.Kind()
+	if k.IsUnsigned() {
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || v > k.maxUnsigned() {
+			return nil, invalid("unsigned integer")
+		}
+		switch k {
+		case Uint8Kind:
+			return uint8(v), nil
+		case Uint16Kind:
+			return uint16(v), nil
+		case Uint32Kind:
+			return uint32(v), nil
+		default:
+			return uint(v), nil
+		}
+	}
+	switch k {
+	case BooleanKind:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, invalid("boolean")
+		}
+		return v, nil
+	case IntegerKind:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, invalid("integer")
+		}
+		return v, nil
+	case NumberKind:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, invalid("number")
+		}
+		return v, nil
+	case StringKind:
+		return raw, nil
+	case DateTimeKind:
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, invalid("datetime")
+		}
+		if a.TimeZone() == "local" {
+			return v.Local(), nil
+		}
+		return v.UTC(), nil
+	case UUIDKind:
+		v, err := uuid.FromString(raw)
+		if err != nil {
+			return nil, invalid("uuid")
+		}
+		return v, nil
+	case IPKind:
+		v := net.ParseIP(raw)
+		if v == nil {
+			return nil, invalid("ip")
+		}
+		return v, nil
+	case CIDRKind:
+		_, v, err := net.ParseCIDR(raw)
+		if err != nil {
+			return nil, invalid("cidr")
+		}
+		return *v, nil
+	case LatLonKind:
+		v, err := goa.ParseLatLon(raw)
+		if err != nil {
+			return nil, invalid("latlon")
+		}
+		return v, nil
+	case SemVerKind:
+		if !semVerRegex.MatchString(raw) {
+			return nil, invalid("semver")
+		}
+		return raw, nil
+	case BigIntKind:
+		v, err := goa.ParseBigInt(raw)
+		if err != nil {
+			return nil, invalid("big integer")
+		}
+		return v, nil
+	case AnyKind:
+		types := a.AnyOfTypes()
+		if len(types) == 0 {
+			return raw, nil
+		}
+		names := make([]string, len(types))
+		for i, t := range types {
+			if v, err := (&AttributeDefinition{Type: t}).Coerce(raw); err == nil {
+				return v, nil
+			}
+			names[i] = t.Name()
+		}
+		return nil, invalid(strings.Join(names, " or "))
+	default:
+		return nil, fmt.Errorf("cannot coerce a string to a value of type %s", a.Type.Name())
+	}
+}
+
+// VisibleTo returns the roles allowed to see the attribute in response bodies, see the VisibleTo
+// DSL. VisibleTo returns nil if the attribute is visible to all roles.
+func (a *AttributeDefinition) VisibleTo() []string {
+	return a.Metadata["visible:roles"]
+}
+
+// SQLType returns the column type of user types that implement the database/sql Scanner and
+// database/sql/driver Valuer interfaces, see the SQLType DSL. SQLType returns an empty string if
+// the metadata is not set.
+func (a *AttributeDefinition) SQLType() string {
+	if t, ok := a.Metadata["sql:type"]; ok && len(t) > 0 {
+		return t[0]
+	}
+	return ""
+}
+
 // SetExample sets the custom example. SetExample also handles the case when the user doesn't
 // want any example or any auto-generated example.
 func (a *AttributeDefinition) SetExample(example interface{}) bool {
@@ -1259,10 +1743,20 @@ func (r *ResponseDefinition) Dup() *ResponseDefinition {
 		Description: r.Description,
 		MediaType:   r.MediaType,
 		ViewName:    r.ViewName,
+		RetryAfter:  r.RetryAfter,
+	}
+	if r.CacheControl != nil {
+		res.CacheControl = append([]string{}, r.CacheControl...)
 	}
 	if r.Headers != nil {
 		res.Headers = DupAtt(r.Headers)
 	}
+	if r.Parts != nil {
+		res.Parts = make([]*PartDefinition, len(r.Parts))
+		for i, p := range r.Parts {
+			res.Parts[i] = &PartDefinition{Name: p.Name, ContentType: p.ContentType, Type: p.Type}
+		}
+	}
 	return &res
 }
 
@@ -1284,6 +1778,15 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 		r.MediaType = other.MediaType
 		r.ViewName = other.ViewName
 	}
+	if r.Parts == nil {
+		r.Parts = other.Parts
+	}
+	if r.RetryAfter == 0 {
+		r.RetryAfter = other.RetryAfter
+	}
+	if r.CacheControl == nil {
+		r.CacheControl = other.CacheControl
+	}
 	if other.Headers != nil {
 		otherHeaders := other.Headers.Type.ToObject()
 		if len(otherHeaders) > 0 {
@@ -1356,6 +1859,21 @@ func (a *ActionDefinition) AllParams() *AttributeDefinition {
 	return res
 }
 
+// AllContextKeys returns the context keys of the action merged with the context keys of its
+// parent resource and of the API, nil if there are none.
+func (a *ActionDefinition) AllContextKeys() *AttributeDefinition {
+	res := &AttributeDefinition{Type: Object{}}
+	res = res.Merge(Design.ContextKeys)
+	if a.Parent != nil {
+		res = res.Merge(a.Parent.ContextKeys)
+	}
+	res = res.Merge(a.ContextKeys)
+	if len(res.Type.ToObject()) == 0 {
+		return nil
+	}
+	return res
+}
+
 // HasAbsoluteRoutes returns true if all the action routes are absolute.
 func (a *ActionDefinition) HasAbsoluteRoutes() bool {
 	for _, r := range a.Routes {
@@ -1397,7 +1915,7 @@ func (a *ActionDefinition) EffectiveSchemes() []string {
 			schemes = parent.Schemes
 			parent = parent.Parent()
 		}
-		if len(schemes) == 0 {
+		if len(schemes) == 0 && Design != nil {
 			schemes = Design.Schemes
 		}
 	}
@@ -1439,6 +1957,7 @@ func (a *ActionDefinition) Finalize() {
 
 	a.mergeResponses()
 	a.initImplicitParams()
+	a.initRouteParamTypes()
 	a.initQueryParams()
 }
 
@@ -1564,6 +2083,19 @@ func (a *ActionDefinition) initImplicitParams() {
 	}
 }
 
+// initRouteParamTypes records the kinds of the route path parameters. It must be called after
+// initImplicitParams so that all the path parameters are defined.
+func (a *ActionDefinition) initRouteParamTypes() {
+	for _, ro := range a.Routes {
+		ro.ParamTypes = make(map[string]Kind)
+		for _, wc := range ro.Params() {
+			if att, ok := a.Params.Type.ToObject()[wc]; ok {
+				ro.ParamTypes[wc] = att.Type.Kind()
+			}
+		}
+	}
+}
+
 // initQueryParams extract the query parameters from the action params.
 func (a *ActionDefinition) initQueryParams() {
 	// 3. Compute QueryParams from Params and set all path params as non zero attributes
@@ -1634,6 +2166,34 @@ func (b ByFilePath) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
 func (b ByFilePath) Len() int           { return len(b) }
 func (b ByFilePath) Less(i, j int) bool { return b[i].FilePath < b[j].FilePath }
 
+// Context returns the generic definition name used in error messages.
+func (sa *StaticAssetsDefinition) Context() string {
+	suffix := fmt.Sprintf("static assets %s", sa.FilePath)
+	var prefix string
+	if sa.Parent != nil {
+		prefix = sa.Parent.Context() + " "
+	}
+	return prefix + suffix
+}
+
+// Finalize makes sure the request path starts with a "/" and does not end with one so that it
+// can be given as is to http.StripPrefix. It also sets the default Cache-Control header value.
+func (sa *StaticAssetsDefinition) Finalize() {
+	if !strings.HasPrefix(sa.RequestPath, "/") {
+		sa.RequestPath = "/" + sa.RequestPath
+	}
+	sa.RequestPath = strings.TrimSuffix(sa.RequestPath, "/")
+	if sa.CacheControl == "" {
+		sa.CacheControl = DefaultStaticAssetsCacheControl
+	}
+}
+
+// RoutePath returns the path of the routes serving the assets, it ends with the "*filepath"
+// wildcard.
+func (sa *StaticAssetsDefinition) RoutePath() string {
+	return sa.RequestPath + "/*filepath"
+}
+
 // Context returns the generic definition name used in error messages.
 func (l *LinkDefinition) Context() string {
 	var prefix, suffix string
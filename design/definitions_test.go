@@ -1,12 +1,17 @@
 package design_test
 
 import (
+	"math/big"
+	"net"
 	"path"
+	"time"
 
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
 )
 
 var _ = Describe("IsRequired", func() {
@@ -48,6 +53,192 @@ var _ = Describe("IsRequired", func() {
 	})
 })
 
+var _ = Describe("Coerce", func() {
+	anyOf := &design.AttributeDefinition{
+		Type:     design.Any,
+		Metadata: dslengine.MetadataDefinition{"anyof:types": {"integer", "boolean"}},
+	}
+	_, network, _ := net.ParseCIDR("10.0.0.0/8")
+	id, _ := uuid.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	cases := []struct {
+		Attribute *design.AttributeDefinition
+		Raw       string
+		Expected  interface{}
+	}{
+		{&design.AttributeDefinition{Type: design.Boolean}, "true", true},
+		{&design.AttributeDefinition{Type: design.Integer}, "-42", -42},
+		{&design.AttributeDefinition{Type: design.Number}, "4.5", 4.5},
+		{&design.AttributeDefinition{Type: design.String}, "foo", "foo"},
+		{&design.AttributeDefinition{Type: design.DateTime}, "2016-01-02T15:04:05+01:00", time.Date(2016, 1, 2, 14, 4, 5, 0, time.UTC)},
+		{&design.AttributeDefinition{Type: design.UUID}, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", id},
+		{&design.AttributeDefinition{Type: design.IP}, "10.0.0.1", net.ParseIP("10.0.0.1")},
+		{&design.AttributeDefinition{Type: design.CIDR}, "10.0.0.0/8", *network},
+		{&design.AttributeDefinition{Type: design.LatLon}, "48.85,2.35", goa.LatLon{Lat: 48.85, Lon: 2.35}},
+		{&design.AttributeDefinition{Type: design.SemVer}, "v1.2.3", "v1.2.3"},
+		{&design.AttributeDefinition{Type: design.BigIntType}, "123456789012345678901234567890", goa.NewBigInt(mustBigInt("123456789012345678901234567890"))},
+		{&design.AttributeDefinition{Type: design.UintType}, "42", uint(42)},
+		{&design.AttributeDefinition{Type: design.Uint8Type}, "255", uint8(255)},
+		{&design.AttributeDefinition{Type: design.Uint16Type}, "65535", uint16(65535)},
+		{&design.AttributeDefinition{Type: design.Uint32Type}, "4294967295", uint32(4294967295)},
+		{&design.AttributeDefinition{Type: design.Any}, "foo", "foo"},
+		{anyOf, "42", 42},
+		{anyOf, "true", true},
+	}
+
+	It("coerces the raw values to the Go type of the attribute", func() {
+		for _, c := range cases {
+			v, err := c.Attribute.Coerce(c.Raw)
+			Ω(err).ShouldNot(HaveOccurred(), c.Raw)
+			Ω(v).Should(BeAssignableToTypeOf(c.Expected), c.Raw)
+			Ω(v).Should(Equal(c.Expected), c.Raw)
+		}
+	})
+
+	invalid := []struct {
+		Attribute *design.AttributeDefinition
+		Raw       string
+	}{
+		{&design.AttributeDefinition{Type: design.Boolean}, "maybe"},
+		{&design.AttributeDefinition{Type: design.Integer}, "4.5"},
+		{&design.AttributeDefinition{Type: design.Number}, "foo"},
+		{&design.AttributeDefinition{Type: design.DateTime}, "yesterday"},
+		{&design.AttributeDefinition{Type: design.UUID}, "foo"},
+		{&design.AttributeDefinition{Type: design.IP}, "10.0.0"},
+		{&design.AttributeDefinition{Type: design.CIDR}, "10.0.0.0"},
+		{&design.AttributeDefinition{Type: design.LatLon}, "north"},
+		{&design.AttributeDefinition{Type: design.SemVer}, "1.2.3"},
+		{&design.AttributeDefinition{Type: design.BigIntType}, "1e10"},
+		{&design.AttributeDefinition{Type: design.Uint8Type}, "256"},
+		{&design.AttributeDefinition{Type: design.UintType}, "-1"},
+		{anyOf, "foo"},
+		{&design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}}, "foo"},
+		{&design.AttributeDefinition{Type: design.Object{}}, "foo"},
+		{&design.AttributeDefinition{Type: design.FileType}, "foo"},
+	}
+
+	It("returns an error for invalid values and non primitive types", func() {
+		for _, c := range invalid {
+			_, err := c.Attribute.Coerce(c.Raw)
+			Ω(err).Should(HaveOccurred(), c.Raw)
+		}
+	})
+})
+
+// mustBigInt parses the decimal integer s.
+func mustBigInt(s string) *big.Int {
+	i, _ := new(big.Int).SetString(s, 10)
+	return i
+}
+
+var _ = Describe("SortedResources", func() {
+	It("returns the resources sorted by name", func() {
+		api := &design.APIDefinition{Resources: make(map[string]*design.ResourceDefinition)}
+		for _, n := range []string{"wineries", "bottles", "cellars", "accounts"} {
+			api.Resources[n] = &design.ResourceDefinition{Name: n}
+		}
+		var names []string
+		for _, r := range api.SortedResources() {
+			names = append(names, r.Name)
+		}
+		Ω(names).Should(Equal([]string{"accounts", "bottles", "cellars", "wineries"}))
+	})
+})
+
+var _ = Describe("Merge", func() {
+	var api, other *design.APIDefinition
+	var first, last *design.ResourceDefinition
+	var policy []design.MergePolicy
+	var mergeErr error
+
+	BeforeEach(func() {
+		first = &design.ResourceDefinition{Name: "bottle", Description: "first"}
+		last = &design.ResourceDefinition{Name: "bottle", Description: "last"}
+		api = &design.APIDefinition{
+			Name:      "cellar",
+			Resources: map[string]*design.ResourceDefinition{"bottle": first},
+		}
+		other = &design.APIDefinition{
+			Name: "winery",
+			Resources: map[string]*design.ResourceDefinition{
+				"bottle": last,
+				"winery": {Name: "winery"},
+			},
+			Types: map[string]*design.UserTypeDefinition{
+				"Address": {TypeName: "Address", AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}}},
+			},
+		}
+		policy = nil
+	})
+
+	JustBeforeEach(func() {
+		mergeErr = api.Merge(other, policy...)
+	})
+
+	Context("with the default policy", func() {
+		It("fails and leaves the API unchanged", func() {
+			Ω(mergeErr).Should(HaveOccurred())
+			Ω(mergeErr.Error()).Should(ContainSubstring(`resource "bottle"`))
+			Ω(api.Resources).Should(HaveLen(1))
+			Ω(api.Resources["bottle"]).Should(Equal(first))
+			Ω(api.Types).Should(BeEmpty())
+		})
+	})
+
+	Context("with the FailOnConflict policy and no conflict", func() {
+		BeforeEach(func() {
+			policy = []design.MergePolicy{design.FailOnConflict}
+			delete(other.Resources, "bottle")
+		})
+
+		It("merges the definitions", func() {
+			Ω(mergeErr).ShouldNot(HaveOccurred())
+			Ω(api.Resources).Should(HaveLen(2))
+			Ω(api.Resources["bottle"]).Should(Equal(first))
+			Ω(api.Types).Should(HaveKey("Address"))
+		})
+	})
+
+	Context("with the LastWins policy", func() {
+		BeforeEach(func() {
+			policy = []design.MergePolicy{design.LastWins}
+		})
+
+		It("keeps the definition of the merged design", func() {
+			Ω(mergeErr).ShouldNot(HaveOccurred())
+			Ω(api.Resources).Should(HaveLen(2))
+			Ω(api.Resources["bottle"].Description).Should(Equal("last"))
+			Ω(api.Types).Should(HaveKey("Address"))
+		})
+	})
+
+	Context("with the FirstWins policy", func() {
+		BeforeEach(func() {
+			policy = []design.MergePolicy{design.FirstWins}
+		})
+
+		It("keeps the definition of the receiver", func() {
+			Ω(mergeErr).ShouldNot(HaveOccurred())
+			Ω(api.Resources).Should(HaveLen(2))
+			Ω(api.Resources["bottle"].Description).Should(Equal("first"))
+			Ω(api.Types).Should(HaveKey("Address"))
+		})
+	})
+})
+
+var _ = Describe("SortedActions", func() {
+	It("returns the actions sorted by name", func() {
+		res := &design.ResourceDefinition{Actions: make(map[string]*design.ActionDefinition)}
+		for _, n := range []string{"show", "create", "update", "list"} {
+			res.Actions[n] = &design.ActionDefinition{Name: n}
+		}
+		var names []string
+		for _, a := range res.SortedActions() {
+			names = append(names, a.Name)
+		}
+		Ω(names).Should(Equal([]string{"create", "list", "show", "update"}))
+	})
+})
+
 var _ = Describe("IterateHeaders", func() {
 	It("works when Parent.Headers is nil", func() {
 		// create a Resource with no headers, Action with one header
@@ -163,6 +163,13 @@ func (eg *exampleGenerator) generateFormatExample() interface{} {
 		}(),
 		"cidr":   "192.168.100.14/24",
 		"regexp": eg.r.faker.Characters(3) + ".*",
+		"e164": func() string {
+			res, err := regen.Generate(`\+[1-9][0-9]{10}`)
+			if err != nil {
+				return "+12125551234"
+			}
+			return res
+		}(),
 	}[format]; ok {
 		return res
 	}
@@ -246,7 +253,7 @@ func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
 		max = *eg.a.Validation.Maximum
 	}
 	if math.IsInf(min, 1) {
-		if eg.a.Type.Kind() == IntegerKind {
+		if eg.a.Type.Kind() == IntegerKind || eg.a.Type.Kind().IsUnsigned() {
 			if max == 0 {
 				return int(max) - eg.r.Int()%3
 			}
@@ -254,7 +261,7 @@ func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
 		}
 		return eg.r.Float64() * max
 	} else if math.IsInf(max, -1) {
-		if eg.a.Type.Kind() == IntegerKind {
+		if eg.a.Type.Kind() == IntegerKind || eg.a.Type.Kind().IsUnsigned() {
 			if min == 0 {
 				return int(min) + eg.r.Int()%3
 			}
@@ -262,12 +269,12 @@ func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
 		}
 		return min + eg.r.Float64()*min
 	} else if min < max {
-		if eg.a.Type.Kind() == IntegerKind {
+		if eg.a.Type.Kind() == IntegerKind || eg.a.Type.Kind().IsUnsigned() {
 			return int(min) + eg.r.Int()%int(max-min)
 		}
 		return min + eg.r.Float64()*(max-min)
 	} else if min == max {
-		if eg.a.Type.Kind() == IntegerKind {
+		if eg.a.Type.Kind() == IntegerKind || eg.a.Type.Kind().IsUnsigned() {
 			return int(min)
 		}
 		return min
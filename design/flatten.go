@@ -0,0 +1,173 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// CircularRefMetadata is the key of the metadata of the placeholder attributes created by Flatten
+// in place of the circular references to user types. The metadata value is the type name.
+const CircularRefMetadata = "circular_ref"
+
+// Flatten returns a deep copy of the API definition where the user types and media types used by
+// attributes are replaced with their attribute definitions inline. External tools can serialize
+// the flattened definition without resolving the type references.
+// The references to a type made while inlining the same type are replaced with an Any attribute
+// whose CircularRefMetadata metadata holds the type name so that Flatten always terminates.
+func (a *APIDefinition) Flatten() *APIDefinition {
+	f := &flattener{}
+	flat := *a
+	flat.Params = f.attribute(a.Params)
+	if a.Types != nil {
+		flat.Types = make(map[string]*UserTypeDefinition, len(a.Types))
+		for n, t := range a.Types {
+			flat.Types[n] = f.userType(t)
+		}
+	}
+	if a.MediaTypes != nil {
+		flat.MediaTypes = make(map[string]*MediaTypeDefinition, len(a.MediaTypes))
+		for id, mt := range a.MediaTypes {
+			m := *mt
+			m.UserTypeDefinition = f.userType(mt.UserTypeDefinition)
+			flat.MediaTypes[id] = &m
+		}
+	}
+	if a.Resources != nil {
+		flat.Resources = make(map[string]*ResourceDefinition, len(a.Resources))
+		for n, r := range a.Resources {
+			flat.Resources[n] = f.resource(r)
+		}
+	}
+	flat.Responses = f.responses(a.Responses)
+	flat.DefaultResponses = f.responses(a.DefaultResponses)
+	return &flat
+}
+
+// flattener inlines the user types, it keeps track of the types being inlined to detect circular
+// references.
+type flattener struct {
+	inlining []string
+}
+
+// resource returns a copy of r with flattened attributes.
+func (f *flattener) resource(r *ResourceDefinition) *ResourceDefinition {
+	res := *r
+	res.Params = f.attribute(r.Params)
+	res.Headers = f.attribute(r.Headers)
+	res.ContextKeys = f.attribute(r.ContextKeys)
+	res.Responses = f.responses(r.Responses)
+	if r.Actions != nil {
+		res.Actions = make(map[string]*ActionDefinition, len(r.Actions))
+		for n, a := range r.Actions {
+			act := *a
+			act.Parent = &res
+			act.Params = f.attribute(a.Params)
+			act.QueryParams = f.attribute(a.QueryParams)
+			act.Headers = f.attribute(a.Headers)
+			act.ContextKeys = f.attribute(a.ContextKeys)
+			if a.Payload != nil {
+				act.Payload = f.userType(a.Payload)
+			}
+			act.Responses = f.responses(a.Responses)
+			res.Actions[n] = &act
+		}
+	}
+	return &res
+}
+
+// responses returns copies of the given responses with flattened types.
+func (f *flattener) responses(responses map[string]*ResponseDefinition) map[string]*ResponseDefinition {
+	if responses == nil {
+		return nil
+	}
+	res := make(map[string]*ResponseDefinition, len(responses))
+	for n, r := range responses {
+		resp := *r
+		if r.Type != nil {
+			resp.Type = f.dataType(r.Type)
+		}
+		resp.Headers = f.attribute(r.Headers)
+		if r.Parts != nil {
+			resp.Parts = make([]*PartDefinition, len(r.Parts))
+			for i, p := range r.Parts {
+				resp.Parts[i] = &PartDefinition{Name: p.Name, ContentType: p.ContentType, Type: f.dataType(p.Type)}
+			}
+		}
+		res[n] = &resp
+	}
+	return res
+}
+
+// userType returns a user type with the same name as ut whose attribute is flattened.
+func (f *flattener) userType(ut *UserTypeDefinition) *UserTypeDefinition {
+	return &UserTypeDefinition{
+		TypeName:            ut.TypeName,
+		AttributeDefinition: f.inline(ut),
+	}
+}
+
+// attribute returns a flattened copy of att.
+func (f *flattener) attribute(att *AttributeDefinition) *AttributeDefinition {
+	if att == nil {
+		return nil
+	}
+	var ut *UserTypeDefinition
+	switch actual := att.Type.(type) {
+	case *UserTypeDefinition:
+		ut = actual
+	case *MediaTypeDefinition:
+		ut = actual.UserTypeDefinition
+	}
+	dup := DupAtt(att)
+	if ut == nil {
+		dup.Type = f.dataType(att.Type)
+		return dup
+	}
+	for _, n := range f.inlining {
+		if n == ut.TypeName {
+			return &AttributeDefinition{
+				Type:        Any,
+				Description: att.Description,
+				Metadata:    dslengine.MetadataDefinition{CircularRefMetadata: {ut.TypeName}},
+			}
+		}
+	}
+	inlined := f.inline(ut)
+	dup.Type = inlined.Type
+	if dup.Description == "" {
+		dup.Description = inlined.Description
+	}
+	if dup.Validation == nil {
+		dup.Validation = inlined.Validation
+	} else if inlined.Validation != nil {
+		dup.Validation.Merge(inlined.Validation)
+	}
+	if dup.NonZeroAttributes == nil {
+		dup.NonZeroAttributes = inlined.NonZeroAttributes
+	}
+	return dup
+}
+
+// inline returns a flattened copy of the attribute of the given user type.
+func (f *flattener) inline(ut *UserTypeDefinition) *AttributeDefinition {
+	f.inlining = append(f.inlining, ut.TypeName)
+	defer func() { f.inlining = f.inlining[:len(f.inlining)-1] }()
+	return f.attribute(ut.AttributeDefinition)
+}
+
+// dataType returns a flattened copy of t.
+func (f *flattener) dataType(t DataType) DataType {
+	switch actual := t.(type) {
+	case *Array:
+		return &Array{ElemType: f.attribute(actual.ElemType)}
+	case *Hash:
+		return &Hash{KeyType: f.attribute(actual.KeyType), ElemType: f.attribute(actual.ElemType)}
+	case Object:
+		res := make(Object, len(actual))
+		for n, att := range actual {
+			res[n] = f.attribute(att)
+		}
+		return res
+	case *UserTypeDefinition, *MediaTypeDefinition:
+		return f.attribute(&AttributeDefinition{Type: t}).Type
+	default:
+		return t
+	}
+}
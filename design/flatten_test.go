@@ -0,0 +1,115 @@
+package design_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Flatten", func() {
+	var api *APIDefinition
+	var flat *APIDefinition
+
+	JustBeforeEach(func() {
+		flat = api.Flatten()
+	})
+
+	Context("with attributes using a user type", func() {
+		var bottle *UserTypeDefinition
+
+		BeforeEach(func() {
+			bottle = &UserTypeDefinition{
+				TypeName: "Bottle",
+				AttributeDefinition: &AttributeDefinition{
+					Description: "A bottle of wine",
+					Type:        Object{"name": &AttributeDefinition{Type: String}},
+					Validation:  &dslengine.ValidationDefinition{Required: []string{"name"}},
+				},
+			}
+			pair := &UserTypeDefinition{
+				TypeName: "Pair",
+				AttributeDefinition: &AttributeDefinition{Type: Object{
+					"first":  &AttributeDefinition{Type: bottle},
+					"second": &AttributeDefinition{Type: bottle, Description: "The second bottle"},
+				}},
+			}
+			api = &APIDefinition{
+				Name:  "test",
+				Types: map[string]*UserTypeDefinition{"Bottle": bottle, "Pair": pair},
+				Resources: map[string]*ResourceDefinition{
+					"pair": {
+						Name: "pair",
+						Actions: map[string]*ActionDefinition{
+							"create": {Name: "create", Payload: pair},
+						},
+					},
+				},
+			}
+		})
+
+		It("inlines the user type attributes", func() {
+			obj := flat.Types["Pair"].Type.ToObject()
+			Ω(obj["first"].Type).Should(Equal(bottle.Type))
+			Ω(obj["first"].Description).Should(Equal("A bottle of wine"))
+			Ω(obj["first"].IsRequired("name")).Should(BeTrue())
+			Ω(obj["second"].Type).Should(Equal(bottle.Type))
+			Ω(obj["second"].Description).Should(Equal("The second bottle"))
+			Ω(obj["second"].Metadata).ShouldNot(HaveKey(CircularRefMetadata))
+		})
+
+		It("flattens the action payloads", func() {
+			action := flat.Resources["pair"].Actions["create"]
+			Ω(action.Parent).Should(Equal(flat.Resources["pair"]))
+			Ω(action.Payload.Type.ToObject()["first"].Type).Should(Equal(bottle.Type))
+		})
+
+		It("does not modify the API definition", func() {
+			Ω(api.Types["Pair"].Type.ToObject()["first"].Type).Should(Equal(bottle))
+		})
+	})
+
+	Context("with a self-referential type", func() {
+		BeforeEach(func() {
+			node := &UserTypeDefinition{TypeName: "Node"}
+			node.AttributeDefinition = &AttributeDefinition{Type: Object{
+				"value":    &AttributeDefinition{Type: String},
+				"next":     &AttributeDefinition{Type: node, Description: "The next node"},
+				"children": &AttributeDefinition{Type: &Array{ElemType: &AttributeDefinition{Type: node}}},
+			}}
+			api = &APIDefinition{
+				Name:  "test",
+				Types: map[string]*UserTypeDefinition{"Node": node},
+				Resources: map[string]*ResourceDefinition{
+					"tree": {
+						Name: "tree",
+						Actions: map[string]*ActionDefinition{
+							"create": {
+								Name:   "create",
+								Params: &AttributeDefinition{Type: Object{"root": &AttributeDefinition{Type: node}}},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("replaces the circular references with placeholders", func() {
+			obj := flat.Types["Node"].Type.ToObject()
+			Ω(obj["value"].Type).Should(Equal(String))
+			Ω(obj["next"].Type).Should(Equal(Any))
+			Ω(obj["next"].Description).Should(Equal("The next node"))
+			Ω(obj["next"].Metadata).Should(HaveKeyWithValue(CircularRefMetadata, []string{"Node"}))
+			elem := obj["children"].Type.(*Array).ElemType
+			Ω(elem.Type).Should(Equal(Any))
+			Ω(elem.Metadata).Should(HaveKeyWithValue(CircularRefMetadata, []string{"Node"}))
+		})
+
+		It("inlines the type once in the attributes using it", func() {
+			root := flat.Resources["tree"].Actions["create"].Params.Type.ToObject()["root"]
+			obj := root.Type.ToObject()
+			Ω(obj).Should(HaveKey("value"))
+			Ω(obj["next"].Metadata).Should(HaveKeyWithValue(CircularRefMetadata, []string{"Node"}))
+		})
+	})
+})
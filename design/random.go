@@ -3,6 +3,7 @@ package design
 import (
 	"crypto/md5"
 	"encoding/binary"
+	"fmt"
 	"math/rand"
 	"time"
 
@@ -63,6 +64,32 @@ func (r *RandomGenerator) UUID() uuid.UUID {
 	return uuid.NewV4()
 }
 
+// IP produces a random IPv4 address.
+func (r *RandomGenerator) IP() string {
+	return fmt.Sprintf("10.%d.%d.%d", r.rand.Intn(256), r.rand.Intn(256), r.rand.Intn(256))
+}
+
+// CIDR produces a random IPv4 CIDR notation address.
+func (r *RandomGenerator) CIDR() string {
+	return fmt.Sprintf("10.%d.0.0/16", r.rand.Intn(256))
+}
+
+// LatLon produces a random "lat,lon" geographic coordinate.
+func (r *RandomGenerator) LatLon() string {
+	return fmt.Sprintf("%.4f,%.4f", r.rand.Float64()*180-90, r.rand.Float64()*360-180)
+}
+
+// SemVer produces a random semantic version.
+func (r *RandomGenerator) SemVer() string {
+	return fmt.Sprintf("v%d.%d.%d", r.rand.Intn(10), r.rand.Intn(20), r.rand.Intn(20))
+}
+
+// BigInt produces the decimal representation of a random integer that exceeds the range of
+// int64.
+func (r *RandomGenerator) BigInt() string {
+	return fmt.Sprintf("%d%018d", 10+r.rand.Intn(90), r.rand.Int63n(1e18))
+}
+
 // Bool produces a random boolean.
 func (r *RandomGenerator) Bool() bool {
 	return r.rand.Int()%2 == 0
@@ -11,12 +11,16 @@ package design
 
 import (
 	"fmt"
+	"math"
 	"mime"
+	"net"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/satori/go.uuid"
 )
@@ -163,6 +167,28 @@ const (
 	UserTypeKind
 	// MediaTypeKind represents a media type.
 	MediaTypeKind
+	// IPKind represents a JSON string that is parsed as a Go net.IP
+	IPKind
+	// CIDRKind represents a JSON string that is parsed as a Go goa.CIDR
+	CIDRKind
+	// LatLonKind represents a JSON "lat,lon" string that is parsed as a Go goa.LatLon
+	LatLonKind
+	// SemVerKind represents a JSON string that holds a semantic version such as "v1.2.3"
+	SemVerKind
+	// FileKind represents a file uploaded in a multipart/form-data request that is parsed as a
+	// Go *multipart.FileHeader.
+	FileKind
+	// UintKind represents a JSON integer that is parsed as a Go uint.
+	UintKind
+	// Uint8Kind represents a JSON integer that is parsed as a Go uint8.
+	Uint8Kind
+	// Uint16Kind represents a JSON integer that is parsed as a Go uint16.
+	Uint16Kind
+	// Uint32Kind represents a JSON integer that is parsed as a Go uint32.
+	Uint32Kind
+	// BigIntKind represents a JSON string holding a decimal integer that is parsed as a Go
+	// goa.BigInt.
+	BigIntKind
 )
 
 const (
@@ -186,10 +212,67 @@ const (
 	// UUID expects an RFC4122 formatted value.
 	UUID = Primitive(UUIDKind)
 
+	// IP is the type for a JSON string parsed as a Go net.IP
+	// IP expects an IPv4 or IPv6 address.
+	IP = Primitive(IPKind)
+
+	// CIDR is the type for a JSON string parsed as a Go goa.CIDR
+	// CIDR expects an RFC4632 or RFC4291 CIDR notation IP address.
+	CIDR = Primitive(CIDRKind)
+
+	// LatLon is the type for a JSON string parsed as a Go goa.LatLon
+	// LatLon expects a geographic coordinate formatted as "lat,lon" in decimal degrees.
+	LatLon = Primitive(LatLonKind)
+
+	// SemVer is the type for a JSON string holding a semantic version as a Go goa.SemVerString
+	// SemVer expects a version as defined by golang.org/x/mod/semver, e.g. "v1.2.3".
+	SemVer = Primitive(SemVerKind)
+
 	// Any is the type for an arbitrary JSON value (interface{} in Go).
 	Any = Primitive(AnyKind)
+
+	// FileType is the type for the file parts of multipart/form-data payloads, see the FormData DSL.
+	// FileType attributes are parsed as Go *multipart.FileHeader values.
+	FileType = Primitive(FileKind)
+
+	// UintType is the type for a JSON integer parsed as a Go uint.
+	// Unsigned integer attributes have an implicit minimum of 0.
+	UintType = Primitive(UintKind)
+
+	// Uint8Type is the type for a JSON integer parsed as a Go uint8.
+	Uint8Type = Primitive(Uint8Kind)
+
+	// Uint16Type is the type for a JSON integer parsed as a Go uint16.
+	Uint16Type = Primitive(Uint16Kind)
+
+	// Uint32Type is the type for a JSON integer parsed as a Go uint32.
+	Uint32Type = Primitive(Uint32Kind)
+
+	// BigIntType is the type for an arbitrary-precision integer parsed as a Go goa.BigInt.
+	// BigIntType values are serialized as JSON strings holding the decimal representation of
+	// the integer.
+	BigIntType = Primitive(BigIntKind)
 )
 
+// IsUnsigned returns true if k is one of the unsigned integer kinds.
+func (k Kind) IsUnsigned() bool {
+	return k == UintKind || k == Uint8Kind || k == Uint16Kind || k == Uint32Kind
+}
+
+// maxUnsigned returns the largest value of the Go type of the unsigned integer kind k.
+func (k Kind) maxUnsigned() uint64 {
+	switch k {
+	case Uint8Kind:
+		return math.MaxUint8
+	case Uint16Kind:
+		return math.MaxUint16
+	case Uint32Kind:
+		return math.MaxUint32
+	default:
+		return uint64(^uint(0))
+	}
+}
+
 // DataType implementation
 
 // Kind implements DataKind.
@@ -200,14 +283,16 @@ func (p Primitive) Name() string {
 	switch p {
 	case Boolean:
 		return "boolean"
-	case Integer:
+	case Integer, UintType, Uint8Type, Uint16Type, Uint32Type:
 		return "integer"
 	case Number:
 		return "number"
-	case String, DateTime, UUID:
+	case String, DateTime, UUID, IP, CIDR, LatLon, SemVer, BigIntType:
 		return "string"
 	case Any:
 		return "any"
+	case FileType:
+		return "file"
 	default:
 		panic("unknown primitive type") // bug
 	}
@@ -240,7 +325,7 @@ func (p Primitive) ToHash() *Hash { return nil }
 // CanHaveDefault returns whether the primitive can have a default value.
 func (p Primitive) CanHaveDefault() (ok bool) {
 	switch p {
-	case Boolean, Integer, Number, String, DateTime:
+	case Boolean, Integer, Number, String, DateTime, UintType, Uint8Type, Uint16Type, Uint32Type:
 		ok = true
 	}
 	return
@@ -248,7 +333,7 @@ func (p Primitive) CanHaveDefault() (ok bool) {
 
 // IsCompatible returns true if val is compatible with p.
 func (p Primitive) IsCompatible(val interface{}) bool {
-	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any {
+	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != IP && p != CIDR && p != LatLon && p != SemVer && p != Any && p != FileType && p != BigIntType && !p.Kind().IsUnsigned() {
 		panic("unknown primitive type") // bug
 	}
 	if p == Any {
@@ -258,7 +343,16 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 	case bool:
 		return p == Boolean
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return p == Integer || p == Number
+		if p.Kind().IsUnsigned() {
+			v := reflect.ValueOf(val)
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return v.Int() >= 0 && uint64(v.Int()) <= p.Kind().maxUnsigned()
+			default:
+				return v.Uint() <= p.Kind().maxUnsigned()
+			}
+		}
+		return p == Integer || p == Number || p == BigIntType
 	case float32, float64:
 		return p == Number
 	case string:
@@ -273,10 +367,32 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 			_, err := uuid.FromString(val.(string))
 			return err == nil
 		}
+		if p == IP {
+			return net.ParseIP(val.(string)) != nil
+		}
+		if p == CIDR {
+			_, _, err := net.ParseCIDR(val.(string))
+			return err == nil
+		}
+		if p == LatLon {
+			_, err := goa.ParseLatLon(val.(string))
+			return err == nil
+		}
+		if p == SemVer {
+			return semVerRegex.MatchString(val.(string))
+		}
+		if p == BigIntType {
+			_, err := goa.ParseBigInt(val.(string))
+			return err == nil
+		}
 	}
 	return false
 }
 
+// semVerRegex matches the semantic versions accepted by golang.org/x/mod/semver including the
+// "vMAJOR" and "vMAJOR.MINOR" shorthands.
+var semVerRegex = regexp.MustCompile(`^v(0|[1-9]\d*)(\.(0|[1-9]\d*)(\.(0|[1-9]\d*)(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?)?)?$`)
+
 var anyPrimitive = []Primitive{Boolean, Integer, Number, DateTime, UUID}
 
 // GenerateExample returns an instance of the given data type.
@@ -294,9 +410,27 @@ func (p Primitive) GenerateExample(r *RandomGenerator, seen []string) interface{
 		return r.DateTime()
 	case UUID:
 		return r.UUID()
+	case IP:
+		return r.IP()
+	case CIDR:
+		return r.CIDR()
+	case LatLon:
+		return r.LatLon()
+	case SemVer:
+		return r.SemVer()
 	case Any:
 		// to not make it too complicated, pick one of the primitive types
 		return anyPrimitive[r.Int()%len(anyPrimitive)].GenerateExample(r, seen)
+	case FileType:
+		// file contents cannot be represented in examples
+		return nil
+	case UintType, Uint8Type, Uint16Type, Uint32Type:
+		if max := p.Kind().maxUnsigned(); max <= math.MaxUint32 {
+			return int(uint64(r.Int()) % (max + 1))
+		}
+		return r.Int()
+	case BigIntType:
+		return r.BigInt()
 	default:
 		panic("unknown primitive type") // bug
 	}
@@ -437,6 +571,11 @@ func (o Object) GenerateExample(r *RandomGenerator, seen []string) interface{} {
 	res := make(map[string]interface{})
 	for _, n := range keys {
 		att := o[n]
+		if att.Example != nil {
+			// Examples defined in the design take precedence over random values
+			res[n] = att.Example
+			continue
+		}
 		res[n] = att.Type.GenerateExample(r, seen)
 	}
 	return res
@@ -541,6 +680,22 @@ func (o Object) IterateAttributes(it AttributeIterator) error {
 	return nil
 }
 
+// DeclaredNames returns the names of the object attributes in the order in which they are
+// declared in the design. Attributes with the same index are sorted by name.
+func (o Object) DeclaredNames() []string {
+	names := make([]string, 0, len(o))
+	for n := range o {
+		names = append(names, n)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if o[names[i]].Index != o[names[j]].Index {
+			return o[names[i]].Index < o[names[j]].Index
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
 // UserTypes traverses the data type recursively and collects all the user types used to
 // define it. The returned map is indexed by type name.
 func UserTypes(dt DataType) map[string]*UserTypeDefinition {
@@ -1016,6 +1171,102 @@ func walk(at *AttributeDefinition, walker func(*AttributeDefinition) error, seen
 	return nil
 }
 
+// Walk traverses the API definition depth-first and calls visitor once on each node. The nodes are
+// the user types, the media types and the resources of the API in this order, each sorted by name.
+// Walk visits the attributes of a type after the type, the actions of a resource after the
+// resource parameters and headers and the parameters, headers and payload of an action after the
+// action. The fields of object attributes are visited in alphabetical order. Each type and
+// attribute is visited at most once so that recursive types do not cause infinite loops. Walk
+// stops and returns the error returned by visitor if any. visitor cannot be nil.
+func Walk(api *APIDefinition, visitor func(node interface{}) error) error {
+	if visitor == nil {
+		panic("design.Walk: visitor cannot be nil, pass a function called on each node")
+	}
+	seen := make(map[interface{}]bool)
+	var walkType func(DataType) error
+	var walkAtt func(*AttributeDefinition) error
+	visit := func(node interface{}) (bool, error) {
+		if seen[node] {
+			return false, nil
+		}
+		seen[node] = true
+		return true, visitor(node)
+	}
+	walkAtt = func(att *AttributeDefinition) error {
+		if att == nil {
+			return nil
+		}
+		if ok, err := visit(att); !ok || err != nil {
+			return err
+		}
+		return walkType(att.Type)
+	}
+	walkType = func(dt DataType) error {
+		switch actual := dt.(type) {
+		case *Array:
+			return walkAtt(actual.ElemType)
+		case *Hash:
+			if err := walkAtt(actual.KeyType); err != nil {
+				return err
+			}
+			return walkAtt(actual.ElemType)
+		case Object:
+			return actual.IterateAttributes(func(_ string, att *AttributeDefinition) error {
+				return walkAtt(att)
+			})
+		case *UserTypeDefinition:
+			if ok, err := visit(actual); !ok || err != nil {
+				return err
+			}
+			return walkAtt(actual.AttributeDefinition)
+		case *MediaTypeDefinition:
+			if ok, err := visit(actual); !ok || err != nil {
+				return err
+			}
+			return walkAtt(actual.AttributeDefinition)
+		}
+		return nil
+	}
+	err := api.IterateUserTypes(func(u *UserTypeDefinition) error {
+		return walkType(u)
+	})
+	if err != nil {
+		return err
+	}
+	err = api.IterateMediaTypes(func(m *MediaTypeDefinition) error {
+		return walkType(m)
+	})
+	if err != nil {
+		return err
+	}
+	return api.IterateResources(func(r *ResourceDefinition) error {
+		if err := visitor(r); err != nil {
+			return err
+		}
+		if err := walkAtt(r.Params); err != nil {
+			return err
+		}
+		if err := walkAtt(r.Headers); err != nil {
+			return err
+		}
+		return r.IterateActions(func(a *ActionDefinition) error {
+			if err := visitor(a); err != nil {
+				return err
+			}
+			if err := walkAtt(a.Params); err != nil {
+				return err
+			}
+			if err := walkAtt(a.Headers); err != nil {
+				return err
+			}
+			if a.Payload != nil {
+				return walkType(a.Payload)
+			}
+			return nil
+		})
+	})
+}
+
 // toReflectType converts the DataType to reflect.Type.
 func toReflectType(dtype DataType) reflect.Type {
 	switch dtype.Kind() {
@@ -1023,6 +1274,14 @@ func toReflectType(dtype DataType) reflect.Type {
 		return reflect.TypeOf(true)
 	case IntegerKind:
 		return reflect.TypeOf(int(0))
+	case UintKind:
+		return reflect.TypeOf(uint(0))
+	case Uint8Kind:
+		return reflect.TypeOf(uint8(0))
+	case Uint16Kind:
+		return reflect.TypeOf(uint16(0))
+	case Uint32Kind:
+		return reflect.TypeOf(uint32(0))
 	case NumberKind:
 		return reflect.TypeOf(float64(0))
 	case StringKind:
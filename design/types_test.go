@@ -457,3 +457,171 @@ var _ = Describe("Finalize", func() {
 		}).ShouldNot(HaveOccurred())
 	})
 })
+
+var _ = Describe("GenerateExample", func() {
+	var o Object
+	var example interface{}
+
+	BeforeEach(func() {
+		o = Object{
+			"name":    &AttributeDefinition{Type: String, Example: "Chateau Montelena"},
+			"vintage": &AttributeDefinition{Type: Integer},
+		}
+	})
+
+	JustBeforeEach(func() {
+		example = o.GenerateExample(NewRandomGenerator("test"), nil)
+	})
+
+	It("uses the attribute examples defined in the design", func() {
+		Ω(example).Should(HaveKeyWithValue("name", "Chateau Montelena"))
+		Ω(example).Should(HaveKey("vintage"))
+	})
+})
+
+var _ = Describe("DeclaredNames", func() {
+	var ut *UserTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		ut = Type("Bottle", func() {
+			Attribute("vintage", Integer)
+			Attribute("name", String)
+			Attribute("color", String)
+			Attribute("name", String, "Name of the wine")
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	})
+
+	It("returns the attribute names in declaration order", func() {
+		Ω(ut.Type.ToObject().DeclaredNames()).Should(Equal([]string{"vintage", "name", "color"}))
+	})
+})
+
+var _ = Describe("IsCompatible", func() {
+	It("accepts IPv4 and IPv6 addresses for IP", func() {
+		Ω(IP.IsCompatible("192.168.1.1")).Should(BeTrue())
+		Ω(IP.IsCompatible("2001:db8::1")).Should(BeTrue())
+		Ω(IP.IsCompatible("not an IP")).Should(BeFalse())
+	})
+
+	It("accepts CIDR notation addresses for CIDR", func() {
+		Ω(CIDR.IsCompatible("10.0.0.0/8")).Should(BeTrue())
+		Ω(CIDR.IsCompatible("2001:db8::/32")).Should(BeTrue())
+		Ω(CIDR.IsCompatible("10.0.0.1")).Should(BeFalse())
+	})
+
+	It("accepts semantic versions for SemVer", func() {
+		Ω(SemVer.IsCompatible("v1.2.3")).Should(BeTrue())
+		Ω(SemVer.IsCompatible("v1.2.3-rc.1+build.5")).Should(BeTrue())
+		Ω(SemVer.IsCompatible("1.2")).Should(BeFalse())
+		Ω(SemVer.IsCompatible("v1.02.3")).Should(BeFalse())
+	})
+
+	It("accepts integers and decimal strings for BigIntType", func() {
+		Ω(BigIntType.IsCompatible(42)).Should(BeTrue())
+		Ω(BigIntType.IsCompatible("184467440737095516160")).Should(BeTrue())
+		Ω(BigIntType.IsCompatible("1.5")).Should(BeFalse())
+		Ω(BigIntType.IsCompatible(1.5)).Should(BeFalse())
+	})
+
+	It("accepts \"lat,lon\" coordinates for LatLon", func() {
+		Ω(LatLon.IsCompatible("48.8583,2.2945")).Should(BeTrue())
+		Ω(LatLon.IsCompatible("91,2.2945")).Should(BeFalse())
+		Ω(LatLon.IsCompatible("48.8583")).Should(BeFalse())
+	})
+})
+
+var _ = Describe("Walk API", func() {
+	var visited []string
+
+	// describe returns a string describing the given node.
+	describe := func(node interface{}) string {
+		switch n := node.(type) {
+		case *ResourceDefinition:
+			return "resource " + n.Name
+		case *ActionDefinition:
+			return "action " + n.Name
+		case *MediaTypeDefinition:
+			return "media type " + n.Identifier
+		case *UserTypeDefinition:
+			return "type " + n.TypeName
+		case *AttributeDefinition:
+			return "attribute " + n.Type.Name()
+		}
+		return "unknown"
+	}
+
+	visitor := func(node interface{}) error {
+		visited = append(visited, describe(node))
+		return nil
+	}
+
+	BeforeEach(func() {
+		visited = nil
+		dslengine.Reset()
+		bottle := Type("Bottle", func() {
+			Attribute("name", String)
+			Attribute("parent", "Bottle")
+		})
+		MediaType("application/vnd.bottle+json", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+			})
+			View("default", func() {
+				Attribute("id")
+			})
+		})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Params(func() {
+					Param("id", Integer)
+				})
+				Payload(bottle)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+	})
+
+	It("visits the nodes depth-first in a deterministic order", func() {
+		Ω(Walk(Design, visitor)).Should(Succeed())
+		Ω(visited).Should(Equal([]string{
+			"type Bottle",
+			"attribute object",
+			"attribute string",
+			"attribute object",
+			"media type application/vnd.bottle+json",
+			"attribute object",
+			"attribute integer",
+			"resource bottle",
+			"action show",
+			"attribute object",
+			"attribute integer",
+		}))
+		first := visited
+		visited = nil
+		Ω(Walk(Design, visitor)).Should(Succeed())
+		Ω(visited).Should(Equal(first))
+	})
+
+	It("stops at the first error", func() {
+		err := Walk(Design, func(node interface{}) error {
+			if _, ok := node.(*ResourceDefinition); ok {
+				return errors.New("stop")
+			}
+			return visitor(node)
+		})
+		Ω(err).Should(MatchError("stop"))
+		Ω(visited).ShouldNot(ContainElement("action show"))
+	})
+
+	It("panics with a helpful message given a nil visitor", func() {
+		Ω(func() { Walk(Design, nil) }).Should(Panic())
+		defer func() {
+			Ω(recover()).Should(ContainSubstring("visitor cannot be nil"))
+		}()
+		Walk(Design, nil)
+	})
+})
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/goadesign/goa/dslengine"
@@ -61,6 +62,31 @@ func (r *routeInfo) DifferentWildcards(other *routeInfo) (res [][2]*wildCardInfo
 	return
 }
 
+// ValidateRoutes returns an error for each action route that uses the same HTTP method and path
+// as a route defined before it. Paths are compared after removing the wildcard names so that
+// "GET /bottles/:id" and "GET /bottles/:bottleID" conflict.
+func ValidateRoutes(api *APIDefinition) []error {
+	var errs []error
+	seen := make(map[string]*RouteDefinition)
+	api.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(a *ActionDefinition) error {
+			for _, ro := range a.Routes {
+				key := ro.Verb + " " + WildcardRegex.ReplaceAllStringFunc(ro.FullPath(), func(w string) string {
+					return w[:2] // keep the wildcard kind, "/:" or "/*"
+				})
+				if other, ok := seen[key]; ok {
+					errs = append(errs, fmt.Errorf("route %s %s of %s duplicates route %s %s of %s",
+						ro.Verb, ro.FullPath(), a.Context(), other.Verb, other.FullPath(), other.Parent.Context()))
+					continue
+				}
+				seen[key] = ro
+			}
+			return nil
+		})
+	})
+	return errs
+}
+
 // Validate tests whether the API definition is consistent: all resource parent names resolve to
 // an actual resource.
 func (a *APIDefinition) Validate() error {
@@ -84,6 +110,8 @@ func (a *APIDefinition) Validate() error {
 	a.validateLicense(verr)
 	a.validateDocs(verr)
 	a.validateOrigins(verr)
+	a.validateSunset(verr)
+	a.validateContextKeys(verr)
 
 	var allRoutes []*routeInfo
 	a.IterateResources(func(r *ResourceDefinition) error {
@@ -192,6 +220,83 @@ func (a *APIDefinition) validateDocs(verr *dslengine.ValidationErrors) {
 	}
 }
 
+func (a *APIDefinition) validateSunset(verr *dslengine.ValidationErrors) {
+	if a.Sunset == nil {
+		return
+	}
+	if a.Sunset.Date.IsZero() {
+		verr.Add(a, "sunset date must be set")
+	}
+	if r := a.Sunset.RedirectTo; r != "" && !strings.HasPrefix(r, "/") {
+		if u, err := url.Parse(r); err != nil || u.Scheme == "" || u.Host == "" {
+			verr.Add(a, `invalid sunset redirect "%s", must be a path or an absolute URL`, r)
+		}
+	}
+}
+
+// validateContextKeys checks that the context keys with the same name have the same type and that
+// the action context keys do not conflict with the action parameters.
+func (a *APIDefinition) validateContextKeys(verr *dslengine.ValidationErrors) {
+	types := make(map[string]string)
+	check := func(def dslengine.Definition, keys *AttributeDefinition) {
+		if keys == nil {
+			return
+		}
+		keys.Type.ToObject().IterateAttributes(func(n string, att *AttributeDefinition) error {
+			id := typeID(att.Type)
+			if t, ok := types[n]; ok && t != id {
+				verr.Add(def, `context key "%s" is defined with different types`, n)
+			}
+			types[n] = id
+			return nil
+		})
+	}
+	check(a, a.ContextKeys)
+	a.IterateResources(func(r *ResourceDefinition) error {
+		check(r, r.ContextKeys)
+		return r.IterateActions(func(ac *ActionDefinition) error {
+			check(ac, ac.ContextKeys)
+			keys := ac.AllContextKeys()
+			if keys == nil {
+				return nil
+			}
+			params := make(map[string]bool)
+			for n := range ac.AllParams().Type.ToObject() {
+				params[n] = true
+			}
+			for _, route := range ac.Routes {
+				for _, p := range route.Params() {
+					params[p] = true
+				}
+			}
+			for n := range keys.Type.ToObject() {
+				if params[n] {
+					verr.Add(ac, `context key "%s" conflicts with the parameter with the same name`, n)
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// typeID returns a string that identifies the given data type.
+func typeID(dt DataType) string {
+	switch t := dt.(type) {
+	case *MediaTypeDefinition:
+		return "media:" + t.Identifier
+	case *UserTypeDefinition:
+		return "type:" + t.TypeName
+	case *Array:
+		return "array:" + typeID(t.ElemType.Type)
+	case *Hash:
+		return "hash:" + typeID(t.KeyType.Type) + ":" + typeID(t.ElemType.Type)
+	case Object:
+		return "object"
+	default:
+		return dt.Name() + ":" + strconv.Itoa(int(dt.Kind()))
+	}
+}
+
 func (a *APIDefinition) validateOrigins(verr *dslengine.ValidationErrors) {
 	for _, origin := range a.Origins {
 		verr.Merge(origin.Validate())
@@ -232,9 +337,37 @@ func (r *ResourceDefinition) validateActions(verr *dslengine.ValidationErrors) {
 	for _, f := range r.FileServers {
 		verr.Merge(f.Validate())
 	}
+	for _, sa := range r.StaticAssets {
+		verr.Merge(sa.Validate())
+	}
 	if r.CanonicalActionName != "" && !found {
 		verr.Add(r, `unknown canonical action "%s"`, r.CanonicalActionName)
 	}
+	r.validateOptionalSegments(verr)
+}
+
+func (r *ResourceDefinition) validateOptionalSegments(verr *dslengine.ValidationErrors) {
+	if len(r.OptionalSegments) == 0 {
+		return
+	}
+	canonical := r.URITemplate()
+	if canonical == "" {
+		verr.Add(r, "optional segments require a canonical action")
+		return
+	}
+	for _, seg := range r.OptionalSegments {
+		if !strings.HasPrefix(seg, "/") || strings.HasSuffix(seg, "/") {
+			verr.Add(r, `optional segment "%s" must start with "/" and not end with "/"`, seg)
+			continue
+		}
+		if len(ExtractWildcards(seg)) == 0 {
+			verr.Add(r, `optional segment "%s" must contain at least one wildcard`, seg)
+			continue
+		}
+		if !strings.Contains(canonical+"/", seg+"/") {
+			verr.Add(r, `optional segment "%s" is not part of the canonical path "%s"`, seg, canonical)
+		}
+	}
 }
 
 func (r *ResourceDefinition) validateParent(verr *dslengine.ValidationErrors) {
@@ -331,10 +464,93 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	verr.Merge(a.ValidateParams())
 	if a.Payload != nil {
 		verr.Merge(a.Payload.Validate("action payload", a))
+		a.validateFormData(verr)
 	}
 	if a.Parent == nil {
 		verr.Add(a, "missing parent resource")
 	}
+	if a.Webhook != nil {
+		for _, r := range a.Routes {
+			if r.Verb != "POST" {
+				verr.Add(a, "webhook receiver action route %s %s must use POST", r.Verb, r.Path)
+			}
+		}
+	}
+	ws := a.Parent != nil && a.WebSocket()
+	if ws {
+		for _, r := range a.Routes {
+			if r.Verb != "GET" {
+				verr.Add(a, "WebSocket action route %s %s must use GET", r.Verb, r.Path)
+			}
+		}
+		if a.Webhook != nil {
+			verr.Add(a, "WebSocket action cannot be a webhook receiver")
+		}
+	}
+	if a.LongPoll {
+		for _, r := range a.Routes {
+			if r.Verb != "GET" {
+				verr.Add(a, "LongPoll action route %s %s must use GET", r.Verb, r.Path)
+			}
+		}
+		if ws {
+			verr.Add(a, "WebSocket action cannot be a long-poll action")
+		}
+		if a.UnionResult {
+			verr.Add(a, "LongPoll action cannot return a union result")
+		}
+	}
+	if a.Audit != nil {
+		for _, r := range a.Routes {
+			if r.Verb == "GET" || r.Verb == "HEAD" || r.Verb == "OPTIONS" {
+				verr.Add(a, "audited action route %s %s must use a state-changing method", r.Verb, r.Path)
+			}
+		}
+		if ws {
+			verr.Add(a, "WebSocket action cannot be audited")
+		}
+		found := a.AllParams().Type.ToObject()[a.Audit.Resource] != nil
+		for _, r := range a.Routes {
+			for _, p := range r.Params() {
+				found = found || p == a.Audit.Resource
+			}
+		}
+		if !found {
+			verr.Add(a, "audit resource %#v is not a parameter of the action", a.Audit.Resource)
+		}
+	}
+	if a.UnionResult {
+		a.validateUnionResult(verr)
+	}
+	if a.JSONMergePatch {
+		for _, r := range a.Routes {
+			if r.Verb != "PATCH" {
+				verr.Add(a, "JSONMergePatch action route %s %s must use PATCH", r.Verb, r.Path)
+			}
+		}
+		if a.Payload == nil || !a.Payload.IsObject() || a.Payload.Discriminator != nil {
+			verr.Add(a, "JSONMergePatch action must define an object payload")
+		} else if a.PayloadMediaType != nil {
+			// The MergePatch method is generated for the payload type only, the values of
+			// the media type could not be patched.
+			verr.Add(a, "JSONMergePatch action payload cannot be the media type %s, define it with Type", a.PayloadMediaType.Identifier)
+		}
+		if a.FormData {
+			verr.Add(a, "JSONMergePatch action cannot read its payload from a form")
+		}
+	}
+	if a.MultipartIngest && (a.FormData || a.JSONMergePatch) {
+		verr.Add(a, "MultipartIngest action cannot read its payload from a form or a merge patch")
+	}
+	if len(a.PatchFormats) > 0 {
+		patch := false
+		for _, r := range a.Routes {
+			patch = patch || r.Verb == "PATCH"
+		}
+		if !patch {
+			verr.Add(a, "AcceptPatch requires a PATCH route")
+		}
+	}
 
 	return verr.AsError()
 }
@@ -364,6 +580,88 @@ func (f *FileServerDefinition) Validate() *dslengine.ValidationErrors {
 	return verr.AsError()
 }
 
+// Validate checks the static assets endpoint is properly initialized.
+func (sa *StaticAssetsDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if sa.FilePath == "" {
+		verr.Add(sa, "static assets must have a non empty directory path")
+	}
+	if sa.Parent == nil {
+		verr.Add(sa, "missing parent resource")
+	}
+	if WildcardRegex.MatchString(sa.RequestPath) {
+		verr.Add(sa, "invalid request path %s, static assets paths cannot contain wildcards", sa.RequestPath)
+	}
+	return verr.AsError()
+}
+
+// validateUnionResult checks that the action defines at least two success responses, that the
+// success responses use distinct media types and that the action result can be returned by the
+// controller.
+func (a *ActionDefinition) validateUnionResult(verr *dslengine.ValidationErrors) {
+	if a.Parent != nil && a.WebSocket() {
+		verr.Add(a, "WebSocket action cannot return a union result")
+	}
+	if a.Audit != nil {
+		verr.Add(a, "audited action cannot return a union result")
+	}
+	names := make([]string, 0, len(a.Responses))
+	for name := range a.Responses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seen := make(map[string]string)
+	count := 0
+	for _, name := range names {
+		r := a.Responses[name]
+		if r.Status < 200 || r.Status >= 300 {
+			continue
+		}
+		mt, ok := r.Type.(*MediaTypeDefinition)
+		if !ok {
+			mt = Design.MediaTypeWithIdentifier(r.MediaType)
+		}
+		if mt == nil {
+			verr.Add(a, "union result response %#v must use a media type", name)
+			continue
+		}
+		if r.RetryAfter != 0 {
+			verr.Add(a, "union result response %#v cannot set a Retry-After delay", name)
+		}
+		if other, ok := seen[mt.Identifier]; ok {
+			verr.Add(a, "union result responses %#v and %#v use the same media type %s", other, name, mt.Identifier)
+		}
+		seen[mt.Identifier] = name
+		count++
+	}
+	if count < 2 {
+		verr.Add(a, "union result action must define at least two success responses")
+	}
+}
+
+// validateFormData checks that the attributes of multipart/form-data payloads are primitives and
+// that FileType attributes are only used in such payloads.
+func (a *ActionDefinition) validateFormData(verr *dslengine.ValidationErrors) {
+	obj := a.Payload.Type.ToObject()
+	if !a.FormData {
+		for n, att := range obj {
+			if att.Type.Kind() == FileKind {
+				verr.Add(a, "payload attribute %#v has type FileType which is only valid in FormData payloads", n)
+			}
+		}
+		return
+	}
+	if obj == nil {
+		verr.Add(a, "FormData payload must be an object")
+		return
+	}
+	for n, att := range obj {
+		if !att.Type.IsPrimitive() || att.Type.Kind() == AnyKind {
+			verr.Add(a, "FormData payload attribute %#v must be a primitive or a FileType", n)
+		}
+	}
+}
+
 // ValidateParams checks the action parameters (make sure they have names, members and types).
 func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
@@ -463,6 +761,9 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 		}
 		for n, att := range o {
 			ctx = fmt.Sprintf("field %s", n)
+			if len(att.VisibleTo()) > 0 && (a.IsRequired(n) || a.HasDefaultValue(n) || att.IsNullable()) {
+				verr.Add(parent, "%s is visible to specific roles only and cannot be required, have a default value or be nullable", ctx)
+			}
 			verr.Merge(att.Validate(ctx, parent))
 		}
 	} else {
@@ -485,6 +786,23 @@ func (r *ResponseDefinition) Validate() *dslengine.ValidationErrors {
 	if r.Status == 0 {
 		verr.Add(r, "response status not defined")
 	}
+	names := make(map[string]bool, len(r.Parts))
+	for _, p := range r.Parts {
+		if p.Name == "" {
+			verr.Add(r, "multipart response part must have a name")
+			continue
+		}
+		if names[p.Name] {
+			verr.Add(r, "multipart response part %#v is defined twice", p.Name)
+		}
+		names[p.Name] = true
+		if p.ContentType == "" {
+			verr.Add(r, "multipart response part %#v must define a content type", p.Name)
+		}
+		if _, ok := p.Type.(*MediaTypeDefinition); !ok && p.Type != BinaryType {
+			verr.Add(r, "type of multipart response part %#v must be a media type or BinaryType", p.Name)
+		}
+	}
 	return verr.AsError()
 }
 
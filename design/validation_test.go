@@ -272,6 +272,21 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("with a required field visible to specific roles", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						VisibleTo("admin")
+					})
+					Required(attName)
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
 		Context("with a required field validation", func() {
 			BeforeEach(func() {
 				dsl = func() {
@@ -288,3 +303,85 @@ var _ = Describe("Validation", func() {
 		})
 	})
 })
+
+var _ = Describe("ValidateRoutes", func() {
+	var dsl func()
+	var errs []error
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", nil)
+		dsl()
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		errs = ValidateRoutes(Design)
+	})
+
+	Context("with distinct routes", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Resource("bottle", func() {
+					Action("show", func() {
+						Routing(GET("/bottles/:id"))
+					})
+					Action("update", func() {
+						Routing(PUT("/bottles/:id"))
+					})
+					Action("list", func() {
+						Routing(GET("/bottles"))
+					})
+				})
+			}
+		})
+
+		It("does not return any error", func() {
+			Ω(errs).Should(BeEmpty())
+		})
+	})
+
+	Context("with a route defined twice", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Resource("bottle", func() {
+					Action("show", func() {
+						Routing(GET("/bottles/:id"))
+					})
+					Action("get", func() {
+						Routing(GET("/bottles/:id"))
+					})
+				})
+			}
+		})
+
+		It("returns an error for the duplicate", func() {
+			Ω(errs).Should(HaveLen(1))
+			Ω(errs[0].Error()).Should(Equal(`route GET /bottles/:id of resource "bottle" action "show" duplicates route GET /bottles/:id of resource "bottle" action "get"`))
+		})
+	})
+
+	Context("with duplicate routes in different resources", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Resource("bottle", func() {
+					BasePath("/bottles")
+					Action("show", func() {
+						Routing(GET("/:id"))
+					})
+				})
+				Resource("wine", func() {
+					Action("show", func() {
+						Routing(GET("/bottles/:id"))
+					})
+				})
+			}
+		})
+
+		It("returns an error for the duplicate", func() {
+			Ω(errs).Should(HaveLen(1))
+			Ω(errs[0].Error()).Should(ContainSubstring(`route GET /bottles/:id of resource "wine" action "show" duplicates`))
+		})
+	})
+})
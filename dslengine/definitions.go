@@ -1,6 +1,9 @@
 package dslengine
 
-import "fmt"
+import (
+	"fmt"
+	"math/big"
+)
 
 type (
 
@@ -103,6 +106,29 @@ type (
 		// Required list the required fields of object attributes as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor61.
 		Required []string
+		// IPVersion restricts the values of IP attributes to IPv4 (4) or IPv6 (6) addresses.
+		IPVersion int
+		// BoundingBox restricts the values of LatLon attributes to the given area.
+		BoundingBox *BoundingBoxDefinition
+		// MaxDepth is the maximum number of objects, arrays and maps nested in the values of
+		// the attribute, the attribute value itself counts as one level.
+		MaxDepth int
+		// BigMinimum is the minimum value of BigInt attributes.
+		BigMinimum *big.Int
+		// BigMaximum is the maximum value of BigInt attributes.
+		BigMaximum *big.Int
+	}
+
+	// BoundingBoxDefinition describes the area delimited by two latitudes and two longitudes.
+	BoundingBoxDefinition struct {
+		// MinLat is the southern boundary.
+		MinLat float64
+		// MaxLat is the northern boundary.
+		MaxLat float64
+		// MinLon is the western boundary.
+		MinLon float64
+		// MaxLon is the eastern boundary.
+		MaxLon float64
 	}
 )
 
@@ -147,6 +173,21 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	if v.MaxLength == nil || (other.MaxLength != nil && *v.MaxLength < *other.MaxLength) {
 		v.MaxLength = other.MaxLength
 	}
+	if v.IPVersion == 0 {
+		v.IPVersion = other.IPVersion
+	}
+	if v.BoundingBox == nil {
+		v.BoundingBox = other.BoundingBox
+	}
+	if v.MaxDepth == 0 || (other.MaxDepth != 0 && v.MaxDepth > other.MaxDepth) {
+		v.MaxDepth = other.MaxDepth
+	}
+	if v.BigMinimum == nil || (other.BigMinimum != nil && v.BigMinimum.Cmp(other.BigMinimum) > 0) {
+		v.BigMinimum = other.BigMinimum
+	}
+	if v.BigMaximum == nil || (other.BigMaximum != nil && v.BigMaximum.Cmp(other.BigMaximum) < 0) {
+		v.BigMaximum = other.BigMaximum
+	}
 	v.AddRequired(other.Required)
 }
 
@@ -171,10 +212,10 @@ func (v *ValidationDefinition) HasRequiredOnly() bool {
 	if len(v.Values) > 0 {
 		return false
 	}
-	if v.Format != "" || v.Pattern != "" {
+	if v.Format != "" || v.Pattern != "" || v.IPVersion != 0 || v.BoundingBox != nil || v.MaxDepth != 0 {
 		return false
 	}
-	if (v.Minimum != nil) || (v.Maximum != nil) || (v.MaxLength != nil) {
+	if (v.Minimum != nil) || (v.Maximum != nil) || (v.MaxLength != nil) || (v.BigMinimum != nil) || (v.BigMaximum != nil) {
 		return false
 	}
 	return true
@@ -183,13 +224,18 @@ func (v *ValidationDefinition) HasRequiredOnly() bool {
 // Dup makes a shallow dup of the validation.
 func (v *ValidationDefinition) Dup() *ValidationDefinition {
 	return &ValidationDefinition{
-		Values:    v.Values,
-		Format:    v.Format,
-		Pattern:   v.Pattern,
-		Minimum:   v.Minimum,
-		Maximum:   v.Maximum,
-		MinLength: v.MinLength,
-		MaxLength: v.MaxLength,
-		Required:  v.Required,
+		Values:      v.Values,
+		Format:      v.Format,
+		Pattern:     v.Pattern,
+		Minimum:     v.Minimum,
+		Maximum:     v.Maximum,
+		MinLength:   v.MinLength,
+		MaxLength:   v.MaxLength,
+		Required:    v.Required,
+		IPVersion:   v.IPVersion,
+		BoundingBox: v.BoundingBox,
+		MaxDepth:    v.MaxDepth,
+		BigMinimum:  v.BigMinimum,
+		BigMaximum:  v.BigMaximum,
 	}
 }
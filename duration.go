@@ -0,0 +1,67 @@
+package goa
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Lengths used to convert the ISO 8601 calendar components which do not have a fixed duration.
+const (
+	// ISO8601Year is the duration of one year ("P1Y").
+	ISO8601Year = 365 * 24 * time.Hour
+	// ISO8601Month is the duration of one month ("P1M").
+	ISO8601Month = 30 * 24 * time.Hour
+)
+
+// iso8601DurationRegex matches ISO 8601 durations, e.g. "P1Y2M3W4DT5H6M7.5S".
+var iso8601DurationRegex = regexp.MustCompile(`^(-)?P(?:(\d+(?:[.,]\d+)?)Y)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// iso8601Units lists the durations of the components captured by iso8601DurationRegex in order.
+var iso8601Units = []time.Duration{
+	ISO8601Year,
+	ISO8601Month,
+	7 * 24 * time.Hour,
+	24 * time.Hour,
+	time.Hour,
+	time.Minute,
+	time.Second,
+}
+
+// ParseDuration parses a duration expressed either in the Go format accepted by
+// time.ParseDuration (e.g. "1h30m") or in the ISO 8601 format (e.g. "PT1H30M").
+func ParseDuration(s string) (time.Duration, error) {
+	if strings.HasPrefix(s, "P") || strings.HasPrefix(s, "-P") {
+		return ParseISO8601Duration(s)
+	}
+	return time.ParseDuration(s)
+}
+
+// ParseISO8601Duration parses an ISO 8601 duration such as "P1DT12H" or "PT1H30M". All the
+// period components (years, months, weeks, days, hours, minutes and seconds) are supported and
+// may have a fractional part. Years and months are converted using ISO8601Year and
+// ISO8601Month respectively.
+func ParseISO8601Duration(s string) (time.Duration, error) {
+	matches := iso8601DurationRegex.FindStringSubmatch(s)
+	if matches == nil || strings.HasSuffix(s, "P") || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %#v", s)
+	}
+	var d time.Duration
+	for i, unit := range iso8601Units {
+		val := matches[i+2]
+		if val == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.Replace(val, ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %#v, %s", s, err)
+		}
+		d += time.Duration(f * float64(unit))
+	}
+	if matches[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
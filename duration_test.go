@@ -0,0 +1,108 @@
+package goa_test
+
+import (
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseISO8601Duration", func() {
+	var val string
+	var d time.Duration
+	var err error
+
+	JustBeforeEach(func() {
+		d, err = goa.ParseISO8601Duration(val)
+	})
+
+	Context("with days", func() {
+		BeforeEach(func() {
+			val = "P1D"
+		})
+
+		It("parses", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(d).Should(Equal(24 * time.Hour))
+		})
+	})
+
+	Context("with hours and minutes", func() {
+		BeforeEach(func() {
+			val = "PT1H30M"
+		})
+
+		It("parses", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(d).Should(Equal(90 * time.Minute))
+		})
+	})
+
+	Context("with years and months", func() {
+		BeforeEach(func() {
+			val = "P1Y6M"
+		})
+
+		It("parses", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(d).Should(Equal(goa.ISO8601Year + 6*goa.ISO8601Month))
+		})
+	})
+
+	Context("with days and hours", func() {
+		BeforeEach(func() {
+			val = "P1DT12H"
+		})
+
+		It("parses", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(d).Should(Equal(36 * time.Hour))
+		})
+	})
+
+	Context("with fractional seconds", func() {
+		BeforeEach(func() {
+			val = "PT0.5S"
+		})
+
+		It("parses", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(d).Should(Equal(500 * time.Millisecond))
+		})
+	})
+
+	Context("with no component", func() {
+		BeforeEach(func() {
+			val = "P"
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an empty time part", func() {
+		BeforeEach(func() {
+			val = "P1DT"
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("ParseDuration", func() {
+	It("parses Go durations", func() {
+		d, err := goa.ParseDuration("1h30m")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d).Should(Equal(90 * time.Minute))
+	})
+
+	It("parses ISO 8601 durations", func() {
+		d, err := goa.ParseDuration("PT1H30M")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d).Should(Equal(90 * time.Minute))
+	})
+})
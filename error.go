@@ -54,6 +54,10 @@ var (
 	// ErrInvalidEncoding is the error produced when a request body fails to be decoded.
 	ErrInvalidEncoding = NewErrorClass("invalid_encoding", 400)
 
+	// ErrUnsupportedMediaType is the error produced when the content of a request body does not
+	// match its Content-Type header, for example a XML body sent with a JSON content type.
+	ErrUnsupportedMediaType = NewErrorClass("unsupported_media_type", 415)
+
 	// ErrRequestBodyTooLarge is the error produced when the size of a request body exceeds
 	// MaxRequestBodyLength bytes.
 	ErrRequestBodyTooLarge = NewErrorClass("request_too_large", 413)
@@ -202,6 +206,20 @@ func InvalidPatternError(ctx, target string, pattern string) error {
 	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "regexp", pattern)
 }
 
+// InvalidDiscriminatorError is the error produced when the value of the discriminator field of a
+// polymorphic payload does not match any of the types defined in the design.
+func InvalidDiscriminatorError(field string, val interface{}, allowed []string) error {
+	msg := fmt.Sprintf("value of %s must be one of %s but got value %#v", field, strings.Join(allowed, ", "), val)
+	return ErrInvalidRequest(msg, "attribute", field, "value", val, "expected", strings.Join(allowed, ", "))
+}
+
+// InvalidBoundingBoxError is the error produced when the value of a coordinate parameter or
+// payload field lies outside of the bounding box validation defined in the design.
+func InvalidBoundingBoxError(ctx string, target LatLon, boxError error) error {
+	msg := fmt.Sprintf("%s must be within the bounding box but got value %#v, %s", ctx, target.String(), boxError.Error())
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", target.String(), "error", boxError.Error())
+}
+
 // InvalidRangeError is the error produced when the value of a parameter or payload field does
 // not match the range validation defined in the design. value may be a int or a float64.
 func InvalidRangeError(ctx string, target interface{}, value interface{}, min bool) error {
@@ -224,6 +242,14 @@ func InvalidLengthError(ctx string, target interface{}, ln, value int, min bool)
 	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "len", ln, "comp", comp, "expected", value)
 }
 
+// InvalidDepthError is the error produced when the value of a payload field nests more objects,
+// arrays or maps than allowed by the max depth validation defined in the design. ctx identifies
+// the field defining the maximum depth.
+func InvalidDepthError(ctx string, max int) error {
+	msg := fmt.Sprintf("%s exceeds the maximum nesting depth of %d", ctx, max)
+	return ErrInvalidRequest(msg, "attribute", ctx, "expected", max)
+}
+
 // NoAuthMiddleware is the error produced when goa is unable to lookup a auth middleware for a
 // security scheme defined in the design.
 func NoAuthMiddleware(schemeName string) error {
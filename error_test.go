@@ -183,6 +183,26 @@ var _ = Describe("InvalidPatternError", func() {
 	})
 })
 
+var _ = Describe("InvalidDiscriminatorError", func() {
+	var valErr error
+	field := "type"
+	val := "bird"
+	allowed := []string{"cat", "dog"}
+
+	JustBeforeEach(func() {
+		valErr = InvalidDiscriminatorError(field, val, allowed)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(field))
+		Ω(err.Detail).Should(ContainSubstring(`"bird"`))
+		Ω(err.Detail).Should(ContainSubstring("cat, dog"))
+	})
+})
+
 var _ = Describe("InvalidRangeError", func() {
 	var valErr error
 	var value interface{}
@@ -228,6 +248,21 @@ var _ = Describe("InvalidRangeError", func() {
 	})
 })
 
+var _ = Describe("InvalidDepthError", func() {
+	const ctx = "payload.tree"
+	const max = 2
+
+	It("creates a http error", func() {
+		valErr := InvalidDepthError(ctx, max)
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Status).Should(Equal(400))
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("maximum nesting depth of 2"))
+	})
+})
+
 var _ = Describe("InvalidLengthError", func() {
 	const ctx = "ctx"
 	const value = 42
@@ -55,6 +55,9 @@ func RecursiveFinalizer(att *design.AttributeDefinition, target string, depth in
 			att = ut.AttributeDefinition
 		}
 		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			if catt.IsFreeForm() {
+				return nil
+			}
 			if att.HasDefaultValue(n) {
 				data := map[string]interface{}{
 					"target":     target,
@@ -62,7 +65,7 @@ func RecursiveFinalizer(att *design.AttributeDefinition, target string, depth in
 					"catt":       catt,
 					"depth":      depth,
 					"isDatetime": catt.Type == design.DateTime,
-					"defaultVal": printVal(catt.Type, catt.DefaultValue),
+					"defaultVal": PrintVal(catt.Type, catt.DefaultValue),
 				}
 				assignments = append(assignments, RunTemplate(assignmentT, data))
 			}
@@ -95,9 +98,9 @@ func RecursiveFinalizer(att *design.AttributeDefinition, target string, depth in
 	return strings.Join(assignments, "\n")
 }
 
-// printVal prints the given value corresponding to the given data type.
+// PrintVal prints the Go literal of the given value corresponding to the given data type.
 // The value is already checked for the compatibility with the data type.
-func printVal(t design.DataType, val interface{}) string {
+func PrintVal(t design.DataType, val interface{}) string {
 	switch {
 	case t.IsPrimitive():
 		// For primitive types, simply print the value
@@ -116,7 +119,7 @@ func printVal(t design.DataType, val interface{}) string {
 		var buffer bytes.Buffer
 		buffer.WriteString(fmt.Sprintf("%s{", GoTypeName(t, nil, 0, false)))
 		for k, v := range hval {
-			buffer.WriteString(fmt.Sprintf("%s: %s, ", printVal(h.KeyType.Type, k), printVal(h.ElemType.Type, v)))
+			buffer.WriteString(fmt.Sprintf("%s: %s, ", PrintVal(h.KeyType.Type, k), PrintVal(h.ElemType.Type, v)))
 		}
 		buffer.Truncate(buffer.Len() - 2) // remove ", "
 		buffer.WriteString("}")
@@ -131,7 +134,7 @@ func printVal(t design.DataType, val interface{}) string {
 		var buffer bytes.Buffer
 		buffer.WriteString(fmt.Sprintf("%s{", GoTypeName(t, nil, 0, false)))
 		for _, e := range aval {
-			buffer.WriteString(fmt.Sprintf("%s, ", printVal(a.ElemType.Type, e)))
+			buffer.WriteString(fmt.Sprintf("%s, ", PrintVal(a.ElemType.Type, e)))
 		}
 		buffer.Truncate(buffer.Len() - 2) // remove ", "
 		buffer.WriteString("}")
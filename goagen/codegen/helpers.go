@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -132,6 +133,43 @@ func CanonicalParams(r *design.ResourceDefinition) []string {
 // Casing exceptions
 var toLower = map[string]string{"OAuth": "oauth"}
 
+// SuccessStatus returns the lowest 2xx status declared by the action responses, 0 if there is
+// none.
+func SuccessStatus(a *design.ActionDefinition) int {
+	var statuses []int
+	for _, resp := range a.Responses {
+		if resp.Status >= 200 && resp.Status < 300 {
+			statuses = append(statuses, resp.Status)
+		}
+	}
+	if len(statuses) == 0 {
+		return 0
+	}
+	sort.Ints(statuses)
+	return statuses[0]
+}
+
+// HasRequired returns true if att defines a required attribute other than the ones listed in
+// except.
+func HasRequired(att *design.AttributeDefinition, except ...string) bool {
+	if att == nil || att.Validation == nil {
+		return false
+	}
+	for _, r := range att.Validation.Required {
+		found := false
+		for _, e := range except {
+			if r == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
+
 // SnakeCase produces the snake_case version of the given CamelCase string.
 func SnakeCase(name string) string {
 	for u, l := range toLower {
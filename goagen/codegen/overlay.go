@@ -0,0 +1,62 @@
+package codegen
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"text/template/parse"
+)
+
+// GoGenConfig configures the Go code generators.
+type GoGenConfig struct {
+	// OverlayDir is the path to the directory containing the user templates that override the
+	// built-in templates. The file <name>.tmpl overrides the template stored in the Go constant
+	// <name>, e.g. ctxT.tmpl overrides the template that generates the action context data
+	// structures.
+	OverlayDir string
+	// OverlayFS is the file system containing the user templates, it takes precedence over
+	// OverlayDir. It makes it possible to embed the templates with go:embed.
+	OverlayFS fs.FS
+}
+
+// GoGen is the configuration shared by the Go code generators.
+var GoGen = &GoGenConfig{}
+
+// Overrides returns the sources of the user templates that override the built-in templates with
+// the given names indexed by name. It returns an empty map if no overlay is configured and an
+// error if a user template cannot be parsed.
+func (c *GoGenConfig) Overrides(names ...string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	fsys := c.OverlayFS
+	if fsys == nil {
+		if c.OverlayDir == "" {
+			return overrides, nil
+		}
+		fsys = os.DirFS(c.OverlayDir)
+	}
+	for _, name := range names {
+		b, err := fs.ReadFile(fsys, name+".tmpl")
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if err := check(name, string(b)); err != nil {
+			return nil, err
+		}
+		overrides[name] = string(b)
+	}
+	return overrides, nil
+}
+
+// check parses the source of the user template with the given name. The functions used by the
+// template are not checked as they depend on the generator executing it.
+func check(name, src string) error {
+	t := parse.New(name)
+	t.Mode = parse.SkipFuncCheck
+	if _, err := t.Parse(src, "", "", make(map[string]*parse.Tree)); err != nil {
+		return fmt.Errorf("invalid overlay template %s.tmpl: %s", name, err)
+	}
+	return nil
+}
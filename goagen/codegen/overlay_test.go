@@ -0,0 +1,47 @@
+package codegen_test
+
+import (
+	"testing/fstest"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoGenConfig", func() {
+	var fsys fstest.MapFS
+	var overrides map[string]string
+	var err error
+
+	JustBeforeEach(func() {
+		config := &codegen.GoGenConfig{OverlayFS: fsys}
+		overrides, err = config.Overrides("ctxT", "mountT")
+	})
+
+	Context("with user templates", func() {
+		BeforeEach(func() {
+			fsys = fstest.MapFS{
+				"ctxT.tmpl":   {Data: []byte(`// {{ .Name }} uses {{ goify .Name true }}`)},
+				"otherT.tmpl": {Data: []byte(`{{ .Name }}`)},
+			}
+		})
+
+		It("returns the sources of the overridden templates", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(overrides).Should(Equal(map[string]string{"ctxT": `// {{ .Name }} uses {{ goify .Name true }}`}))
+		})
+	})
+
+	Context("with a malformed user template", func() {
+		BeforeEach(func() {
+			fsys = fstest.MapFS{
+				"mountT.tmpl": {Data: []byte(`{{ if .Name }}missing end`)},
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("mountT.tmpl"))
+		})
+	})
+})
@@ -82,7 +82,7 @@ func Publicizer(att *design.AttributeDefinition, sourceField, targetField string
 		"init":        init,
 	}
 	switch {
-	case att.Type.IsPrimitive():
+	case att.Type.IsPrimitive(), att.IsFreeForm():
 		publication = RunTemplate(simplePublicizeT, data)
 	case att.Type.IsObject():
 		if _, ok := att.Type.(*design.MediaTypeDefinition); ok {
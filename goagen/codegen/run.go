@@ -1,6 +1,12 @@
 package codegen
 
-import "github.com/goadesign/goa/dslengine"
+import (
+	"errors"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
 
 type generator interface {
 	Generate() ([]string, error)
@@ -35,4 +41,13 @@ func ParseDSL() {
 
 	// Catch any runtime errors, when analyzing the DSL
 	dslengine.FailOnError(dslengine.Run())
+
+	// Catch duplicate routes that would make the router panic at runtime
+	if errs := design.ValidateRoutes(design.Design); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		dslengine.FailOnError(errors.New(strings.Join(msgs, "\n")))
+	}
 }
@@ -20,6 +20,11 @@ var (
 	// TempCount holds the value appended to variable names to make them unique.
 	TempCount int
 
+	// OrderedFields defines the order of the fields of the struct types generated by GoTypeDef:
+	// "alpha" sorts the fields by attribute name and "design" uses the order in which the
+	// attributes are declared in the design.
+	OrderedFields = "alpha"
+
 	// Templates used by GoTypeTransform
 	transformT       *template.Template
 	transformArrayT  *template.Template
@@ -100,19 +105,17 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs int, jsonTags, private bool) string {
 	var buffer bytes.Buffer
 	buffer.WriteString("struct {\n")
-	keys := make([]string, len(obj))
-	i := 0
-	for n := range obj {
-		keys[i] = n
-		i++
-	}
-	sort.Strings(keys)
-	for _, name := range keys {
+	for _, name := range fieldNames(obj) {
 		WriteTabs(&buffer, tabs+1)
 		field := obj[name]
-		typedef := GoTypeDef(field, tabs+1, jsonTags, private)
-		if (field.Type.IsPrimitive() && private) || field.Type.IsObject() || def.IsPrimitivePointer(name) {
-			typedef = "*" + typedef
+		var typedef string
+		if field.IsFreeForm() {
+			typedef = "map[string]json.RawMessage"
+		} else {
+			typedef = GoTypeDef(field, tabs+1, jsonTags, private)
+			if (field.Type.IsPrimitive() && private) || field.Type.IsObject() || def.IsPrimitivePointer(name) {
+				typedef = "*" + typedef
+			}
 		}
 		fname := GoifyAtt(field, name, true)
 		var tags string
@@ -126,11 +129,44 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 		}
 		buffer.WriteString(fmt.Sprintf("%s%s %s%s\n", desc, fname, typedef, tags))
 	}
+	if private && tabs == 0 && len(RequiredNullable(def)) > 0 {
+		// Private types record the members set to null, see NullChecker.
+		buffer.WriteString("\tnulls map[string]bool\n")
+	}
 	WriteTabs(&buffer, tabs)
 	buffer.WriteString("}")
 	return buffer.String()
 }
 
+// SetOrderedFields sets OrderedFields after checking that order is "alpha" or "design". The
+// alphabetical order is used if order is empty.
+func SetOrderedFields(order string) error {
+	switch order {
+	case "":
+		OrderedFields = "alpha"
+	case "alpha", "design":
+		OrderedFields = order
+	default:
+		return fmt.Errorf("unsupported attribute order %#v, must be one of \"alpha\" or \"design\"", order)
+	}
+	return nil
+}
+
+// fieldNames returns the names of the object attributes in the order defined by OrderedFields.
+func fieldNames(obj design.Object) []string {
+	if OrderedFields == "design" {
+		return obj.DeclaredNames()
+	}
+	keys := make([]string, len(obj))
+	i := 0
+	for n := range obj {
+		keys[i] = n
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // attributeTags computes the struct field tags.
 func attributeTags(parent, att *design.AttributeDefinition, name string, private bool) string {
 	var elems []string
@@ -154,7 +190,7 @@ func attributeTags(parent, att *design.AttributeDefinition, name string, private
 	}
 	// Default algorithm
 	var omit string
-	if private || (!parent.IsRequired(name) && !parent.HasDefaultValue(name)) {
+	if private || (!parent.IsRequired(name) && !parent.HasDefaultValue(name) && !att.IsNullable()) {
 		omit = ",omitempty"
 	}
 	return fmt.Sprintf(" `form:\"%s%s\" json:\"%s%s\" xml:\"%s%s\"`", name, omit, name, omit, name, omit)
@@ -234,8 +270,28 @@ func GoNativeType(t design.DataType) string {
 			return "time.Time"
 		case design.UUIDKind:
 			return "uuid.UUID"
+		case design.IPKind:
+			return "net.IP"
+		case design.CIDRKind:
+			return "goa.CIDR"
+		case design.LatLonKind:
+			return "goa.LatLon"
+		case design.SemVerKind:
+			return "goa.SemVerString"
 		case design.AnyKind:
 			return "interface{}"
+		case design.FileKind:
+			return "*multipart.FileHeader"
+		case design.UintKind:
+			return "uint"
+		case design.Uint8Kind:
+			return "uint8"
+		case design.Uint16Kind:
+			return "uint16"
+		case design.Uint32Kind:
+			return "uint32"
+		case design.BigIntKind:
+			return "goa.BigInt"
 		default:
 			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
 		}
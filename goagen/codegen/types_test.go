@@ -1,6 +1,7 @@
 package codegen_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -176,6 +177,46 @@ var _ = Describe("code generation", func() {
 	})
 
 	Describe("GoTypeDef", func() {
+		Context("with the design field order", func() {
+			// typeDef evaluates the design and returns the generated struct definition.
+			typeDef := func() string {
+				dslengine.Reset()
+				ut := Type("Bottle", func() {
+					Attribute("vintage", Integer)
+					Attribute("name", String)
+					Attribute("rating", Number)
+					Attribute("color", String)
+				})
+				dslengine.Run()
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				return codegen.GoTypeDef(ut, 0, false, false)
+			}
+
+			BeforeEach(func() {
+				Ω(codegen.SetOrderedFields("design")).Should(Succeed())
+			})
+
+			AfterEach(func() {
+				Ω(codegen.SetOrderedFields("alpha")).Should(Succeed())
+			})
+
+			It("produces identical structs with the fields in declaration order", func() {
+				expected := "struct {\n" +
+					"	Vintage *int\n" +
+					"	Name *string\n" +
+					"	Rating *float64\n" +
+					"	Color *string\n" +
+					"}"
+				for i := 0; i < 10; i++ {
+					Ω(typeDef()).Should(Equal(expected))
+				}
+			})
+
+			It("rejects unknown orders", func() {
+				Ω(codegen.SetOrderedFields("random")).Should(HaveOccurred())
+			})
+		})
+
 		Context("given an attribute definition with fields", func() {
 			var att *AttributeDefinition
 			var object Object
@@ -256,6 +297,67 @@ var _ = Describe("code generation", func() {
 				})
 			})
 
+			Context("of nullable primitive types", func() {
+				BeforeEach(func() {
+					object = Object{
+						"count": &AttributeDefinition{
+							Type:     Integer,
+							Metadata: dslengine.MetadataDefinition{"struct:field:nullable": nil},
+						},
+						"total": &AttributeDefinition{Type: Integer},
+					}
+					required = &dslengine.ValidationDefinition{Required: []string{"count", "total"}}
+				})
+
+				It("produces pointer fields serialized as null when nil", func() {
+					expected := "struct {\n" +
+						"	Count *int `form:\"count\" json:\"count\" xml:\"count\"`\n" +
+						"	Total int `form:\"total\" json:\"total\" xml:\"total\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+
+				It("records the members set to null in private structs", func() {
+					st := codegen.GoTypeDef(att, 0, true, true)
+					Ω(st).Should(HaveSuffix("\tnulls map[string]bool\n}"))
+				})
+			})
+
+			Context("of free-form objects", func() {
+				BeforeEach(func() {
+					object = Object{
+						"metadata": &AttributeDefinition{
+							Type:     Object{},
+							Metadata: dslengine.MetadataDefinition{"struct:field:freeform": nil},
+						},
+					}
+					required = nil
+				})
+
+				It("produces raw JSON map fields", func() {
+					expected := "struct {\n" +
+						"	Metadata map[string]json.RawMessage `form:\"metadata,omitempty\" json:\"metadata,omitempty\" xml:\"metadata,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+
+				It("round-trips arbitrary JSON values", func() {
+					var v struct {
+						Metadata map[string]json.RawMessage `form:"metadata,omitempty" json:"metadata,omitempty" xml:"metadata,omitempty"`
+					}
+					err := json.Unmarshal([]byte(`{"metadata":{"count":5,"tag":"foo"}}`), &v)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := json.Marshal(&v)
+					Ω(err).ShouldNot(HaveOccurred())
+					var res map[string]map[string]interface{}
+					err = json.Unmarshal(b, &res)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(res["metadata"]).Should(HaveLen(2))
+					Ω(res["metadata"]["count"]).Should(BeEquivalentTo(5))
+					Ω(res["metadata"]["tag"]).Should(Equal("foo"))
+				})
+			})
+
 			Context("of hash of primitive types", func() {
 				BeforeEach(func() {
 					elemType := &AttributeDefinition{Type: Integer}
@@ -3,6 +3,7 @@ package codegen
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -11,17 +12,20 @@ import (
 )
 
 var (
-	arrayValT    *template.Template
-	userValT     *template.Template
-	enumValT     *template.Template
-	formatValT   *template.Template
-	patternValT  *template.Template
-	minMaxValT   *template.Template
-	lengthValT   *template.Template
-	requiredValT *template.Template
+	arrayValT       *template.Template
+	userValT        *template.Template
+	enumValT        *template.Template
+	formatValT      *template.Template
+	patternValT     *template.Template
+	ipVersionValT   *template.Template
+	boundingBoxValT *template.Template
+	minMaxValT      *template.Template
+	bigMinMaxValT   *template.Template
+	lengthValT      *template.Template
+	requiredValT    *template.Template
 )
 
-//  init instantiates the templates.
+// init instantiates the templates.
 func init() {
 	var err error
 	fm := template.FuncMap{
@@ -48,9 +52,18 @@ func init() {
 	if patternValT, err = template.New("pattern").Funcs(fm).Parse(patternValTmpl); err != nil {
 		panic(err)
 	}
+	if ipVersionValT, err = template.New("ipVersion").Funcs(fm).Parse(ipVersionValTmpl); err != nil {
+		panic(err)
+	}
+	if boundingBoxValT, err = template.New("boundingBox").Funcs(fm).Parse(boundingBoxValTmpl); err != nil {
+		panic(err)
+	}
 	if minMaxValT, err = template.New("minMax").Funcs(fm).Parse(minMaxValTmpl); err != nil {
 		panic(err)
 	}
+	if bigMinMaxValT, err = template.New("bigMinMax").Funcs(fm).Parse(bigMinMaxValTmpl); err != nil {
+		panic(err)
+	}
 	if lengthValT, err = template.New("length").Funcs(fm).Parse(lengthValTmpl); err != nil {
 		panic(err)
 	}
@@ -71,7 +84,16 @@ func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDef
 		if validation != "" {
 			checks = append(checks, validation)
 		}
+		if att.Validation != nil && att.Validation.MaxDepth > 0 {
+			if validation := DepthChecker(att, target, context, depth, att.Validation.MaxDepth, private); validation != "" {
+				checks = append(checks, validation)
+			}
+		}
 		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			if catt.IsFreeForm() {
+				// Free-form attributes hold arbitrary values that are not validated.
+				return nil
+			}
 			var validation string
 			if ds, ok := catt.Type.(design.DataStructure); ok {
 				// We need to check empirically whether there are validations to be
@@ -114,16 +136,28 @@ func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDef
 						},
 					)
 				}
+				if catt.Validation != nil && catt.Validation.MaxDepth > 0 {
+					ctarget := fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true))
+					ccontext := fmt.Sprintf("%s.%s", context, n)
+					if check := DepthChecker(catt, ctarget, ccontext, depth, catt.Validation.MaxDepth, private); check != "" {
+						if validation != "" {
+							validation += "\n"
+						}
+						validation += check
+					}
+				}
 			} else {
 				dp := depth
 				if catt.Type.IsObject() {
 					dp++
 				}
+				// Nullable attributes are always pointers
+				nullable := catt.IsNullable()
 				validation = RecursiveChecker(
 					catt,
-					att.IsNonZero(n),
-					att.IsRequired(n),
-					att.HasDefaultValue(n),
+					att.IsNonZero(n) && !nullable,
+					att.IsRequired(n) && !nullable,
+					att.HasDefaultValue(n) && !nullable,
 					fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
 					fmt.Sprintf("%s.%s", context, n),
 					dp,
@@ -145,6 +179,11 @@ func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDef
 		if validation != "" {
 			checks = append(checks, validation)
 		}
+		if att.Validation != nil && att.Validation.MaxDepth > 0 {
+			if validation := DepthChecker(att, target, context, depth, att.Validation.MaxDepth, private); validation != "" {
+				checks = append(checks, validation)
+			}
+		}
 		data := map[string]interface{}{
 			"elemType": a.ElemType,
 			"context":  context,
@@ -161,10 +200,210 @@ func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDef
 		if validation != "" {
 			checks = append(checks, validation)
 		}
+		if att.Type.IsHash() && att.Validation != nil && att.Validation.MaxDepth > 0 {
+			if validation := DepthChecker(att, target, context, depth, att.Validation.MaxDepth, private); validation != "" {
+				checks = append(checks, validation)
+			}
+		}
 	}
 	return strings.Join(checks, "\n")
 }
 
+// DepthChecker produces Go code that checks that the value of the given object, array or map
+// attribute does not nest more than max objects, arrays or maps. The generated code walks the value
+// with a depth counter. It declares one function per user type reached by the walk so that the
+// size of the code does not depend on max and that values of recursive types are supported.
+func DepthChecker(att *design.AttributeDefinition, target, context string, depth, max int, private bool) string {
+	c := &depthChecker{max: max, private: private, declared: make(map[string]bool)}
+	var check string
+	if name, ok := c.function(att.Type); ok {
+		check = fmt.Sprintf("%sif %s != nil && !%s(%s, 1) {\n", Tabs(depth), target, name, target)
+	} else {
+		body := c.body(att.Type, target, depthCounter{off: 1}, depth+1, 1)
+		if body == "" {
+			return ""
+		}
+		check = fmt.Sprintf("%sif !func() bool {\n%s\n%s\treturn true\n%s}() {\n",
+			Tabs(depth), body, Tabs(depth), Tabs(depth))
+	}
+	check += fmt.Sprintf("%s\terr = goa.MergeErrors(err, goa.InvalidDepthError(`%s`, %d))\n%s}",
+		Tabs(depth), context, max, Tabs(depth))
+	if len(c.pending) == 0 {
+		return check
+	}
+	var decls []string
+	for i := 0; i < len(c.pending); i++ {
+		decls = append(decls, c.declare(c.pending[i], depth+1))
+	}
+	return fmt.Sprintf("%s{\n%s\n%s\n%s}", Tabs(depth), strings.Join(decls, "\n"),
+		Indent(check, "\t"), Tabs(depth))
+}
+
+// depthChecker keeps track of the functions declared by the code generated by DepthChecker.
+type depthChecker struct {
+	// max is the maximum depth.
+	max int
+	// private is true if the checked values are of the private types used to unmarshal
+	// request bodies.
+	private bool
+	// declared records the names of the functions already declared.
+	declared map[string]bool
+	// pending lists the user types whose functions must be declared in declaration order.
+	pending []design.DataStructure
+}
+
+// depthCounter is the depth of a value in the generated code. It consists of the name of the
+// variable holding the depth of the enclosing user type value if any and an offset.
+type depthCounter struct {
+	name string
+	off  int
+}
+
+// String returns the Go expression that computes the depth.
+func (d depthCounter) String() string {
+	if d.name == "" {
+		return fmt.Sprintf("%d", d.off)
+	}
+	if d.off == 0 {
+		return d.name
+	}
+	return fmt.Sprintf("%s+%d", d.name, d.off)
+}
+
+// function returns the name of the function checking the values of the given user type and
+// records that the function must be declared. It returns false if the type is not a user type.
+func (c *depthChecker) function(t design.DataType) (string, bool) {
+	var typeName string
+	switch actual := t.(type) {
+	case *design.UserTypeDefinition:
+		typeName = actual.TypeName
+	case *design.MediaTypeDefinition:
+		typeName = actual.TypeName
+	default:
+		return "", false
+	}
+	name := "depth" + Goify(typeName, true)
+	if !c.declared[name] {
+		c.declared[name] = true
+		c.pending = append(c.pending, t.(design.DataStructure))
+	}
+	return name, true
+}
+
+// declare produces the declaration of the function checking the values of the given user type.
+func (c *depthChecker) declare(ds design.DataStructure, tabs int) string {
+	name, _ := c.function(ds.(design.DataType))
+	ref := GoTypeRef(ds.(design.DataType), nil, 0, c.private)
+	body := c.body(ds.Definition().Type, "v", depthCounter{name: "depth"}, tabs+1, 1)
+	if body != "" {
+		body += "\n"
+	}
+	return fmt.Sprintf("%svar %s func(v %s, depth int) bool\n"+
+		"%s%s = func(v %s, depth int) bool {\n"+
+		"%s\tif depth > %d {\n%s\t\treturn false\n%s\t}\n"+
+		"%s%s\treturn true\n%s}",
+		Tabs(tabs), name, ref,
+		Tabs(tabs), name, ref,
+		Tabs(tabs), c.max, Tabs(tabs), Tabs(tabs),
+		body, Tabs(tabs), Tabs(tabs))
+}
+
+// body produces the statements that check the values nested in the value of type t held by the
+// variable v at depth d. The statements return false if a value is nested too deep. level is
+// used to name the loop variables.
+func (c *depthChecker) body(t design.DataType, v string, d depthCounter, tabs, level int) string {
+	var checks []string
+	nested := func(ct design.DataType, cv string, tabs int) {
+		if !isNestable(ct) {
+			return
+		}
+		if mt, ok := ct.(*design.MediaTypeDefinition); ok && mt.IsError() {
+			return
+		}
+		cd := depthCounter{name: d.name, off: d.off + 1}
+		if name, ok := c.function(ct); ok {
+			checks = append(checks, fmt.Sprintf("%sif %s != nil && !%s(%s, %s) {\n%s\treturn false\n%s}",
+				Tabs(tabs), cv, name, cv, cd, Tabs(tabs), Tabs(tabs)))
+			return
+		}
+		var check string
+		switch {
+		case cd.name == "" && cd.off > c.max:
+			check = fmt.Sprintf("%s\treturn false", Tabs(tabs))
+		case cd.name == "":
+			check = c.body(ct, cv, cd, tabs+1, level+1)
+		default:
+			check = fmt.Sprintf("%s\tif %s > %d {\n%s\t\treturn false\n%s\t}", Tabs(tabs), cd, c.max, Tabs(tabs), Tabs(tabs))
+			if b := c.body(ct, cv, cd, tabs+1, level+1); b != "" {
+				check += "\n" + b
+			}
+		}
+		if check == "" {
+			return
+		}
+		checks = append(checks, fmt.Sprintf("%sif %s != nil {\n%s\n%s}", Tabs(tabs), cv, check, Tabs(tabs)))
+	}
+	ranged := func(elem *design.AttributeDefinition) {
+		e := fmt.Sprintf("e%d", level)
+		n := len(checks)
+		nested(elem.Type, e, tabs+1)
+		if len(checks) > n {
+			checks[n] = fmt.Sprintf("%sfor _, %s := range %s {\n%s\n%s}", Tabs(tabs), e, v, checks[n], Tabs(tabs))
+		}
+	}
+	if o := t.ToObject(); o != nil {
+		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			nested(catt.Type, fmt.Sprintf("%s.%s", v, GoifyAtt(catt, n, true)), tabs)
+			return nil
+		})
+	} else if a := t.ToArray(); a != nil {
+		ranged(a.ElemType)
+	} else if h := t.ToHash(); h != nil {
+		ranged(h.ElemType)
+	}
+	return strings.Join(checks, "\n")
+}
+
+// isNestable returns true if values of the given type add a nesting level.
+func isNestable(t design.DataType) bool {
+	return t.IsObject() || t.IsArray() || t.IsHash()
+}
+
+// NullChecker produces Go code that checks that the required nullable attributes of the given
+// object attribute are present in the private struct held by target. The struct fields of these
+// attributes are nil both when the attribute is missing and when it is set to null so the check
+// relies on the nulls field recorded by the UnmarshalJSON method of the struct.
+func NullChecker(att *design.AttributeDefinition, target, context string) string {
+	var checks []string
+	for _, n := range RequiredNullable(att) {
+		catt := att.Type.ToObject()[n]
+		checks = append(checks, fmt.Sprintf(
+			"\tif %s.%s == nil && !%s.nulls[%q] {\n\t\terr = goa.MergeErrors(err, goa.MissingAttributeError(`%s`, %q))\n\t}\n",
+			target, GoifyAtt(catt, n, true), target, n, context, n))
+	}
+	return strings.Join(checks, "")
+}
+
+// RequiredNullable returns the sorted names of the required attributes of the given object
+// attribute that are nullable.
+func RequiredNullable(att *design.AttributeDefinition) []string {
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return nil
+	}
+	if ds, ok := att.Type.(design.DataStructure); ok {
+		att = ds.Definition()
+	}
+	var names []string
+	for _, n := range att.AllRequired() {
+		if catt, ok := obj[n]; ok && catt.IsNullable() {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ValidationChecker produces Go code that runs the validation defined in the given attribute
 // definition against the content of the variable named target recursively.
 // context is used to keep track of recursion to produce helpful error messages in case of type
@@ -188,6 +427,7 @@ func ValidationChecker(att *design.AttributeDefinition, nonzero, required, hasDe
 		"string":    att.Type.Name() == "string",
 		"array":     att.Type.IsArray(),
 		"hash":      att.Type.IsHash(),
+		"unsigned":  att.Type.Kind().IsUnsigned(),
 		"depth":     depth,
 		"private":   private,
 	}
@@ -211,13 +451,26 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 			res = append(res, val)
 		}
 	}
+	if version := validation.IPVersion; version != 0 {
+		data["ipVersion"] = version
+		if val := RunTemplate(ipVersionValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if box := validation.BoundingBox; box != nil {
+		data["box"] = box
+		if val := RunTemplate(boundingBoxValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if pattern := validation.Pattern; pattern != "" {
 		data["pattern"] = pattern
 		if val := RunTemplate(patternValT, data); val != "" {
 			res = append(res, val)
 		}
 	}
-	if min := validation.Minimum; min != nil {
+	// Unsigned integers cannot be lower than a non-positive minimum, skip the check
+	if min := validation.Minimum; min != nil && !(data["unsigned"] == true && *min <= 0) {
 		data["min"] = *min
 		data["isMin"] = true
 		delete(data, "max")
@@ -233,6 +486,20 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 			res = append(res, val)
 		}
 	}
+	if min := validation.BigMinimum; min != nil {
+		data["bigBound"] = min.String()
+		data["isMin"] = true
+		if val := RunTemplate(bigMinMaxValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if max := validation.BigMaximum; max != nil {
+		data["bigBound"] = max.String()
+		data["isMin"] = false
+		if val := RunTemplate(bigMinMaxValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if minLength := validation.MinLength; minLength != nil {
 		data["minLength"] = minLength
 		data["isMinLength"] = true
@@ -291,6 +558,8 @@ func constant(formatName string) string {
 		return "goa.FormatCIDR"
 	case "regexp":
 		return "goa.FormatRegexp"
+	case "e164":
+		return "goa.FormatE164"
 	}
 	panic("unknown format") // bug
 }
@@ -326,6 +595,20 @@ const (
 {{if .isPointer}}{{tabs $depth}}}
 {{end}}{{tabs .depth}}}`
 
+	ipVersionValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
+*/}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
+{{end}}{{tabs $depth}}if err2 := goa.ValidateIPVersion({{.ipVersion}}, {{.targetVal}}); err2 != nil {
+{{tabs $depth}}		err = goa.MergeErrors(err, goa.InvalidFormatError(` + "`" + `{{.context}}` + "`" + `, {{.target}}.String(), goa.FormatIPv{{.ipVersion}}, err2))
+{{if .isPointer}}{{tabs $depth}}}
+{{end}}{{tabs .depth}}}`
+
+	boundingBoxValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
+*/}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
+{{end}}{{tabs $depth}}if err2 := goa.ValidateBoundingBox({{.targetVal}}, {{.box.MinLat}}, {{.box.MaxLat}}, {{.box.MinLon}}, {{.box.MaxLon}}); err2 != nil {
+{{tabs $depth}}	err = goa.MergeErrors(err, goa.InvalidBoundingBoxError(` + "`" + `{{.context}}` + "`" + `, {{.targetVal}}, err2))
+{{tabs $depth}}}{{if .isPointer}}
+{{tabs .depth}}}{{end}}`
+
 	minMaxValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
 */}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
 {{end}}{{tabs .depth}}	if {{.targetVal}} {{if .isMin}}<{{else}}>{{end}} {{if .isMin}}{{.min}}{{else}}{{.max}}{{end}} {
@@ -333,6 +616,13 @@ const (
 {{if .isPointer}}{{tabs $depth}}}
 {{end}}{{tabs .depth}}}`
 
+	bigMinMaxValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
+*/}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
+{{end}}{{tabs $depth}}if bound := goa.BigIntBound("{{.bigBound}}"); {{.target}}.Cmp(bound) {{if .isMin}}<{{else}}>{{end}} 0 {
+{{tabs $depth}}	err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `{{.context}}` + "`" + `, {{.target}}.String(), bound, {{.isMin}}))
+{{tabs $depth}}}{{if .isPointer}}
+{{tabs .depth}}}{{end}}`
+
 	lengthValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
 */}}{{$target := or (and (or (or .array .hash) .nonzero) .target) .targetVal}}{{/*
 */}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
@@ -342,10 +632,10 @@ const (
 {{end}}{{tabs .depth}}}`
 
 	requiredValTmpl = `{{range $r := .required}}{{$catt := index $.attribute.Type.ToObject $r}}{{/*
-*/}}{{if and (not $.private) (eq $catt.Type.Kind 4)}}{{tabs $.depth}}if {{$.target}}.{{goifyAtt $catt $r true}} == "" {
+*/}}{{if $catt.IsNullable}}{{/* null is a valid value */}}{{else if and (not $.private) (eq $catt.Type.Kind 4)}}{{tabs $.depth}}if {{$.target}}.{{goifyAtt $catt $r true}} == "" {
 {{tabs $.depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{$.context}}` + "`" + `, "{{$r}}"))
 {{tabs $.depth}}}
-{{else if or $.private (not $catt.Type.IsPrimitive)}}{{tabs $.depth}}if {{$.target}}.{{goifyAtt $catt $r true}} == nil {
+{{else if or $.private (not $catt.Type.IsPrimitive) (eq $catt.Type.Kind 17)}}{{tabs $.depth}}if {{$.target}}.{{goifyAtt $catt $r true}} == nil {
 {{tabs $.depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{$.context}}` + "`" + `, "{{$r}}"))
 {{tabs $.depth}}}
 {{end}}{{end}}`
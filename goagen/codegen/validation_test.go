@@ -1,6 +1,7 @@
 package codegen_test
 
 import (
+	"math/big"
 	"strings"
 
 	"github.com/goadesign/goa/design"
@@ -57,6 +58,34 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of e164 format", func() {
+				BeforeEach(func() {
+					attType = design.String
+					validation = &dslengine.ValidationDefinition{
+						Format: "e164",
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(e164ValCode))
+				})
+			})
+
+			Context("of bounding box", func() {
+				BeforeEach(func() {
+					attType = design.LatLon
+					validation = &dslengine.ValidationDefinition{
+						BoundingBox: &dslengine.BoundingBoxDefinition{
+							MinLat: 45.8, MaxLat: 47.8, MinLon: -5, MaxLon: 10.5,
+						},
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(boundingBoxValCode))
+				})
+			})
+
 			Context("of min value 0", func() {
 				BeforeEach(func() {
 					attType = design.Integer
@@ -71,6 +100,20 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of big integer min value", func() {
+				BeforeEach(func() {
+					attType = design.BigIntType
+					min, _ := new(big.Int).SetString("18446744073709551616", 10)
+					validation = &dslengine.ValidationDefinition{
+						BigMinimum: min,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(bigMinValCode))
+				})
+			})
+
 			Context("of array min length 1", func() {
 				BeforeEach(func() {
 					attType = &design.Array{
@@ -169,6 +212,169 @@ var _ = Describe("validation code generation", func() {
 
 		})
 	})
+
+	Describe("required attributes added programmatically", func() {
+		var ut *design.UserTypeDefinition
+		var code string // generated code
+
+		BeforeEach(func() {
+			obj := design.Object{}
+			for _, n := range []string{"a", "b", "c", "d", "e"} {
+				obj[n] = &design.AttributeDefinition{Type: design.String}
+			}
+			ut = &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{Type: obj},
+				TypeName:            "Letters",
+			}
+		})
+
+		JustBeforeEach(func() {
+			code = codegen.RecursiveChecker(ut.AttributeDefinition, false, false, false, "ut", "response", 1, false)
+		})
+
+		Context("with RequireAll", func() {
+			BeforeEach(func() {
+				Ω(ut.RequireAll("c", "a")).ShouldNot(HaveOccurred())
+			})
+
+			It("checks the listed attributes only", func() {
+				Ω(code).Should(Equal(requireAllValCode))
+			})
+		})
+
+		Context("with RequireAllExcept", func() {
+			BeforeEach(func() {
+				Ω(ut.RequireAllExcept("b", "d")).ShouldNot(HaveOccurred())
+			})
+
+			It("checks all the attributes but the listed ones", func() {
+				Ω(code).Should(Equal(requireAllExceptValCode))
+			})
+		})
+
+		Context("with an unknown attribute", func() {
+			It("returns an error", func() {
+				Ω(ut.RequireAll("f")).Should(HaveOccurred())
+				Ω(ut.RequireAllExcept("f")).Should(HaveOccurred())
+				Ω(ut.Validation).Should(BeNil())
+			})
+		})
+
+		Context("with an inline attribute", func() {
+			It("checks all the attributes but the listed ones", func() {
+				att := &design.AttributeDefinition{Type: ut.Type}
+				Ω(att.RequireAllExcept("b", "d")).ShouldNot(HaveOccurred())
+				code := codegen.RecursiveChecker(att, false, false, false, "ut", "response", 1, false)
+				Ω(code).Should(Equal(requireAllExceptValCode))
+			})
+
+			It("returns an error if the attribute is not an object", func() {
+				att := &design.AttributeDefinition{Type: design.String}
+				Ω(att.RequireAll("a")).Should(HaveOccurred())
+				Ω(att.RequireAllExcept()).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("NullChecker", func() {
+		It("checks that the required nullable attributes are set or null", func() {
+			att := &design.AttributeDefinition{
+				Type: design.Object{
+					"name": &design.AttributeDefinition{Type: design.String},
+					"rating": &design.AttributeDefinition{
+						Type:     design.Integer,
+						Metadata: dslengine.MetadataDefinition{"struct:field:nullable": nil},
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name", "rating"}},
+			}
+			Ω(codegen.NullChecker(att, "payload", "raw")).Should(Equal(nullValCode))
+		})
+	})
+
+	Describe("max depth", func() {
+		var att *design.AttributeDefinition
+		var code string // generated code
+
+		BeforeEach(func() {
+			// Three levels of nested objects: payload, payload.child and
+			// payload.child.child.
+			level3 := &design.AttributeDefinition{Type: design.Object{
+				"name": &design.AttributeDefinition{Type: design.String},
+			}}
+			level2 := &design.AttributeDefinition{Type: design.Object{
+				"name":  &design.AttributeDefinition{Type: design.String},
+				"child": level3,
+			}}
+			att = &design.AttributeDefinition{
+				Type: design.Object{
+					"name":  &design.AttributeDefinition{Type: design.String},
+					"child": level2,
+				},
+				Validation: &dslengine.ValidationDefinition{MaxDepth: 2},
+			}
+		})
+
+		JustBeforeEach(func() {
+			code = codegen.RecursiveChecker(att, false, false, false, "payload", "raw", 1, false)
+		})
+
+		It("reports the values nested past the maximum depth", func() {
+			Ω(code).Should(Equal(maxDepthValCode))
+		})
+
+		Context("with arrays", func() {
+			BeforeEach(func() {
+				att.Type = design.Object{
+					"matrix": &design.AttributeDefinition{Type: &design.Array{
+						ElemType: &design.AttributeDefinition{Type: &design.Array{
+							ElemType: &design.AttributeDefinition{Type: design.Integer},
+						}},
+					}},
+				}
+			})
+
+			It("counts the arrays as nesting levels", func() {
+				Ω(code).Should(Equal(maxDepthArrayValCode))
+			})
+		})
+
+		Context("with a recursive type", func() {
+			var node *design.UserTypeDefinition
+
+			BeforeEach(func() {
+				node = &design.UserTypeDefinition{TypeName: "Node", AttributeDefinition: &design.AttributeDefinition{}}
+				node.Type = design.Object{
+					"value": &design.AttributeDefinition{Type: design.Integer},
+					"left":  &design.AttributeDefinition{Type: node},
+					"right": &design.AttributeDefinition{Type: node},
+				}
+				att.Validation = nil
+				att.Type = design.Object{
+					"meta": &design.AttributeDefinition{
+						Type: &design.Hash{
+							KeyType: &design.AttributeDefinition{Type: design.String},
+							ElemType: &design.AttributeDefinition{Type: &design.Hash{
+								KeyType:  &design.AttributeDefinition{Type: design.String},
+								ElemType: &design.AttributeDefinition{Type: node},
+							}},
+						},
+						Validation: &dslengine.ValidationDefinition{MaxDepth: 3},
+					},
+				}
+			})
+
+			It("checks the maps and the user type values with a depth counter", func() {
+				Ω(code).Should(Equal(maxDepthHashValCode))
+			})
+
+			It("produces code whose size does not depend on the max depth", func() {
+				small := codegen.DepthChecker(&design.AttributeDefinition{Type: node}, "payload.Root", "raw.root", 1, 2, false)
+				large := codegen.DepthChecker(&design.AttributeDefinition{Type: node}, "payload.Root", "raw.root", 1, 16, false)
+				Ω(len(large) - len(small)).Should(BeNumerically("<=", 2))
+			})
+		})
+	})
 })
 
 const (
@@ -184,12 +390,30 @@ const (
 		}
 	}`
 
+	e164ValCode = `	if val != nil {
+		if err2 := goa.ValidateFormat(goa.FormatE164, *val); err2 != nil {
+				err = goa.MergeErrors(err, goa.InvalidFormatError(` + "`context`" + `, *val, goa.FormatE164, err2))
+		}
+	}`
+
+	boundingBoxValCode = `	if val != nil {
+		if err2 := goa.ValidateBoundingBox(*val, 45.8, 47.8, -5, 10.5); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidBoundingBoxError(` + "`context`" + `, *val, err2))
+		}
+	}`
+
 	minValCode = `	if val != nil {
 		if *val < 0 {
 			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context` + "`" + `, *val, 0, true))
 		}
 	}`
 
+	bigMinValCode = `	if val != nil {
+		if bound := goa.BigIntBound("18446744073709551616"); val.Cmp(bound) < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context` + "`" + `, val.String(), bound, true))
+		}
+	}`
+
 	arrayMinLengthValCode = `	if val != nil {
 		if len(val) < 1 {
 			err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `context` + "`" + `, val, len(val), 1, true))
@@ -237,4 +461,82 @@ const (
 			}
 		}
 	}`
+
+	maxDepthValCode = `	if !func() bool {
+		if payload.Child != nil {
+			if payload.Child.Child != nil {
+				return false
+			}
+		}
+		return true
+	}() {
+		err = goa.MergeErrors(err, goa.InvalidDepthError(` + "`raw`" + `, 2))
+	}`
+
+	maxDepthArrayValCode = `	if !func() bool {
+		if payload.Matrix != nil {
+			for _, e2 := range payload.Matrix {
+				if e2 != nil {
+					return false
+				}
+			}
+		}
+		return true
+	}() {
+		err = goa.MergeErrors(err, goa.InvalidDepthError(` + "`raw`" + `, 2))
+	}`
+
+	maxDepthHashValCode = `	{
+		var depthNode func(v *Node, depth int) bool
+		depthNode = func(v *Node, depth int) bool {
+			if depth > 3 {
+				return false
+			}
+			if v.Left != nil && !depthNode(v.Left, depth+1) {
+				return false
+			}
+			if v.Right != nil && !depthNode(v.Right, depth+1) {
+				return false
+			}
+			return true
+		}
+		if !func() bool {
+			for _, e1 := range payload.Meta {
+				if e1 != nil {
+					for _, e2 := range e1 {
+						if e2 != nil && !depthNode(e2, 3) {
+							return false
+						}
+					}
+				}
+			}
+			return true
+		}() {
+			err = goa.MergeErrors(err, goa.InvalidDepthError(` + "`raw.meta`" + `, 3))
+		}
+	}`
+
+	requireAllValCode = `	if ut.C == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`response`" + `, "c"))
+	}
+	if ut.A == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`response`" + `, "a"))
+	}
+`
+
+	requireAllExceptValCode = `	if ut.A == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`response`" + `, "a"))
+	}
+	if ut.C == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`response`" + `, "c"))
+	}
+	if ut.E == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`response`" + `, "e"))
+	}
+`
+
+	nullValCode = `	if payload.Rating == nil && !payload.nulls["rating"] {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`raw`" + `, "rating"))
+	}
+`
 )
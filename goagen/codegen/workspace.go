@@ -65,9 +65,11 @@ var (
 		"gotypedesc":          GoTypeDesc,
 		"gotyperef":           GoTypeRef,
 		"join":                strings.Join,
+		"nullChecker":         NullChecker,
 		"recursiveFinalizer":  RecursiveFinalizer,
 		"recursiveValidate":   RecursiveChecker,
 		"recursivePublicizer": RecursivePublicizer,
+		"requiredNullable":    RequiredNullable,
 		"tabs":                Tabs,
 		"tempvar":             Tempvar,
 		"title":               strings.Title,
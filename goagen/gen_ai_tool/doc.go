@@ -0,0 +1,10 @@
+/*
+Package genaitool provides a generator for the tool definitions used by LLM function calling.
+The generator creates a tools.json file under the "ai_tool" directory that contains the array of
+tools expected by the OpenAI "tools" request parameter. Each API action becomes a "function" tool
+named after the resource and action (e.g. "bottle_show"). The tool parameters are described by a
+JSON schema listing the action parameters and, if the action has a payload, a "payload" property
+describing the request body. The property descriptions come from the design attribute
+descriptions.
+*/
+package genaitool
@@ -0,0 +1,13 @@
+package genaitool_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenAITool(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenAITool Suite")
+}
@@ -0,0 +1,76 @@
+package genaitool
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the LLM tool definitions generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("ai_tool", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the ai_tool/tools.json file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	toolDir := filepath.Join(g.OutDir, "ai_tool")
+	os.RemoveAll(toolDir)
+	if err = os.MkdirAll(toolDir, 0755); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, toolDir)
+
+	js, err := json.MarshalIndent(Tools(Controllers(g.API)), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	filename := filepath.Join(toolDir, "tools.json")
+	if err := ioutil.WriteFile(filename, append(js, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, filename)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
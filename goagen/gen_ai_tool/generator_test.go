@@ -0,0 +1,109 @@
+package genaitool_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_ai_tool"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("aitooltest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genaitool.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with resources", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Description("Retrieve a bottle by ID")
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer, "Bottle ID")
+						apidsl.Param("view", design.String, "Rendering view")
+						apidsl.Param("fields", design.String)
+					})
+					apidsl.Response(design.NoContent)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String, "Bottle name")
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.Created)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("generates one function tool per action", func() {
+			Ω(genErr).Should(BeNil())
+			filename := filepath.Join(testPkg.Abs(), "ai_tool", "tools.json")
+			Ω(files).Should(ContainElement(filename))
+			content, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			var tools []map[string]interface{}
+			Ω(json.Unmarshal(content, &tools)).ShouldNot(HaveOccurred())
+			Ω(tools).Should(HaveLen(2))
+
+			byName := make(map[string]map[string]interface{})
+			for _, t := range tools {
+				Ω(t["type"]).Should(Equal("function"))
+				fn := t["function"].(map[string]interface{})
+				byName[fn["name"].(string)] = fn
+			}
+			Ω(byName).Should(HaveKey("bottle_show"))
+			Ω(byName).Should(HaveKey("bottle_create"))
+
+			show := byName["bottle_show"]
+			Ω(show["description"]).Should(Equal("Retrieve a bottle by ID"))
+			params := show["parameters"].(map[string]interface{})
+			Ω(params["type"]).Should(Equal("object"))
+			props := params["properties"].(map[string]interface{})
+			Ω(props).Should(HaveLen(len(design.Design.Resources["bottle"].Actions["show"].Params.Type.ToObject())))
+			Ω(props["id"].(map[string]interface{})["description"]).Should(Equal("Bottle ID"))
+			Ω(props["view"].(map[string]interface{})["description"]).Should(Equal("Rendering view"))
+			Ω(params["required"]).Should(Equal([]interface{}{"id"}))
+
+			create := byName["bottle_create"]
+			props = create["parameters"].(map[string]interface{})["properties"].(map[string]interface{})
+			Ω(props).Should(HaveLen(1))
+			payload := props["payload"].(map[string]interface{})
+			Ω(payload["type"]).Should(Equal("object"))
+			Ω(payload["required"]).Should(Equal([]interface{}{"name"}))
+			name := payload["properties"].(map[string]interface{})["name"].(map[string]interface{})
+			Ω(name["description"]).Should(Equal("Bottle name"))
+		})
+	})
+})
@@ -0,0 +1,128 @@
+package genaitool
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
+	"github.com/goadesign/goa/goagen/gen_schema"
+)
+
+type (
+	// Tool represents an element of the OpenAI "tools" array.
+	Tool struct {
+		Type     string    `json:"type"`
+		Function *Function `json:"function"`
+	}
+
+	// Function describes a function the model may call.
+	Function struct {
+		Name        string                `json:"name"`
+		Description string                `json:"description,omitempty"`
+		Parameters  *genschema.JSONSchema `json:"parameters"`
+	}
+)
+
+// Controllers returns the controller data of the API resources that define actions. Each action
+// has the keys "Name" holding the tool name, "Description" and "Parameters" holding the JSON
+// schema of the tool parameters.
+func Controllers(api *design.APIDefinition) []*genapp.ControllerTemplateData {
+	var controllers []*genapp.ControllerTemplateData
+	for _, r := range api.SortedResources() {
+		data := &genapp.ControllerTemplateData{API: api, Resource: codegen.Goify(r.Name, true)}
+		for _, a := range r.SortedActions() {
+			desc := a.Description
+			if desc == "" {
+				desc = fmt.Sprintf("%s action of the %s resource", a.Name, r.Name)
+			}
+			data.Actions = append(data.Actions, map[string]interface{}{
+				"Name":        codegen.SnakeCase(r.Name) + "_" + codegen.SnakeCase(a.Name),
+				"Description": desc,
+				"Parameters":  Parameters(api, a),
+			})
+		}
+		if len(data.Actions) > 0 {
+			controllers = append(controllers, data)
+		}
+	}
+	return controllers
+}
+
+// Tools returns the function tools corresponding to the actions of the given controllers.
+func Tools(controllers []*genapp.ControllerTemplateData) []*Tool {
+	tools := make([]*Tool, 0)
+	for _, c := range controllers {
+		for _, a := range c.Actions {
+			tools = append(tools, &Tool{
+				Type: "function",
+				Function: &Function{
+					Name:        a["Name"].(string),
+					Description: a["Description"].(string),
+					Parameters:  a["Parameters"].(*genschema.JSONSchema),
+				},
+			})
+		}
+	}
+	return tools
+}
+
+// Parameters returns the JSON schema of the tool parameters of the given action. The schema
+// object has one property per action parameter and a "payload" property if the action has a
+// payload. The schema includes the definitions of the user types it refers to.
+func Parameters(api *design.APIDefinition, a *design.ActionDefinition) *genschema.JSONSchema {
+	defs := genschema.Definitions
+	genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	defer func() { genschema.Definitions = defs }()
+
+	params := a.AllParams()
+	s := genschema.TypeSchema(api, params.Type)
+	if params.Validation != nil {
+		s.Required = append(s.Required, params.Validation.Required...)
+	}
+	// Path parameters are always required.
+	required := make(map[string]bool)
+	for _, n := range s.Required {
+		required[n] = true
+	}
+	for _, r := range a.Routes {
+		for _, p := range r.Params() {
+			if _, ok := s.Properties[p]; ok && !required[p] {
+				s.Required = append(s.Required, p)
+				required[p] = true
+			}
+		}
+	}
+	if a.Payload != nil {
+		payload := genschema.TypeSchema(api, a.Payload.Type)
+		payload.Description = a.Payload.Description
+		if payload.Description == "" {
+			payload.Description = "Request body"
+		}
+		if a.Payload.Validation != nil {
+			payload.Required = a.Payload.Validation.Required
+		}
+		s.Properties["payload"] = payload
+		if !a.PayloadOptional {
+			s.Required = append(s.Required, "payload")
+		}
+	}
+	s.Definitions = genschema.Definitions
+	clearExamples(s)
+	return s
+}
+
+// clearExamples removes the randomly generated examples from the properties of s so that they do
+// not get mistaken for meaningful values by the model.
+func clearExamples(s *genschema.JSONSchema) {
+	s.Example = nil
+	for _, p := range s.Properties {
+		clearExamples(p)
+	}
+	if s.Items != nil {
+		clearExamples(s.Items)
+	}
+	for _, d := range s.Definitions {
+		clearExamples(d)
+	}
+}
@@ -52,6 +52,7 @@ func BuildEncoders(info []*design.EncodingDefinition, encoder bool) ([]*EncoderT
 			Function:    enc.Function,
 			MIMETypes:   enc.MIMETypes,
 			Default:     isDefault,
+			Compression: enc.Compression,
 		}
 		data[i] = d
 	}
@@ -77,6 +78,7 @@ func normalizeEncodingDefinitions(defs []*design.EncodingDefinition) []*design.E
 				PackagePath: enc.PackagePath,
 				Function:    enc.Function,
 				Encoder:     enc.Encoder,
+				Compression: enc.Compression,
 			})
 		}
 	}
@@ -144,6 +146,7 @@ func serialize(byfn map[string][]*design.EncodingDefinition, first string) []*de
 			MIMETypes:   encs[0].MIMETypes,
 			PackagePath: encs[0].PackagePath,
 			Function:    encs[0].Function,
+			Compression: encs[0].Compression,
 		}
 		if len(encs) > 0 {
 			encs = encs[1:]
@@ -160,6 +163,18 @@ func serialize(byfn map[string][]*design.EncodingDefinition, first string) []*de
 						res[i].MIMETypes = append(res[i].MIMETypes, m)
 					}
 				}
+				for _, c := range enc.Compression {
+					found := false
+					for _, rc := range res[i].Compression {
+						if c == rc {
+							found = true
+							break
+						}
+					}
+					if !found {
+						res[i].Compression = append(res[i].Compression, c)
+					}
+				}
 			}
 		}
 		i++
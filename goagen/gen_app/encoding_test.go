@@ -46,6 +46,63 @@ var _ = Describe("BuildEncoders", func() {
 		})
 	})
 
+	Context("with definitions using compression", func() {
+		const packagePath = "github.com/goadesign/goa/design" // Just to pick something always available
+
+		BeforeEach(func() {
+			info = append(info,
+				&design.EncodingDefinition{
+					PackagePath: packagePath,
+					MIMETypes:   []string{"application/vnd.custom"},
+					Encoder:     true,
+					Compression: []string{"gzip"},
+				},
+				&design.EncodingDefinition{
+					PackagePath: packagePath,
+					MIMETypes:   []string{"application/vnd.custom2"},
+					Encoder:     true,
+					Compression: []string{"br", "gzip"},
+				},
+			)
+			encoder = true
+		})
+
+		It("merges the compression algorithms", func() {
+			Ω(resErr).ShouldNot(HaveOccurred())
+			Ω(data).Should(HaveLen(1))
+			Ω(data[0].Function).Should(Equal("NewEncoder"))
+			Ω(data[0].MIMETypes).Should(Equal([]string{"application/vnd.custom", "application/vnd.custom2"}))
+			Ω(data[0].Compression).Should(Equal([]string{"gzip", "br"}))
+		})
+	})
+
+	Context("with different encoders using compression", func() {
+		BeforeEach(func() {
+			info = append(info,
+				&design.EncodingDefinition{
+					MIMETypes:   []string{"application/json"},
+					Encoder:     true,
+					Compression: []string{"gzip"},
+				},
+				&design.EncodingDefinition{
+					MIMETypes:   []string{"application/xml"},
+					Encoder:     true,
+					Compression: []string{"br"},
+				},
+			)
+			encoder = true
+		})
+
+		It("keeps the compression algorithms of each encoder", func() {
+			Ω(resErr).ShouldNot(HaveOccurred())
+			Ω(data).Should(HaveLen(2))
+			Ω(data[0].Function).Should(Equal("NewJSONEncoder"))
+			Ω(data[0].Compression).Should(Equal([]string{"gzip"}))
+			Ω(data[1].Function).Should(Equal("NewXMLEncoder"))
+			Ω(data[1].Compression).Should(Equal([]string{"br"}))
+		})
+	})
+
 	Context("with a single definition using a single known MIME type for decoding", func() {
 		BeforeEach(func() {
 			simple := &design.EncodingDefinition{
@@ -1,31 +1,41 @@
 package genapp
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_schema"
 	"github.com/goadesign/goa/goagen/utils"
 )
 
 // Generator is the application code generator.
 type Generator struct {
-	API      *design.APIDefinition // The API definition
-	OutDir   string                // Path to output directory
-	Target   string                // Name of generated package
-	NoTest   bool                  // Whether to skip test generation
-	genfiles []string              // Generated files
+	API            *design.APIDefinition // The API definition
+	OutDir         string                // Path to output directory
+	Target         string                // Name of generated package
+	NoTest         bool                  // Whether to skip test generation
+	Expvar         bool                  // Whether to record request metrics with expvar
+	Router         string                // Router used by the generated code, "httptreemux", "stdlib" or "chi"
+	SchemaValidate bool                  // Whether to validate the JSON request bodies against the payload schemas
+	Standalone     bool                  // Whether to generate the resource services implementing http.Handler
+	FlattenLinks   bool                  // Whether to marshal the media type links at the top level of the JSON objects
+	Tracer         string                // APM tracer instrumenting the service if any, "datadog"
+	OrderedAttrs   string                // Order of the generated struct fields, "alpha" or "design"
+	genfiles       []string              // Generated files
 }
 
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, target, ver string
-		notest              bool
+		outDir, target, ver, router, ordered, overlay, tracer string
+		notest, expvar, schema, standalone, flatten           bool
 	)
 
 	set := flag.NewFlagSet("app", flag.PanicOnError)
@@ -34,15 +44,42 @@ func Generate() (files []string, err error) {
 	set.StringVar(&target, "pkg", "app", "")
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&notest, "notest", false, "")
+	set.BoolVar(&expvar, "expvar", false, "")
+	set.BoolVar(&schema, "schema-validate", false, "")
+	set.BoolVar(&standalone, "standalone", false, "")
+	set.BoolVar(&flatten, "flatten-links", false, "")
+	set.StringVar(&router, "router", "httptreemux", "")
+	set.StringVar(&ordered, "ordered-attrs", "alpha", "")
+	set.StringVar(&overlay, "overlay", "", "")
+	set.StringVar(&tracer, "tracer", "", "")
 	set.Parse(os.Args[1:])
 	outDir = filepath.Join(outDir, target)
 
 	if err := codegen.CheckVersion(ver); err != nil {
 		return nil, err
 	}
+	if router != "httptreemux" && router != "stdlib" && router != "chi" {
+		return nil, fmt.Errorf("unsupported router %#v, must be one of \"httptreemux\", \"stdlib\" or \"chi\"", router)
+	}
+
+	if overlay != "" {
+		codegen.GoGen.OverlayDir = overlay
+	}
 
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, Target: target, NoTest: notest, API: design.Design}
+	g := &Generator{
+		OutDir:         outDir,
+		Target:         target,
+		NoTest:         notest,
+		Expvar:         expvar,
+		Router:         router,
+		SchemaValidate: schema,
+		Standalone:     standalone,
+		FlattenLinks:   flatten,
+		Tracer:         tracer,
+		OrderedAttrs:   ordered,
+		API:            design.Design,
+	}
 
 	return g.Generate()
 }
@@ -52,6 +89,16 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if g.API == nil {
 		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
 	}
+	if g.Router == "chi" && g.Standalone {
+		// The chi mount functions take the router the standalone services do not have.
+		return nil, fmt.Errorf("the chi router does not support standalone services")
+	}
+	if g.Router == "chi" && (g.Expvar || g.API.Debug) {
+		return nil, fmt.Errorf("the chi router does not support the expvar and profiling handlers")
+	}
+	if g.Tracer != "" && g.Tracer != "datadog" {
+		return nil, fmt.Errorf("unsupported tracer %#v, must be \"datadog\"", g.Tracer)
+	}
 
 	go utils.Catch(nil, func() { g.Cleanup() })
 
@@ -61,6 +108,9 @@ func (g *Generator) Generate() (_ []string, err error) {
 		}
 	}()
 
+	if err := codegen.SetOrderedFields(g.OrderedAttrs); err != nil {
+		return nil, err
+	}
 	codegen.Reserved[g.Target] = true
 
 	os.RemoveAll(g.OutDir)
@@ -87,6 +137,24 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err := g.generateUserTypes(); err != nil {
 		return nil, err
 	}
+	if g.API.Debug {
+		if err := g.generateDebug(); err != nil {
+			return nil, err
+		}
+	}
+	if err := g.generateAudit(); err != nil {
+		return nil, err
+	}
+	if usesSemVer(g.API) {
+		if err := g.generateSemVer(); err != nil {
+			return nil, err
+		}
+	}
+	if g.Tracer == "datadog" {
+		if err := g.generateTracing(); err != nil {
+			return nil, err
+		}
+	}
 	if !g.NoTest {
 		if err := g.generateResourceTest(); err != nil {
 			return nil, err
@@ -115,17 +183,31 @@ func (g *Generator) generateContexts() error {
 	}
 	title := fmt.Sprintf("%s: Application Contexts", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("io"),
+		codegen.SimpleImport("mime/multipart"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/textproto"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("math/big"),
+		codegen.SimpleImport("net"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+		codegen.SimpleImport("golang.org/x/mod/semver"),
+	}
+	if g.hasWebSocket() {
+		imports = append(imports, codegen.SimpleImport("golang.org/x/net/websocket"))
 	}
 	g.genfiles = append(g.genfiles, ctxFile)
 	ctxWr.WriteHeader(title, g.Target, imports)
+	if err = ctxWr.WriteContextKeys(g.API.AllContextKeys()); err != nil {
+		return err
+	}
 	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
 		return r.IterateActions(func(a *design.ActionDefinition) error {
 			ctxName := codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true) + "Context"
@@ -156,6 +238,11 @@ func (g *Generator) generateContexts() error {
 				API:          g.API,
 				DefaultPkg:   g.Target,
 				Security:     a.Security,
+				WebSocket:    a.WebSocket(),
+				ContextKeys:  a.AllContextKeys(),
+				UnionResult:  a.UnionResult,
+				LongPoll:     a.LongPoll,
+				MergePatch:   a.JSONMergePatch,
 			}
 			return ctxWr.Execute(&ctxData)
 		})
@@ -177,11 +264,99 @@ func (g *Generator) generateControllers() error {
 	title := fmt.Sprintf("%s: Application Controllers", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/url"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("github.com/goadesign/goa/cors"),
 		codegen.SimpleImport("regexp"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("runtime/debug"),
+	}
+	if g.Expvar {
+		imports = append(imports,
+			codegen.SimpleImport("expvar"),
+			codegen.SimpleImport("net/url"),
+			codegen.SimpleImport("time"),
+		)
+	}
+	hasWebhook, hasDiscriminator, hasMergePatch, hasFormData, hasDecodedPayload := false, false, false, false, false
+	hasMultipartIngest := false
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Webhook != nil {
+				hasWebhook = true
+			}
+			if a.Payload != nil && a.Payload.Discriminator != nil {
+				hasDiscriminator = true
+			}
+			if a.Payload != nil && a.JSONMergePatch {
+				hasMergePatch = true
+			}
+			if a.Payload != nil && a.FormData {
+				hasFormData = true
+			}
+			if a.Payload != nil && a.MultipartIngest {
+				hasMultipartIngest = true
+			}
+			if a.Payload != nil && a.Payload.Discriminator == nil && !a.FormData && !a.JSONMergePatch && !a.MultipartIngest {
+				hasDecodedPayload = true
+			}
+			return nil
+		})
+	})
+	if hasWebhook {
+		imports = append(imports, codegen.SimpleImport("github.com/goadesign/goa/middleware"))
+	}
+	if g.Router == "chi" {
+		imports = append(imports,
+			codegen.SimpleImport("strings"),
+			codegen.SimpleImport("github.com/go-chi/chi"),
+		)
+	}
+	if g.hasWebSocket() {
+		imports = append(imports, codegen.SimpleImport("golang.org/x/net/websocket"))
+	}
+	if g.API.Sunset != nil {
+		imports = append(imports,
+			codegen.SimpleImport("strings"),
+			codegen.SimpleImport("time"),
+		)
+	}
+	if hasDiscriminator || hasMergePatch {
+		imports = append(imports,
+			codegen.SimpleImport("encoding/json"),
+			codegen.SimpleImport("io/ioutil"),
+		)
+	}
+	if hasDecodedPayload {
+		imports = append(imports,
+			codegen.SimpleImport("bytes"),
+			codegen.SimpleImport("io/ioutil"),
+			codegen.SimpleImport("mime"),
+			codegen.SimpleImport("strings"),
+		)
+	}
+	if hasMultipartIngest {
+		imports = append(imports, codegen.SimpleImport("io"))
+	}
+	if hasFormData {
+		imports = append(imports,
+			codegen.SimpleImport("strconv"),
+			codegen.SimpleImport("time"),
+			codegen.SimpleImport("math/big"),
+			codegen.SimpleImport("net"),
+			codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+			codegen.SimpleImport("golang.org/x/mod/semver"),
+		)
+	}
+	if g.SchemaValidate {
+		imports = append(imports,
+			codegen.SimpleImport("bytes"),
+			codegen.SimpleImport("io/ioutil"),
+			codegen.SimpleImport("strings"),
+			codegen.SimpleImport("github.com/xeipuuv/gojsonschema"),
+		)
 	}
 	encoders, err := BuildEncoders(g.API.Produces, true)
 	if err != nil {
@@ -191,9 +366,18 @@ func (g *Generator) generateControllers() error {
 	if err != nil {
 		return err
 	}
+	compressionImports := map[string][]string{
+		"br":   {"io", "github.com/andybalholm/brotli"},
+		"zstd": {"io", "github.com/klauspost/compress/zstd"},
+	}
 	encoderImports := make(map[string]bool)
 	for _, data := range encoders {
 		encoderImports[data.PackagePath] = true
+		for _, alg := range data.Compression {
+			for _, imp := range compressionImports[alg] {
+				encoderImports[imp] = true
+			}
+		}
 	}
 	for _, data := range decoders {
 		encoderImports[data.PackagePath] = true
@@ -235,26 +419,58 @@ func (g *Generator) generateControllers() error {
 			Resource:       codegen.Goify(r.Name, true),
 			PreflightPaths: r.PreflightPaths(),
 			FileServers:    fileServers,
+			StaticAssets:   r.StaticAssets,
+			Expvar:         g.Expvar,
+			Standalone:     g.Standalone,
+			Tracer:         g.Tracer,
+			Debug:          g.API.Debug,
+			Router:         g.Router,
+			Sunset:         g.API.Sunset,
 		}
-		ierr := r.IterateActions(func(a *design.ActionDefinition) error {
+		for _, a := range r.SortedActions() {
 			context := fmt.Sprintf("%s%sContext", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
 			unmarshal := fmt.Sprintf("unmarshal%s%sPayload", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
+			wsContext := fmt.Sprintf("%s%sWebSocketContext", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
+			audit := fmt.Sprintf("Audit%s%s", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
+			var result string
+			if a.UnionResult {
+				result = fmt.Sprintf("%s%sResult", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
+			}
+			var schema string
+			if g.SchemaValidate && a.Payload != nil && a.Payload.Discriminator == nil && !a.MultipartIngest {
+				js, err := payloadSchema(g.API, a.Payload)
+				if err != nil {
+					return err
+				}
+				schema = js
+			}
 			action := map[string]interface{}{
-				"Name":            codegen.Goify(a.Name, true),
-				"Routes":          a.Routes,
-				"Context":         context,
-				"Unmarshal":       unmarshal,
-				"Payload":         a.Payload,
-				"PayloadOptional": a.PayloadOptional,
-				"Security":        a.Security,
+				"Name":             codegen.Goify(a.Name, true),
+				"Span":             r.Name + "." + a.Name,
+				"Routes":           a.Routes,
+				"Context":          context,
+				"Unmarshal":        unmarshal,
+				"Payload":          a.Payload,
+				"PayloadOptional":  a.PayloadOptional,
+				"FormData":         a.FormData,
+				"Security":         a.Security,
+				"Description":      a.Description,
+				"Summary":          actionSummary(a),
+				"Tags":             strings.Join(actionTags(a), ", "),
+				"Webhook":          a.Webhook,
+				"WebSocket":        a.WebSocket(),
+				"WebSocketContext": wsContext,
+				"Audit":            a.Audit,
+				"AuditName":        audit,
+				"Schema":           schema,
+				"Result":           result,
+				"AcceptPatch":      a.PatchFormats,
+				"MergePatch":       a.JSONMergePatch,
+				"MultipartIngest":  a.MultipartIngest,
 			}
 			data.Actions = append(data.Actions, action)
-			return nil
-		})
-		if ierr != nil {
-			return ierr
 		}
-		if len(data.Actions) > 0 || len(data.FileServers) > 0 {
+		if len(data.Actions) > 0 || len(data.FileServers) > 0 || len(data.StaticAssets) > 0 {
 			data.Encoders = encoders
 			data.Decoders = decoders
 			data.Origins = r.AllOrigins()
@@ -272,10 +488,205 @@ func (g *Generator) generateControllers() error {
 	return ctlWr.FormatCode()
 }
 
+// generateSemVer generates the semantic version helpers used with SemVer attributes.
+func (g *Generator) generateSemVer() error {
+	semverFile := filepath.Join(g.OutDir, "semver.go")
+	o, err := newOverlay()
+	if err != nil {
+		return err
+	}
+	file, err := codegen.SourceFileFor(semverFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Semantic Versions", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("golang.org/x/mod/semver"),
+	}
+	file.WriteHeader(title, g.Target, imports)
+	g.genfiles = append(g.genfiles, semverFile)
+	if err := file.ExecuteTemplate("semver", o.template("semverT"), nil, nil); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// generateTracing generates the code that instruments the service with Datadog APM spans.
+func (g *Generator) generateTracing() error {
+	tracingFile := filepath.Join(g.OutDir, "tracing.go")
+	o, err := newOverlay()
+	if err != nil {
+		return err
+	}
+	file, err := codegen.SourceFileFor(tracingFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Tracing", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("httptrace", "gopkg.in/DataDog/dd-trace-go.v1/contrib/net/http"),
+		codegen.SimpleImport("gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"),
+		codegen.SimpleImport("gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"),
+	}
+	file.WriteHeader(title, g.Target, imports)
+	g.genfiles = append(g.genfiles, tracingFile)
+	if err := file.ExecuteTemplate("tracing", o.template("tracingT"), nil, nil); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// generateAudit generates the audit event types and the functions that record the events of the
+// audited actions. generateAudit does not generate any file if no action is audited.
+func (g *Generator) generateAudit() error {
+	var actions []map[string]interface{}
+	err := g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Audit == nil {
+				return nil
+			}
+			actions = append(actions, map[string]interface{}{
+				"Name":         fmt.Sprintf("Audit%s%s", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true)),
+				"Context":      fmt.Sprintf("%s%sContext", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true)),
+				"ResourceName": r.Name,
+				"ActionName":   a.Name,
+				"Actor":        a.Audit.Actor,
+				"Resource":     a.Audit.Resource,
+				"HasPayload":   a.Payload != nil,
+			})
+			return nil
+		})
+	})
+	if err != nil || len(actions) == 0 {
+		return err
+	}
+	auditFile := filepath.Join(g.OutDir, "audit.go")
+	o, err := newOverlay()
+	if err != nil {
+		return err
+	}
+	file, err := codegen.SourceFileFor(auditFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Audit Events", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("sort"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("time"),
+	}
+	file.WriteHeader(title, g.Target, imports)
+	g.genfiles = append(g.genfiles, auditFile)
+	if err := file.ExecuteTemplate("audit", o.template("auditT"), nil, nil); err != nil {
+		return err
+	}
+	for _, a := range actions {
+		if err := file.ExecuteTemplate("auditAction", o.template("auditActionT"), nil, a); err != nil {
+			return err
+		}
+	}
+	return file.FormatCode()
+}
+
+// usesSemVer returns true if at least one attribute of the API is of type SemVer.
+func usesSemVer(api *design.APIDefinition) bool {
+	found := false
+	check := func(att *design.AttributeDefinition) error {
+		if att.Type != nil && att.Type.Kind() == design.SemVerKind {
+			found = true
+		}
+		return nil
+	}
+	walk := func(att *design.AttributeDefinition) {
+		if att != nil && !found {
+			att.Walk(check)
+		}
+	}
+	for _, t := range api.Types {
+		walk(t.AttributeDefinition)
+	}
+	for _, mt := range api.MediaTypes {
+		walk(mt.AttributeDefinition)
+	}
+	walk(api.Params)
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		walk(r.Params)
+		walk(r.Headers)
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			walk(a.Params)
+			walk(a.Headers)
+			if a.Payload != nil {
+				walk(a.Payload.AttributeDefinition)
+			}
+			return nil
+		})
+	})
+	return found
+}
+
+// payloadSchema returns the JSON schema of the given payload type including the definitions of the
+// user types it refers to.
+func payloadSchema(api *design.APIDefinition, payload *design.UserTypeDefinition) (string, error) {
+	defs := genschema.Definitions
+	genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	defer func() { genschema.Definitions = defs }()
+	s := genschema.TypeSchema(api, payload)
+	s.Definitions = genschema.Definitions
+	js, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(js), nil
+}
+
+// hasWebSocket returns true if at least one action of the API is a WebSocket action.
+func (g *Generator) hasWebSocket() bool {
+	found := false
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.WebSocket() {
+				found = true
+			}
+			return nil
+		})
+	})
+	return found
+}
+
+// actionSummary returns the action summary defined with the "swagger:summary" metadata if any.
+func actionSummary(a *design.ActionDefinition) string {
+	if summary := a.Metadata["swagger:summary"]; len(summary) > 0 {
+		return summary[0]
+	}
+	return ""
+}
+
+// actionTags returns the sorted names of the tags defined on the action with the
+// "swagger:tag:xxx" metadata.
+func actionTags(a *design.ActionDefinition) []string {
+	var tags []string
+	for key := range a.Metadata {
+		chunks := strings.Split(key, ":")
+		if len(chunks) == 3 && chunks[0] == "swagger" && chunks[1] == "tag" {
+			tags = append(tags, chunks[2])
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
 // generateControllers iterates through the API resources and generates the low level
 // controllers.
 func (g *Generator) generateSecurity() error {
-	if len(g.API.SecuritySchemes) == 0 {
+	if len(g.API.SecuritySchemes) == 0 && g.API.JWTAuth == nil {
 		return nil
 	}
 
@@ -292,12 +703,26 @@ func (g *Generator) generateSecurity() error {
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 	}
+	if g.API.JWTAuth != nil {
+		imports = append(imports,
+			codegen.SimpleImport("fmt"),
+			codegen.SimpleImport("strings"),
+			codegen.NewImport("jwt", "github.com/golang-jwt/jwt/v5"),
+		)
+	}
 	secWr.WriteHeader(title, g.Target, imports)
 
 	g.genfiles = append(g.genfiles, secFile)
 
-	if err = secWr.Execute(design.Design.SecuritySchemes); err != nil {
-		return err
+	if len(design.Design.SecuritySchemes) > 0 {
+		if err = secWr.Execute(design.Design.SecuritySchemes); err != nil {
+			return err
+		}
+	}
+	if g.API.JWTAuth != nil {
+		if err = secWr.ExecuteJWTAuth(g.API); err != nil {
+			return err
+		}
 	}
 
 	return secWr.FormatCode()
@@ -331,6 +756,7 @@ func (g *Generator) generateHrefs() error {
 			Type:              m,
 			CanonicalTemplate: codegen.CanonicalTemplate(r),
 			CanonicalParams:   codegen.CanonicalParams(r),
+			OptionalSegments:  r.OptionalSegments,
 		}
 		return resWr.Execute(&data)
 	})
@@ -352,11 +778,18 @@ func (g *Generator) generateMediaTypes() error {
 	title := fmt.Sprintf("%s: Application Media Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("math/big"),
+		codegen.SimpleImport("net"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
+	if g.FlattenLinks {
+		mtWr.FlattenLinks = true
+		imports = append(imports, codegen.SimpleImport("reflect"))
+	}
 	mtWr.WriteHeader(title, g.Target, imports)
 	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
 		if mt.IsError() {
@@ -384,13 +817,29 @@ func (g *Generator) generateUserTypes() error {
 	}
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("math/big"),
+		codegen.SimpleImport("net"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+		codegen.SimpleImport("database/sql/driver"),
 	}
 	utWr.WriteHeader(title, g.Target, imports)
+	utWr.MergePatchTypes = make(map[string]bool)
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.JSONMergePatch && a.Payload != nil {
+				utWr.MergePatchTypes[a.Payload.TypeName] = true
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
 		return utWr.Execute(t)
 	})
@@ -400,3 +849,38 @@ func (g *Generator) generateUserTypes() error {
 	}
 	return utWr.FormatCode()
 }
+
+// generateDebug generates the profiling handlers compiled with the "debug" build tag in debug.go
+// and the no-op mount function compiled without it in nodebug.go.
+func (g *Generator) generateDebug() error {
+	title := fmt.Sprintf("%s: Application Profiling Handlers", g.API.Context())
+	for _, enabled := range []bool{true, false} {
+		debugFile := filepath.Join(g.OutDir, "debug.go")
+		constraint := "//go:build debug\n// +build debug\n\n"
+		imports := []*codegen.ImportSpec{
+			codegen.SimpleImport("net/http"),
+			codegen.SimpleImport("net/http/pprof"),
+			codegen.SimpleImport("net/url"),
+			codegen.SimpleImport("github.com/goadesign/goa"),
+		}
+		if !enabled {
+			debugFile = filepath.Join(g.OutDir, "nodebug.go")
+			constraint = "//go:build !debug\n// +build !debug\n\n"
+			imports = []*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa")}
+		}
+		debugWr, err := NewDebugWriter(debugFile)
+		if err != nil {
+			panic(err) // bug
+		}
+		debugWr.Write([]byte(constraint))
+		debugWr.WriteHeader(title, g.Target, imports)
+		g.genfiles = append(g.genfiles, debugFile)
+		if err := debugWr.Execute(g.Router, enabled); err != nil {
+			return err
+		}
+		if err := debugWr.FormatCode(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
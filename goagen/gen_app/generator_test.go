@@ -2,6 +2,7 @@ package genapp_test
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -67,6 +68,193 @@ var _ = Describe("Generate", func() {
 			isEmptySource("hrefs.go")
 			isEmptySource("media_types.go")
 		})
+
+		It("does not generate the profiling handlers", func() {
+			_, err := os.Stat(filepath.Join(outDir, "app", "debug.go"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+	})
+
+	Context("with a debug API", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{Name: "widget"}
+			show := &design.ActionDefinition{
+				Name:      "show",
+				Parent:    res,
+				Routes:    []*design.RouteDefinition{{Verb: "GET", Path: "/widgets/:id"}},
+				Responses: map[string]*design.ResponseDefinition{},
+			}
+			show.Routes[0].Parent = show
+			res.Actions = map[string]*design.ActionDefinition{"show": show}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Debug:     true,
+				Resources: map[string]*design.ResourceDefinition{"widget": res},
+			}
+		})
+
+		It("generates the profiling handlers under the debug build tag", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "debug.go")))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "debug.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			debug := string(content)
+			Ω(debug).Should(HavePrefix("//go:build debug\n// +build debug\n"))
+			Ω(debug).Should(ContainSubstring("func RegisterDebugHandlers(mux *http.ServeMux) {"))
+			Ω(debug).Should(ContainSubstring(`mux.HandleFunc("/debug/pprof/profile", pprof.Profile)`))
+			Ω(debug).Should(ContainSubstring(`service.Mux.Handle("GET", "/debug/pprof/*profile", h)`))
+			Ω(debug).ShouldNot(ContainSubstring("sync.Once"))
+		})
+
+		It("generates a no-op mount function without the debug build tag", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "nodebug.go")))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "nodebug.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			nodebug := string(content)
+			Ω(nodebug).Should(HavePrefix("//go:build !debug\n// +build !debug\n"))
+			Ω(nodebug).Should(ContainSubstring("func mountDebug(service *goa.Service) {}"))
+		})
+
+		It("mounts the profiling handlers only when built with the debug tag", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			controllers := string(content)
+			Ω(controllers).ShouldNot(ContainSubstring("RegisterDebugHandlers"))
+			Ω(controllers).ShouldNot(ContainSubstring("var mountDebug"))
+			Ω(controllers).Should(ContainSubstring("\tmountDebug(service)\n"))
+		})
+	})
+
+	Context("with the datadog tracer", func() {
+		BeforeEach(func() {
+			os.Args = append(os.Args, "--tracer=datadog")
+			design.Design = &design.APIDefinition{Name: "test api"}
+		})
+
+		It("generates the tracing code", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "tracing.go")))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "tracing.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			tracing := string(content)
+			Ω(tracing).Should(ContainSubstring(`httptrace "gopkg.in/DataDog/dd-trace-go.v1/contrib/net/http"`))
+			Ω(tracing).Should(ContainSubstring("func TraceService(service *goa.Service, opts ...httptrace.Option) http.Handler {"))
+			Ω(tracing).Should(ContainSubstring("span.SetTag(ext.ResourceName, resource)"))
+		})
+	})
+
+	Context("with an unsupported tracer", func() {
+		BeforeEach(func() {
+			os.Args = append(os.Args, "--tracer=zipkin")
+			design.Design = &design.APIDefinition{Name: "test api"}
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(HaveOccurred())
+		})
+
+		It("fails when invoked directly", func() {
+			g := &genapp.Generator{
+				API:    design.Design,
+				OutDir: filepath.Join(outDir, "app"),
+				Target: "app",
+				Tracer: "zipkin",
+			}
+			_, err := g.Generate()
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("unsupported tracer"))
+		})
+	})
+
+	Context("with standalone services and the chi router", func() {
+		BeforeEach(func() {
+			os.Args = append(os.Args, "--router=chi", "--standalone")
+			design.Design = &design.APIDefinition{Name: "test api"}
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(genErr.Error()).Should(ContainSubstring("standalone"))
+		})
+
+		It("fails when invoked directly", func() {
+			g := &genapp.Generator{
+				API:        design.Design,
+				OutDir:     filepath.Join(outDir, "app"),
+				Target:     "app",
+				Router:     "chi",
+				Standalone: true,
+			}
+			_, err := g.Generate()
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with expvar and the chi router", func() {
+		It("fails when invoked directly", func() {
+			g := &genapp.Generator{
+				API:    &design.APIDefinition{Name: "test api"},
+				OutDir: filepath.Join(outDir, "app"),
+				Target: "app",
+				Router: "chi",
+				Expvar: true,
+			}
+			_, err := g.Generate()
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("expvar"))
+		})
+	})
+
+	Context("with a resource defining several actions", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{Name: "widget", Actions: make(map[string]*design.ActionDefinition)}
+			for _, name := range []string{"show", "create", "update", "list", "delete"} {
+				a := &design.ActionDefinition{
+					Name:      name,
+					Parent:    res,
+					Routes:    []*design.RouteDefinition{{Verb: "POST", Path: "/widgets/" + name}},
+					Responses: map[string]*design.ResponseDefinition{},
+				}
+				a.Routes[0].Parent = a
+				res.Actions[name] = a
+			}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Resources: map[string]*design.ResourceDefinition{"widget": res},
+			}
+		})
+
+		It("lists the controller methods in alphabetical order", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(
+				"\tCreate(*CreateWidgetContext) error\n" +
+					"\tDelete(*DeleteWidgetContext) error\n" +
+					"\tList(*ListWidgetContext) error\n" +
+					"\tShow(*ShowWidgetContext) error\n" +
+					"\tUpdate(*UpdateWidgetContext) error\n"))
+		})
+
+		It("generates identical code on every run", func() {
+			Ω(genErr).Should(BeNil())
+			var sums [][sha256.Size]byte
+			for i := 0; i < 20; i++ {
+				delete(codegen.Reserved, "app")
+				design.GeneratedMediaTypes = make(design.MediaTypeRoot)
+				design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+				_, err := genapp.Generate()
+				Ω(err).ShouldNot(HaveOccurred())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				sums = append(sums, sha256.Sum256(content))
+			}
+			for _, sum := range sums[1:] {
+				Ω(sum).Should(Equal(sums[0]))
+			}
+		})
 	})
 
 	Context("with a simple API", func() {
@@ -274,10 +462,20 @@ type GetWidgetContext struct {
 	ID string
 }
 
+// RequestID returns the ID of the request read from the X-Request-ID, X-Correlation-ID or
+// traceparent headers, a random UUID is generated if none is set.
+func (ctx *GetWidgetContext) RequestID() string {
+	return goa.ExtractRequestID(ctx.RequestData.Request)
+}
+
 // NewGetWidgetContext parses the incoming request URL and body, performs validations and creates the
-// context used by the Widget controller get action.
+// context used by the Widget controller get action. The service is read from ctx
+// if nil, see goa.ServiceFromContext.
 func NewGetWidgetContext(ctx context.Context, service *goa.Service) (*GetWidgetContext, error) {
 	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
@@ -312,9 +510,13 @@ const controllersCodeTmpl = `//*************************************************
 package app
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/goadesign/goa"
 	"golang.org/x/net/context"
 	"net/http"
+	"net/url"
+	"runtime/debug"
 )
 
 // initService sets up the service encoders, decoders and mux.
@@ -324,14 +526,86 @@ func initService(service *goa.Service) {
 	// Setup default encoder and decoder
 }
 
+// chainMuxHandler wraps h with the given net/http middleware, the first middleware is the
+// outermost. The request context given to the middleware holds the service, see
+// goa.ServiceFromContext.
+func chainMuxHandler(service *goa.Service, h goa.MuxHandler, chain []func(http.Handler) http.Handler) goa.MuxHandler {
+	var next http.Handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		params, _ := req.Context().Value(muxParamsKey{}).(url.Values)
+		h(rw, req, params)
+	})
+	for i := len(chain) - 1; i >= 0; i-- {
+		next = chain[i](next)
+	}
+	return func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+		ctx := context.WithValue(req.Context(), goa.ServiceKey, service)
+		ctx = context.WithValue(ctx, muxParamsKey{}, params)
+		next.ServeHTTP(rw, req.WithContext(ctx))
+	}
+}
+
+// muxParamsKey is the request context key used to forward the route parameters through the
+// middleware chain.
+type muxParamsKey struct{}
+
+// handleRecover returns a handler that recovers from the panics raised by h. The panic value and
+// stack trace are logged with the service logger and the client receives a 500 response with a
+// problem+json (RFC 7807) body holding the ID of the logged error, the server keeps running.
+func handleRecover(h goa.Handler) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			perr := goa.ErrInternal(fmt.Sprintf("panic: %v", r)).(goa.ServiceError)
+			goa.LogError(ctx, "panic", "id", perr.Token(), "err", perr, "stack", string(debug.Stack()))
+			resp := goa.ContextResponse(ctx)
+			if resp == nil || resp.Written() {
+				return
+			}
+			resp.Header().Set("Content-Type", "application/problem+json")
+			resp.WriteHeader(http.StatusInternalServerError)
+			err = json.NewEncoder(resp).Encode(map[string]interface{}{
+				"type":   "about:blank",
+				"title":  http.StatusText(http.StatusInternalServerError),
+				"status": http.StatusInternalServerError,
+				"detail": "the server failed to handle the request",
+				"id":     perr.Token(),
+			})
+		}()
+		return h(ctx, rw, req)
+	}
+}
+
+// headHandler returns a handler that serves HEAD requests with the GET handler h: the response
+// status and headers written by h are sent and the response body is discarded.
+func headHandler(h goa.MuxHandler) goa.MuxHandler {
+	return func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+		h(headResponseWriter{rw}, req, params)
+	}
+}
+
+// headResponseWriter is the response writer given to the GET handlers serving HEAD requests.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write discards the response body.
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
 // WidgetController is the controller interface for the Widget actions.
 type WidgetController interface {
 	goa.Muxer
+	// get widgets
 	Get(*GetWidgetContext) error
 }
 
 // MountWidgetController "mounts" a Widget resource controller on the given service.
-func MountWidgetController(service *goa.Service, ctrl WidgetController) {
+// The chain middleware wrap the handler of each route, the first middleware is the outermost.
+func MountWidgetController(service *goa.Service, ctrl WidgetController, chain ...func(http.Handler) http.Handler) {
 	initService(service)
 	var h goa.Handler
 
@@ -347,8 +621,11 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
-	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("Get", h, nil))
+	h = handleRecover(h)
+	service.Mux.Handle("GET", "/:id", chainMuxHandler(service, ctrl.MuxHandler("Get", h, nil), chain))
 	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
+	service.Mux.Handle("HEAD", "/:id", chainMuxHandler(service, headHandler(ctrl.MuxHandler("Get", h, nil)), chain))
+	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "HEAD /:id")
 }
 `
 
@@ -395,7 +672,8 @@ package app
 
 const controllersSlicePayloadCode = `
 // MountWidgetController "mounts" a Widget resource controller on the given service.
-func MountWidgetController(service *goa.Service, ctrl WidgetController) {
+// The chain middleware wrap the handler of each route, the first middleware is the outermost.
+func MountWidgetController(service *goa.Service, ctrl WidgetController, chain ...func(http.Handler) http.Handler) {
 	initService(service)
 	var h goa.Handler
 
@@ -417,12 +695,18 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
-	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("Get", h, unmarshalGetWidgetPayload))
+	h = handleRecover(h)
+	service.Mux.Handle("GET", "/:id", chainMuxHandler(service, ctrl.MuxHandler("Get", h, unmarshalGetWidgetPayload), chain))
 	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
+	service.Mux.Handle("HEAD", "/:id", chainMuxHandler(service, headHandler(ctrl.MuxHandler("Get", h, unmarshalGetWidgetPayload)), chain))
+	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "HEAD /:id")
 }
 
 // unmarshalGetWidgetPayload unmarshals the request body into the context request data Payload field.
 func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	if err := checkJSONBody(req); err != nil {
+		return err
+	}
 	var payload Collection
 	if err := service.DecodeRequest(req, &payload); err != nil {
 		return err
@@ -434,7 +718,8 @@ func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *h
 
 const controllersOptionalPayloadCode = `
 // MountWidgetController "mounts" a Widget resource controller on the given service.
-func MountWidgetController(service *goa.Service, ctrl WidgetController) {
+// The chain middleware wrap the handler of each route, the first middleware is the outermost.
+func MountWidgetController(service *goa.Service, ctrl WidgetController, chain ...func(http.Handler) http.Handler) {
 	initService(service)
 	var h goa.Handler
 
@@ -454,12 +739,18 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
-	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("Get", h, unmarshalGetWidgetPayload))
+	h = handleRecover(h)
+	service.Mux.Handle("GET", "/:id", chainMuxHandler(service, ctrl.MuxHandler("Get", h, unmarshalGetWidgetPayload), chain))
 	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
+	service.Mux.Handle("HEAD", "/:id", chainMuxHandler(service, headHandler(ctrl.MuxHandler("Get", h, unmarshalGetWidgetPayload)), chain))
+	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "HEAD /:id")
 }
 
 // unmarshalGetWidgetPayload unmarshals the request body into the context request data Payload field.
 func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	if err := checkJSONBody(req); err != nil {
+		return err
+	}
 	var payload Collection
 	if err := service.DecodeRequest(req, &payload); err != nil {
 		return err
@@ -41,6 +41,7 @@ type TestMethod struct {
 	Params         []*ObjectType
 	QueryParams    []*ObjectType
 	Payload        *ObjectType
+	UnionResult    bool
 }
 
 // ObjectType structure
@@ -68,6 +69,7 @@ func (g *Generator) generateResourceTest() error {
 	}
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("io"),
 		codegen.SimpleImport("log"),
@@ -96,6 +98,9 @@ func (g *Generator) generateResourceTest() error {
 		var methods []*TestMethod
 
 		if err := res.IterateActions(func(action *design.ActionDefinition) error {
+			if action.WebSocket() { // WebSocket actions cannot be exercised with a recorder
+				return nil
+			}
 			if err := action.IterateResponses(func(response *design.ResponseDefinition) error {
 				if response.Status == 101 { // SwitchingProtocols, Don't currently handle WebSocket endpoints
 					return nil
@@ -180,7 +185,9 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 	}
 	comment += "."
 
-	if action.Payload != nil {
+	if action.Payload != nil && action.JSONMergePatch {
+		payload = &ObjectType{Name: "payload", Type: "map[string]json.RawMessage"}
+	} else if action.Payload != nil {
 		payload = &ObjectType{}
 		payload.Name = "payload"
 		payload.Type = fmt.Sprintf("%s.%s", g.Target, codegen.Goify(action.Payload.TypeName, true))
@@ -209,6 +216,7 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 		RouteVerb:      route.Verb,
 		Status:         response.Status,
 		FullPath:       goPathFormat(route.FullPath()),
+		UnionResult:    action.UnionResult,
 	}
 }
 
@@ -344,8 +352,12 @@ func {{ $test.Name }}(t goatest.TInterface, ctx context.Context, service *goa.Se
 	{{ if $test.Payload }}{{ $test.ContextVarName }}.Payload = {{ $test.Payload.Name }}{{ end }}
 
 	// Perform action
-	err = ctrl.{{ $test.ActionName}}({{ $test.ContextVarName }})
-
+{{ if $test.UnionResult }}	res, err := ctrl.{{ $test.ActionName}}({{ $test.ContextVarName }})
+	if err == nil {
+		err = {{ $test.ContextVarName }}.Respond(res)
+	}
+{{ else }}	err = ctrl.{{ $test.ActionName}}({{ $test.ContextVarName }})
+{{ end }}
 	// Validate response
 	if err != nil {
 		t.Fatalf("controller returned %s, logs:\n%s", err, logBuf.String())
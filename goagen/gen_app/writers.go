@@ -17,9 +17,14 @@ import (
 var WildcardRegex = regexp.MustCompile("(?:[^/]*/:([^/]+))+")
 
 type (
+	// overlay maps the names of the built-in templates overridden by user templates to the user
+	// template sources, see codegen.GoGenConfig.
+	overlay map[string]string
+
 	// ContextsWriter generate codes for a goa application contexts.
 	ContextsWriter struct {
 		*codegen.SourceFile
+		overlay
 		CtxTmpl     *template.Template
 		CtxNewTmpl  *template.Template
 		CtxRespTmpl *template.Template
@@ -31,6 +36,7 @@ type (
 	// resulting HTTP response.
 	ControllersWriter struct {
 		*codegen.SourceFile
+		overlay
 		CtrlTmpl    *template.Template
 		MountTmpl   *template.Template
 		handleCORST *template.Template
@@ -39,6 +45,7 @@ type (
 	// SecurityWriter generate code for action-level security handlers.
 	SecurityWriter struct {
 		*codegen.SourceFile
+		overlay
 		SecurityTmpl *template.Template
 	}
 
@@ -47,6 +54,7 @@ type (
 	// actions.
 	ResourcesWriter struct {
 		*codegen.SourceFile
+		overlay
 		ResourceTmpl *template.Template
 	}
 
@@ -54,14 +62,31 @@ type (
 	// Media types are data structures used to render the response bodies.
 	MediaTypesWriter struct {
 		*codegen.SourceFile
+		overlay
 		MediaTypeTmpl *template.Template
+		// FlattenLinks causes the media types with links to marshal the link fields at the
+		// top level of the JSON object instead of nesting them under "links".
+		FlattenLinks bool
+		// mergeDone is true once the JSON objects merge helper has been written.
+		mergeDone bool
 	}
 
 	// UserTypesWriter generate code for a goa application user types.
 	// User types are data structures defined in the DSL with "Type".
 	UserTypesWriter struct {
 		*codegen.SourceFile
+		overlay
 		UserTypeTmpl *template.Template
+		// MergePatchTypes lists the names of the payload types of the JSONMergePatch actions.
+		MergePatchTypes map[string]bool
+	}
+
+	// DebugWriter generate code for the profiling handlers of a goa application.
+	// The handlers are only compiled with the "debug" build tag.
+	DebugWriter struct {
+		*codegen.SourceFile
+		overlay
+		DebugTmpl *template.Template
 	}
 
 	// ContextTemplateData contains all the information used by the template to render the context
@@ -78,18 +103,39 @@ type (
 		API          *design.APIDefinition
 		DefaultPkg   string
 		Security     *design.SecurityDefinition
+		WebSocket    bool                        // Whether the action upgrades the connection to the WebSocket protocol
+		ContextKeys  *design.AttributeDefinition // Values injected in the request context by middleware
+		UnionResult  bool                        // Whether the controller action method returns the action result
+		LongPoll     bool                        // Whether the action streams its 200 response in chunks
+		MergePatch   bool                        // Whether the action payload is a JSON merge patch document
+	}
+
+	// ParamInfo describes an action parameter, see ContextTemplateData.AllPathParams and
+	// ContextTemplateData.AllQueryParams.
+	ParamInfo struct {
+		Name      string                      // Name of the parameter as defined in the design, e.g. "id"
+		Type      design.DataType             // Parameter type
+		Required  bool                        // Whether the parameter is required, always true for path parameters
+		Attribute *design.AttributeDefinition // Parameter attribute
 	}
 
 	// ControllerTemplateData contains the information required to generate an action handler.
 	ControllerTemplateData struct {
-		API            *design.APIDefinition          // API definition
-		Resource       string                         // Lower case plural resource name, e.g. "bottles"
-		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "Routes", "Context" and "Unmarshal"
-		FileServers    []*design.FileServerDefinition // File servers
-		Encoders       []*EncoderTemplateData         // Encoder data
-		Decoders       []*EncoderTemplateData         // Decoder data
-		Origins        []*design.CORSDefinition       // CORS policies
+		API            *design.APIDefinition            // API definition
+		Resource       string                           // Lower case plural resource name, e.g. "bottles"
+		Actions        []map[string]interface{}         // Array of actions, each action has keys "Name", "Span", "Routes", "Context" and "Unmarshal"
+		FileServers    []*design.FileServerDefinition   // File servers
+		StaticAssets   []*design.StaticAssetsDefinition // Static assets endpoints
+		Encoders       []*EncoderTemplateData           // Encoder data
+		Decoders       []*EncoderTemplateData           // Decoder data
+		Origins        []*design.CORSDefinition         // CORS policies
 		PreflightPaths []string
+		Expvar         bool                     // Whether to record request metrics with expvar
+		Standalone     bool                     // Whether to generate the resource service implementing http.Handler
+		Tracer         string                   // APM tracer instrumenting the service if any, "datadog"
+		Debug          bool                     // Whether to mount the profiling handlers defined in debug.go
+		Router         string                   // Router used by the generated code, "httptreemux", "stdlib" or "chi"
+		Sunset         *design.SunsetDefinition // API version sunset if any
 	}
 
 	// ResourceData contains the information required to generate the resource GoGenerator
@@ -100,6 +146,24 @@ type (
 		Type              *design.MediaTypeDefinition // Type of resource media type
 		CanonicalTemplate string                      // CanonicalFormat represents the resource canonical path in the form of a fmt.Sprintf format.
 		CanonicalParams   []string                    // CanonicalParams is the list of parameter names that appear in the resource canonical path in order.
+		OptionalSegments  []string                    // OptionalSegments lists the canonical path segments only included in the href when their parameters are given.
+	}
+
+	// patchPath describes a route path of PATCH actions and the methods that the path does not
+	// allow.
+	patchPath struct {
+		Path        string   // Route path, e.g. "/bottles/:id"
+		Methods     []string // Methods not allowed on the path sorted alphabetically
+		Allow       string   // Value of the Allow header, e.g. "GET, HEAD, PATCH"
+		AcceptPatch string   // Value of the Accept-Patch header, e.g. "application/merge-patch+json"
+	}
+
+	// hrefSegment is a piece of the resource canonical href.
+	hrefSegment struct {
+		Template string   // Template is the segment path in the form of a fmt.Sprintf format.
+		Params   []string // Params is the list of segment parameter names in order.
+		Keys     []string // Keys is the list of segment wildcard names as they appear in the design.
+		Optional bool     // Optional is true if the segment is only included when its parameters are given.
 	}
 
 	// EncoderTemplateData contains the data needed to render the registration code for a single
@@ -115,9 +179,82 @@ type (
 		MIMETypes []string
 		// Default is true if this encoder/decoder should be set as the default.
 		Default bool
+		// Compression lists the algorithms used to compress the encoded responses, e.g. "gzip".
+		Compression []string
 	}
 )
 
+// builtins maps the names of the constants storing the built-in templates executed by the
+// writers to the template sources. The user templates of the overlay may override any of them.
+var builtins = map[string]string{
+	"ctxKeysT":          ctxKeysT,
+	"ctxT":              ctxT,
+	"ctxNewT":           ctxNewT,
+	"ctxAnyOfT":         ctxAnyOfT,
+	"payloadT":          payloadT,
+	"mergePatchT":       mergePatchT,
+	"ctxMultipartRespT": ctxMultipartRespT,
+	"ctxTRespT":         ctxTRespT,
+	"ctxMTRespT":        ctxMTRespT,
+	"ctxNoMTRespT":      ctxNoMTRespT,
+	"ctxLongPollT":      ctxLongPollT,
+	"ctxResultT":        ctxResultT,
+	"serviceT":          serviceT,
+	"chainT":            chainT,
+	"recoverT":          recoverT,
+	"jsonBodyT":         jsonBodyT,
+	"schemaT":           schemaT,
+	"compressT":         compressT,
+	"sunsetT":           sunsetT,
+	"methodNotAllowedT": methodNotAllowedT,
+	"staticAssetsT":     staticAssetsT,
+	"headT":             headT,
+	"chiT":              chiT,
+	"stdServiceT":       stdServiceT,
+	"expvarT":           expvarT,
+	"ctrlT":             ctrlT,
+	"mountT":            mountT,
+	"handleCORST":       handleCORST,
+	"unmarshalT":        unmarshalT,
+	"securitySchemesT":  securitySchemesT,
+	"jwtAuthT":          jwtAuthT,
+	"resourceT":         resourceT,
+	"mediaTypeT":        mediaTypeT,
+	"mediaTypeLinkT":    mediaTypeLinkT,
+	"mergeJSONT":        mergeJSONT,
+	"flattenLinksT":     flattenLinksT,
+	"userTypeT":         userTypeT,
+	"debugT":            debugT,
+	"noDebugT":          noDebugT,
+	"semverT":           semverT,
+	"tracingT":          tracingT,
+	"auditT":            auditT,
+	"auditActionT":      auditActionT,
+}
+
+// newOverlay loads the user templates that override the built-in templates.
+func newOverlay() (overlay, error) {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return codegen.GoGen.Overrides(names...)
+}
+
+// template returns the source of the user template that overrides the built-in template stored
+// in the constant with the given name if any, the built-in template otherwise.
+func (o overlay) template(name string) string {
+	if src, ok := o[name]; ok {
+		return src
+	}
+	src, ok := builtins[name]
+	if !ok {
+		panic("unknown template " + name) // bug
+	}
+	return src
+}
+
 // IsPathParam returns true if the given parameter name corresponds to a path parameter for all
 // the context action routes. Such parameter is required but does not need to be validated as
 // httptreemux takes care of that.
@@ -141,6 +278,40 @@ func (c *ContextTemplateData) IsPathParam(param string) bool {
 	return pp
 }
 
+// AllPathParams returns the parameters that are path parameters for all the context action routes
+// sorted by name.
+func (c *ContextTemplateData) AllPathParams() []*ParamInfo {
+	return c.paramInfos(true)
+}
+
+// AllQueryParams returns the parameters that are not path parameters for all the context action
+// routes sorted by name. The action headers are not parameters and are not included.
+func (c *ContextTemplateData) AllQueryParams() []*ParamInfo {
+	return c.paramInfos(false)
+}
+
+// paramInfos returns the path parameters if path is true, the query parameters otherwise.
+func (c *ContextTemplateData) paramInfos(path bool) []*ParamInfo {
+	if c.Params == nil || !c.Params.Type.IsObject() {
+		return nil
+	}
+	var infos []*ParamInfo
+	c.Params.Type.ToObject().IterateAttributes(func(name string, att *design.AttributeDefinition) error {
+		isPath := c.IsPathParam(name)
+		if isPath != path {
+			return nil
+		}
+		infos = append(infos, &ParamInfo{
+			Name:      name,
+			Type:      att.Type,
+			Required:  isPath || c.Params.IsRequired(name),
+			Attribute: att,
+		})
+		return nil
+	})
+	return infos
+}
+
 // HasParamAndHeader returns true if the generated struct field name for the given header name
 // matches the generated struct field name of a param in c.Params.
 func (c *ContextTemplateData) HasParamAndHeader(name string) bool {
@@ -159,6 +330,89 @@ func (c *ContextTemplateData) HasParamAndHeader(name string) bool {
 	return false
 }
 
+// WebSocketName returns the name of the WebSocket context data type, e.g.
+// "EchoBottleWebSocketContext".
+func (c *ContextTemplateData) WebSocketName() string {
+	return strings.TrimSuffix(c.Name, "Context") + "WebSocketContext"
+}
+
+// ResultName returns the name of the action result interface, e.g. "ShowBottleResult".
+func (c *ContextTemplateData) ResultName() string {
+	return strings.TrimSuffix(c.Name, "Context") + "Result"
+}
+
+// resultType describes a type that implements the action result interface.
+type resultType struct {
+	TypeRef  string // Go type reference, e.g. "*GoaExampleBottle"
+	Response string // Name of the context method that sends the response, e.g. "OK"
+}
+
+// ResultTypes returns the types of the action success responses sorted by status code. The types
+// are the projections of the response media types using the response views.
+func (c *ContextTemplateData) ResultTypes() ([]*resultType, error) {
+	var types []*resultType
+	err := c.IterateResponses(func(resp *design.ResponseDefinition) error {
+		if resp.Status < 200 || resp.Status >= 300 {
+			return nil
+		}
+		mt, ok := resp.Type.(*design.MediaTypeDefinition)
+		if !ok {
+			mt = c.API.MediaTypeWithIdentifier(resp.MediaType)
+		}
+		if mt == nil {
+			return nil
+		}
+		view := resp.ViewName
+		if view == "" {
+			view = design.DefaultView
+		}
+		projected, _, err := mt.Project(view)
+		if err != nil {
+			return err
+		}
+		name := resp.Name
+		if view != design.DefaultView {
+			name += strings.Title(view)
+		}
+		types = append(types, &resultType{
+			TypeRef:  codegen.GoTypeRef(projected, projected.AllRequired(), 0, false),
+			Response: codegen.Goify(name, true),
+		})
+		return nil
+	})
+	return types, err
+}
+
+// HasField returns true if the generated struct field name of a param or header matches the given
+// name.
+func (c *ContextTemplateData) HasField(name string) bool {
+	for _, att := range []*design.AttributeDefinition{c.Params, c.Headers} {
+		if att == nil {
+			continue
+		}
+		for n, a := range att.Type.ToObject() {
+			if codegen.GoifyAtt(a, n, true) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasFileParams returns true if any of the action parameters is a file, see the FileType type.
+// The context factory parses the multipart request body to read the file parameters.
+func (c *ContextTemplateData) HasFileParams() bool {
+	if c.Params == nil {
+		return false
+	}
+	for _, att := range c.Params.Type.ToObject() {
+		if att.Type.Kind() == design.FileKind {
+			return true
+		}
+	}
+	return false
+}
+
 // MustValidate returns true if code that checks for the presence of the given param must be
 // generated.
 func (c *ContextTemplateData) MustValidate(name string) bool {
@@ -183,6 +437,233 @@ func (c *ContextTemplateData) IterateResponses(it func(*design.ResponseDefinitio
 	return nil
 }
 
+// Compression returns the algorithms used to compress the responses of the controller actions in
+// order of preference.
+func (c *ControllerTemplateData) Compression() []string {
+	var algs []string
+	seen := make(map[string]bool)
+	for _, enc := range c.Encoders {
+		for _, alg := range enc.Compression {
+			if !seen[alg] {
+				seen[alg] = true
+				algs = append(algs, alg)
+			}
+		}
+	}
+	return algs
+}
+
+// HasSchema returns true if at least one of the controller actions validates its request body
+// against the payload JSON schema.
+func (c *ControllerTemplateData) HasSchema() bool {
+	for _, a := range c.Actions {
+		if s, ok := a["Schema"].(string); ok && s != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDecodedPayload returns true if at least one of the controller actions decodes its request
+// body with the service decoder, that is defines a payload that is not read from a form, a merge
+// patch, multipart ingest parts or a discriminated union.
+func (c *ControllerTemplateData) HasDecodedPayload() bool {
+	for _, a := range c.Actions {
+		p, ok := a["Payload"].(*design.UserTypeDefinition)
+		if !ok || p == nil || p.Discriminator != nil {
+			continue
+		}
+		if fd, ok := a["FormData"].(bool); ok && fd {
+			continue
+		}
+		if mp, ok := a["MergePatch"].(bool); ok && mp {
+			continue
+		}
+		if mi, ok := a["MultipartIngest"].(bool); ok && mi {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// HasWebSocket returns true if at least one of the controller actions is a WebSocket action.
+func (c *ControllerTemplateData) HasWebSocket() bool {
+	for _, a := range c.Actions {
+		if ws, ok := a["WebSocket"].(bool); ok && ws {
+			return true
+		}
+	}
+	return false
+}
+
+// HasHead returns true if one of the controller actions or of the API actions defines a HEAD
+// route with the given path.
+func (c *ControllerTemplateData) HasHead(path string) bool {
+	for _, a := range c.Actions {
+		routes, _ := a["Routes"].([]*design.RouteDefinition)
+		for _, r := range routes {
+			if r.Verb == "HEAD" && r.FullPath() == path {
+				return true
+			}
+		}
+	}
+	if c.API == nil {
+		return false
+	}
+	for _, res := range c.API.SortedResources() {
+		for _, a := range res.Actions {
+			for _, r := range a.Routes {
+				if r.Verb == "HEAD" && r.FullPath() == path {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// HasAutoHead returns true if the mount function registers HEAD handlers for some of the
+// controller GET routes. See AutoHead.
+func (c *ControllerTemplateData) HasAutoHead() bool {
+	if len(c.FileServers) > 0 {
+		return true
+	}
+	for _, a := range c.Actions {
+		routes, _ := a["Routes"].([]*design.RouteDefinition)
+		for _, r := range routes {
+			if c.AutoHead(a, r.Verb, r.FullPath()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AutoHead returns true if the mount function registers a HEAD handler for the action route
+// with the given verb and path: GET routes of actions that are neither WebSocket nor webhook
+// actions get one unless a HEAD route with the same path is defined explicitly.
+func (c *ControllerTemplateData) AutoHead(action map[string]interface{}, verb, path string) bool {
+	if verb != "GET" || c.HasHead(path) {
+		return false
+	}
+	if ws, ok := action["WebSocket"].(bool); ok && ws {
+		return false
+	}
+	if wh, ok := action["Webhook"].(*design.WebhookDefinition); ok && wh != nil {
+		return false
+	}
+	return true
+}
+
+// PatchPaths returns the paths of the controller PATCH routes sorted alphabetically. The mount
+// function registers 405 handlers for the methods that the paths do not allow. The Accept-Patch
+// header lists the content types accepted by the PATCH actions of the path, see the AcceptPatch
+// DSL, or the MIME types of the decoders if the actions do not list any.
+func (c *ControllerTemplateData) PatchPaths() []*patchPath {
+	var paths []string
+	formats := make(map[string][]string)
+	for _, a := range c.Actions {
+		routes, _ := a["Routes"].([]*design.RouteDefinition)
+		for _, r := range routes {
+			if r.Verb != "PATCH" {
+				continue
+			}
+			path := r.FullPath()
+			if _, ok := formats[path]; !ok {
+				paths = append(paths, path)
+			}
+			types, _ := a["AcceptPatch"].([]string)
+			if len(types) == 0 {
+				for _, dec := range c.Decoders {
+					types = append(types, dec.MIMETypes...)
+				}
+			}
+			for _, t := range types {
+				found := false
+				for _, f := range formats[path] {
+					found = found || f == t
+				}
+				if !found {
+					formats[path] = append(formats[path], t)
+				}
+			}
+		}
+	}
+	sort.Strings(paths)
+	res := make([]*patchPath, len(paths))
+	for i, path := range paths {
+		allowed := c.allowedMethods(path)
+		var allow, methods []string
+		for _, m := range []string{"DELETE", "GET", "HEAD", "PATCH", "POST", "PUT"} {
+			if allowed[m] {
+				allow = append(allow, m)
+			} else {
+				methods = append(methods, m)
+			}
+		}
+		res[i] = &patchPath{
+			Path:        path,
+			Methods:     methods,
+			Allow:       strings.Join(allow, ", "),
+			AcceptPatch: strings.Join(formats[path], ", "),
+		}
+	}
+	return res
+}
+
+// allowedMethods returns the methods of the controller and API routes and of the file servers
+// with the given path. HEAD is allowed on paths that allow GET as the mount functions serve HEAD
+// requests with the GET handlers.
+func (c *ControllerTemplateData) allowedMethods(path string) map[string]bool {
+	allowed := make(map[string]bool)
+	add := func(routes []*design.RouteDefinition) {
+		for _, r := range routes {
+			if r.FullPath() == path {
+				allowed[r.Verb] = true
+			}
+		}
+	}
+	for _, a := range c.Actions {
+		routes, _ := a["Routes"].([]*design.RouteDefinition)
+		add(routes)
+	}
+	if c.API != nil {
+		for _, res := range c.API.SortedResources() {
+			for _, fs := range res.FileServers {
+				if fs.RequestPath == path {
+					allowed["GET"] = true
+				}
+			}
+			res.IterateActions(func(a *design.ActionDefinition) error {
+				add(a.Routes)
+				return nil
+			})
+		}
+	}
+	for _, fs := range c.FileServers {
+		if fs.RequestPath == path {
+			allowed["GET"] = true
+		}
+	}
+	if allowed["GET"] {
+		allowed["HEAD"] = true
+	}
+	return allowed
+}
+
+// compressor returns the code that instantiates the CompressorFunc of the given algorithm.
+func compressor(alg string) string {
+	switch alg {
+	case "br":
+		return "func(w io.Writer) (io.WriteCloser, error) { return brotli.NewWriter(w), nil }"
+	case "zstd":
+		return "func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) }"
+	default:
+		return "goa.NewGzipCompressor"
+	}
+}
+
 // NewContextsWriter returns a contexts code writer.
 // Contexts provide the glue between the underlying request data and the user controller.
 func NewContextsWriter(filename string) (*ContextsWriter, error) {
@@ -190,20 +671,41 @@ func NewContextsWriter(filename string) (*ContextsWriter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &ContextsWriter{SourceFile: file}, nil
+	overrides, err := newOverlay()
+	if err != nil {
+		return nil, err
+	}
+	return &ContextsWriter{SourceFile: file, overlay: overrides}, nil
+}
+
+// WriteContextKeys writes the private key types and the functions that set and retrieve the
+// values injected in the request context by middleware.
+func (w *ContextsWriter) WriteContextKeys(keys *design.AttributeDefinition) error {
+	if keys == nil {
+		return nil
+	}
+	return w.ExecuteTemplate("contextKeys", w.template("ctxKeysT"), nil, keys)
 }
 
 // Execute writes the code for the context types to the writer.
 func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
-	if err := w.ExecuteTemplate("context", ctxT, nil, data); err != nil {
-		return err
-	}
 	fn := template.FuncMap{
 		"newCoerceData":      newCoerceData,
+		"withEnum":           withEnum,
+		"withoutEnum":        withoutEnum,
 		"arrayAttribute":     arrayAttribute,
 		"canonicalHeaderKey": http.CanonicalHeaderKey,
+		"enum":               enumAttribute,
+		"enums":              enumAttributes,
+		"defaultAssignment":  defaultAssignment,
 	}
-	if err := w.ExecuteTemplate("new", ctxNewT, fn, data); err != nil {
+	if err := w.ExecuteTemplate("context", w.template("ctxT"), fn, data); err != nil {
+		return err
+	}
+	if err := w.ExecuteTemplate("new", w.template("ctxNewT"), fn, data); err != nil {
+		return err
+	}
+	if err := w.ExecuteTemplate("anyOf", w.template("ctxAnyOfT"), nil, data); err != nil {
 		return err
 	}
 	if data.Payload != nil {
@@ -215,23 +717,46 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			}
 		}
 		if !found {
-			if err := w.ExecuteTemplate("payload", payloadT, nil, data); err != nil {
+			if err := w.ExecuteTemplate("payload", w.template("payloadT"), fn, data); err != nil {
 				return err
 			}
+			if data.MergePatch {
+				if err := w.ExecuteTemplate("mergePatch", w.template("mergePatchT"), nil, data.Payload); err != nil {
+					return err
+				}
+			}
 		}
 	}
-	return data.IterateResponses(func(resp *design.ResponseDefinition) error {
+	err := data.IterateResponses(func(resp *design.ResponseDefinition) error {
 		respData := map[string]interface{}{
 			"Context":  data,
 			"Response": resp,
 		}
+		if len(resp.Parts) > 0 {
+			if err := w.ExecuteTemplate("multipart", w.template("ctxMultipartRespT"), nil, respData); err != nil {
+				return err
+			}
+		}
+		// executeResponse renders a response helper, responses that define a retry delay
+		// get an additional helper sending the Retry-After header as an HTTP date.
+		executeResponse := func(t string, fn template.FuncMap) error {
+			if err := w.ExecuteTemplate("response", t, fn, respData); err != nil {
+				return err
+			}
+			if resp.RetryAfter == 0 {
+				return nil
+			}
+			respData["RetryAt"] = true
+			defer delete(respData, "RetryAt")
+			return w.ExecuteTemplate("response", t, fn, respData)
+		}
 		var mt *design.MediaTypeDefinition
 		if resp.Type != nil {
 			var ok bool
 			if mt, ok = resp.Type.(*design.MediaTypeDefinition); !ok {
 				respData["Type"] = resp.Type
 				respData["ContentType"] = resp.MediaType
-				return w.ExecuteTemplate("response", ctxTRespT, nil, respData)
+				return executeResponse(w.template("ctxTRespT"), nil)
 			}
 		} else {
 			mt = design.Design.MediaTypeWithIdentifier(resp.MediaType)
@@ -258,20 +783,56 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 				respData["ViewName"] = view
 				respData["MediaType"] = mt
 				respData["ContentType"] = mt.ContentType
+				respData["VisibleFields"] = visibleFields(projected)
 				if view == "default" {
 					respData["RespName"] = codegen.Goify(resp.Name, true)
 				} else {
 					base := fmt.Sprintf("%s%s", resp.Name, strings.Title(view))
 					respData["RespName"] = codegen.Goify(base, true)
 				}
-				if err := w.ExecuteTemplate("response", ctxMTRespT, fn, respData); err != nil {
+				if err := executeResponse(w.template("ctxMTRespT"), fn); err != nil {
 					return err
 				}
 			}
 			return nil
 		}
-		return w.ExecuteTemplate("response", ctxNoMTRespT, nil, respData)
+		return executeResponse(w.template("ctxNoMTRespT"), nil)
+	})
+	if err != nil {
+		return err
+	}
+	if data.LongPoll {
+		if err := w.ExecuteTemplate("longPoll", w.template("ctxLongPollT"), nil, data); err != nil {
+			return err
+		}
+	}
+	if !data.UnionResult {
+		return nil
+	}
+	return w.ExecuteTemplate("result", w.template("ctxResultT"), nil, data)
+}
+
+// visibleField describes a response attribute only visible to specific roles.
+type visibleField struct {
+	Field string   // Name of the Go struct field
+	Roles []string // Roles allowed to see the attribute
+}
+
+// visibleFields returns the top level attributes of the given media type that are only visible to
+// specific roles sorted by name, see the VisibleTo DSL.
+func visibleFields(mt *design.MediaTypeDefinition) []*visibleField {
+	o := mt.Type.ToObject()
+	if o == nil {
+		return nil
+	}
+	var fields []*visibleField
+	o.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		if roles := att.VisibleTo(); len(roles) > 0 {
+			fields = append(fields, &visibleField{Field: codegen.GoifyAtt(att, n, true), Roles: roles})
+		}
+		return nil
 	})
+	return fields
 }
 
 // NewControllersWriter returns a handlers code writer.
@@ -281,7 +842,11 @@ func NewControllersWriter(filename string) (*ControllersWriter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &ControllersWriter{SourceFile: file}, nil
+	overrides, err := newOverlay()
+	if err != nil {
+		return nil, err
+	}
+	return &ControllersWriter{SourceFile: file, overlay: overrides}, nil
 }
 
 // WriteInitService writes the initService function
@@ -291,7 +856,7 @@ func (w *ControllersWriter) WriteInitService(encoders, decoders []*EncoderTempla
 		"Encoders": encoders,
 		"Decoders": decoders,
 	}
-	if err := w.ExecuteTemplate("service", serviceT, nil, ctx); err != nil {
+	if err := w.ExecuteTemplate("service", w.template("serviceT"), nil, ctx); err != nil {
 		return err
 	}
 	return nil
@@ -302,25 +867,166 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 	if len(data) == 0 {
 		return nil
 	}
+	if err := w.ExecuteTemplate("chain", w.template("chainT"), nil, data[0]); err != nil {
+		return err
+	}
+	if err := w.ExecuteTemplate("recover", w.template("recoverT"), nil, data[0]); err != nil {
+		return err
+	}
+	expvarDone, compressDone, schemaDone, chiDone, stdlibDone, sunsetDone, headDone := false, false, false, false, false, false, false
+	methodNotAllowedDone, staticAssetsDone, jsonBodyDone := false, false, false
 	for _, d := range data {
-		if err := w.ExecuteTemplate("controller", ctrlT, nil, d); err != nil {
+		if d.HasDecodedPayload() && !jsonBodyDone {
+			if err := w.ExecuteTemplate("jsonBody", w.template("jsonBodyT"), nil, d); err != nil {
+				return err
+			}
+			jsonBodyDone = true
+		}
+		if d.HasSchema() && !schemaDone {
+			if err := w.ExecuteTemplate("schema", w.template("schemaT"), nil, d); err != nil {
+				return err
+			}
+			schemaDone = true
+		}
+		if len(d.Compression()) > 0 && !compressDone {
+			if err := w.ExecuteTemplate("compress", w.template("compressT"), nil, d); err != nil {
+				return err
+			}
+			compressDone = true
+		}
+		if d.Sunset != nil && !sunsetDone {
+			if err := w.ExecuteTemplate("sunset", w.template("sunsetT"), nil, d); err != nil {
+				return err
+			}
+			sunsetDone = true
+		}
+		if len(d.PatchPaths()) > 0 && !methodNotAllowedDone {
+			if err := w.ExecuteTemplate("methodNotAllowed", w.template("methodNotAllowedT"), nil, d); err != nil {
+				return err
+			}
+			methodNotAllowedDone = true
+		}
+		if len(d.StaticAssets) > 0 && !staticAssetsDone {
+			if err := w.ExecuteTemplate("staticAssets", w.template("staticAssetsT"), nil, d); err != nil {
+				return err
+			}
+			staticAssetsDone = true
+		}
+		if d.HasAutoHead() && !headDone {
+			if err := w.ExecuteTemplate("head", w.template("headT"), nil, d); err != nil {
+				return err
+			}
+			headDone = true
+		}
+		if d.Router == "chi" && !chiDone {
+			if err := w.ExecuteTemplate("chi", w.template("chiT"), nil, d); err != nil {
+				return err
+			}
+			chiDone = true
+		}
+		if d.Router == "stdlib" && !stdlibDone {
+			if err := w.ExecuteTemplate("stdService", w.template("stdServiceT"), nil, d); err != nil {
+				return err
+			}
+			stdlibDone = true
+		}
+		if d.Expvar && !expvarDone {
+			if err := w.ExecuteTemplate("expvar", w.template("expvarT"), nil, d); err != nil {
+				return err
+			}
+			expvarDone = true
+		}
+		handle, handleEnd := muxHandle(d.Router)
+		fn := template.FuncMap{
+			"indent":     codegen.Indent,
+			"routePath":  routePath(d.Router),
+			"compressor": compressor,
+			"handle":     handle,
+			"handleEnd":  handleEnd,
+		}
+		if err := w.ExecuteTemplate("controller", w.template("ctrlT"), fn, d); err != nil {
 			return err
 		}
-		if err := w.ExecuteTemplate("mount", mountT, nil, d); err != nil {
+		if err := w.ExecuteTemplate("mount", w.template("mountT"), fn, d); err != nil {
 			return err
 		}
 		if len(d.Origins) > 0 {
-			if err := w.ExecuteTemplate("handleCORS", handleCORST, nil, d); err != nil {
+			if err := w.ExecuteTemplate("handleCORS", w.template("handleCORST"), nil, d); err != nil {
 				return err
 			}
 		}
-		if err := w.ExecuteTemplate("unmarshal", unmarshalT, nil, d); err != nil {
+		if err := w.ExecuteTemplate("unmarshal", w.template("unmarshalT"), template.FuncMap{"newCoerceData": newCoerceData, "mappingValues": mappingValues}, d); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// claimType returns the Go type of the values of JWT claims of the given type once decoded from
+// JSON.
+func claimType(t design.DataType) string {
+	switch t.Kind() {
+	case design.BooleanKind:
+		return "bool"
+	case design.StringKind:
+		return "string"
+	default:
+		return "float64"
+	}
+}
+
+// routePath returns a template function that formats the route paths for the given router.
+// The stdlib router uses the http.ServeMux wildcard syntax: ":id" becomes "{id}" and "*filepath"
+// becomes "{filepath...}". The chi router uses the chi syntax: ":id" becomes "{id}" and
+// "*filepath" becomes "*".
+func routePath(router string) func(string) string {
+	return func(path string) string {
+		if router != "stdlib" && router != "chi" {
+			return path
+		}
+		return design.WildcardRegex.ReplaceAllStringFunc(path, func(w string) string {
+			name := w[2:]
+			if w[1] == '*' {
+				if router == "chi" {
+					return "/*"
+				}
+				return "/{" + name + "...}"
+			}
+			return "/{" + name + "}"
+		})
+	}
+}
+
+// muxHandle returns template functions that write the beginning and the end of the code that
+// registers a handler with the router: the service mux or the chi router given to the mount
+// function. The chi handlers read the path parameters with chi.URLParam.
+func muxHandle(router string) (func(string, string) string, func(string) string) {
+	start := func(verb, path string) string {
+		if router == "chi" {
+			return fmt.Sprintf("r.Method(%q, %q, chiHandler(chainMuxHandler(service, ", verb, routePath(router)(path))
+		}
+		return fmt.Sprintf("service.Mux.Handle(%q, %q, chainMuxHandler(service, ", verb, routePath(router)(path))
+	}
+	end := func(path string) string {
+		if router != "chi" {
+			return ", chain))"
+		}
+		var names []string
+		for _, m := range design.WildcardRegex.FindAllStringSubmatch(path, -1) {
+			name := m[1]
+			if strings.HasPrefix(m[0], "/*") {
+				name = "*" + name
+			}
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+		if len(names) == 0 {
+			return ", chain)))"
+		}
+		return ", chain), " + strings.Join(names, ", ") + "))"
+	}
+	return start, end
+}
+
 // NewSecurityWriter returns a security functionality code writer.
 // Those functionalities are there to support action-middleware related to security.
 func NewSecurityWriter(filename string) (*SecurityWriter, error) {
@@ -328,12 +1034,22 @@ func NewSecurityWriter(filename string) (*SecurityWriter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &SecurityWriter{SourceFile: file}, nil
+	overrides, err := newOverlay()
+	if err != nil {
+		return nil, err
+	}
+	return &SecurityWriter{SourceFile: file, overlay: overrides}, nil
 }
 
 // Execute adds the different security schemes and middleware supporting functions.
 func (w *SecurityWriter) Execute(schemes []*design.SecuritySchemeDefinition) error {
-	return w.ExecuteTemplate("security_schemes", securitySchemesT, nil, schemes)
+	return w.ExecuteTemplate("security_schemes", w.template("securitySchemesT"), nil, schemes)
+}
+
+// ExecuteJWTAuth writes the JWT authentication middleware described by the API JWTAuth field.
+func (w *SecurityWriter) ExecuteJWTAuth(api *design.APIDefinition) error {
+	fn := template.FuncMap{"claimType": claimType}
+	return w.ExecuteTemplate("jwt_auth", w.template("jwtAuthT"), fn, api)
 }
 
 // NewResourcesWriter returns a contexts code writer.
@@ -343,28 +1059,82 @@ func NewResourcesWriter(filename string) (*ResourcesWriter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &ResourcesWriter{SourceFile: file}, nil
+	overrides, err := newOverlay()
+	if err != nil {
+		return nil, err
+	}
+	return &ResourcesWriter{SourceFile: file, overlay: overrides}, nil
 }
 
 // Execute writes the code for the context types to the writer.
 func (w *ResourcesWriter) Execute(data *ResourceData) error {
-	return w.ExecuteTemplate("resource", resourceT, nil, data)
+	fn := template.FuncMap{"hrefSegments": hrefSegments, "requiredParams": requiredParams}
+	return w.ExecuteTemplate("resource", w.template("resourceT"), fn, data)
 }
 
-// NewMediaTypesWriter returns a contexts code writer.
+// hrefSegments splits the resource canonical template into its required and optional segments.
+func hrefSegments(data *ResourceData) []*hrefSegment {
+	var segs []*hrefSegment
+	tmpl, params := data.CanonicalTemplate, data.CanonicalParams
+	add := func(t string, keys []string) {
+		if t == "" {
+			return
+		}
+		n := strings.Count(t, "/%v")
+		segs = append(segs, &hrefSegment{Template: t, Params: params[:n], Keys: keys, Optional: keys != nil})
+		params = params[n:]
+	}
+	for tmpl != "" {
+		idx, opt := -1, ""
+		for _, seg := range data.OptionalSegments {
+			t := design.WildcardRegex.ReplaceAllLiteralString(seg, "/%v")
+			if i := strings.Index(tmpl+"/", t+"/"); i >= 0 && (idx < 0 || i < idx) {
+				idx, opt = i, seg
+			}
+		}
+		if idx < 0 {
+			add(tmpl, nil)
+			break
+		}
+		t := design.WildcardRegex.ReplaceAllLiteralString(opt, "/%v")
+		add(tmpl[:idx], nil)
+		add(t, design.ExtractWildcards(opt))
+		tmpl = tmpl[idx+len(t):]
+	}
+	return segs
+}
+
+// requiredParams returns the names of the canonical parameters that are not part of an optional
+// segment.
+func requiredParams(data *ResourceData) []string {
+	var params []string
+	for _, seg := range hrefSegments(data) {
+		if !seg.Optional {
+			params = append(params, seg.Params...)
+		}
+	}
+	return params
+}
+
+// NewMediaTypesWriter returns a contexts code writer.
 // Media types contain the data used to render response bodies.
 func NewMediaTypesWriter(filename string) (*MediaTypesWriter, error) {
 	file, err := codegen.SourceFileFor(filename)
 	if err != nil {
 		return nil, err
 	}
-	return &MediaTypesWriter{SourceFile: file}, nil
+	overrides, err := newOverlay()
+	if err != nil {
+		return nil, err
+	}
+	return &MediaTypesWriter{SourceFile: file, overlay: overrides}, nil
 }
 
 // Execute writes the code for the context types to the writer.
 func (w *MediaTypesWriter) Execute(mt *design.MediaTypeDefinition) error {
 	var mLinks *design.UserTypeDefinition
 	viewMT := mt
+	fn := template.FuncMap{"enums": enumAttributes}
 	err := mt.IterateViews(func(view *design.ViewDefinition) error {
 		p, links, err := mt.Project(view.Name)
 		if mLinks == nil {
@@ -374,22 +1144,44 @@ func (w *MediaTypesWriter) Execute(mt *design.MediaTypeDefinition) error {
 			return err
 		}
 		viewMT = p
-		if err := w.ExecuteTemplate("mediatype", mediaTypeT, nil, viewMT); err != nil {
+		if err := w.ExecuteTemplate("mediatype", w.template("mediaTypeT"), fn, viewMT); err != nil {
 			return err
 		}
+		if w.FlattenLinks && links != nil {
+			return w.flattenLinks(p, links)
+		}
 		return nil
 	})
 	if err != nil {
 		return err
 	}
 	if mLinks != nil {
-		if err := w.ExecuteTemplate("mediatypelink", mediaTypeLinkT, nil, mLinks); err != nil {
+		if err := w.ExecuteTemplate("mediatypelink", w.template("mediaTypeLinkT"), nil, mLinks); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// flattenLinks writes the JSON marshaling methods of the projected media type p that merge the
+// fields of its links into the top-level JSON object.
+func (w *MediaTypesWriter) flattenLinks(p *design.MediaTypeDefinition, links *design.UserTypeDefinition) error {
+	obj := p.Type.ToObject()
+	for n := range links.Type.ToObject() {
+		if _, ok := obj[n]; ok {
+			return fmt.Errorf("cannot flatten the links of %s: link %#v conflicts with the attribute of the same name", p.TypeName, n)
+		}
+	}
+	if !w.mergeDone {
+		if err := w.ExecuteTemplate("mergejson", w.template("mergeJSONT"), nil, nil); err != nil {
+			return err
+		}
+		w.mergeDone = true
+	}
+	data := map[string]interface{}{"MediaType": p, "Links": links}
+	return w.ExecuteTemplate("flattenlinks", w.template("flattenLinksT"), nil, data)
+}
+
 // NewUserTypesWriter returns a contexts code writer.
 // User types contain custom data structured defined in the DSL with "Type".
 func NewUserTypesWriter(filename string) (*UserTypesWriter, error) {
@@ -397,12 +1189,47 @@ func NewUserTypesWriter(filename string) (*UserTypesWriter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &UserTypesWriter{SourceFile: file}, nil
+	overrides, err := newOverlay()
+	if err != nil {
+		return nil, err
+	}
+	return &UserTypesWriter{SourceFile: file, overlay: overrides}, nil
 }
 
 // Execute writes the code for the context types to the writer.
 func (w *UserTypesWriter) Execute(t *design.UserTypeDefinition) error {
-	return w.ExecuteTemplate("types", userTypeT, nil, t)
+	fn := template.FuncMap{"enums": enumAttributes}
+	if err := w.ExecuteTemplate("types", w.template("userTypeT"), fn, t); err != nil {
+		return err
+	}
+	if !w.MergePatchTypes[t.TypeName] {
+		return nil
+	}
+	return w.ExecuteTemplate("mergePatch", w.template("mergePatchT"), nil, t)
+}
+
+// NewDebugWriter returns a profiling handlers code writer.
+func NewDebugWriter(filename string) (*DebugWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := newOverlay()
+	if err != nil {
+		return nil, err
+	}
+	return &DebugWriter{SourceFile: file, overlay: overrides}, nil
+}
+
+// Execute writes the code for the profiling handlers to the writer. router is the router used by
+// the generated code, "httptreemux" or "stdlib", the chi router does not support the profiling
+// handlers. enabled is false when writing the file compiled without the "debug" build tag.
+func (w *DebugWriter) Execute(router string, enabled bool) error {
+	if !enabled {
+		return w.ExecuteTemplate("noDebug", w.template("noDebugT"), nil, nil)
+	}
+	fn := template.FuncMap{"routePath": routePath(router)}
+	return w.ExecuteTemplate("debug", w.template("debugT"), fn, nil)
 }
 
 // newCoerceData is a helper function that creates a map that can be given to the "Coerce" template.
@@ -414,18 +1241,136 @@ func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, p
 		"Attribute": att,
 		"Pkg":       pkg,
 		"Depth":     depth,
+		"Unsigned":  att.Type.Kind().IsUnsigned(),
+		"BitSize":   unsignedBitSize(att.Type.Kind()),
+	}
+}
+
+// withEnum sets the enum type data used by the "Coerce" template to check the values of a string
+// parameter with the IsValid helper generated for the enum type, see enumAttribute.
+func withEnum(data map[string]interface{}, enum map[string]interface{}) map[string]interface{} {
+	data["Enum"] = enum
+	return data
+}
+
+// withoutEnum returns a copy of att without the enum validation, it is used to generate the
+// validation code of parameters whose values are already checked by the "Coerce" template.
+func withoutEnum(att *design.AttributeDefinition) *design.AttributeDefinition {
+	if att.Validation == nil || len(att.Validation.Values) == 0 {
+		return att
+	}
+	dup := *att
+	val := *att.Validation
+	val.Values = nil
+	dup.Validation = &val
+	return &dup
+}
+
+// unsignedBitSize returns the bit size given to strconv.ParseUint to parse values of the given
+// unsigned integer kind, 0 stands for the size of uint.
+func unsignedBitSize(k design.Kind) int {
+	switch k {
+	case design.Uint8Kind:
+		return 8
+	case design.Uint16Kind:
+		return 16
+	case design.Uint32Kind:
+		return 32
+	default:
+		return 0
 	}
 }
 
+// mappingValues returns the sorted discriminator values of a polymorphic payload mapping.
+func mappingValues(mapping map[string]design.DataType) []string {
+	values := make([]string, 0, len(mapping))
+	for v := range mapping {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// defaultAssignment returns the code that assigns the default value of the given parameter or
+// header attribute to target. The default value is used when the request does not set the
+// parameter or header.
+func defaultAssignment(att *design.AttributeDefinition, target string) string {
+	switch att.Type.Kind() {
+	case design.DateTimeKind:
+		return fmt.Sprintf("%s, _ = time.Parse(time.RFC3339, %q)", target, att.DefaultValue)
+	case design.UUIDKind:
+		return fmt.Sprintf("%s = uuid.FromStringOrNil(%q)", target, att.DefaultValue)
+	case design.IPKind:
+		return fmt.Sprintf("%s = net.ParseIP(%q)", target, att.DefaultValue)
+	case design.CIDRKind:
+		return fmt.Sprintf("%s, _ = goa.ParseCIDR(%q)", target, att.DefaultValue)
+	case design.LatLonKind:
+		return fmt.Sprintf("%s, _ = goa.ParseLatLon(%q)", target, att.DefaultValue)
+	case design.SemVerKind:
+		return fmt.Sprintf("%s = goa.SemVerString(%q)", target, att.DefaultValue)
+	case design.BigIntKind:
+		return fmt.Sprintf("%s, _ = goa.ParseBigInt(%q)", target, fmt.Sprint(att.DefaultValue))
+	}
+	return fmt.Sprintf("%s = %s", target, codegen.PrintVal(att.Type, att.DefaultValue))
+}
+
 // arrayAttribute returns the array element attribute definition.
 func arrayAttribute(a *design.AttributeDefinition) *design.AttributeDefinition {
 	return a.Type.(*design.Array).ElemType
 }
 
+// enumAttributes returns the data given to the "Enums" template for each string attribute of the
+// given object that defines an enum validation. Attributes whose values do not produce distinct Go
+// identifiers are skipped.
+func enumAttributes(typeName string, att *design.AttributeDefinition) []map[string]interface{} {
+	o := att.Type.ToObject()
+	if o == nil {
+		return nil
+	}
+	var enums []map[string]interface{}
+	o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		if enum := enumAttribute(typeName, n, catt); enum != nil {
+			enums = append(enums, enum)
+		}
+		return nil
+	})
+	return enums
+}
+
+// enumAttribute returns the data given to the "Enums" template for the attribute with the given
+// name of the type or context typeName, nil if the attribute is not a string attribute defining an
+// enum validation or if its values do not produce distinct Go identifiers.
+func enumAttribute(typeName, name string, att *design.AttributeDefinition) map[string]interface{} {
+	if att.Type.Kind() != design.StringKind || att.Validation == nil || len(att.Validation.Values) == 0 {
+		return nil
+	}
+	enumName := typeName + codegen.Goify(name, true)
+	values := make([]map[string]interface{}, len(att.Validation.Values))
+	names := make([]string, len(att.Validation.Values))
+	seen := make(map[string]bool)
+	for i, v := range att.Validation.Values {
+		val := fmt.Sprintf("%v", v)
+		suffix := codegen.Goify(val, true)
+		if suffix == "" || seen[suffix] {
+			return nil
+		}
+		seen[suffix] = true
+		names[i] = enumName + suffix
+		values[i] = map[string]interface{}{"Name": names[i], "Value": val}
+	}
+	return map[string]interface{}{
+		"TypeName":  enumName,
+		"Parent":    typeName,
+		"Attribute": name,
+		"Values":    values,
+		"Names":     names,
+	}
+}
+
 const (
 	// ctxT generates the code for the context data type.
 	// template input: *ContextTemplateData
-	ctxT = `// {{ .Name }} provides the {{ .ResourceName }} {{ .ActionName }} action context.
+	ctxT = `{{ define "Enums" }}` + enumT + `{{ end }}` + `// {{ .Name }} provides the {{ .ResourceName }} {{ .ActionName }} action context.
 type {{ .Name }} struct {
 	context.Context
 	*goa.ResponseData
@@ -434,9 +1379,23 @@ type {{ .Name }} struct {
 */}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Headers.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
 {{ end }}{{ end }}{{ end }}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
 */}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Params.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
-{{ end }}{{ end }}{{ if .Payload }}	Payload {{ gotyperef .Payload nil 0 false }}
+{{ end }}{{ end }}{{ if .ContextKeys }}{{ range $name, $att := .ContextKeys.Type.ToObject }}	{{ goify $name true }} {{ gotyperef .Type nil 0 false }}
+{{ end }}{{ end }}{{ if .Payload }}	Payload {{ if .MergePatch }}map[string]json.RawMessage{{ else if .Payload.Discriminator }}interface{}{{ else }}{{ gotyperef .Payload nil 0 false }}{{ end }}
 {{ end }}}
-`
+{{ if not (.HasField "RequestID") }}
+// RequestID returns the ID of the request read from the X-Request-ID, X-Correlation-ID or
+// traceparent headers, a random UUID is generated if none is set.
+func (ctx *{{ .Name }}) RequestID() string {
+	return goa.ExtractRequestID(ctx.RequestData.Request)
+}
+{{ end }}{{ if .WebSocket }}
+// {{ .WebSocketName }} provides the {{ .ResourceName }} {{ .ActionName }} action context and the
+// WebSocket connection.
+type {{ .WebSocketName }} struct {
+	*{{ .Name }}
+	*websocket.Conn
+}
+{{ end }}{{ if .Params }}{{ template "Enums" (enums .Name .Params) }}{{ end }}`
 	// coerceT generates the code that coerces the generic deserialized
 	// data to the actual type.
 	// template input: map[string]interface{} as returned by newCoerceData
@@ -476,11 +1435,15 @@ type {{ .Name }} struct {
 
 */}}{{/* StringType */}}{{/*
 */}}{{ tabs .Depth }}{{ .Pkg }} = {{ if .Pointer }}&{{ end }}raw{{ goify .Name true }}
-{{ end }}{{ if eq .Attribute.Type.Kind 5 }}{{/*
+{{ with .Enum }}{{ tabs $.Depth }}if !IsValid{{ .TypeName }}(raw{{ goify $.Name true }}) {
+{{ tabs $.Depth }}	err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `{{ $.Name }}` + "`" + `, raw{{ goify $.Name true }}, []interface{}{ {{- join .Names ", " -}} }))
+{{ tabs $.Depth }}}
+{{ end }}{{ end }}{{ if eq .Attribute.Type.Kind 5 }}{{/*
 
 */}}{{/* DateTimeType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
 */}}{{ tabs .Depth }}if {{ .VarName }}, err2 := time.Parse(time.RFC3339, raw{{ goify .Name true }}); err2 == nil {
+{{ tabs .Depth }}	{{ .VarName }} = {{ .VarName }}.{{ if eq .Attribute.TimeZone "local" }}Local{{ else }}UTC{{ end }}()
 {{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
 {{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
 {{ tabs .Depth }}} else {
@@ -496,10 +1459,83 @@ type {{ .Name }} struct {
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "uuid"))
 {{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 13 }}{{/*
+
+*/}}{{/* IPType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }} := net.ParseIP(raw{{ goify .Name true }}); {{ .VarName }} != nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "ip"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 14 }}{{/*
+
+*/}}{{/* CIDRType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseCIDR(raw{{ goify .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "cidr"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 15 }}{{/*
+
+*/}}{{/* LatLonType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseLatLon(raw{{ goify .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "latlon"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 16 }}{{/*
+
+*/}}{{/* SemVerType */}}{{/*
+*/}}{{ tabs .Depth }}if semver.IsValid(raw{{ goify .Name true }}) {
+{{ tabs .Depth }}	{{ .Pkg }} = {{ if .Pointer }}&{{ end }}raw{{ goify .Name true }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "semver"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 22 }}{{/*
+
+*/}}{{/* BigIntType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseBigInt(raw{{ goify .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "big integer"))
+{{ tabs .Depth }}}
+{{ end }}{{ if .Unsigned }}{{/*
+
+*/}}{{/* UintType, Uint8Type, Uint16Type, Uint32Type */}}{{/*
+*/}}{{ $tmp := tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := strconv.ParseUint(raw{{ goify .Name true }}, 10, {{ .BitSize }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $tmp }} := {{ gonative .Attribute.Type }}({{ .VarName }})
+{{ tabs .Depth }}	{{ .Pkg }} = &{{ $tmp }}
+{{ else }}{{ tabs .Depth }}	{{ .Pkg }} = {{ gonative .Attribute.Type }}({{ .VarName }})
+{{ end }}{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "unsigned integer"))
+{{ tabs .Depth }}}
 {{ end }}{{ if eq .Attribute.Type.Kind 7 }}{{/*
 
 */}}{{/* AnyType */}}{{/*
-*/}}{{ if .Pointer }}{{ $tmp := tempvar }}{{ tabs .Depth }}{{ $tmp }} := interface{}(raw{{ goify .Name true }})
+*/}}{{ if .Attribute.AnyOfTypes }}{{/*
+*/}}{{ $data := . }}{{ $raw := printf "raw%s" (goify .Name true) }}{{ $matched := false }}{{ $open := false }}{{/*
+*/}}{{ range $i, $t := .Attribute.AnyOfTypes }}{{ if not $matched }}{{ $value := $data.VarName }}{{/*
+*/}}{{ if eq $t.Kind 4 }}{{ $matched = true }}{{ $value = $raw }}{{ if $i }}{{ tabs $data.Depth }}} else {
+{{ end }}{{ else }}{{ $open = true }}{{ tabs $data.Depth }}{{ if $i }}} else {{ end }}if {{ $data.VarName }}, err2 := {{/*
+*/}}{{ if eq $t.Kind 1 }}strconv.ParseBool({{ $raw }}){{ else if eq $t.Kind 2 }}strconv.Atoi({{ $raw }}){{ else }}strconv.ParseFloat({{ $raw }}, 64){{ end }}; err2 == nil {
+{{ end }}{{ $indent := printf "%s%s" (tabs $data.Depth) (or (and $open "\t") "") }}{{/*
+*/}}{{ if $data.Pointer }}{{ $tmp := tempvar }}{{ $indent }}{{ $tmp }} := interface{}({{ $value }})
+{{ $indent }}{{ $data.Pkg }} = &{{ $tmp }}
+{{ else }}{{ $indent }}{{ $data.Pkg }} = {{ $value }}
+{{ end }}{{ end }}{{ end }}{{ if not $matched }}{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", {{ $raw }}, "{{ range $i, $t := .Attribute.AnyOfTypes }}{{ if $i }} or {{ end }}{{ $t.Name }}{{ end }}"))
+{{ tabs .Depth }}}
+{{ else if $open }}{{ tabs .Depth }}}
+{{ end }}{{ else if .Pointer }}{{ $tmp := tempvar }}{{ tabs .Depth }}{{ $tmp }} := interface{}(raw{{ goify .Name true }})
 {{ tabs .Depth }}{{ .Pkg }} = &{{ $tmp }}
 {{ else }}{{ tabs .Depth }}{{ .Pkg }} = raw{{ goify .Name true }}
 {{ end }}{{ end }}`
@@ -508,15 +1544,26 @@ type {{ .Name }} struct {
 	// template input: *ContextTemplateData
 	ctxNewT = `{{ define "Coerce" }}` + coerceT + `{{ end }}` + `
 // New{{ goify .Name true }} parses the incoming request URL and body, performs validations and creates the
-// context used by the {{ .ResourceName }} controller {{ .ActionName }} action.
+// context used by the {{ .ResourceName }} controller {{ .ActionName }} action. The service is read from ctx
+// if nil, see goa.ServiceFromContext.
 func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, error) {
 	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
 	rctx := {{ .Name }}{Context: ctx, ResponseData: resp, RequestData: req}{{/*
 */}}
-{{ if .Headers }}{{ range $name, $att := .Headers.Type.ToObject }}	header{{ goify $name true }} := req.Header["{{ canonicalHeaderKey $name }}"]
+{{ if .HasFileParams }}	if err2 := req.ParseMultipartForm(32 << 20); err2 != nil && err2 != http.ErrNotMultipart {
+		err = goa.MergeErrors(err, goa.ErrBadRequest(err2))
+	}
+	var files map[string][]*multipart.FileHeader
+	if req.MultipartForm != nil {
+		files = req.MultipartForm.File
+	}
+{{ end }}{{ if .Headers }}{{ range $name, $att := .Headers.Type.ToObject }}	header{{ goify $name true }} := req.Header["{{ canonicalHeaderKey $name }}"]
 {{ $mustValidate := $.Headers.IsRequired $name }}{{ if $mustValidate }}	if len(header{{ goify $name true }}) == 0 {
 		err = goa.MergeErrors(err, goa.MissingHeaderError("{{ $name }}"))
 	} else {
@@ -533,10 +1580,18 @@ func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, er
 {{ template "Coerce" (newCoerceData $name $att ($.Headers.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
 */}}{{ $validation := validationChecker $att ($.Headers.IsNonZero $name) ($.Headers.IsRequired $name) ($.Headers.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}
+{{ end }}{{ if and (not $mustValidate) ($.Headers.HasDefaultValue $name) }}	} else {
+		{{ defaultAssignment $att (printf "rctx.%s" (goifyatt $att $name true)) }}
 {{ end }}	}
 {{ end }}{{ end }}{{/* if .Headers }}{{/*
 
-*/}}{{ if.Params }}{{ range $name, $att := .Params.Type.ToObject }}	param{{ goify $name true }} := req.Params["{{ $name }}"]
+*/}}{{ if.Params }}{{ range $name, $att := .Params.Type.ToObject }}{{ if eq $att.Type.Kind 17 }}{{/*
+*/}}	if headers := files["{{ $name }}"]; len(headers) > 0 {
+		{{ printf "rctx.%s" (goifyatt $att $name true) }} = headers[0]
+	}{{ if $.MustValidate $name }} else {
+		err = goa.MergeErrors(err, goa.MissingParamError("{{ $name }}"))
+	}{{ end }}
+{{ else }}{{ $enum := enum $.Name $name $att }}	param{{ goify $name true }} := req.Params["{{ $name }}"]
 {{ $mustValidate := $.MustValidate $name }}{{ if $mustValidate }}	if len(param{{ goify $name true }}) == 0 {
 		err = goa.MergeErrors(err, goa.MissingParamError("{{ $name }}"))
 	} else {
@@ -548,57 +1603,228 @@ func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, er
 */}}		}
 {{ end }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = params
 {{ else }}		raw{{ goify $name true}} := param{{ goify $name true}}[0]
-{{ template "Coerce" (newCoerceData $name $att ($.Params.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
-*/}}{{ $validation := validationChecker $att ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
+{{ template "Coerce" (withEnum (newCoerceData $name $att ($.Params.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) $enum) }}{{ end }}{{/*
+*/}}{{ $validation := validationChecker (or (and $enum (withoutEnum $att)) $att) ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}
+{{ end }}{{ if and (not $mustValidate) ($.Params.HasDefaultValue $name) }}	} else {
+		{{ defaultAssignment $att (printf "rctx.%s" (goifyatt $att $name true)) }}
 {{ end }}	}
-{{ end }}{{ end }}{{/* if .Params */}}	return &rctx, err
+{{ end }}{{ end }}{{ end }}{{/* if .Params */}}{{ if .ContextKeys }}{{ range $name, $att := .ContextKeys.Type.ToObject }}	if v, ok := Context{{ goify $name true }}(ctx); ok {
+		rctx.{{ goify $name true }} = v
+	}
+{{ end }}{{ end }}	return &rctx, err
+}
+`
+
+	// ctxAnyOfT generates the functions that return the values of the params restricted to several
+	// types with AnyOfTypes as one of the types.
+	// template input: *ContextTemplateData
+	ctxAnyOfT = `{{ if .Params }}{{ $ctx := . }}{{ range $name, $att := .Params.Type.ToObject }}{{ range $att.AnyOfTypes }}{{/*
+*/}}{{ $fn := printf "Must%s%s%sAs%s" (goify $ctx.ActionName true) (goify $ctx.ResourceName true) (goify $name true) (goify (gonative .) true) }}
+// {{ $fn }} returns the value of the {{ $name }} param of ctx coerced to {{ gonative . }}, it panics
+// if the value was coerced to another type.
+func {{ $fn }}(ctx *{{ $ctx.Name }}) {{ gonative . }} {
+	return {{ if $ctx.Params.IsPrimitivePointer $name }}(*ctx.{{ goifyatt $att $name true }}){{ else }}ctx.{{ goifyatt $att $name true }}{{ end }}.({{ gonative . }})
+}
+{{ end }}{{ end }}{{ end }}`
+
+	// ctxKeysT generates the private context key types and the functions that set and retrieve
+	// the values injected in the request context by middleware.
+	// template input: *design.AttributeDefinition
+	ctxKeysT = `{{ range $name, $att := .Type.ToObject }}{{ $key := printf "%sContextKey" (goify $name false) }}
+// {{ $key }} is the private type of the context key of the {{ $name }} value.
+type {{ $key }} struct{}
+
+// With{{ goify $name true }} returns a copy of ctx that holds the {{ $name }} value. Use it in
+// middleware to make the value available to the action contexts.
+func With{{ goify $name true }}(ctx context.Context, v {{ gotyperef $att.Type nil 0 false }}) context.Context {
+	return context.WithValue(ctx, {{ $key }}{}, v)
 }
+
+// Context{{ goify $name true }} returns the {{ $name }} value held by ctx if any.
+func Context{{ goify $name true }}(ctx context.Context) ({{ gotyperef $att.Type nil 0 false }}, bool) {
+	v, ok := ctx.Value({{ $key }}{}).({{ gotyperef $att.Type nil 0 false }})
+	return v, ok
+}
+{{ end }}
 `
 
+	// retryAfterT generates the code that sets the Retry-After header of responses that define
+	// a retry delay, either as a number of seconds or as an HTTP date.
+	// template input: map[string]interface{}
+	retryAfterT = `{{ if .Response.RetryAfter }}{{ if .RetryAt }}	ctx.ResponseData.Header().Set("Retry-After", retryAt.UTC().Format(http.TimeFormat))
+{{ else }}	if retryAfter == 0 {
+		retryAfter = {{ .Response.RetryAfter.Seconds }} * time.Second
+	}
+	ctx.ResponseData.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+{{ end }}{{ end }}`
+
+	// retryParamT generates the parameter of the response helpers that set the Retry-After header.
+	// template input: map[string]interface{}
+	retryParamT = `{{ if .RetryAt }}retryAt time.Time{{ else }}retryAfter time.Duration{{ end }}`
+
+	// retryDocT generates the end of the first sentence of the response helper comments.
+	// template input: map[string]interface{}
+	retryDocT = `{{ if .RetryAt }} and a Retry-After header set to retryAt{{ end }}`
+
+	// cacheControlT generates the code that sets the Cache-Control header of responses that
+	// define cache directives.
+	// template input: *design.ResponseDefinition
+	cacheControlT = `{{ if .CacheControl }}	ctx.ResponseData.Header().Set("Cache-Control", {{ printf "%q" (join .CacheControl ", ") }})
+{{ end }}`
+
 	// ctxMTRespT generates the response helpers for responses with media types.
 	// template input: map[string]interface{}
-	ctxMTRespT = `// {{ goify .RespName true }} sends a HTTP response with status code {{ .Response.Status }}.
-func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}(r {{ gotyperef .Projected .Projected.AllRequired 0 false }}) error {
+	ctxMTRespT = `{{ define "RetryAfter" }}` + retryAfterT + `{{ end }}` + `{{ define "RetryParam" }}` + retryParamT + `{{ end }}` + `{{ define "RetryDoc" }}` + retryDocT + `{{ end }}` + `{{ define "CacheControl" }}` + cacheControlT + `{{ end }}` + `// {{ goify .RespName true }}{{ if .RetryAt }}At{{ end }} sends a HTTP response with status code {{ .Response.Status }}{{ template "RetryDoc" . }}.
+func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}{{ if .RetryAt }}At{{ end }}(r {{ gotyperef .Projected .Projected.AllRequired 0 false }}{{ if .Response.RetryAfter }}, {{ template "RetryParam" . }}{{ end }}) error {
 	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
-	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ template "RetryAfter" . }}{{ template "CacheControl" .Response }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+}
+{{ if and .VisibleFields (not .RetryAt) }}
+// {{ goify .RespName true }}Filtered sends a HTTP response with status code {{ .Response.Status }} after removing the fields of r that are
+// not visible to any of the given roles.
+func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}Filtered(r {{ gotyperef .Projected .Projected.AllRequired 0 false }}, roles []string{{ if .Response.RetryAfter }}, retryAfter time.Duration{{ end }}) error {
+	if r != nil {
+		filtered := *r
+{{ range .VisibleFields }}		if !goa.HasAnyRole(roles{{ range .Roles }}, {{ printf "%q" . }}{{ end }}) {
+			filtered.{{ .Field }} = nil
+		}
+{{ end }}		r = &filtered
+	}
+	return ctx.{{ goify .RespName true }}(r{{ if .Response.RetryAfter }}, retryAfter{{ end }})
+}
+{{ end }}`
+
+	// ctxResultT generates the result interface of actions that return their result, the
+	// implementations of the interface and the method that sends the response.
+	// template input: *ContextTemplateData
+	ctxResultT = `{{ $marker := printf "%sMarker" (goify .ResultName false) }}
+// {{ .ResultName }} is the result of the {{ .ResourceName }} {{ .ActionName }} action, it is implemented by the types of the
+// action success responses.
+type {{ .ResultName }} interface {
+	{{ $marker }}()
+}
+{{ range .ResultTypes }}
+func ({{ .TypeRef }}) {{ $marker }}() {}
+{{ end }}
+// Respond sends the response whose type matches the type of r. A nil result indicates that the
+// action already sent the response.
+func (ctx *{{ .Name }}) Respond(r {{ .ResultName }}) error {
+	switch v := r.(type) {
+	case nil:
+		return nil
+{{ range .ResultTypes }}	case {{ .TypeRef }}:
+		return ctx.{{ .Response }}(v)
+{{ end }}	default:
+		return fmt.Errorf("invalid {{ .ActionName }} result type %T", r)
+	}
+}
+`
+
+	// ctxLongPollT generates the methods that stream the response of long-poll actions.
+	// template input: *ContextTemplateData
+	ctxLongPollT = `
+// OKChunk sends data encoded in JSON and followed by a newline as a chunk of the streamed 200
+// response and flushes the response so that the client receives the chunk right away.
+func (ctx *{{ .Name }}) OKChunk(data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return ctx.writeChunk(append(b, '\n'))
+}
+
+// OKDone ends the streamed response by sending the {"done":true} sentinel line, the action must
+// not send any other chunk once OKDone is called. The method is not named Done so that the context
+// keeps implementing context.Context.
+func (ctx *{{ .Name }}) OKDone() error {
+	return ctx.writeChunk([]byte("{\"done\":true}\n"))
+}
+
+// writeChunk writes the response status and headers on the first call, then writes b and flushes
+// the response.
+func (ctx *{{ .Name }}) writeChunk(b []byte) error {
+	if !ctx.ResponseData.Written() {
+		ctx.ResponseData.Header().Set("Content-Type", "application/x-ndjson")
+		ctx.ResponseData.Header().Set("Cache-Control", "no-cache")
+		ctx.ResponseData.WriteHeader(200)
+	}
+	if _, err := ctx.ResponseData.Write(b); err != nil {
+		return err
+	}
+	if f, ok := ctx.ResponseData.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
 }
 `
 
 	// ctxTRespT generates the response helpers for responses with overridden types.
 	// template input: map[string]interface{}
-	ctxTRespT = `// {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
-func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}(r {{ gotyperef .Type nil 0 false }}) error {
+	ctxTRespT = `{{ define "RetryAfter" }}` + retryAfterT + `{{ end }}` + `{{ define "RetryParam" }}` + retryParamT + `{{ end }}` + `{{ define "RetryDoc" }}` + retryDocT + `{{ end }}` + `{{ define "CacheControl" }}` + cacheControlT + `{{ end }}` + `// {{ goify .Response.Name true }}{{ if .RetryAt }}At{{ end }} sends a HTTP response with status code {{ .Response.Status }}{{ template "RetryDoc" . }}.
+func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}{{ if .RetryAt }}At{{ end }}(r {{ gotyperef .Type nil 0 false }}{{ if .Response.RetryAfter }}, {{ template "RetryParam" . }}{{ end }}) error {
 	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
-	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ template "RetryAfter" . }}{{ template "CacheControl" .Response }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
 `
 
 	// ctxNoMTRespT generates the response helpers for responses with no known media type.
 	// template input: *ContextTemplateData
-	ctxNoMTRespT = `
-// {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
-func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}({{ if .Response.MediaType }}resp []byte{{ end }}) error {
+	ctxNoMTRespT = `{{ define "RetryAfter" }}` + retryAfterT + `{{ end }}` + `{{ define "RetryParam" }}` + retryParamT + `{{ end }}` + `{{ define "RetryDoc" }}` + retryDocT + `{{ end }}` + `{{ define "CacheControl" }}` + cacheControlT + `{{ end }}` + `
+// {{ goify .Response.Name true }}{{ if .RetryAt }}At{{ end }} sends a HTTP response with status code {{ .Response.Status }}{{ template "RetryDoc" . }}.
+func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}{{ if .RetryAt }}At{{ end }}({{ if .Response.MediaType }}resp []byte{{ end }}{{/*
+*/}}{{ if .Response.RetryAfter }}{{ if .Response.MediaType }}, {{ end }}{{ template "RetryParam" . }}{{ end }}) error {
 {{ if .Response.MediaType }}	ctx.ResponseData.Header().Set("Content-Type", "{{ .Response.MediaType }}")
-{{ end }}	ctx.ResponseData.WriteHeader({{ .Response.Status }}){{ if .Response.MediaType }}
+{{ end }}{{ template "RetryAfter" . }}{{ template "CacheControl" .Response }}	ctx.ResponseData.WriteHeader({{ .Response.Status }}){{ if .Response.MediaType }}
 	_, err := ctx.ResponseData.Write(resp)
 	return err{{ else }}
 	return nil{{ end }}
 }
+`
+
+	// ctxMultipartRespT generates the response helpers for multipart responses.
+	// template input: map[string]interface{}
+	ctxMultipartRespT = `{{ define "CacheControl" }}` + cacheControlT + `{{ end }}` + `// {{ goify .Response.Name true }}Multipart sends a multipart HTTP response with status code {{ .Response.Status }}.
+// parts contains the content of each part indexed by part name.
+func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}Multipart(parts map[string]io.Reader) error {
+	mw := multipart.NewWriter(ctx.ResponseData)
+	ctx.ResponseData.Header().Set("Content-Type", mw.FormDataContentType())
+{{ template "CacheControl" .Response }}	ctx.ResponseData.WriteHeader({{ .Response.Status }})
+{{ range .Response.Parts }}	if r, ok := parts["{{ .Name }}"]; ok {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", "form-data; name=\"{{ .Name }}\"")
+		h.Set("Content-Type", "{{ .ContentType }}")
+		pw, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(pw, r); err != nil {
+			return err
+		}
+	}
+{{ end }}	return mw.Close()
+}
 `
 
 	// payloadT generates the payload type definition GoGenerator
 	// template input: *ContextTemplateData
-	payloadT = `{{ $payload := .Payload }}{{ if .Payload.IsObject }}// {{ gotypename .Payload nil 0 true }} is the {{ .ResourceName }} {{ .ActionName }} action payload.{{/*
+	payloadT = `{{ define "Enums" }}` + enumT + `{{ end }}` + `{{ $payload := .Payload }}{{ if .Payload.IsObject }}// {{ gotypename .Payload nil 0 true }} is the {{ .ResourceName }} {{ .ActionName }} action payload.{{/*
 */}}{{ $privateTypeName := gotypename .Payload nil 1 true }}
 type {{ $privateTypeName }} {{ gotypedef .Payload 0 true true }}
-
+{{ if requiredNullable .Payload.AttributeDefinition }}
+// UnmarshalJSON decodes the payload and records the members set to null.
+func (payload {{ gotyperef .Payload .Payload.AllRequired 0 true }}) UnmarshalJSON(data []byte) (err error) {
+	type alias {{ $privateTypeName }}
+	payload.nulls, err = goa.DecodeJSONNulls(data, (*alias)(payload))
+	return
+}
+{{ end }}
 {{ $assignment := recursiveFinalizer .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}// Finalize sets the default values defined in the design.
 func (payload {{ gotyperef .Payload .Payload.AllRequired 0 true }}) Finalize() {
 {{ $assignment }}
 }{{ end }}
 
-{{ $validation := recursiveValidate .Payload.AttributeDefinition false false false "payload" "raw" 1 true }}{{ if $validation }}// Validate runs the validation rules defined in the design.
+{{ $validation := printf "%s%s" (nullChecker .Payload.AttributeDefinition "payload" "raw") (recursiveValidate .Payload.AttributeDefinition false false false "payload" "raw" 1 true) }}{{ if $validation }}// Validate runs the validation rules defined in the design.
 func (payload {{ gotyperef .Payload .Payload.AllRequired 0 true }}) Validate() (err error) {
 {{ $validation }}
 	return
@@ -618,16 +1844,28 @@ type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
 func (payload {{ gotyperef .Payload .Payload.AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
-}{{ end }}
+}{{ end }}{{ template "Enums" (enums (gotypename .Payload nil 1 false) .Payload.AttributeDefinition) }}
 `
 	// ctrlT generates the controller interface for a given resource.
 	// template input: *ControllerTemplateData
-	ctrlT = `// {{ .Resource }}Controller is the controller interface for the {{ .Resource }} actions.
+	ctrlT = `{{ define "ActionDoc" }}{{ if .Summary }}	// {{ .Name }}: {{ .Summary }}
+{{ end }}{{ if .Description }}{{ if .Summary }}	//
+{{ end }}{{ indent (comment .Description) "\t" }}
+{{ end }}{{ if .Tags }}	//
+	// Tags: {{ .Tags }}
+{{ end }}{{ end }}{{ if .HasWebSocket }}// {{ .Resource }}WebSocketController is the controller interface for the {{ .Resource }} WebSocket
+// actions.
+type {{ .Resource }}WebSocketController interface {
+{{ range .Actions }}{{ if .WebSocket }}{{ template "ActionDoc" . }}	{{ .Name }}(*{{ .WebSocketContext }}) error
+{{ end }}{{ end }}}
+
+{{ end }}// {{ .Resource }}Controller is the controller interface for the {{ .Resource }} actions.
 type {{ .Resource }}Controller interface {
 	goa.Muxer
 {{ if .FileServers }}	goa.FileServer
-{{ end }}{{ range .Actions }}	{{ .Name }}(*{{ .Context }}) error
-{{ end }}}
+{{ end }}{{ if .HasWebSocket }}	{{ .Resource }}WebSocketController
+{{ end }}{{ range .Actions }}{{ if not .WebSocket }}{{ template "ActionDoc" . }}	{{ .Name }}(*{{ .Context }}) {{ if .Result }}({{ .Result }}, error){{ else }}error{{ end }}
+{{ end }}{{ end }}}
 `
 
 	// serviceT generates the service initialization code.
@@ -648,17 +1886,64 @@ func initService(service *goa.Service) {
 {{ end }}{{ end }}{{ range .Decoders }}{{ if .Default }}{{/*
 */}}	service.Decoder.Register({{ .PackageName }}.{{ .Function }}, "*/*")
 {{ end }}{{ end }}}
+`
+
+	// debugT generates the profiling handlers registration code.
+	// template input: nil
+	debugT = `
+// RegisterDebugHandlers attaches the net/http/pprof handlers to mux under "/debug/pprof/".
+func RegisterDebugHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
+
+// mountDebug mounts the profiling handlers on the service unless they are already mounted, e.g.
+// by the Mount function of another controller.
+func mountDebug(service *goa.Service) {
+	if service.Mux.Lookup("GET", "{{ routePath "/debug/pprof/*profile" }}") != nil {
+		return
+	}
+	mux := http.NewServeMux()
+	RegisterDebugHandlers(mux)
+	h := func(rw http.ResponseWriter, req *http.Request, _ url.Values) {
+		mux.ServeHTTP(rw, req)
+	}
+	service.Mux.Handle("GET", "{{ routePath "/debug/pprof/*profile" }}", h)
+	service.Mux.Handle("POST", "/debug/pprof/symbol", h)
+	service.LogInfo("mount", "route", "GET /debug/pprof/*profile")
+}
+`
+
+	// noDebugT generates the profiling handlers mount function used when the package is not built
+	// with the "debug" build tag.
+	// template input: nil
+	noDebugT = `
+// mountDebug does nothing, the profiling handlers are only mounted when the package is built with
+// the "debug" build tag.
+func mountDebug(service *goa.Service) {}
 `
 
 	// mountT generates the code for a resource "Mount" function.
 	// template input: *ControllerTemplateData
 	mountT = `
-// Mount{{ .Resource }}Controller "mounts" a {{ .Resource }} resource controller on the given service.
-func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Controller) {
+{{ if eq .Router "chi" }}// Mount{{ .Resource }}Controller "mounts" a {{ .Resource }} resource controller on the given chi router.
+// The service provides the encoders, decoders and middleware used by the handlers.
+{{ else }}// Mount{{ .Resource }}Controller "mounts" a {{ .Resource }} resource controller on the given service.
+{{ end }}// The chain middleware wrap the handler of each route, the first middleware is the outermost.
+func Mount{{ .Resource }}Controller(service *goa.Service, {{ if eq .Router "chi" }}r chi.Router, {{ end }}ctrl {{ .Resource }}Controller, chain ...func(http.Handler) http.Handler) {
 	initService(service)
-	var h goa.Handler
+{{ if eq .Router "stdlib" }}	if _, ok := service.Mux.(*goa.StdMux); !ok {
+		panic("Mount{{ .Resource }}Controller requires a service using the standard library router, create it with NewService")
+	}
+{{ end }}{{ if .Expvar }}	mountExpvar(service)
+{{ end }}{{ if .Debug }}	mountDebug(service)
+{{ end }}{{ range .Compression }}	service.SetCompressor({{ printf "%q" . }}, {{ compressor . }})
+{{ end }}	var h goa.Handler
 {{ $res := .Resource }}{{ if .Origins }}{{ range .PreflightPaths }}{{/*
-*/}}	service.Mux.Handle("OPTIONS", "{{ . }}", ctrl.MuxHandler("preflight", handle{{ $res }}Origin(cors.HandlePreflight()), nil))
+*/}}	{{ handle "OPTIONS" . }}ctrl.MuxHandler("preflight", handle{{ $res }}Origin(cors.HandlePreflight()), nil){{ handleEnd . }}
 {{ end }}{{ end }}{{ range .Actions }}{{ $action := . }}
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -670,25 +1955,572 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 		if err != nil {
 			return err
 		}
-{{ if .Payload }}		// Build the payload
+{{ if .WebSocket }}		// Upgrade the connection, the websocket handler writes the error response if the handshake fails
+		resp := goa.ContextResponse(ctx)
+		resp.Status = http.StatusSwitchingProtocols
+		websocket.Handler(func(conn *websocket.Conn) {
+			// The connection is hijacked, errors can only be logged
+			if err := ctrl.{{ .Name }}(&{{ .WebSocketContext }}{ {{- .Context }}: rctx, Conn: conn}); err != nil {
+				goa.LogError(ctx, "websocket", "action", {{ printf "%q" .Name }}, "err", err)
+			}
+		}).ServeHTTP(resp.ResponseWriter, req)
+		return nil
+{{ else }}{{ if .Payload }}		// Build the payload
 		if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
-			rctx.Payload = rawPayload.({{ gotyperef .Payload nil 1 false }})
+			rctx.Payload = rawPayload{{ if .MergePatch }}.(map[string]json.RawMessage){{ else if not .Payload.Discriminator }}.({{ gotyperef .Payload nil 1 false }}){{ end }}
 {{ if not .PayloadOptional }}		} else {
 			return goa.MissingPayloadError()
 {{ end }}		}
-{{ end }}		return ctrl.{{ .Name }}(rctx)
-	}
+{{ end }}{{ if .Audit }}		// Record the audit event once the action completes successfully
+		snapshot := {{ .AuditName }}Before(rctx)
+		if err := ctrl.{{ .Name }}(rctx); err != nil {
+			return err
+		}
+		AuditHandler(ctx, {{ .AuditName }}After(rctx, snapshot))
+		return nil
+{{ else if .Result }}		res, err := ctrl.{{ .Name }}(rctx)
+		if err != nil {
+			return err
+		}
+		return rctx.Respond(res)
+{{ else }}		return ctrl.{{ .Name }}(rctx)
+{{ end }}{{ end }}	}
+	h = handleRecover(h)
 {{ if $.Origins }}	h = handle{{ $res }}Origin(h)
 {{ end }}{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
-{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
-	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ if $.Expvar }}	h = handleExpvar({{ printf "%q" (printf "%s.%s" $res .Name) }}, h)
+{{ end }}{{ if $.Tracer }}	h = handleTrace({{ printf "%q" .Span }}, h)
+{{ end }}{{ if and $.Compression (not .WebSocket) }}	h = compressHandler(service, h)
+{{ end }}{{ if $.Sunset }}	h = handleSunset(h)
+{{ end }}{{ range .Routes }}{{ if $action.Webhook }}	{{ handle .Verb .FullPath }}middleware.VerifyWebhookSignature({{ printf "%q" $action.Webhook.SignatureHeader }}, {{ printf "%q" $action.Webhook.Secret }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }})){{ handleEnd .FullPath }}
+{{ else }}	{{ handle .Verb .FullPath }}ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}){{ handleEnd .FullPath }}
+{{ end }}	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb (routePath .FullPath)) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ if $.AutoHead $action .Verb .FullPath }}	{{ handle "HEAD" .FullPath }}headHandler(ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }})){{ handleEnd .FullPath }}
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "HEAD %s" (routePath .FullPath)) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ end }}{{ end }}{{ range .PatchPaths }}{{ $path := . }}
+	h = handleMethodNotAllowed({{ printf "%q" .Allow }}, {{ printf "%q" .AcceptPatch }})
+{{ range .Methods }}	{{ handle . $path.Path }}ctrl.MuxHandler("method not allowed", h, nil){{ handleEnd $path.Path }}
 {{ end }}{{ end }}{{ range .FileServers }}
 	h = ctrl.FileHandler({{ printf "%q" .RequestPath }}, {{ printf "%q" .FilePath }})
 {{ if $.Origins }}	h = handle{{ $res }}Origin(h)
 {{ end }}{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
-{{ end }}	service.Mux.Handle("GET", "{{ .RequestPath }}", ctrl.MuxHandler("serve", h, nil))
-	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "files", {{ printf "%q" .FilePath }}, "route", {{ printf "%q" (printf "GET %s" .RequestPath) }}{{ with .Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ if $.Sunset }}	h = handleSunset(h)
+{{ end }}	{{ handle "GET" .RequestPath }}ctrl.MuxHandler("serve", h, nil){{ handleEnd .RequestPath }}
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "files", {{ printf "%q" .FilePath }}, "route", {{ printf "%q" (printf "GET %s" (routePath .RequestPath)) }}{{ with .Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ if not ($.HasHead .RequestPath) }}	{{ handle "HEAD" .RequestPath }}headHandler(ctrl.MuxHandler("serve", h, nil)){{ handleEnd .RequestPath }}
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "files", {{ printf "%q" .FilePath }}, "route", {{ printf "%q" (printf "HEAD %s" (routePath .RequestPath)) }}{{ with .Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ end }}{{ range .StaticAssets }}
+	h = handleStaticAssets({{ printf "%q" .RequestPath }}, {{ printf "%q" .FilePath }}, {{ printf "%q" .CacheControl }})
+{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
+{{ end }}{{ if $.Sunset }}	h = handleSunset(h)
+{{ end }}	{{ handle "GET" .RoutePath }}ctrl.MuxHandler("static assets", h, nil){{ handleEnd .RoutePath }}
+	{{ handle "HEAD" .RoutePath }}ctrl.MuxHandler("static assets", h, nil){{ handleEnd .RoutePath }}
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "assets", {{ printf "%q" .FilePath }}, "route", {{ printf "%q" (printf "GET %s" (routePath .RoutePath)) }})
 {{ end }}}
+{{ if .Standalone }}
+// {{ .Resource }}Service is a http.Handler that serves the {{ .Resource }} resource actions so that
+// the controller can be mounted on any net/http server without running the goa service, e.g.:
+//
+//	http.Handle("/", New{{ .Resource }}Service(service, ctrl))
+type {{ .Resource }}Service struct {
+	service *goa.Service
+}
+
+// New{{ .Resource }}Service mounts the controller on the service mux and returns the handler dispatching
+// the requests to it. The service provides the encoders, decoders, middleware and mux used to
+// handle the requests, it must be the service that created the controller. chain lists the
+// middleware wrapping the handler of each route.
+func New{{ .Resource }}Service(service *goa.Service, ctrl {{ .Resource }}Controller, chain ...func(http.Handler) http.Handler) *{{ .Resource }}Service {
+	Mount{{ .Resource }}Controller(service, ctrl, chain...)
+	return &{{ .Resource }}Service{service: service}
+}
+
+// ServeHTTP dispatches the requests to the service mux.
+func (s *{{ .Resource }}Service) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.service.Mux.ServeHTTP(rw, req)
+}
+{{ end }}`
+
+	// sunsetT generates the handler wrapper that implements the API version sunset.
+	// template input: *ControllerTemplateData
+	sunsetT = `
+// sunsetDate is the date after which the API version is no longer served.
+var sunsetDate = time.Unix({{ .Sunset.Date.Unix }}, 0).UTC()
+
+// handleSunset sets the Deprecation and Sunset headers on the responses written by h.
+{{ if .Sunset.RedirectTo }}// Requests made after the sunset date are redirected to {{ printf "%q" .Sunset.RedirectTo }}.
+{{ else }}// Requests made after the sunset date get a 410 Gone response.
+{{ end }}func handleSunset(h goa.Handler) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		rw.Header().Set("Deprecation", "true")
+		rw.Header().Set("Sunset", sunsetDate.Format(http.TimeFormat))
+		if time.Now().Before(sunsetDate) {
+			return h(ctx, rw, req)
+		}
+{{ if .Sunset.RedirectTo }}		location := {{ printf "%q" .Sunset.RedirectTo }} + strings.TrimPrefix(req.URL.Path, {{ printf "%q" .API.BasePath }})
+		if req.URL.RawQuery != "" {
+			location += "?" + req.URL.RawQuery
+		}
+		http.Redirect(rw, req, location, http.StatusMovedPermanently)
+{{ else }}		rw.WriteHeader(http.StatusGone)
+{{ end }}		return nil
+	}
+}
+`
+
+	// staticAssetsT generates the handler that serves the static assets with http.FileServer.
+	// template input: *ControllerTemplateData
+	staticAssetsT = `
+// handleStaticAssets returns a handler that serves the files of the directory dir, the prefix is
+// stripped from the request path to build the file path.
+func handleStaticAssets(prefix, dir, cacheControl string) goa.Handler {
+	h := withCacheControl(cacheControl, http.StripPrefix(prefix, http.FileServer(http.Dir(dir))))
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		h.ServeHTTP(rw, req)
+		return nil
+	}
+}
+
+// withCacheControl is a middleware that sets the Cache-Control header of the responses written by
+// h to value.
+func withCacheControl(value string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", value)
+		h.ServeHTTP(rw, req)
+	})
+}
+`
+
+	// methodNotAllowedT generates the handler that rejects the requests made with a method that the
+	// path of PATCH routes does not allow.
+	// template input: *ControllerTemplateData
+	methodNotAllowedT = `
+// handleMethodNotAllowed returns a handler that responds with 405 Method Not Allowed, the Allow
+// header lists the methods allowed on the request path and the Accept-Patch header the content
+// types of the patch documents accepted by the PATCH actions of the path.
+func handleMethodNotAllowed(allow, acceptPatch string) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		rw.Header().Set("Allow", allow)
+		rw.Header().Set("Accept-Patch", acceptPatch)
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return nil
+	}
+}
+`
+
+	// headT generates the adapter used to serve HEAD requests with the GET handlers.
+	// template input: *ControllerTemplateData
+	headT = `
+// headHandler returns a handler that serves HEAD requests with the GET handler h: the response
+// status and headers written by h are sent and the response body is discarded.
+func headHandler(h goa.MuxHandler) goa.MuxHandler {
+	return func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+		h(headResponseWriter{rw}, req, params)
+	}
+}
+
+// headResponseWriter is the response writer given to the GET handlers serving HEAD requests.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write discards the response body.
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+`
+
+	// chainT generates the adapter that applies the net/http middleware given to the Mount
+	// functions.
+	// template input: *ControllerTemplateData
+	chainT = `
+// chainMuxHandler wraps h with the given net/http middleware, the first middleware is the
+// outermost. The request context given to the middleware holds the service, see
+// goa.ServiceFromContext.
+func chainMuxHandler(service *goa.Service, h goa.MuxHandler, chain []func(http.Handler) http.Handler) goa.MuxHandler {
+	var next http.Handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		params, _ := req.Context().Value(muxParamsKey{}).(url.Values)
+		h(rw, req, params)
+	})
+	for i := len(chain) - 1; i >= 0; i-- {
+		next = chain[i](next)
+	}
+	return func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+		ctx := context.WithValue(req.Context(), goa.ServiceKey, service)
+		ctx = context.WithValue(ctx, muxParamsKey{}, params)
+		next.ServeHTTP(rw, req.WithContext(ctx))
+	}
+}
+
+// muxParamsKey is the request context key used to forward the route parameters through the
+// middleware chain.
+type muxParamsKey struct{}
+`
+
+	// recoverT generates the handler wrapper that recovers from the panics raised by the
+	// actions.
+	// template input: *ControllerTemplateData
+	recoverT = `
+// handleRecover returns a handler that recovers from the panics raised by h. The panic value and
+// stack trace are logged with the service logger and the client receives a 500 response with a
+// problem+json (RFC 7807) body holding the ID of the logged error, the server keeps running.
+func handleRecover(h goa.Handler) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			perr := goa.ErrInternal(fmt.Sprintf("panic: %v", r)).(goa.ServiceError)
+			goa.LogError(ctx, "panic", "id", perr.Token(), "err", perr, "stack", string(debug.Stack()))
+			resp := goa.ContextResponse(ctx)
+			if resp == nil || resp.Written() {
+				return
+			}
+			resp.Header().Set("Content-Type", "application/problem+json")
+			resp.WriteHeader(http.StatusInternalServerError)
+			err = json.NewEncoder(resp).Encode(map[string]interface{}{
+				"type":   "about:blank",
+				"title":  http.StatusText(http.StatusInternalServerError),
+				"status": http.StatusInternalServerError,
+				"detail": "the server failed to handle the request",
+				"id":     perr.Token(),
+			})
+		}()
+		return h(ctx, rw, req)
+	}
+}
+`
+
+	// chiT generates the adapter used to register the action handlers with chi routers.
+	// template input: *ControllerTemplateData
+	chiT = `
+// chiHandler adapts h to the http.Handler interface used by chi routers. names lists the route
+// wildcards whose values are read with chi.URLParam, catch-all wildcards are prefixed with "*".
+func chiHandler(h goa.MuxHandler, names ...string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		params := req.URL.Query()
+		for _, n := range names {
+			if strings.HasPrefix(n, "*") {
+				params.Set(n[1:], chi.URLParam(req, "*"))
+				continue
+			}
+			params.Set(n, chi.URLParam(req, n))
+		}
+		h(rw, req, params)
+	})
+}
+`
+
+	// stdServiceT generates the constructor of the services that use the standard library router.
+	// template input: *ControllerTemplateData
+	stdServiceT = `
+// NewService creates a service that uses the standard library router, the controllers of this
+// package must be mounted on services created with this function.
+func NewService(name string) *goa.Service {
+	service := goa.New(name)
+	service.UseMux(goa.NewStdMux())
+	return service
+}
+`
+
+	// compressT generates the handler wrapper that compresses the responses.
+	// template input: *ControllerTemplateData
+	compressT = `
+// compressHandler compresses the responses written by h using the algorithm negotiated with the
+// request Accept-Encoding header among the ones registered with the service.
+func compressHandler(service *goa.Service, h goa.Handler) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		encoding, compressor := service.Compressor(req.Header.Get("Accept-Encoding"))
+		if compressor == nil {
+			return h(ctx, rw, req)
+		}
+		resp := goa.ContextResponse(ctx)
+		cw := goa.NewCompressResponseWriter(resp.ResponseWriter, encoding, compressor)
+		resp.SwitchWriter(cw)
+		err := h(ctx, rw, req)
+		resp.SwitchWriter(cw.ResponseWriter)
+		if cerr := cw.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}
+}
+`
+
+	// semverT generates the semantic version helpers.
+	// template input: nil
+	semverT = `
+// SemVerString is a semantic version string such as "v1.2.3".
+type SemVerString = goa.SemVerString
+
+// CompareSemVer returns an integer comparing two semantic versions. The result is 0 if a == b,
+// -1 if a < b and +1 if a > b. An invalid version is considered less than all valid versions.
+func CompareSemVer(a, b SemVerString) int {
+	return semver.Compare(a, b)
+}
+`
+
+	// tracingT generates the Datadog APM instrumentation of the service.
+	// template input: nil
+	tracingT = `
+// TraceService returns the service mux wrapped with the dd-trace-go HTTP handler that creates a
+// Datadog APM span for each request. The mount functions name the spans after the resource and
+// action handling the request as named in the design, e.g. "bottle.show". The server must serve the returned handler
+// instead of service.Mux and the tracer must be started with tracer.Start. opts are given to the
+// dd-trace-go HTTP handler.
+func TraceService(service *goa.Service, opts ...httptrace.Option) http.Handler {
+	return httptrace.WrapHandler(service.Mux, service.Name, "", opts...)
+}
+
+// handleTrace sets the resource name of the request span and stores the span in the action
+// context so that the action may create child spans with tracer.StartSpanFromContext.
+func handleTrace(resource string, h goa.Handler) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		span, ok := tracer.SpanFromContext(req.Context())
+		if !ok {
+			return h(ctx, rw, req)
+		}
+		span.SetTag(ext.ResourceName, resource)
+		return h(tracer.ContextWithSpan(ctx, span), rw, req)
+	}
+}
+`
+
+	// auditT generates the audit event types and helpers.
+	// template input: none
+	auditT = `
+// AuditSnapshot captures the state of an audited request.
+type AuditSnapshot struct {
+	// Payload is the JSON representation of the request payload.
+	Payload json.RawMessage
+}
+
+// AuditEvent describes the changes made by an audited action.
+type AuditEvent struct {
+	// Actor identifies the caller.
+	Actor string
+	// Resource is the name of the changed resource.
+	Resource string
+	// ResourceID is the ID of the changed resource.
+	ResourceID string
+	// Action is the name of the audited action.
+	Action string
+	// Before is the JSON representation of the request payload before the action ran.
+	Before json.RawMessage
+	// After is the JSON representation of the request payload after the action ran.
+	After json.RawMessage
+	// Changed lists the names of the payload fields whose values differ in Before and After.
+	Changed []string
+	// Timestamp is the time the action completed.
+	Timestamp time.Time
+}
+
+// AuditHandler is called with the events recorded by the audited actions, the default
+// implementation logs them.
+var AuditHandler = func(ctx context.Context, event AuditEvent) {
+	goa.LogInfo(ctx, "audit", "actor", event.Actor, "resource", event.Resource, "id", event.ResourceID,
+		"action", event.Action, "changed", strings.Join(event.Changed, ","))
+}
+
+// newAuditSnapshot captures the JSON representation of the given payload.
+func newAuditSnapshot(payload interface{}) AuditSnapshot {
+	js, err := json.Marshal(payload)
+	if err != nil {
+		return AuditSnapshot{}
+	}
+	return AuditSnapshot{Payload: js}
+}
+
+// auditChanges returns the sorted names of the fields whose values differ in the before and after
+// JSON objects.
+func auditChanges(before, after json.RawMessage) []string {
+	var b, a map[string]json.RawMessage
+	if json.Unmarshal(before, &b) != nil || json.Unmarshal(after, &a) != nil {
+		return nil
+	}
+	var changed []string
+	for n, v := range b {
+		if av, ok := a[n]; !ok || !bytes.Equal(v, av) {
+			changed = append(changed, n)
+		}
+	}
+	for n := range a {
+		if _, ok := b[n]; !ok {
+			changed = append(changed, n)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+`
+
+	// auditActionT generates the functions that record the audit events of an action.
+	// template input: map[string]interface{}
+	auditActionT = `
+// {{ .Name }}Before captures the {{ .ResourceName }} {{ .ActionName }} request before the action runs.
+func {{ .Name }}Before(ctx *{{ .Context }}) AuditSnapshot {
+	return newAuditSnapshot({{ if .HasPayload }}ctx.Payload{{ else }}nil{{ end }})
+}
+
+// {{ .Name }}After returns the audit event of the {{ .ResourceName }} {{ .ActionName }} request
+// given the snapshot taken before the action ran.
+func {{ .Name }}After(ctx *{{ .Context }}, snapshot AuditSnapshot) AuditEvent {
+	after := {{ .Name }}Before(ctx)
+	return AuditEvent{
+		Actor:      ctx.RequestData.Header.Get({{ printf "%q" .Actor }}),
+		Resource:   {{ printf "%q" .ResourceName }},
+		ResourceID: ctx.RequestData.Params.Get({{ printf "%q" .Resource }}),
+		Action:     {{ printf "%q" .ActionName }},
+		Before:     snapshot.Payload,
+		After:      after.Payload,
+		Changed:    auditChanges(snapshot.Payload, after.Payload),
+		Timestamp:  time.Now().UTC(),
+	}
+}
+`
+
+	// jsonBodyT generates the helper that rejects the request bodies that are not valid JSON
+	// despite a JSON content type.
+	// template input: *ControllerTemplateData
+	jsonBodyT = `
+// checkJSONBody returns an unsupported media type error if the request Content-Type header is a
+// JSON media type but the body is not valid JSON, for example a XML document. This prevents the
+// decoder from sniffing the actual content type of the body. checkJSONBody restores the request
+// body so that it can be decoded afterwards.
+func checkJSONBody(req *http.Request) error {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if !json.Valid(body) {
+		return goa.ErrUnsupportedMediaType("request body is not valid JSON", "content-type", mediaType)
+	}
+	return nil
+}
+`
+
+	// schemaT generates the helpers that validate the request bodies against the payload JSON
+	// schemas.
+	// template input: *ControllerTemplateData
+	schemaT = `
+// compileSchema compiles the JSON schema of an action payload.
+func compileSchema(schema string) *gojsonschema.Schema {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema))
+	if err != nil {
+		panic(err) // bug
+	}
+	return s
+}
+
+// validateSchema validates the JSON request body against the given schema before it gets decoded
+// so that structural errors are reported with the names of the offending fields. validateSchema
+// restores the request body so that it can be decoded afterwards, non JSON bodies are not
+// validated.
+func validateSchema(req *http.Request, schema *gojsonschema.Schema) error {
+	if ct := req.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "json") {
+		return nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	res, err := schema.Validate(gojsonschema.NewBytesLoader(body))
+	if err != nil {
+		return goa.ErrInvalidEncoding(err)
+	}
+	var verr error
+	for _, e := range res.Errors() {
+		verr = goa.MergeErrors(verr, goa.ErrInvalidRequest(e.String(), "attribute", e.Field(), "error", e.Type()))
+	}
+	return verr
+}
+`
+
+	// expvarT generates the code that records the request metrics published via expvar.
+	// template input: *ControllerTemplateData
+	expvarT = `
+// latencyBuckets lists the upper bounds of the buckets of the request latency histograms.
+var latencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// mountExpvar mounts the expvar handler on "GET /debug/vars" unless the service mux already
+// handles it.
+func mountExpvar(service *goa.Service) {
+	if service.Mux.Lookup("GET", "/debug/vars") != nil {
+		return
+	}
+	h := expvar.Handler()
+	service.Mux.Handle("GET", "/debug/vars", func(rw http.ResponseWriter, req *http.Request, _ url.Values) {
+		h.ServeHTTP(rw, req)
+	})
+	service.LogInfo("mount", "route", "GET /debug/vars")
+}
+
+// expvarMap returns the map published with expvar under the given name, publishing it first if
+// needed. The maps are shared with the other generated packages linked in the program.
+func expvarMap(name string) *expvar.Map {
+	if m, ok := expvar.Get(name).(*expvar.Map); ok {
+		return m
+	}
+	return expvar.NewMap(name)
+}
+
+// handleExpvar records the number of requests handled by h in the "goa.requests" map keyed by
+// "resource.action.status" and the histogram of the time spent handling them in the
+// "goa.latencies" map keyed by "resource.action". The histogram buckets are keyed by their upper
+// bound, "+Inf" counts the requests slower than the last bound.
+func handleExpvar(key string, h goa.Handler) goa.Handler {
+	counts := expvarMap("goa.requests")
+	latencies := expvarMap("goa.latencies")
+	histogram, ok := latencies.Get(key).(*expvar.Map)
+	if !ok {
+		histogram = new(expvar.Map).Init()
+		latencies.Set(key, histogram)
+	}
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		start := time.Now()
+		err := h(ctx, rw, req)
+		status := goa.ContextResponse(ctx).Status
+		if err != nil {
+			status = http.StatusInternalServerError
+			if serr, ok := err.(goa.ServiceError); ok {
+				status = serr.ResponseStatus()
+			}
+		}
+		elapsed := time.Since(start)
+		counts.Add(fmt.Sprintf("%s.%d", key, status), 1)
+		bucket := "+Inf"
+		for _, b := range latencyBuckets {
+			if elapsed <= b {
+				bucket = b.String()
+				break
+			}
+		}
+		histogram.Add(bucket, 1)
+		return err
+	}
+}
 `
 
 	// handleCORST generates the code that checks whether a CORS request is authorized
@@ -725,10 +2557,109 @@ func handle{{ .Resource }}Origin(h goa.Handler) goa.Handler {
 
 	// unmarshalT generates the code for an action payload unmarshal function.
 	// template input: *ControllerTemplateData
-	unmarshalT = `{{ range .Actions }}{{ if .Payload }}
+	unmarshalT = `{{ define "Coerce" }}` + coerceT + `{{ end }}` + `{{ range .Actions }}{{ if .Payload }}{{ if .Schema }}
+// {{ .Unmarshal }}Schema is the JSON schema of the {{ .Name }} action payload.
+var {{ .Unmarshal }}Schema = compileSchema({{ printf "%q" .Schema }})
+{{ end }}
 // {{ .Unmarshal }} unmarshals the request body into the context request data Payload field.
 func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Request) error {
-	{{ if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
+{{ if .FormData }}	if err := req.ParseMultipartForm(32 << 20); err != nil {
+		return goa.ErrBadRequest(err)
+	}
+	payload := &{{ gotypename .Payload nil 1 false }}{}
+	var err error
+{{ $payload := .Payload }}{{ range $name, $att := .Payload.Type.ToObject }}{{ if eq $att.Type.Kind 17 }}{{/*
+*/}}	if file, header, err2 := req.FormFile("{{ $name }}"); err2 == nil {
+		file.Close()
+		payload.{{ goifyatt $att $name true }} = header
+	} else if err2 != http.ErrMissingFile {
+		err = goa.MergeErrors(err, goa.ErrBadRequest(err2))
+	}
+{{ else }}	if raw{{ goify $name true }} := req.FormValue("{{ $name }}"); raw{{ goify $name true }} != "" {
+{{ template "Coerce" (newCoerceData $name $att ($payload.IsPrimitivePointer $name) (printf "payload.%s" (goifyatt $att $name true)) 2) }}{{/*
+*/}}	}
+{{ end }}{{ end }}	if err != nil {
+		return err
+	}{{ $validation := recursiveValidate .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}
+	if err := payload.Validate(); err != nil {
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}{{ end }}
+	goa.ContextRequest(ctx).Payload = payload
+	return nil
+{{ else if .MergePatch }}	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload
+	return nil
+{{ else if .MultipartIngest }}{{ $elem := (index .Payload.Type.ToObject "parts").Type.ToArray.ElemType }}	reader, err := req.MultipartReader()
+	if err != nil {
+		return goa.ErrBadRequest(err)
+	}
+	payload := &{{ gotypename .Payload nil 1 true }}{}
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return goa.ErrBadRequest(err)
+		}
+		var part {{ gotyperef $elem.Type $elem.AllRequired 2 true }}
+		err = service.Decoder.Decode(&part, p, p.Header.Get("Content-Type"))
+		p.Close()
+		if err != nil {
+			return goa.ErrBadRequest(fmt.Errorf("failed to decode part %d: %s", len(payload.Parts)+1, err))
+		}
+		payload.Parts = append(payload.Parts, part)
+	}{{ $assignment := recursiveFinalizer .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
+	payload.Finalize(){{ end }}{{ $validation := recursiveValidate .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}{{ end }}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+{{ else }}{{ with .Payload.Discriminator }}	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	var probe struct {
+		Type string ` + "`" + `json:"{{ .Field }}"` + "`" + `
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return err
+	}
+	switch probe.Type {
+{{ range $value, $type := .Mapping }}	case {{ printf "%q" $value }}:
+		payload := &{{ gotypename $type $type.AllRequired 0 true }}{}
+		if err := json.Unmarshal(body, payload); err != nil {
+			return err
+		}{{ $assignment := recursiveFinalizer $type.AttributeDefinition "payload" 2 }}{{ if $assignment }}
+		payload.Finalize(){{ end }}{{ $validation := recursiveValidate $type.AttributeDefinition false false false "payload" "raw" 2 true }}{{ if $validation }}
+		if err := payload.Validate(); err != nil {
+			// Initialize payload with private data structure so it can be logged
+			goa.ContextRequest(ctx).Payload = payload
+			return err
+		}{{ end }}
+		goa.ContextRequest(ctx).Payload = payload.Publicize()
+{{ end }}	default:
+		return goa.InvalidDiscriminatorError({{ printf "%q" .Field }}, probe.Type, []string{ {{- range $i, $value := mappingValues .Mapping }}{{ if $i }}, {{ end }}{{ printf "%q" $value }}{{ end -}} })
+	}
+	return nil
+{{ else }}	if err := checkJSONBody(req); err != nil {
+		return err
+	}
+{{ if .Schema }}	if err := validateSchema(req, {{ .Unmarshal }}Schema); err != nil {
+		return err
+	}
+{{ end }}	{{ if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
 	if err := service.DecodeRequest(req, payload); err != nil {
 		return err
 	}{{ $assignment := recursiveFinalizer .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
@@ -743,23 +2674,85 @@ func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Reque
 	}{{ end }}
 	goa.ContextRequest(ctx).Payload = payload{{ if .Payload.IsObject }}.Publicize(){{ end }}
 	return nil
-}
+{{ end }}{{ end }}}
 {{ end }}
 {{ end }}`
 
+	// mergePatchT generates the method that applies JSON merge patches to the payload types of
+	// the JSONMergePatch actions.
+	// template input: *design.UserTypeDefinition
+	mergePatchT = `{{ $typeName := gotypename . .AllRequired 0 false }}{{ $ref := gotyperef . .AllRequired 0 false }}
+// MergePatch returns a copy of ut with the changes of the JSON merge patch (RFC 7396) applied: the
+// attributes absent from patch keep their value and the attributes set to null are removed.
+func (ut {{ $ref }}) MergePatch(patch map[string]json.RawMessage) ({{ $ref }}, error) {
+	var res {{ $typeName }}
+	if err := goa.MergePatch(&res, ut, patch); err != nil {
+		return nil, goa.ErrBadRequest(err)
+	}{{ $validation := recursiveValidate .AttributeDefinition false false false "ut" "response" 1 false }}{{ if $validation }}
+	if err := res.Validate(); err != nil {
+		return nil, err
+	}{{ end }}
+	return &res, nil
+}
+`
+
 	// resourceT generates the code for a resource.
 	// template input: *ResourceData
-	resourceT = `{{ if .CanonicalTemplate }}// {{ .Name }}Href returns the resource href.
+	resourceT = `{{ if .OptionalSegments }}// {{ .Name }}Href returns the resource href. The optional segments of the path are only included
+// when opts provides values for all their parameters.
+func {{ .Name }}Href({{ $required := requiredParams . }}{{ if $required }}{{ join $required ", " }} interface{}, {{ end }}opts ...map[string]interface{}) string {
+	options := make(map[string]interface{})
+	for _, o := range opts {
+		for k, v := range o {
+			options[k] = v
+		}
+	}
+	var href string
+{{ range $seg := hrefSegments . }}{{ if $seg.Optional }}	if {{ range $i, $k := $seg.Keys }}{{ if $i }} && {{ end }}options[{{ printf "%q" $k }}] != nil{{ end }} {
+{{ range $i, $p := $seg.Params }}		param{{ $p }} := strings.TrimLeftFunc(fmt.Sprintf("%v", options[{{ printf "%q" (index $seg.Keys $i) }}]), func(r rune) bool { return r == '/' })
+{{ end }}		href += fmt.Sprintf("{{ $seg.Template }}", param{{ join $seg.Params ", param" }})
+	}
+{{ else if $seg.Params }}{{ range $p := $seg.Params }}	param{{ $p }} := strings.TrimLeftFunc(fmt.Sprintf("%v", {{ $p }}), func(r rune) bool { return r == '/' })
+{{ end }}	href += fmt.Sprintf("{{ $seg.Template }}", param{{ join $seg.Params ", param" }})
+{{ else }}	href += "{{ $seg.Template }}"
+{{ end }}{{ end }}	if href == "" {
+		return "/"
+	}
+	return href
+}
+{{ else if .CanonicalTemplate }}// {{ .Name }}Href returns the resource href.
 func {{ .Name }}Href({{ if .CanonicalParams }}{{ join .CanonicalParams ", " }} interface{}{{ end }}) string {
 {{ range $param := .CanonicalParams }}	param{{$param}} := strings.TrimLeftFunc(fmt.Sprintf("%v", {{$param}}), func(r rune) bool { return r == '/' })
 {{ end }}{{ if .CanonicalParams }}	return fmt.Sprintf("{{ .CanonicalTemplate }}", param{{ join .CanonicalParams ", param" }})
 {{ else }}	return "{{ .CanonicalTemplate }}"
 {{ end }}}
+{{ end }}`
+
+	// enumT generates the constants and validation helper of the type attributes that define an
+	// enum validation.
+	// template input: []map[string]interface{} as returned by enumAttributes
+	enumT = `{{ range $enum := . }}
+// {{ $enum.TypeName }} is the type of the values of the {{ $enum.Parent }} {{ $enum.Attribute }} attribute.
+type {{ $enum.TypeName }} = string
+
+// Values allowed for {{ $enum.TypeName }}.
+const (
+{{ range $enum.Values }}	{{ .Name }} {{ $enum.TypeName }} = {{ printf "%q" .Value }}
+{{ end }})
+
+// IsValid{{ $enum.TypeName }} returns true if v is one of the values allowed for {{ $enum.TypeName }}.
+func IsValid{{ $enum.TypeName }}(v {{ $enum.TypeName }}) bool {
+	switch v {
+	case {{ join $enum.Names ", " }}:
+		return true
+	}
+	return false
+}
 {{ end }}`
 
 	// mediaTypeT generates the code for a media type.
 	// template input: MediaTypeTemplateData
-	mediaTypeT = `// {{ gotypedesc . true }}
+	mediaTypeT = `{{ define "Enums" }}` + enumT + `{{ end }}` + `// {{ gotypedesc . true }}
 //
 // Identifier: {{ .Identifier }}{{ $typeName := gotypename . .AllRequired 0 false }}
 type {{ $typeName }} {{ gotypedef . 0 true false }}
@@ -769,7 +2762,7 @@ func (mt {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
 }
-{{ end }}
+{{ end }}{{ template "Enums" (enums $typeName .AttributeDefinition) }}
 `
 
 	// mediaTypeLinkT generates the code for a media type link.
@@ -781,17 +2774,82 @@ func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
 }{{ end }}
+`
+
+	// mergeJSONT generates the helper used by the media types with flattened links.
+	// template input: nil
+	mergeJSONT = `// mergeJSONObjects returns the JSON object holding the fields of the JSON objects a and b.
+func mergeJSONObjects(a, b []byte) []byte {
+	if len(b) <= 2 {
+		return a
+	}
+	if len(a) <= 2 {
+		return b
+	}
+	return append(append(a[:len(a)-1:len(a)-1], ','), b[1:]...)
+}
+
+`
+
+	// flattenLinksT generates the JSON marshaling methods of a media type with flattened links.
+	// template input: map[string]interface{} with keys "MediaType" and "Links"
+	flattenLinksT = `{{ $typeName := gotypename .MediaType .MediaType.AllRequired 0 false }}{{/*
+*/}}{{ $linksName := gotypename .Links .Links.AllRequired 0 false }}{{/*
+*/}}// MarshalJSON serializes the {{ $typeName }} media type, the link fields are written at the top
+// level of the JSON object instead of being nested under "links".
+func (mt *{{ $typeName }}) MarshalJSON() ([]byte, error) {
+	type noMethods {{ $typeName }}
+	v := noMethods(*mt)
+	links := v.Links
+	v.Links = nil
+	b, err := json.Marshal(&v)
+	if err != nil || links == nil {
+		return b, err
+	}
+	lb, err := json.Marshal(links)
+	if err != nil {
+		return nil, err
+	}
+	return mergeJSONObjects(b, lb), nil
+}
+
+// UnmarshalJSON deserializes a {{ $typeName }} media type whose link fields are at the top level
+// of the JSON object.
+func (mt *{{ $typeName }}) UnmarshalJSON(data []byte) error {
+	type noMethods {{ $typeName }}
+	var v noMethods
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var links {{ $linksName }}
+	if err := json.Unmarshal(data, &links); err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(links, {{ $linksName }}{}) {
+		v.Links = &links
+	}
+	*mt = {{ $typeName }}(v)
+	return nil
+}
+
 `
 
 	// userTypeT generates the code for a user type.
 	// template input: UserTypeTemplateData
-	userTypeT = `// {{ gotypedesc . false }}{{ $privateTypeName := gotypename . .AllRequired 0 true }}
+	userTypeT = `{{ define "Enums" }}` + enumT + `{{ end }}` + `// {{ gotypedesc . false }}{{ $privateTypeName := gotypename . .AllRequired 0 true }}
 type {{ $privateTypeName }} {{ gotypedef . 0 true true }}
-{{ $assignment := recursiveFinalizer .AttributeDefinition "ut" 1 }}{{ if $assignment }}// Finalize sets the default values for {{$privateTypeName}} type instance.
+{{ if requiredNullable .AttributeDefinition }}
+// UnmarshalJSON decodes the {{$privateTypeName}} type instance and records the members set to null.
+func (ut {{ gotyperef . .AllRequired 0 true }}) UnmarshalJSON(data []byte) (err error) {
+	type alias {{ $privateTypeName }}
+	ut.nulls, err = goa.DecodeJSONNulls(data, (*alias)(ut))
+	return
+}
+{{ end }}{{ $assignment := recursiveFinalizer .AttributeDefinition "ut" 1 }}{{ if $assignment }}// Finalize sets the default values for {{$privateTypeName}} type instance.
 func (ut {{ gotyperef . .AllRequired 0 true }}) Finalize() {
 {{ $assignment }}
 }{{ end }}
-{{ $validation := recursiveValidate .AttributeDefinition false false false "ut" "response" 1 true }}{{ if $validation }}// Validate validates the {{$privateTypeName}} type instance.
+{{ $validation := printf "%s%s" (nullChecker .AttributeDefinition "ut" "response") (recursiveValidate .AttributeDefinition false false false "ut" "response" 1 true) }}{{ if $validation }}// Validate validates the {{$privateTypeName}} type instance.
 func (ut {{ gotyperef . .AllRequired 0 true }}) Validate() (err error) {
 {{ $validation }}
 	return
@@ -810,7 +2868,29 @@ type {{ $typeName }} {{ gotypedef . 0 true false }}
 func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
-}{{ end }}
+}{{ end }}{{ if .SQLType }}
+// Scan implements the database/sql Scanner interface, it decodes the JSON read from {{ .SQLType }}
+// columns.
+func (ut *{{ $typeName }}) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into {{ $typeName }}", src)
+	}
+	return json.Unmarshal(data, ut)
+}
+
+// Value implements the database/sql/driver Valuer interface, it encodes the instance as JSON.
+func (ut {{ $typeName }}) Value() (driver.Value, error) {
+	return json.Marshal(ut)
+}
+{{ end }}{{ template "Enums" (enums $typeName .AttributeDefinition) }}
 `
 
 	// securitySchemesT generates the code for the security module.
@@ -868,5 +2948,42 @@ func handleSecurity(schemeName string, h goa.Handler, scopes ...string) goa.Hand
 		return am(h)(ctx, rw, req)
 	}
 }
+`
+
+	// jwtAuthT generates the JWT authentication middleware.
+	// template input: *design.APIDefinition
+	jwtAuthT = `{{ $keys := .ContextKeys.Type.ToObject }}
+// NewJWTMiddleware returns a middleware that validates the {{ .JWTAuth.Algorithm }} JSON Web Token sent in the
+// Authorization header with key and copies the token claims into the request context. The
+// middleware responds with 401 Unauthorized if the token is missing or invalid.
+func NewJWTMiddleware(key interface{}) goa.Middleware {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{ {{- printf "%q" .JWTAuth.Algorithm -}} }))
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+{{ if .JWTAuth.KeyID }}		if kid, _ := token.Header["kid"].(string); kid != {{ printf "%q" .JWTAuth.KeyID }} {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+{{ end }}		return key, nil
+	}
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			auth := req.Header.Get("Authorization")
+			if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+				return goa.ErrUnauthorized("missing bearer token")
+			}
+			claims := jwt.MapClaims{}
+			if _, err := parser.ParseWithClaims(auth[7:], claims, keyFunc); err != nil {
+				return goa.ErrUnauthorized(err)
+			}
+{{ range .JWTAuth.Claims }}{{ $att := index $keys . }}			if v, ok := claims[{{ printf "%q" . }}]; ok {
+				c, ok := v.({{ claimType $att.Type }})
+				if !ok {
+					return goa.ErrUnauthorized({{ printf "%q" (printf "invalid %s claim" .) }})
+				}
+{{ $type := gotyperef $att.Type nil 0 false }}				ctx = With{{ goify . true }}(ctx, {{ if eq $type (claimType $att.Type) }}c{{ else }}{{ $type }}(c){{ end }})
+			}
+{{ end }}			return h(ctx, rw, req)
+		}
+	}
+}
 `
 )
@@ -2,7 +2,11 @@ package genapp_test
 
 import (
 	"io/ioutil"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
@@ -85,6 +89,146 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(written).ShouldNot(BeEmpty())
 					Ω(written).Should(ContainSubstring(emptyContext))
 					Ω(written).Should(ContainSubstring(emptyContextFactory))
+					Ω(written).Should(ContainSubstring(requestIDContext))
+				})
+			})
+
+			Context("with a template overlay", func() {
+				var overlayDir string
+
+				BeforeEach(func() {
+					var err error
+					overlayDir, err = ioutil.TempDir("", "overlay")
+					Ω(err).ShouldNot(HaveOccurred())
+					err = ioutil.WriteFile(filepath.Join(overlayDir, "ctxT.tmpl"), []byte(overlayContextT), 0644)
+					Ω(err).ShouldNot(HaveOccurred())
+					codegen.GoGen.OverlayDir = overlayDir
+				})
+
+				AfterEach(func() {
+					codegen.GoGen.OverlayDir = ""
+					os.RemoveAll(overlayDir)
+				})
+
+				It("uses the user template instead of the built-in one", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(overlayContext))
+					Ω(written).ShouldNot(ContainSubstring(emptyContext))
+					Ω(written).Should(ContainSubstring(emptyContextFactory))
+				})
+			})
+
+			Context("with a param named request_id", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{"request_id": {Type: design.String}},
+					}
+				})
+
+				It("does not generate the RequestID method", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring("RequestID *string"))
+					Ω(written).ShouldNot(ContainSubstring("func (ctx *ListBottleContext) RequestID() string"))
+				})
+			})
+
+			Context("with a WebSocket action", func() {
+				It("writes the WebSocket context", func() {
+					data.WebSocket = true
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(webSocketContext))
+				})
+			})
+
+			Context("with a context key", func() {
+				It("writes the context key accessors and field", func() {
+					keys := &design.AttributeDefinition{
+						Type: design.Object{"userID": {Type: design.String}},
+					}
+					data.ContextKeys = keys
+					Ω(writer.WriteContextKeys(keys)).ShouldNot(HaveOccurred())
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(contextKeyAccessors))
+					Ω(written).Should(ContainSubstring(contextKeyContext))
+					Ω(written).Should(ContainSubstring(contextKeyContextFactory))
+				})
+			})
+
+			Context("with a union result", func() {
+				BeforeEach(func() {
+					mediaType := func(id, typeName string) *design.MediaTypeDefinition {
+						mt := &design.MediaTypeDefinition{
+							UserTypeDefinition: &design.UserTypeDefinition{
+								AttributeDefinition: &design.AttributeDefinition{
+									Type: design.Object{"foo": {Type: design.String}},
+								},
+								TypeName: typeName,
+							},
+							Identifier: id,
+						}
+						mt.Views = map[string]*design.ViewDefinition{"default": {
+							AttributeDefinition: mt.AttributeDefinition,
+							Name:                "default",
+							Parent:              mt,
+						}}
+						return mt
+					}
+					bottle := mediaType("application/vnd.goa.bottle", "GoaBottle")
+					box := mediaType("application/vnd.goa.box", "GoaBox")
+					design.Design = new(design.APIDefinition)
+					design.Design.MediaTypes = map[string]*design.MediaTypeDefinition{
+						design.CanonicalIdentifier(bottle.Identifier): bottle,
+						design.CanonicalIdentifier(box.Identifier):    box,
+					}
+					design.ProjectedMediaTypes = make(map[string]*design.MediaTypeDefinition)
+					responses = map[string]*design.ResponseDefinition{
+						"OK":       {Name: "OK", Status: 200, MediaType: bottle.Identifier},
+						"Accepted": {Name: "Accepted", Status: 202, MediaType: box.Identifier},
+						"NotFound": {Name: "NotFound", Status: 404},
+					}
+				})
+
+				It("writes the result interface and the Respond method", func() {
+					data.UnionResult = true
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(unionResult))
+				})
+			})
+
+			Context("with a long-poll action", func() {
+				It("writes the chunk streaming methods", func() {
+					data.LongPoll = true
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(longPollContext))
 				})
 			})
 
@@ -153,83 +297,123 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
-			Context("with a string param", func() {
+			Context("with a file param", func() {
 				BeforeEach(func() {
-					strParam := &design.AttributeDefinition{Type: design.String}
-					dataType := design.Object{
-						"param": strParam,
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"label": &design.AttributeDefinition{Type: design.FileType},
+							"name":  &design.AttributeDefinition{Type: design.String},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"label"}},
+					}
+				})
+
+				It("parses the multipart request body", func() {
+					Ω(data.HasFileParams()).Should(BeTrue())
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(fileParamContextFactory))
+				})
+			})
+
+			Context("with params restricted to several types", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"name":    &design.AttributeDefinition{Type: design.Any, Metadata: dslengine.MetadataDefinition{"anyof:types": {"string", "integer"}}},
+							"rating":  &design.AttributeDefinition{Type: design.Any, Metadata: dslengine.MetadataDefinition{"anyof:types": {"integer", "boolean"}}},
+							"vintage": &design.AttributeDefinition{Type: design.Any, Metadata: dslengine.MetadataDefinition{"anyof:types": {"integer", "string"}}},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"name", "rating", "vintage"}},
 					}
+				})
+
+				It("coerces the values to the first matching type", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(anyOfStringFirstCoerce))
+					Ω(written).Should(ContainSubstring(anyOfIntFirstCoerce))
+					Ω(written).Should(ContainSubstring(anyOfNoMatchCoerce))
+					Ω(written).Should(ContainSubstring(anyOfHelper))
+				})
+			})
+
+			Context("without file params", func() {
+				BeforeEach(func() {
 					params = &design.AttributeDefinition{
-						Type: dataType,
+						Type: design.Object{"name": &design.AttributeDefinition{Type: design.String}},
 					}
 				})
 
-				It("writes the contexts code", func() {
+				It("does not parse the multipart request body", func() {
+					Ω(data.HasFileParams()).Should(BeFalse())
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(strContext))
-					Ω(written).Should(ContainSubstring(strContextFactory))
+					Ω(written).Should(ContainSubstring(`paramName := req.Params["name"]`))
+					Ω(written).ShouldNot(ContainSubstring("ParseMultipartForm"))
 				})
 			})
 
-			Context("with a number param", func() {
+			Context("with an integer param with a default value", func() {
 				BeforeEach(func() {
-					numParam := &design.AttributeDefinition{Type: design.Number}
+					intParam := &design.AttributeDefinition{Type: design.Integer, DefaultValue: 1}
 					dataType := design.Object{
-						"param": numParam,
+						"param": intParam,
 					}
 					params = &design.AttributeDefinition{
 						Type: dataType,
 					}
 				})
 
-				It("writes the contexts code", func() {
+				It("writes the default value assignment", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(numContext))
-					Ω(written).Should(ContainSubstring(numContextFactory))
+					Ω(written).Should(ContainSubstring(intDefaultContext))
+					Ω(written).Should(ContainSubstring(intDefaultContextFactory))
 				})
 			})
 
-			Context("with a boolean param", func() {
+			Context("with an unsigned integer param", func() {
 				BeforeEach(func() {
-					boolParam := &design.AttributeDefinition{Type: design.Boolean}
+					uintParam := &design.AttributeDefinition{Type: design.Uint8Type}
 					dataType := design.Object{
-						"param": boolParam,
+						"param": uintParam,
 					}
 					params = &design.AttributeDefinition{
 						Type: dataType,
 					}
 				})
 
-				It("writes the contexts code", func() {
+				It("writes the unsigned integer contexts code", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(boolContext))
-					Ω(written).Should(ContainSubstring(boolContextFactory))
+					Ω(written).Should(ContainSubstring(uintContext))
+					Ω(written).Should(ContainSubstring(uintContextFactory))
 				})
 			})
 
-			Context("with an array param", func() {
+			Context("with a string param", func() {
 				BeforeEach(func() {
-					str := &design.AttributeDefinition{Type: design.String}
-					arrayParam := &design.AttributeDefinition{
-						Type: &design.Array{ElemType: str},
-					}
+					strParam := &design.AttributeDefinition{Type: design.String}
 					dataType := design.Object{
-						"param": arrayParam,
+						"param": strParam,
 					}
 					params = &design.AttributeDefinition{
 						Type: dataType,
@@ -243,42 +427,42 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(arrayContext))
-					Ω(written).Should(ContainSubstring(arrayContextFactory))
+					Ω(written).Should(ContainSubstring(strContext))
+					Ω(written).Should(ContainSubstring(strContextFactory))
 				})
 			})
 
-			Context("with an integer array param", func() {
+			Context("with a string enum param", func() {
 				BeforeEach(func() {
-					i := &design.AttributeDefinition{Type: design.Integer}
-					intArrayParam := &design.AttributeDefinition{
-						Type: &design.Array{ElemType: i},
+					strParam := &design.AttributeDefinition{
+						Type:       design.String,
+						Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "white"}},
 					}
 					dataType := design.Object{
-						"param": intArrayParam,
+						"param": strParam,
 					}
 					params = &design.AttributeDefinition{
 						Type: dataType,
 					}
 				})
 
-				It("writes the contexts code", func() {
+				It("checks the values with the enum validation helper", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(intArrayContext))
-					Ω(written).Should(ContainSubstring(intArrayContextFactory))
+					Ω(written).Should(ContainSubstring("func IsValidListBottleContextParam(v ListBottleContextParam) bool {"))
+					Ω(written).Should(ContainSubstring(strEnumCoerce))
+					Ω(written).ShouldNot(ContainSubstring("rctx.Param == \"red\""))
 				})
 			})
 
-			Context("with an param using a reserved keyword as name", func() {
+			Context("with a number param", func() {
 				BeforeEach(func() {
-					intParam := &design.AttributeDefinition{Type: design.Integer}
+					numParam := &design.AttributeDefinition{Type: design.Number}
 					dataType := design.Object{
-						"int": intParam,
+						"param": numParam,
 					}
 					params = &design.AttributeDefinition{
 						Type: dataType,
@@ -292,23 +476,19 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(resContext))
-					Ω(written).Should(ContainSubstring(resContextFactory))
+					Ω(written).Should(ContainSubstring(numContext))
+					Ω(written).Should(ContainSubstring(numContextFactory))
 				})
 			})
 
-			Context("with a required param", func() {
+			Context("with a boolean param", func() {
 				BeforeEach(func() {
-					intParam := &design.AttributeDefinition{Type: design.Integer}
+					boolParam := &design.AttributeDefinition{Type: design.Boolean}
 					dataType := design.Object{
-						"int": intParam,
-					}
-					required := &dslengine.ValidationDefinition{
-						Required: []string{"int"},
+						"param": boolParam,
 					}
 					params = &design.AttributeDefinition{
-						Type:       dataType,
-						Validation: required,
+						Type: dataType,
 					}
 				})
 
@@ -319,46 +499,60 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(requiredContext))
-					Ω(written).Should(ContainSubstring(requiredContextFactory))
+					Ω(written).Should(ContainSubstring(boolContext))
+					Ω(written).Should(ContainSubstring(boolContextFactory))
 				})
 			})
 
-			Context("with a custom name param", func() {
+			Context("with a datetime param", func() {
+				var metadata dslengine.MetadataDefinition
+
 				BeforeEach(func() {
-					intParam := &design.AttributeDefinition{
-						Type: design.Integer,
-						Metadata: dslengine.MetadataDefinition{
-							"struct:field:name": []string{"custom"},
-						},
-					}
-					dataType := design.Object{
-						"int": intParam,
-					}
-					params = &design.AttributeDefinition{
-						Type: dataType,
+					metadata = nil
+				})
+
+				JustBeforeEach(func() {
+					dateTimeParam := &design.AttributeDefinition{Type: design.DateTime, Metadata: metadata}
+					data.Params = &design.AttributeDefinition{
+						Type: design.Object{"param": dateTimeParam},
 					}
 				})
 
-				It("writes the contexts code", func() {
+				It("normalizes the value to UTC", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(customContext))
-					Ω(written).Should(ContainSubstring(customContextFactory))
+					Ω(written).Should(ContainSubstring(dateTimeContextFactory))
+				})
+
+				Context("using the local time zone", func() {
+					BeforeEach(func() {
+						metadata = dslengine.MetadataDefinition{"datetime:tz": {"local"}}
+					})
+
+					It("normalizes the value to the local time zone", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring("param = param.Local()"))
+						Ω(written).ShouldNot(ContainSubstring("param = param.UTC()"))
+					})
 				})
 			})
 
-			Context("with a string header", func() {
+			Context("with an IP param", func() {
 				BeforeEach(func() {
-					strHeader := &design.AttributeDefinition{Type: design.String}
+					ipParam := &design.AttributeDefinition{Type: design.IP}
 					dataType := design.Object{
-						"Header": strHeader,
+						"param": ipParam,
 					}
-					headers = &design.AttributeDefinition{
+					params = &design.AttributeDefinition{
 						Type: dataType,
 					}
 				})
@@ -370,22 +564,20 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(strHeaderContext))
-					Ω(written).Should(ContainSubstring(strHeaderContextFactory))
+					Ω(written).Should(ContainSubstring(ipContext))
+					Ω(written).Should(ContainSubstring(ipContextFactory))
 				})
 			})
 
-			Context("with a string header and param with the same name", func() {
+			Context("with a required LatLon param", func() {
 				BeforeEach(func() {
-					str := &design.AttributeDefinition{Type: design.String}
+					latLonParam := &design.AttributeDefinition{Type: design.LatLon}
 					dataType := design.Object{
-						"param": str,
+						"param": latLonParam,
 					}
 					params = &design.AttributeDefinition{
-						Type: dataType,
-					}
-					headers = &design.AttributeDefinition{
-						Type: dataType,
+						Type:       dataType,
+						Validation: &dslengine.ValidationDefinition{Required: []string{"param"}},
 					}
 				})
 
@@ -396,17 +588,20 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(strContext))
-					Ω(written).Should(ContainSubstring(strHeaderParamContextFactory))
+					Ω(written).Should(ContainSubstring(latLonContext))
+					Ω(written).Should(ContainSubstring(latLonContextFactory))
 				})
 			})
 
-			Context("with a simple payload", func() {
+			Context("with a required SemVer param", func() {
 				BeforeEach(func() {
-					design.Design = new(design.APIDefinition)
-					payload = &design.UserTypeDefinition{
-						AttributeDefinition: &design.AttributeDefinition{Type: design.String},
-						TypeName:            "ListBottlePayload",
+					semVerParam := &design.AttributeDefinition{Type: design.SemVer}
+					dataType := design.Object{
+						"param": semVerParam,
+					}
+					params = &design.AttributeDefinition{
+						Type:       dataType,
+						Validation: &dslengine.ValidationDefinition{Required: []string{"param"}},
 					}
 				})
 
@@ -417,29 +612,20 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(payloadContext))
-					Ω(written).Should(ContainSubstring(payloadContextFactory))
+					Ω(written).Should(ContainSubstring(semVerContext))
+					Ω(written).Should(ContainSubstring(semVerContextFactory))
 				})
 			})
 
-			Context("with a object payload", func() {
+			Context("with a required BigInt param", func() {
 				BeforeEach(func() {
-					design.Design = new(design.APIDefinition)
-					intParam := &design.AttributeDefinition{Type: design.Integer}
-					strParam := &design.AttributeDefinition{Type: design.String}
+					bigIntParam := &design.AttributeDefinition{Type: design.BigIntType}
 					dataType := design.Object{
-						"int": intParam,
-						"str": strParam,
-					}
-					required := &dslengine.ValidationDefinition{
-						Required: []string{"int"},
+						"param": bigIntParam,
 					}
-					payload = &design.UserTypeDefinition{
-						AttributeDefinition: &design.AttributeDefinition{
-							Type:       dataType,
-							Validation: required,
-						},
-						TypeName: "ListBottlePayload",
+					params = &design.AttributeDefinition{
+						Type:       dataType,
+						Validation: &dslengine.ValidationDefinition{Required: []string{"param"}},
 					}
 				})
 
@@ -450,540 +636,397 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(payloadObjContext))
+					Ω(written).Should(ContainSubstring(bigIntContext))
+					Ω(written).Should(ContainSubstring(bigIntContextFactory))
 				})
+			})
 
-				var _ = Describe("IterateResponses", func() {
-					var resps []*design.ResponseDefinition
-					var testIt = func(r *design.ResponseDefinition) error {
-						resps = append(resps, r)
-						return nil
+			Context("with a required CIDR param", func() {
+				BeforeEach(func() {
+					cidrParam := &design.AttributeDefinition{Type: design.CIDR}
+					dataType := design.Object{
+						"param": cidrParam,
+					}
+					params = &design.AttributeDefinition{
+						Type:       dataType,
+						Validation: &dslengine.ValidationDefinition{Required: []string{"param"}},
 					}
-					Context("with responses", func() {
-						BeforeEach(func() {
-							responses = map[string]*design.ResponseDefinition{
-								"OK":      {Status: 200},
-								"Created": {Status: 201},
-							}
-						})
-						It("iterates responses in order", func() {
-							data.IterateResponses(testIt)
-							Ω(resps).Should(Equal([]*design.ResponseDefinition{
-								responses["OK"],
-								responses["Created"],
-							}))
-						})
-					})
 				})
-			})
-		})
-	})
-})
-
-var _ = Describe("ControllersWriter", func() {
-	var writer *genapp.ControllersWriter
-	var workspace *codegen.Workspace
-	var filename string
-
-	BeforeEach(func() {
-		var err error
-		workspace, err = codegen.NewWorkspace("test")
-		Ω(err).ShouldNot(HaveOccurred())
-		pkg, err := workspace.NewPackage("controllers")
-		Ω(err).ShouldNot(HaveOccurred())
-		src := pkg.CreateSourceFile("test.go")
-		filename = src.Abs()
-	})
-
-	JustBeforeEach(func() {
-		var err error
-		writer, err = genapp.NewControllersWriter(filename)
-		Ω(err).ShouldNot(HaveOccurred())
-	})
-
-	AfterEach(func() {
-		workspace.Delete()
-	})
-
-	Context("correctly configured", func() {
-		BeforeEach(func() {
-			os.Create(filename)
-		})
-
-		Context("with file servers", func() {
-			requestPath := "/swagger.json"
-			filePath := "swagger/swagger.json"
-			var origins []*design.CORSDefinition
-			var preflightPaths []string
-
-			var data []*genapp.ControllerTemplateData
-
-			BeforeEach(func() {
-				origins = nil
-				preflightPaths = nil
-			})
-
-			JustBeforeEach(func() {
-				codegen.TempCount = 0
-				fileServer := &design.FileServerDefinition{
-					FilePath:    filePath,
-					RequestPath: requestPath,
-				}
-				d := &genapp.ControllerTemplateData{
-					API:            &design.APIDefinition{},
-					Origins:        origins,
-					PreflightPaths: preflightPaths,
-					Resource:       "Public",
-					FileServers:    []*design.FileServerDefinition{fileServer},
-				}
-				data = []*genapp.ControllerTemplateData{d}
-			})
 
-			It("writes the file server code", func() {
-				err := writer.Execute(data)
-				Ω(err).ShouldNot(HaveOccurred())
-				b, err := ioutil.ReadFile(filename)
-				Ω(err).ShouldNot(HaveOccurred())
-				written := string(b)
-				Ω(written).ShouldNot(BeEmpty())
-				Ω(written).Should(ContainSubstring(simpleFileServer))
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(cidrContext))
+					Ω(written).Should(ContainSubstring(cidrContextFactory))
+				})
 			})
 
-			Context("with CORS", func() {
+			Context("with an array param", func() {
 				BeforeEach(func() {
-					origins = []*design.CORSDefinition{
-						{
-							Origin:      "here.example.com",
-							Headers:     []string{"X-One", "X-Two"},
-							Methods:     []string{"GET", "POST"},
-							Exposed:     []string{"X-Three"},
-							Credentials: true,
-						},
+					str := &design.AttributeDefinition{Type: design.String}
+					arrayParam := &design.AttributeDefinition{
+						Type: &design.Array{ElemType: str},
+					}
+					dataType := design.Object{
+						"param": arrayParam,
+					}
+					params = &design.AttributeDefinition{
+						Type: dataType,
 					}
-					preflightPaths = []string{"/public/*filepath"}
 				})
 
-				It("writes the OPTIONS handler code", func() {
+				It("writes the contexts code", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(fileServerOptionsHandler))
+					Ω(written).Should(ContainSubstring(arrayContext))
+					Ω(written).Should(ContainSubstring(arrayContextFactory))
 				})
 			})
-		})
-
-		Context("with data", func() {
-			var actions, verbs, paths, contexts, unmarshals []string
-			var payloads []*design.UserTypeDefinition
-			var encoders, decoders []*genapp.EncoderTemplateData
-			var origins []*design.CORSDefinition
-
-			var data []*genapp.ControllerTemplateData
-
-			BeforeEach(func() {
-				actions = nil
-				verbs = nil
-				paths = nil
-				contexts = nil
-				unmarshals = nil
-				payloads = nil
-				encoders = nil
-				decoders = nil
-				origins = nil
-			})
 
-			JustBeforeEach(func() {
-				codegen.TempCount = 0
-				api := &design.APIDefinition{}
-				d := &genapp.ControllerTemplateData{
-					Resource: "Bottles",
-					Origins:  origins,
-				}
-				as := make([]map[string]interface{}, len(actions))
-				for i, a := range actions {
-					var unmarshal string
-					var payload *design.UserTypeDefinition
-					if i < len(unmarshals) {
-						unmarshal = unmarshals[i]
+			Context("with an integer array param", func() {
+				BeforeEach(func() {
+					i := &design.AttributeDefinition{Type: design.Integer}
+					intArrayParam := &design.AttributeDefinition{
+						Type: &design.Array{ElemType: i},
 					}
-					if i < len(payloads) {
-						payload = payloads[i]
+					dataType := design.Object{
+						"param": intArrayParam,
 					}
-					as[i] = map[string]interface{}{
-						"Name": a,
-						"Routes": []*design.RouteDefinition{
-							{
-								Verb: verbs[i],
-								Path: paths[i],
-							}},
-						"Context":   contexts[i],
-						"Unmarshal": unmarshal,
-						"Payload":   payload,
+					params = &design.AttributeDefinition{
+						Type: dataType,
 					}
-				}
-				if len(as) > 0 {
-					d.API = api
-					d.Actions = as
-					d.Encoders = encoders
-					d.Decoders = decoders
-					data = []*genapp.ControllerTemplateData{d}
-				} else {
-					data = nil
-				}
-			})
+				})
 
-			Context("with missing data", func() {
-				It("returns an empty string", func() {
+				It("writes the contexts code", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).Should(BeEmpty())
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(intArrayContext))
+					Ω(written).Should(ContainSubstring(intArrayContextFactory))
 				})
 			})
 
-			Context("with a simple controller", func() {
+			Context("with an param using a reserved keyword as name", func() {
 				BeforeEach(func() {
-					actions = []string{"List"}
-					verbs = []string{"GET"}
-					paths = []string{"/accounts/:accountID/bottles"}
-					contexts = []string{"ListBottleContext"}
+					intParam := &design.AttributeDefinition{Type: design.Integer}
+					dataType := design.Object{
+						"int": intParam,
+					}
+					params = &design.AttributeDefinition{
+						Type: dataType,
+					}
 				})
 
-				It("writes the controller code", func() {
+				It("writes the contexts code", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(simpleController))
-					Ω(written).Should(ContainSubstring(simpleMount))
+					Ω(written).Should(ContainSubstring(resContext))
+					Ω(written).Should(ContainSubstring(resContextFactory))
 				})
 			})
 
-			Context("with actions that take a payload", func() {
+			Context("with a required param", func() {
 				BeforeEach(func() {
-					actions = []string{"List"}
-					verbs = []string{"GET"}
-					paths = []string{"/accounts/:accountID/bottles"}
-					contexts = []string{"ListBottleContext"}
-					unmarshals = []string{"unmarshalListBottlePayload"}
-					payloads = []*design.UserTypeDefinition{
-						{
-							TypeName: "ListBottlePayload",
-							AttributeDefinition: &design.AttributeDefinition{
-								Type: design.Object{
-									"id": &design.AttributeDefinition{
-										Type: design.String,
-									},
-								},
-							},
-						},
+					intParam := &design.AttributeDefinition{Type: design.Integer}
+					dataType := design.Object{
+						"int": intParam,
+					}
+					required := &dslengine.ValidationDefinition{
+						Required: []string{"int"},
+					}
+					params = &design.AttributeDefinition{
+						Type:       dataType,
+						Validation: required,
 					}
 				})
 
-				It("writes the payload unmarshal function", func() {
+				It("writes the contexts code", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).Should(ContainSubstring(payloadNoValidationsObjUnmarshal))
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(requiredContext))
+					Ω(written).Should(ContainSubstring(requiredContextFactory))
 				})
 			})
-			Context("with actions that take a payload with a required validation", func() {
+
+			Context("with a custom name param", func() {
 				BeforeEach(func() {
-					actions = []string{"List"}
-					required := &dslengine.ValidationDefinition{
-						Required: []string{"id"},
-					}
-					verbs = []string{"GET"}
-					paths = []string{"/accounts/:accountID/bottles"}
-					contexts = []string{"ListBottleContext"}
-					unmarshals = []string{"unmarshalListBottlePayload"}
-					payloads = []*design.UserTypeDefinition{
-						{
-							TypeName: "ListBottlePayload",
-							AttributeDefinition: &design.AttributeDefinition{
-								Type: design.Object{
-									"id": &design.AttributeDefinition{
-										Type: design.String,
-									},
-								},
-								Validation: required,
-							},
+					intParam := &design.AttributeDefinition{
+						Type: design.Integer,
+						Metadata: dslengine.MetadataDefinition{
+							"struct:field:name": []string{"custom"},
 						},
 					}
+					dataType := design.Object{
+						"int": intParam,
+					}
+					params = &design.AttributeDefinition{
+						Type: dataType,
+					}
 				})
 
-				It("writes the payload unmarshal function", func() {
+				It("writes the contexts code", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).Should(ContainSubstring(payloadObjUnmarshal))
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(customContext))
+					Ω(written).Should(ContainSubstring(customContextFactory))
 				})
 			})
 
-			Context("with multiple controllers", func() {
+			Context("with a string header", func() {
 				BeforeEach(func() {
-					actions = []string{"List", "Show"}
-					verbs = []string{"GET", "GET"}
-					paths = []string{"/accounts/:accountID/bottles", "/accounts/:accountID/bottles/:id"}
-					contexts = []string{"ListBottleContext", "ShowBottleContext"}
+					strHeader := &design.AttributeDefinition{Type: design.String}
+					dataType := design.Object{
+						"Header": strHeader,
+					}
+					headers = &design.AttributeDefinition{
+						Type: dataType,
+					}
 				})
 
-				It("writes the controllers code", func() {
+				It("writes the contexts code", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(multiController))
-					Ω(written).Should(ContainSubstring(multiMount))
+					Ω(written).Should(ContainSubstring(strHeaderContext))
+					Ω(written).Should(ContainSubstring(strHeaderContextFactory))
 				})
 			})
 
-			Context("with encoder and decoder maps", func() {
+			Context("with a string header and param with the same name", func() {
 				BeforeEach(func() {
-					actions = []string{"List"}
-					verbs = []string{"GET"}
-					paths = []string{"/accounts/:accountID/bottles"}
-					contexts = []string{"ListBottleContext"}
-					encoders = []*genapp.EncoderTemplateData{
-						{
-							PackageName: "goa",
-							Function:    "NewEncoder",
-							MIMETypes:   []string{"application/json"},
-						},
+					str := &design.AttributeDefinition{Type: design.String}
+					dataType := design.Object{
+						"param": str,
 					}
-					decoders = []*genapp.EncoderTemplateData{
-						{
-							PackageName: "goa",
-							Function:    "NewDecoder",
-							MIMETypes:   []string{"application/json"},
-						},
+					params = &design.AttributeDefinition{
+						Type: dataType,
+					}
+					headers = &design.AttributeDefinition{
+						Type: dataType,
 					}
 				})
 
-				It("writes the controllers code", func() {
+				It("writes the contexts code", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(encoderController))
+					Ω(written).Should(ContainSubstring(strContext))
+					Ω(written).Should(ContainSubstring(strHeaderParamContextFactory))
 				})
 			})
 
-			Context("with multiple origins", func() {
+			Context("with a multipart response", func() {
 				BeforeEach(func() {
-					actions = []string{"List"}
-					verbs = []string{"GET"}
-					paths = []string{"/accounts"}
-					contexts = []string{"ListBottleContext"}
-					origins = []*design.CORSDefinition{
-						{
-							Origin:      "here.example.com",
-							Headers:     []string{"X-One", "X-Two"},
-							Methods:     []string{"GET", "POST"},
-							Exposed:     []string{"X-Three"},
-							Credentials: true,
-						},
-						{
-							Origin:  "there.example.com",
-							Headers: []string{"*"},
-							Methods: []string{"*"},
+					design.Design = new(design.APIDefinition)
+					responses = map[string]*design.ResponseDefinition{"OK": {
+						Name:   "OK",
+						Status: 200,
+						Parts: []*design.PartDefinition{
+							{Name: "report", ContentType: "application/json", Type: design.ErrorMedia},
+							{Name: "chart", ContentType: "image/png", Type: design.BinaryType},
 						},
-					}
-
+					}}
 				})
 
-				It("writes the controller code", func() {
+				It("writes the multipart response helper", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(originsIntegration))
-					Ω(written).Should(ContainSubstring(originsHandler))
+					Ω(written).Should(ContainSubstring(multipartResp))
 				})
 			})
 
-			Context("with regexp origins", func() {
+			Context("with a response with a retry delay", func() {
 				BeforeEach(func() {
-					actions = []string{"List"}
-					verbs = []string{"GET"}
-					paths = []string{"/accounts"}
-					contexts = []string{"ListBottleContext"}
-					origins = []*design.CORSDefinition{
-						{
-							Origin:      "[here|there].example.com",
-							Headers:     []string{"X-One", "X-Two"},
-							Methods:     []string{"GET", "POST"},
-							Exposed:     []string{"X-Three"},
-							Credentials: true,
-							Regexp:      true,
-						},
-						{
-							Origin:  "there.example.com",
-							Headers: []string{"*"},
-							Methods: []string{"*"},
-						},
-					}
-
+					design.Design = new(design.APIDefinition)
+					responses = map[string]*design.ResponseDefinition{"ServiceUnavailable": {
+						Name:       "ServiceUnavailable",
+						Status:     503,
+						RetryAfter: 30 * time.Second,
+					}}
 				})
 
-				It("writes the controller code", func() {
+				It("writes the Retry-After header", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(originsIntegration))
-					Ω(written).Should(ContainSubstring(regexpOriginsHandler))
+					Ω(written).Should(ContainSubstring(retryAfterResp))
 				})
 			})
 
-		})
-	})
-})
-
-var _ = Describe("HrefWriter", func() {
-	var writer *genapp.ResourcesWriter
-	var workspace *codegen.Workspace
-	var filename string
-
-	BeforeEach(func() {
-		var err error
-		workspace, err = codegen.NewWorkspace("test")
-		Ω(err).ShouldNot(HaveOccurred())
-		pkg, err := workspace.NewPackage("controllers")
-		Ω(err).ShouldNot(HaveOccurred())
-		src := pkg.CreateSourceFile("test.go")
-		filename = src.Abs()
-	})
-
-	JustBeforeEach(func() {
-		var err error
-		writer, err = genapp.NewResourcesWriter(filename)
-		Ω(err).ShouldNot(HaveOccurred())
-	})
-
-	AfterEach(func() {
-		workspace.Delete()
-	})
-
-	Context("correctly configured", func() {
-		Context("with data", func() {
-			var canoTemplate string
-			var canoParams []string
-			var mediaType *design.MediaTypeDefinition
-
-			var data *genapp.ResourceData
-
-			BeforeEach(func() {
-				mediaType = nil
-				canoTemplate = ""
-				canoParams = nil
-				data = nil
-			})
-
-			JustBeforeEach(func() {
-				data = &genapp.ResourceData{
-					Name:              "Bottle",
-					Identifier:        "vnd.acme.com/resources",
-					Description:       "A bottle resource",
-					Type:              mediaType,
-					CanonicalTemplate: canoTemplate,
-					CanonicalParams:   canoParams,
-				}
-			})
+			Context("with a response with cache directives", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					responses = map[string]*design.ResponseDefinition{
+						"OK": {
+							Name:         "OK",
+							Status:       200,
+							MediaType:    "text/plain",
+							CacheControl: []string{"max-age=300", "public"},
+						},
+						"NotFound": {Name: "NotFound", Status: 404},
+					}
+				})
 
-			Context("with missing resource type definition", func() {
-				It("does not return an error", func() {
+				It("writes the Cache-Control header of the annotated response only", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(cacheControlResp))
+					Ω(written).Should(ContainSubstring(notFoundResp))
 				})
 			})
 
-			Context("with a string resource", func() {
+			Context("with a response with fields visible to specific roles", func() {
 				BeforeEach(func() {
-					attDef := &design.AttributeDefinition{
-						Type: design.String,
+					notes := &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{"visible:roles": {"admin", "support"}},
 					}
-					mediaType = &design.MediaTypeDefinition{
+					mediaType := &design.MediaTypeDefinition{
 						UserTypeDefinition: &design.UserTypeDefinition{
-							AttributeDefinition: attDef,
-							TypeName:            "Bottle",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{"name": {Type: design.String}, "internal_notes": notes},
+							},
+							TypeName: "Bottle",
 						},
+						Identifier: "application/vnd.goa.example.bottle+json",
 					}
+					mediaType.Views = map[string]*design.ViewDefinition{"default": {
+						AttributeDefinition: mediaType.AttributeDefinition,
+						Name:                "default",
+						Parent:              mediaType,
+					}}
+					design.Design = new(design.APIDefinition)
+					design.ProjectedMediaTypes = make(map[string]*design.MediaTypeDefinition)
+					responses = map[string]*design.ResponseDefinition{"OK": {
+						Name:   "OK",
+						Status: 200,
+						Type:   mediaType,
+					}}
 				})
-				It("does not return an error", func() {
+
+				It("writes the filtered response helper", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(filteredResp))
 				})
 			})
 
-			Context("with a user type resource", func() {
+			Context("with a simple payload", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					payload = &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+						TypeName:            "ListBottlePayload",
+					}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(payloadContext))
+					Ω(written).Should(ContainSubstring(payloadContextFactory))
+				})
+			})
+
+			Context("with a object payload", func() {
 				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
 					intParam := &design.AttributeDefinition{Type: design.Integer}
 					strParam := &design.AttributeDefinition{Type: design.String}
 					dataType := design.Object{
 						"int": intParam,
 						"str": strParam,
 					}
-					attDef := &design.AttributeDefinition{
-						Type: dataType,
+					required := &dslengine.ValidationDefinition{
+						Required: []string{"int"},
 					}
-					mediaType = &design.MediaTypeDefinition{
-						UserTypeDefinition: &design.UserTypeDefinition{
-							AttributeDefinition: attDef,
-							TypeName:            "Bottle",
+					payload = &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type:       dataType,
+							Validation: required,
 						},
+						TypeName: "ListBottlePayload",
 					}
 				})
 
-				Context("and a canonical action", func() {
-					BeforeEach(func() {
-						canoTemplate = "/bottles/%v"
-						canoParams = []string{"id"}
-					})
-
-					It("writes the href method", func() {
-						err := writer.Execute(data)
-						Ω(err).ShouldNot(HaveOccurred())
-						b, err := ioutil.ReadFile(filename)
-						Ω(err).ShouldNot(HaveOccurred())
-						written := string(b)
-						Ω(written).ShouldNot(BeEmpty())
-						Ω(written).Should(ContainSubstring(simpleResourceHref))
-					})
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(payloadObjContext))
 				})
 
-				Context("and a canonical action with no param", func() {
-					BeforeEach(func() {
-						canoTemplate = "/bottles"
-					})
-
-					It("writes the href method", func() {
-						err := writer.Execute(data)
-						Ω(err).ShouldNot(HaveOccurred())
-						b, err := ioutil.ReadFile(filename)
-						Ω(err).ShouldNot(HaveOccurred())
-						written := string(b)
-						Ω(written).ShouldNot(BeEmpty())
-						Ω(written).Should(ContainSubstring(noParamHref))
+				var _ = Describe("IterateResponses", func() {
+					var resps []*design.ResponseDefinition
+					var testIt = func(r *design.ResponseDefinition) error {
+						resps = append(resps, r)
+						return nil
+					}
+					Context("with responses", func() {
+						BeforeEach(func() {
+							responses = map[string]*design.ResponseDefinition{
+								"OK":      {Status: 200},
+								"Created": {Status: 201},
+							}
+						})
+						It("iterates responses in order", func() {
+							data.IterateResponses(testIt)
+							Ω(resps).Should(Equal([]*design.ResponseDefinition{
+								responses["OK"],
+								responses["Created"],
+							}))
+						})
 					})
 				})
 			})
@@ -991,38 +1034,1983 @@ var _ = Describe("HrefWriter", func() {
 	})
 })
 
-const (
-	emptyContext = `
+var _ = Describe("ContextTemplateData", func() {
+	var data *genapp.ContextTemplateData
+
+	BeforeEach(func() {
+		route := &design.RouteDefinition{Verb: "GET", Path: "/accounts/:accountID/bottles/:id"}
+		data = &genapp.ContextTemplateData{
+			Name:         "ShowBottleContext",
+			ResourceName: "bottles",
+			ActionName:   "show",
+			Params: &design.AttributeDefinition{
+				Type: design.Object{
+					"accountID": {Type: design.Integer},
+					"id":        {Type: design.Integer},
+					"fields":    {Type: design.String},
+					"view":      {Type: design.String},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"view"}},
+			},
+			Headers: &design.AttributeDefinition{
+				Type: design.Object{"X-Trace": {Type: design.String}},
+			},
+			Routes: []*design.RouteDefinition{route},
+		}
+	})
+
+	It("classifies the path parameters", func() {
+		params := data.AllPathParams()
+		Ω(params).Should(HaveLen(2))
+		Ω(params[0].Name).Should(Equal("accountID"))
+		Ω(params[1].Name).Should(Equal("id"))
+		Ω(params[1].Type).Should(Equal(design.Integer))
+		Ω(params[1].Required).Should(BeTrue())
+		Ω(params[1].Attribute).Should(Equal(data.Params.Type.ToObject()["id"]))
+	})
+
+	It("classifies the query parameters", func() {
+		params := data.AllQueryParams()
+		Ω(params).Should(HaveLen(2))
+		Ω(params[0].Name).Should(Equal("fields"))
+		Ω(params[0].Required).Should(BeFalse())
+		Ω(params[1].Name).Should(Equal("view"))
+		Ω(params[1].Type).Should(Equal(design.String))
+		Ω(params[1].Required).Should(BeTrue())
+	})
+
+	Context("with no parameter", func() {
+		BeforeEach(func() {
+			data.Params = nil
+		})
+
+		It("returns no parameter", func() {
+			Ω(data.AllPathParams()).Should(BeEmpty())
+			Ω(data.AllQueryParams()).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("ControllersWriter", func() {
+	var writer *genapp.ControllersWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("controllers")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewControllersWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("correctly configured", func() {
+		BeforeEach(func() {
+			os.Create(filename)
+		})
+
+		Context("with the stdlib router", func() {
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				codegen.TempCount = 0
+				data = []*genapp.ControllerTemplateData{{
+					API:      &design.APIDefinition{},
+					Resource: "Bottles",
+					Router:   "stdlib",
+					Actions: []map[string]interface{}{{
+						"Name": "Show",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "GET", Path: "/accounts/:accountID/bottles/:id"},
+						},
+						"Context": "ShowBottleContext",
+					}},
+					FileServers: []*design.FileServerDefinition{
+						{FilePath: "/www/public", RequestPath: "/public/*filepath"},
+					},
+				}}
+			})
+
+			It("writes the code matching the golden file", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				golden, err := ioutil.ReadFile(filepath.Join("testdata", "stdlib_mount.golden"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).Should(Equal(string(golden)))
+			})
+		})
+
+		Context("with the chi router", func() {
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				codegen.TempCount = 0
+				data = []*genapp.ControllerTemplateData{{
+					API:      &design.APIDefinition{},
+					Resource: "Bottles",
+					Router:   "chi",
+					Actions: []map[string]interface{}{{
+						"Name": "Show",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "GET", Path: "/accounts/:accountID/bottles/:id"},
+						},
+						"Context": "ShowBottleContext",
+					}},
+					FileServers: []*design.FileServerDefinition{
+						{FilePath: "/www/public", RequestPath: "/public/*filepath"},
+					},
+				}}
+			})
+
+			It("writes the code matching the golden file", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				golden, err := ioutil.ReadFile(filepath.Join("testdata", "chi_mount.golden"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).Should(Equal(string(golden)))
+			})
+		})
+
+		Context("with an audited action", func() {
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				data = []*genapp.ControllerTemplateData{{
+					API:      &design.APIDefinition{},
+					Resource: "Bottles",
+					Actions: []map[string]interface{}{{
+						"Name": "Update",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "PUT", Path: "/bottles/:id"},
+						},
+						"Context":   "UpdateBottleContext",
+						"Audit":     &design.AuditDefinition{Actor: "X-User-ID", Resource: "id"},
+						"AuditName": "AuditUpdateBottle",
+					}},
+				}}
+			})
+
+			It("records the audit event after the action", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring(auditMount))
+			})
+		})
+
+		Context("with a union result action", func() {
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				data = []*genapp.ControllerTemplateData{{
+					API:      &design.APIDefinition{},
+					Resource: "Bottles",
+					Actions: []map[string]interface{}{{
+						"Name": "Show",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "GET", Path: "/bottles/:id"},
+						},
+						"Context": "ShowBottleContext",
+						"Result":  "ShowBottleResult",
+					}},
+				}}
+			})
+
+			It("returns the result from the controller and sends the matching response", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring("Show(*ShowBottleContext) (ShowBottleResult, error)"))
+				Ω(written).Should(ContainSubstring(unionResultMount))
+			})
+		})
+
+		Context("with a PATCH action", func() {
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				data = []*genapp.ControllerTemplateData{{
+					API:      &design.APIDefinition{},
+					Resource: "Bottles",
+					Actions: []map[string]interface{}{{
+						"Name": "Show",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "GET", Path: "/bottles/:id"},
+						},
+						"Context": "ShowBottleContext",
+					}, {
+						"Name": "Update",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "PATCH", Path: "/bottles/:id"},
+						},
+						"Context":     "UpdateBottleContext",
+						"AcceptPatch": []string{"application/merge-patch+json"},
+					}},
+				}}
+			})
+
+			It("rejects the methods not allowed on the PATCH route path", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring("func handleMethodNotAllowed(allow, acceptPatch string) goa.Handler {"))
+				Ω(written).Should(ContainSubstring(methodNotAllowedMount))
+			})
+		})
+
+		Context("with static assets", func() {
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				data = []*genapp.ControllerTemplateData{{
+					API:      &design.APIDefinition{},
+					Resource: "UI",
+					StaticAssets: []*design.StaticAssetsDefinition{{
+						RequestPath:  "/ui",
+						FilePath:     "public",
+						CacheControl: "no-cache",
+					}},
+				}}
+			})
+
+			It("mounts the http.FileServer handler", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring("http.StripPrefix(prefix, http.FileServer(http.Dir(dir)))"))
+				Ω(written).Should(ContainSubstring(staticAssetsMount))
+			})
+		})
+
+		Context("with API resources added in random order", func() {
+			var data []*genapp.ControllerTemplateData
+
+			// newAPI returns an API whose resources are inserted in a random order.
+			newAPI := func() *design.APIDefinition {
+				resources := []*design.ResourceDefinition{
+					{Name: "accounts", Actions: map[string]*design.ActionDefinition{
+						"show": {Name: "show", Routes: []*design.RouteDefinition{{Verb: "HEAD", Path: "/bottles/:id"}}},
+					}},
+					{Name: "bottles", Actions: map[string]*design.ActionDefinition{
+						"update": {Name: "update", Routes: []*design.RouteDefinition{{Verb: "PATCH", Path: "/bottles/:id"}}},
+					}},
+					{Name: "cellars", Actions: map[string]*design.ActionDefinition{
+						"delete": {Name: "delete", Routes: []*design.RouteDefinition{{Verb: "DELETE", Path: "/bottles/:id"}}},
+					}},
+					{Name: "wineries", FileServers: []*design.FileServerDefinition{{RequestPath: "/bottles/:id"}}},
+				}
+				api := &design.APIDefinition{Resources: make(map[string]*design.ResourceDefinition)}
+				for _, i := range rand.Perm(len(resources)) {
+					api.Resources[resources[i].Name] = resources[i]
+				}
+				return api
+			}
+
+			BeforeEach(func() {
+				data = []*genapp.ControllerTemplateData{{
+					Resource: "Bottles",
+					Actions: []map[string]interface{}{{
+						"Name": "Update",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "PATCH", Path: "/bottles/:id"},
+						},
+						"Context":     "UpdateBottleContext",
+						"AcceptPatch": []string{"application/merge-patch+json"},
+					}},
+				}}
+			})
+
+			It("generates the same code on each run", func() {
+				var first string
+				for i := 0; i < 10; i++ {
+					data[0].API = newAPI()
+					Ω(os.Truncate(filename, 0)).ShouldNot(HaveOccurred())
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					if i == 0 {
+						first = string(b)
+						Ω(first).Should(ContainSubstring(`handleMethodNotAllowed("DELETE, GET, HEAD, PATCH", "application/merge-patch+json")`))
+						continue
+					}
+					Ω(string(b)).Should(Equal(first))
+				}
+			})
+		})
+
+		Context("with described actions", func() {
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				data = []*genapp.ControllerTemplateData{{
+					API:      &design.APIDefinition{},
+					Resource: "Bottles",
+					Actions: []map[string]interface{}{{
+						"Name": "Show",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "GET", Path: "/bottles/:id"},
+						},
+						"Context":     "ShowBottleContext",
+						"Description": "Retrieve the bottle with the given id.",
+						"Summary":     "Show a bottle",
+						"Tags":        "bottle, cellar",
+					}},
+				}}
+			})
+
+			It("writes the action documentation in the controller interface", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring(describedController))
+			})
+		})
+
+		Context("with a webhook receiver action", func() {
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				data = []*genapp.ControllerTemplateData{{
+					API:      &design.APIDefinition{},
+					Resource: "Hooks",
+					Actions: []map[string]interface{}{{
+						"Name": "Push",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "POST", Path: "/hooks/push"},
+						},
+						"Context": "PushHooksContext",
+						"Webhook": &design.WebhookDefinition{SignatureHeader: "X-Hub-Signature-256", Secret: "secret"},
+					}},
+				}}
+			})
+
+			It("verifies the request signatures", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring(`service.Mux.Handle("POST", "/hooks/push", chainMuxHandler(service, middleware.VerifyWebhookSignature("X-Hub-Signature-256", "secret", ctrl.MuxHandler("Push", h, nil)), chain))`))
+			})
+		})
+
+		Context("with a WebSocket action", func() {
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				data = []*genapp.ControllerTemplateData{{
+					API:      &design.APIDefinition{},
+					Resource: "Chat",
+					Actions: []map[string]interface{}{{
+						"Name": "Echo",
+						"Routes": []*design.RouteDefinition{
+							{Verb: "GET", Path: "/chat/echo"},
+						},
+						"Context":          "EchoChatContext",
+						"WebSocket":        true,
+						"WebSocketContext": "EchoChatWebSocketContext",
+					}},
+					Encoders: []*genapp.EncoderTemplateData{{
+						PackagePath: "github.com/goadesign/goa",
+						PackageName: "goa",
+						Function:    "NewJSONEncoder",
+						MIMETypes:   []string{"application/json"},
+						Default:     true,
+						Compression: []string{"gzip"},
+					}},
+				}}
+			})
+
+			It("upgrades the connection and delegates to the WebSocket controller", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring(webSocketController))
+				Ω(written).Should(ContainSubstring(webSocketMount))
+				Ω(written).ShouldNot(ContainSubstring("h = compressHandler(service, h)"))
+			})
+		})
+
+		Context("with file servers", func() {
+			requestPath := "/swagger.json"
+			filePath := "swagger/swagger.json"
+			var origins []*design.CORSDefinition
+			var preflightPaths []string
+
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				origins = nil
+				preflightPaths = nil
+			})
+
+			JustBeforeEach(func() {
+				codegen.TempCount = 0
+				fileServer := &design.FileServerDefinition{
+					FilePath:    filePath,
+					RequestPath: requestPath,
+				}
+				d := &genapp.ControllerTemplateData{
+					API:            &design.APIDefinition{},
+					Origins:        origins,
+					PreflightPaths: preflightPaths,
+					Resource:       "Public",
+					FileServers:    []*design.FileServerDefinition{fileServer},
+				}
+				data = []*genapp.ControllerTemplateData{d}
+			})
+
+			It("writes the file server code", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).ShouldNot(BeEmpty())
+				Ω(written).Should(ContainSubstring(simpleFileServer))
+			})
+
+			Context("with CORS", func() {
+				BeforeEach(func() {
+					origins = []*design.CORSDefinition{
+						{
+							Origin:      "here.example.com",
+							Headers:     []string{"X-One", "X-Two"},
+							Methods:     []string{"GET", "POST"},
+							Exposed:     []string{"X-Three"},
+							Credentials: true,
+						},
+					}
+					preflightPaths = []string{"/public/*filepath"}
+				})
+
+				It("writes the OPTIONS handler code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(fileServerOptionsHandler))
+				})
+			})
+		})
+
+		Context("with data", func() {
+			var actions, verbs, paths, contexts, unmarshals, schemas []string
+			var payloads []*design.UserTypeDefinition
+			var encoders, decoders []*genapp.EncoderTemplateData
+			var origins []*design.CORSDefinition
+			var expvar, formData, multipartIngest, standalone bool
+			var tracer string
+			var sunset *design.SunsetDefinition
+
+			var data []*genapp.ControllerTemplateData
+
+			BeforeEach(func() {
+				expvar = false
+				formData = false
+				multipartIngest = false
+				standalone = false
+				tracer = ""
+				sunset = nil
+				actions = nil
+				verbs = nil
+				paths = nil
+				contexts = nil
+				unmarshals = nil
+				schemas = nil
+				payloads = nil
+				encoders = nil
+				decoders = nil
+				origins = nil
+			})
+
+			JustBeforeEach(func() {
+				codegen.TempCount = 0
+				api := &design.APIDefinition{}
+				d := &genapp.ControllerTemplateData{
+					Resource:   "Bottles",
+					Origins:    origins,
+					Expvar:     expvar,
+					Standalone: standalone,
+					Tracer:     tracer,
+					Sunset:     sunset,
+				}
+				as := make([]map[string]interface{}, len(actions))
+				for i, a := range actions {
+					var unmarshal, schema string
+					var payload *design.UserTypeDefinition
+					if i < len(unmarshals) {
+						unmarshal = unmarshals[i]
+					}
+					if i < len(schemas) {
+						schema = schemas[i]
+					}
+					if i < len(payloads) {
+						payload = payloads[i]
+					}
+					as[i] = map[string]interface{}{
+						"Name": a,
+						"Span": "bottles." + strings.ToLower(a),
+						"Routes": []*design.RouteDefinition{
+							{
+								Verb: verbs[i],
+								Path: paths[i],
+							}},
+						"Context":         contexts[i],
+						"Unmarshal":       unmarshal,
+						"Payload":         payload,
+						"FormData":        formData,
+						"MultipartIngest": multipartIngest,
+						"Schema":          schema,
+					}
+				}
+				if len(as) > 0 {
+					d.API = api
+					d.Actions = as
+					d.Encoders = encoders
+					d.Decoders = decoders
+					data = []*genapp.ControllerTemplateData{d}
+				} else {
+					data = nil
+				}
+			})
+
+			Context("with missing data", func() {
+				It("returns an empty string", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(BeEmpty())
+				})
+			})
+
+			Context("with a simple controller", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("writes the controller code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(simpleController))
+					Ω(written).Should(ContainSubstring(simpleMount))
+					Ω(written).Should(ContainSubstring(headHandler))
+				})
+			})
+
+			Context("with a HEAD action on the path of a GET action", func() {
+				BeforeEach(func() {
+					actions = []string{"List", "Count"}
+					verbs = []string{"GET", "HEAD"}
+					paths = []string{"/accounts/:accountID/bottles", "/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext", "CountBottleContext"}
+				})
+
+				It("does not register the GET handler for HEAD requests", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`service.Mux.Handle("HEAD", "/accounts/:accountID/bottles", chainMuxHandler(service, ctrl.MuxHandler("Count", h, nil), chain))`))
+					Ω(written).ShouldNot(ContainSubstring("headHandler"))
+				})
+			})
+
+			Context("with expvar", func() {
+				BeforeEach(func() {
+					expvar = true
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("wraps the action handlers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(expvarMount))
+				})
+			})
+
+			Context("with standalone services", func() {
+				BeforeEach(func() {
+					standalone = true
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("writes the http.Handler service", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(simpleMount))
+					Ω(written).Should(ContainSubstring(standaloneService))
+				})
+			})
+
+			Context("with the datadog tracer", func() {
+				BeforeEach(func() {
+					tracer = "datadog"
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("names the request spans after the resource and action", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\th = handleRecover(h)\n\th = handleTrace(\"bottles.list\", h)\n"))
+				})
+			})
+
+			Context("with a sunsetted API version", func() {
+				BeforeEach(func() {
+					sunset = &design.SunsetDefinition{Date: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)}
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("writes the sunset handler code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(sunsetHandler))
+					Ω(written).Should(ContainSubstring(sunsetMount))
+				})
+			})
+
+			Context("with compression", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					encoders = []*genapp.EncoderTemplateData{{
+						PackagePath: "github.com/goadesign/goa",
+						PackageName: "goa",
+						Function:    "NewJSONEncoder",
+						MIMETypes:   []string{"application/json"},
+						Default:     true,
+						Compression: []string{"gzip", "zstd"},
+					}}
+				})
+
+				It("writes the compression code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("func compressHandler(service *goa.Service, h goa.Handler) goa.Handler {"))
+					Ω(written).Should(ContainSubstring(compressMount))
+				})
+			})
+
+			Context("with actions that take a payload", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					unmarshals = []string{"unmarshalListBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "ListBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"id": &design.AttributeDefinition{
+										Type: design.String,
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("writes the payload unmarshal function", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadNoValidationsObjUnmarshal))
+					Ω(written).Should(ContainSubstring("func checkJSONBody(req *http.Request) error {"))
+					Ω(written).Should(ContainSubstring("if !json.Valid(body) {"))
+				})
+			})
+			Context("with actions that validate the payload JSON schema", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					unmarshals = []string{"unmarshalListBottlePayload"}
+					schemas = []string{`{"type":"object","properties":{"id":{"type":"string"}}}`}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "ListBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"id": &design.AttributeDefinition{
+										Type: design.String,
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("validates the request body before decoding it", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("func validateSchema(req *http.Request, schema *gojsonschema.Schema) error {"))
+					Ω(written).Should(ContainSubstring(payloadSchemaUnmarshal))
+				})
+			})
+
+			Context("with actions that take a payload with a required validation", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					required := &dslengine.ValidationDefinition{
+						Required: []string{"id"},
+					}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					unmarshals = []string{"unmarshalListBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "ListBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"id": &design.AttributeDefinition{
+										Type: design.String,
+									},
+								},
+								Validation: required,
+							},
+						},
+					}
+				})
+
+				It("writes the payload unmarshal function", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadObjUnmarshal))
+				})
+			})
+
+			Context("with actions that take a discriminated payload", func() {
+				BeforeEach(func() {
+					dog := &design.UserTypeDefinition{
+						TypeName: "Dog",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"type":  &design.AttributeDefinition{Type: design.String},
+								"barks": &design.AttributeDefinition{Type: design.Boolean},
+							},
+						},
+					}
+					cat := &design.UserTypeDefinition{
+						TypeName: "Cat",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"type":  &design.AttributeDefinition{Type: design.String},
+								"lives": &design.AttributeDefinition{Type: design.Integer},
+							},
+						},
+					}
+					actions = []string{"Create"}
+					verbs = []string{"POST"}
+					paths = []string{"/pets"}
+					contexts = []string{"CreatePetContext"}
+					unmarshals = []string{"unmarshalCreatePetPayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "Pet",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"type": &design.AttributeDefinition{Type: design.String},
+								},
+								Discriminator: &design.DiscriminatorDefinition{
+									Field:   "type",
+									Mapping: map[string]design.DataType{"dog": dog, "cat": cat},
+								},
+							},
+						},
+					}
+				})
+
+				It("unmarshals the payload into the concrete type", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadDiscriminatedUnmarshal))
+					Ω(written).Should(ContainSubstring("rctx.Payload = rawPayload\n"))
+					Ω(written).ShouldNot(ContainSubstring("checkJSONBody"))
+				})
+			})
+
+			Context("with actions that take a form data payload", func() {
+				BeforeEach(func() {
+					actions = []string{"Upload"}
+					verbs = []string{"POST"}
+					paths = []string{"/labels"}
+					contexts = []string{"UploadBottleContext"}
+					unmarshals = []string{"unmarshalUploadBottlePayload"}
+					formData = true
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "UploadBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"caption": &design.AttributeDefinition{Type: design.String},
+									"label":   &design.AttributeDefinition{Type: design.FileType},
+								},
+								Validation: &dslengine.ValidationDefinition{Required: []string{"label"}},
+							},
+						},
+					}
+				})
+
+				It("parses the multipart form into the payload", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadFormDataUnmarshal))
+				})
+			})
+
+			Context("with actions that ingest multipart parts", func() {
+				BeforeEach(func() {
+					actions = []string{"Ingest"}
+					verbs = []string{"POST"}
+					paths = []string{"/bottles"}
+					contexts = []string{"IngestBottleContext"}
+					unmarshals = []string{"unmarshalIngestBottlePayload"}
+					multipartIngest = true
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "IngestBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"parts": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+								},
+								Validation: &dslengine.ValidationDefinition{Required: []string{"parts"}},
+							},
+						},
+					}
+				})
+
+				It("decodes each part into the payload parts", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadMultipartIngestUnmarshal))
+					Ω(written).ShouldNot(ContainSubstring("checkJSONBody"))
+				})
+			})
+
+			Context("with multiple controllers", func() {
+				BeforeEach(func() {
+					actions = []string{"List", "Show"}
+					verbs = []string{"GET", "GET"}
+					paths = []string{"/accounts/:accountID/bottles", "/accounts/:accountID/bottles/:id"}
+					contexts = []string{"ListBottleContext", "ShowBottleContext"}
+				})
+
+				It("writes the controllers code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(multiController))
+					Ω(written).Should(ContainSubstring(multiMount))
+				})
+			})
+
+			Context("with encoder and decoder maps", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					encoders = []*genapp.EncoderTemplateData{
+						{
+							PackageName: "goa",
+							Function:    "NewEncoder",
+							MIMETypes:   []string{"application/json"},
+						},
+					}
+					decoders = []*genapp.EncoderTemplateData{
+						{
+							PackageName: "goa",
+							Function:    "NewDecoder",
+							MIMETypes:   []string{"application/json"},
+						},
+					}
+				})
+
+				It("writes the controllers code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(encoderController))
+				})
+			})
+
+			Context("with multiple origins", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts"}
+					contexts = []string{"ListBottleContext"}
+					origins = []*design.CORSDefinition{
+						{
+							Origin:      "here.example.com",
+							Headers:     []string{"X-One", "X-Two"},
+							Methods:     []string{"GET", "POST"},
+							Exposed:     []string{"X-Three"},
+							Credentials: true,
+						},
+						{
+							Origin:  "there.example.com",
+							Headers: []string{"*"},
+							Methods: []string{"*"},
+						},
+					}
+
+				})
+
+				It("writes the controller code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(originsIntegration))
+					Ω(written).Should(ContainSubstring(originsHandler))
+				})
+			})
+
+			Context("with regexp origins", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts"}
+					contexts = []string{"ListBottleContext"}
+					origins = []*design.CORSDefinition{
+						{
+							Origin:      "[here|there].example.com",
+							Headers:     []string{"X-One", "X-Two"},
+							Methods:     []string{"GET", "POST"},
+							Exposed:     []string{"X-Three"},
+							Credentials: true,
+							Regexp:      true,
+						},
+						{
+							Origin:  "there.example.com",
+							Headers: []string{"*"},
+							Methods: []string{"*"},
+						},
+					}
+
+				})
+
+				It("writes the controller code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(originsIntegration))
+					Ω(written).Should(ContainSubstring(regexpOriginsHandler))
+				})
+			})
+
+		})
+	})
+})
+
+var _ = Describe("HrefWriter", func() {
+	var writer *genapp.ResourcesWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("controllers")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewResourcesWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("correctly configured", func() {
+		Context("with data", func() {
+			var canoTemplate string
+			var canoParams []string
+			var optionalSegments []string
+			var mediaType *design.MediaTypeDefinition
+
+			var data *genapp.ResourceData
+
+			BeforeEach(func() {
+				mediaType = nil
+				canoTemplate = ""
+				canoParams = nil
+				optionalSegments = nil
+				data = nil
+			})
+
+			JustBeforeEach(func() {
+				data = &genapp.ResourceData{
+					Name:              "Bottle",
+					Identifier:        "vnd.acme.com/resources",
+					Description:       "A bottle resource",
+					Type:              mediaType,
+					CanonicalTemplate: canoTemplate,
+					CanonicalParams:   canoParams,
+					OptionalSegments:  optionalSegments,
+				}
+			})
+
+			Context("with missing resource type definition", func() {
+				It("does not return an error", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+				})
+			})
+
+			Context("with a string resource", func() {
+				BeforeEach(func() {
+					attDef := &design.AttributeDefinition{
+						Type: design.String,
+					}
+					mediaType = &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: attDef,
+							TypeName:            "Bottle",
+						},
+					}
+				})
+				It("does not return an error", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+				})
+			})
+
+			Context("with a user type resource", func() {
+				BeforeEach(func() {
+					intParam := &design.AttributeDefinition{Type: design.Integer}
+					strParam := &design.AttributeDefinition{Type: design.String}
+					dataType := design.Object{
+						"int": intParam,
+						"str": strParam,
+					}
+					attDef := &design.AttributeDefinition{
+						Type: dataType,
+					}
+					mediaType = &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: attDef,
+							TypeName:            "Bottle",
+						},
+					}
+				})
+
+				Context("and a canonical action", func() {
+					BeforeEach(func() {
+						canoTemplate = "/bottles/%v"
+						canoParams = []string{"id"}
+					})
+
+					It("writes the href method", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(simpleResourceHref))
+					})
+				})
+
+				Context("and a canonical action with no param", func() {
+					BeforeEach(func() {
+						canoTemplate = "/bottles"
+					})
+
+					It("writes the href method", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(noParamHref))
+					})
+				})
+
+				Context("and a canonical action with an optional segment", func() {
+					BeforeEach(func() {
+						canoTemplate = "/accounts/%v/bottles/%v"
+						canoParams = []string{"accountID", "id"}
+						optionalSegments = []string{"/accounts/:accountID"}
+					})
+
+					It("writes the href method", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(optionalSegmentHref))
+					})
+				})
+			})
+		})
+	})
+})
+
+var _ = Describe("UserTypesWriter", func() {
+	var writer *genapp.UserTypesWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("types")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewUserTypesWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with enum attributes", func() {
+		var ut *design.UserTypeDefinition
+
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName: "Wine",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"color": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "white"}},
+						},
+						"vintage": &design.AttributeDefinition{
+							Type:       design.Integer,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{2010, 2011}},
+						},
+					},
+				},
+			}
+		})
+
+		It("writes the enum constants and validation helper", func() {
+			err := writer.Execute(ut)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring(enumConstants))
+			Ω(written).ShouldNot(ContainSubstring("WineVintage"))
+		})
+	})
+
+	Context("with a SQL column type", func() {
+		var ut *design.UserTypeDefinition
+
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName: "Address",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"city": &design.AttributeDefinition{Type: design.String},
+					},
+					Metadata: dslengine.MetadataDefinition{"sql:type": []string{"jsonb"}},
+				},
+			}
+		})
+
+		It("writes the Scan and Value methods", func() {
+			err := writer.Execute(ut)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring(sqlScanner))
+		})
+	})
+
+	Context("used as a JSON merge patch payload", func() {
+		var ut *design.UserTypeDefinition
+
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName: "Wine",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"color": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "white"}},
+						},
+					},
+				},
+			}
+		})
+
+		It("writes the MergePatch method", func() {
+			writer.MergePatchTypes = map[string]bool{"Wine": true}
+			err := writer.Execute(ut)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring(mergePatchMethod))
+		})
+	})
+})
+
+var _ = Describe("MediaTypesWriter", func() {
+	var writer *genapp.MediaTypesWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("types")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+		design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewMediaTypesWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		writer.FlattenLinks = true
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with flattened links", func() {
+		var bottle *design.MediaTypeDefinition
+		var viewAtts design.Object
+
+		BeforeEach(func() {
+			account := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					TypeName: "Account",
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+						"id":   &design.AttributeDefinition{Type: design.Integer},
+						"name": &design.AttributeDefinition{Type: design.String},
+					}},
+				},
+				Identifier: "application/vnd.account+json",
+			}
+			account.Views = map[string]*design.ViewDefinition{
+				"default": {Name: "default", Parent: account, AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+					"id":   &design.AttributeDefinition{Type: design.Integer},
+					"name": &design.AttributeDefinition{Type: design.String},
+				}}},
+				"link": {Name: "link", Parent: account, AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+					"id": &design.AttributeDefinition{Type: design.Integer},
+				}}},
+			}
+			bottle = &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					TypeName: "Bottle",
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+						"name":    &design.AttributeDefinition{Type: design.String},
+						"account": &design.AttributeDefinition{Type: account},
+					}},
+				},
+				Identifier: "application/vnd.bottle+json",
+			}
+			bottle.Links = map[string]*design.LinkDefinition{"account": {Name: "account", Parent: bottle}}
+			viewAtts = design.Object{
+				"name":  &design.AttributeDefinition{Type: design.String},
+				"links": &design.AttributeDefinition{},
+			}
+			bottle.Views = map[string]*design.ViewDefinition{
+				"default": {Name: "default", Parent: bottle, AttributeDefinition: &design.AttributeDefinition{Type: viewAtts}},
+			}
+		})
+
+		It("writes the JSON marshaling methods merging the links", func() {
+			err := writer.Execute(bottle)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("func mergeJSONObjects(a, b []byte) []byte {"))
+			Ω(written).Should(ContainSubstring(flattenLinksCode))
+		})
+
+		Context("with a view rendering the linked attribute", func() {
+			BeforeEach(func() {
+				viewAtts["account"] = &design.AttributeDefinition{}
+			})
+
+			It("returns an error", func() {
+				err := writer.Execute(bottle)
+				Ω(err).Should(MatchError(`cannot flatten the links of Bottle: link "account" conflicts with the attribute of the same name`))
+			})
+		})
+	})
+
+	Context("with a self-referencing media type", func() {
+		var tree *design.MediaTypeDefinition
+
+		BeforeEach(func() {
+			tree = &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					TypeName: "Tree",
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+						"name": &design.AttributeDefinition{Type: design.String},
+					}},
+				},
+				Identifier: "application/vnd.tree+json",
+			}
+			tree.Type.ToObject()["parent"] = &design.AttributeDefinition{Type: tree}
+			tree.Views = map[string]*design.ViewDefinition{
+				"default": {Name: "default", Parent: tree, AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"parent": &design.AttributeDefinition{View: "tiny"},
+				}}},
+				"tiny": {Name: "tiny", Parent: tree, AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"parent": &design.AttributeDefinition{},
+				}}},
+			}
+		})
+
+		It("terminates and references the projected types", func() {
+			err := writer.Execute(tree)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("type Tree struct {"))
+			Ω(written).Should(ContainSubstring("Parent *TreeTiny `"))
+			Ω(written).Should(ContainSubstring("type TreeTiny struct {"))
+			Ω(written).Should(ContainSubstring("Parent *Tree `"))
+		})
+	})
+})
+
+const (
+	emptyContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+}
+`
+
+	requestIDContext = `
+// RequestID returns the ID of the request read from the X-Request-ID, X-Correlation-ID or
+// traceparent headers, a random UUID is generated if none is set.
+func (ctx *ListBottleContext) RequestID() string {
+	return goa.ExtractRequestID(ctx.RequestData.Request)
+}
+`
+
+	webSocketContext = `
+// ListBottleWebSocketContext provides the bottles list action context and the
+// WebSocket connection.
+type ListBottleWebSocketContext struct {
+	*ListBottleContext
+	*websocket.Conn
+}
+`
+
+	contextKeyAccessors = `
+// userIDContextKey is the private type of the context key of the userID value.
+type userIDContextKey struct{}
+
+// WithUserID returns a copy of ctx that holds the userID value. Use it in
+// middleware to make the value available to the action contexts.
+func WithUserID(ctx context.Context, v string) context.Context {
+	return context.WithValue(ctx, userIDContextKey{}, v)
+}
+
+// ContextUserID returns the userID value held by ctx if any.
+func ContextUserID(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(userIDContextKey{}).(string)
+	return v, ok
+}
+`
+
+	contextKeyContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	UserID string
+}
+`
+
+	contextKeyContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	if v, ok := ContextUserID(ctx); ok {
+		rctx.UserID = v
+	}
+	return &rctx, err
+}
+`
+
+	emptyContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	return &rctx, err
+}
+`
+
+	intContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param *int
+}
+`
+
+	intDefaultContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param int
+}
+`
+
+	intDefaultContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if param, err2 := strconv.Atoi(rawParam); err2 == nil {
+			rctx.Param = param
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "integer"))
+		}
+	} else {
+		rctx.Param = 1
+	}
+	return &rctx, err
+}
+`
+
+	intContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if param, err2 := strconv.Atoi(rawParam); err2 == nil {
+			tmp2 := param
+			tmp1 := &tmp2
+			rctx.Param = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "integer"))
+		}
+	}
+	return &rctx, err
+}
+`
+
+	anyOfStringFirstCoerce = `
+		rawName := paramName[0]
+		rctx.Name = rawName
+`
+
+	anyOfIntFirstCoerce = `
+		rawVintage := paramVintage[0]
+		if vintage, err2 := strconv.Atoi(rawVintage); err2 == nil {
+			rctx.Vintage = vintage
+		} else {
+			rctx.Vintage = rawVintage
+		}
+`
+
+	anyOfNoMatchCoerce = `
+		rawRating := paramRating[0]
+		if rating, err2 := strconv.Atoi(rawRating); err2 == nil {
+			rctx.Rating = rating
+		} else if rating, err2 := strconv.ParseBool(rawRating); err2 == nil {
+			rctx.Rating = rating
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("rating", rawRating, "integer or boolean"))
+		}
+`
+
+	anyOfHelper = `
+// MustListBottlesVintageAsInt returns the value of the vintage param of ctx coerced to int, it panics
+// if the value was coerced to another type.
+func MustListBottlesVintageAsInt(ctx *ListBottleContext) int {
+	return ctx.Vintage.(int)
+}
+`
+
+	fileParamContextFactory = `
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	if err2 := req.ParseMultipartForm(32 << 20); err2 != nil && err2 != http.ErrNotMultipart {
+		err = goa.MergeErrors(err, goa.ErrBadRequest(err2))
+	}
+	var files map[string][]*multipart.FileHeader
+	if req.MultipartForm != nil {
+		files = req.MultipartForm.File
+	}
+	if headers := files["label"]; len(headers) > 0 {
+		rctx.Label = headers[0]
+	} else {
+		err = goa.MergeErrors(err, goa.MissingParamError("label"))
+	}
+	paramName := req.Params["name"]
+	if len(paramName) > 0 {
+		rawName := paramName[0]
+		rctx.Name = &rawName
+	}
+	return &rctx, err
+}
+`
+
+	uintContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param *uint8
+}
+`
+
+	uintContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if param, err2 := strconv.ParseUint(rawParam, 10, 8); err2 == nil {
+			tmp1 := uint8(param)
+			rctx.Param = &tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "unsigned integer"))
+		}
+	}
+	return &rctx, err
+}
+`
+
+	strContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param *string
+}
+`
+
+	strContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		rctx.Param = &rawParam
+	}
+	return &rctx, err
+}
+`
+
+	strHeaderContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Header *string
+}
+`
+
+	strHeaderContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	headerHeader := req.Header["Header"]
+	if len(headerHeader) > 0 {
+		rawHeader := headerHeader[0]
+		req.Params["Header"] = []string{rawHeader}
+		rctx.Header = &rawHeader
+	}
+	return &rctx, err
+}
+`
+
+	strHeaderParamContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	headerParam := req.Header["Param"]
+	if len(headerParam) > 0 {
+		rawParam := headerParam[0]
+		req.Params["param"] = []string{rawParam}
+		rctx.Param = &rawParam
+	}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		rctx.Param = &rawParam
+	}
+	return &rctx, err
+}
+`
+
+	numContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param *float64
+}
+`
+
+	numContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if param, err2 := strconv.ParseFloat(rawParam, 64); err2 == nil {
+			tmp1 := &param
+			rctx.Param = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "number"))
+		}
+	}
+	return &rctx, err
+}
+`
+	ipContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param *net.IP
+}
+`
+
+	ipContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if param := net.ParseIP(rawParam); param != nil {
+			tmp1 := &param
+			rctx.Param = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "ip"))
+		}
+	}
+`
+
+	latLonContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param goa.LatLon
+}
+`
+
+	latLonContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("param"))
+	} else {
+		rawParam := paramParam[0]
+		if param, err2 := goa.ParseLatLon(rawParam); err2 == nil {
+			rctx.Param = param
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "latlon"))
+		}
+	}
+`
+
+	semVerContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param goa.SemVerString
+}
+`
+
+	semVerContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("param"))
+	} else {
+		rawParam := paramParam[0]
+		if semver.IsValid(rawParam) {
+			rctx.Param = rawParam
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "semver"))
+		}
+	}
+`
+
+	bigIntContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param goa.BigInt
+}
+`
+
+	bigIntContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("param"))
+	} else {
+		rawParam := paramParam[0]
+		if param, err2 := goa.ParseBigInt(rawParam); err2 == nil {
+			rctx.Param = param
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "big integer"))
+		}
+	}
+`
+
+	cidrContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Param goa.CIDR
+}
+`
+
+	cidrContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("param"))
+	} else {
+		rawParam := paramParam[0]
+		if param, err2 := goa.ParseCIDR(rawParam); err2 == nil {
+			rctx.Param = param
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "cidr"))
+		}
+	}
+`
+
+	boolContext = `
 type ListBottleContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
+	Param *bool
 }
 `
 
-	emptyContextFactory = `
+	boolContextFactory = `
 func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
 	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
 	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if param, err2 := strconv.ParseBool(rawParam); err2 == nil {
+			tmp1 := &param
+			rctx.Param = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "boolean"))
+		}
+	}
 	return &rctx, err
 }
 `
 
-	intContext = `
-type ListBottleContext struct {
-	context.Context
-	*goa.ResponseData
-	*goa.RequestData
-	Param *int
-}
-`
-
-	intContextFactory = `
+	dateTimeContextFactory = `
 func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
 	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
@@ -1030,347 +3018,650 @@ func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottl
 	paramParam := req.Params["param"]
 	if len(paramParam) > 0 {
 		rawParam := paramParam[0]
-		if param, err2 := strconv.Atoi(rawParam); err2 == nil {
-			tmp2 := param
-			tmp1 := &tmp2
+		if param, err2 := time.Parse(time.RFC3339, rawParam); err2 == nil {
+			param = param.UTC()
+			tmp1 := &param
 			rctx.Param = tmp1
 		} else {
-			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "integer"))
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "datetime"))
 		}
 	}
 	return &rctx, err
 }
 `
 
-	strContext = `
+	arrayContext = `
 type ListBottleContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
-	Param *string
+	Param []string
 }
 `
 
-	strContextFactory = `
+	arrayContextFactory = `
 func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
 	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
 	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
 	paramParam := req.Params["param"]
 	if len(paramParam) > 0 {
-		rawParam := paramParam[0]
-		rctx.Param = &rawParam
+		params := paramParam
+		rctx.Param = params
 	}
 	return &rctx, err
 }
 `
 
-	strHeaderContext = `
+	intArrayContext = `
 type ListBottleContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
-	Header *string
+	Param []int
 }
 `
 
-	strHeaderContextFactory = `
+	intArrayContextFactory = `
 func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
 	var err error
-	resp := goa.ContextResponse(ctx)
-	resp.Service = service
-	req := goa.ContextRequest(ctx)
-	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	headerHeader := req.Header["Header"]
-	if len(headerHeader) > 0 {
-		rawHeader := headerHeader[0]
-		req.Params["Header"] = []string{rawHeader}
-		rctx.Header = &rawHeader
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
 	}
-	return &rctx, err
-}
-`
-
-	strHeaderParamContextFactory = `
-func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
-	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
 	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	headerParam := req.Header["Param"]
-	if len(headerParam) > 0 {
-		rawParam := headerParam[0]
-		req.Params["param"] = []string{rawParam}
-		rctx.Param = &rawParam
-	}
 	paramParam := req.Params["param"]
 	if len(paramParam) > 0 {
-		rawParam := paramParam[0]
-		rctx.Param = &rawParam
+		params := make([]int, len(paramParam))
+		for i, rawParam := range paramParam {
+			if param, err2 := strconv.Atoi(rawParam); err2 == nil {
+				params[i] = param
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "integer"))
+			}
+		}
+		rctx.Param = params
 	}
 	return &rctx, err
 }
 `
 
-	numContext = `
+	resContext = `
 type ListBottleContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
-	Param *float64
+	Int *int
 }
 `
 
-	numContextFactory = `
+	resContextFactory = `
 func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
 	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
 	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	paramParam := req.Params["param"]
-	if len(paramParam) > 0 {
-		rawParam := paramParam[0]
-		if param, err2 := strconv.ParseFloat(rawParam, 64); err2 == nil {
-			tmp1 := &param
-			rctx.Param = tmp1
+	paramInt := req.Params["int"]
+	if len(paramInt) > 0 {
+		rawInt := paramInt[0]
+		if int_, err2 := strconv.Atoi(rawInt); err2 == nil {
+			tmp2 := int_
+			tmp1 := &tmp2
+			rctx.Int = tmp1
 		} else {
-			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "number"))
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("int", rawInt, "integer"))
 		}
 	}
 	return &rctx, err
 }
 `
-	boolContext = `
+
+	requiredContext = `
 type ListBottleContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
-	Param *bool
+	Int int
 }
 `
 
-	boolContextFactory = `
+	requiredContextFactory = `
 func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
 	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
 	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	paramParam := req.Params["param"]
-	if len(paramParam) > 0 {
-		rawParam := paramParam[0]
-		if param, err2 := strconv.ParseBool(rawParam); err2 == nil {
-			tmp1 := &param
-			rctx.Param = tmp1
+	paramInt := req.Params["int"]
+	if len(paramInt) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("int"))
+	} else {
+		rawInt := paramInt[0]
+		if int_, err2 := strconv.Atoi(rawInt); err2 == nil {
+			rctx.Int = int_
 		} else {
-			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "boolean"))
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("int", rawInt, "integer"))
 		}
 	}
 	return &rctx, err
 }
 `
 
-	arrayContext = `
+	customContext = `
 type ListBottleContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
-	Param []string
+	Custom *int
 }
 `
 
-	arrayContextFactory = `
+	customContextFactory = `
 func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
 	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
 	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	paramParam := req.Params["param"]
-	if len(paramParam) > 0 {
-		params := paramParam
-		rctx.Param = params
+	paramInt := req.Params["int"]
+	if len(paramInt) > 0 {
+		rawInt := paramInt[0]
+		if int_, err2 := strconv.Atoi(rawInt); err2 == nil {
+			tmp2 := int_
+			tmp1 := &tmp2
+			rctx.Custom = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("int", rawInt, "integer"))
+		}
 	}
 	return &rctx, err
 }
 `
 
-	intArrayContext = `
+	payloadContext = `
 type ListBottleContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
-	Param []int
+	Payload ListBottlePayload
 }
 `
 
-	intArrayContextFactory = `
+	payloadContextFactory = `
 func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
 	var err error
+	if service == nil {
+		service = goa.ServiceFromContext(ctx)
+	}
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
 	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	paramParam := req.Params["param"]
-	if len(paramParam) > 0 {
-		params := make([]int, len(paramParam))
-		for i, rawParam := range paramParam {
-			if param, err2 := strconv.Atoi(rawParam); err2 == nil {
-				params[i] = param
-			} else {
-				err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "integer"))
-			}
+	return &rctx, err
+}
+`
+	payloadObjContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Payload *ListBottlePayload
+}
+`
+
+	multipartResp = `// OKMultipart sends a multipart HTTP response with status code 200.
+// parts contains the content of each part indexed by part name.
+func (ctx *ListBottleContext) OKMultipart(parts map[string]io.Reader) error {
+	mw := multipart.NewWriter(ctx.ResponseData)
+	ctx.ResponseData.Header().Set("Content-Type", mw.FormDataContentType())
+	ctx.ResponseData.WriteHeader(200)
+	if r, ok := parts["report"]; ok {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", "form-data; name=\"report\"")
+		h.Set("Content-Type", "application/json")
+		pw, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(pw, r); err != nil {
+			return err
 		}
-		rctx.Param = params
 	}
-	return &rctx, err
+	if r, ok := parts["chart"]; ok {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", "form-data; name=\"chart\"")
+		h.Set("Content-Type", "image/png")
+		pw, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(pw, r); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+`
+
+	cacheControlResp = `
+// OK sends a HTTP response with status code 200.
+func (ctx *ListBottleContext) OK(resp []byte) error {
+	ctx.ResponseData.Header().Set("Content-Type", "text/plain")
+	ctx.ResponseData.Header().Set("Cache-Control", "max-age=300, public")
+	ctx.ResponseData.WriteHeader(200)
+	_, err := ctx.ResponseData.Write(resp)
+	return err
+}
+`
+
+	notFoundResp = `
+// NotFound sends a HTTP response with status code 404.
+func (ctx *ListBottleContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+`
+
+	retryAfterResp = `
+// ServiceUnavailable sends a HTTP response with status code 503.
+func (ctx *ListBottleContext) ServiceUnavailable(retryAfter time.Duration) error {
+	if retryAfter == 0 {
+		retryAfter = 30 * time.Second
+	}
+	ctx.ResponseData.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	ctx.ResponseData.WriteHeader(503)
+	return nil
+}
+
+// ServiceUnavailableAt sends a HTTP response with status code 503 and a Retry-After header set to retryAt.
+func (ctx *ListBottleContext) ServiceUnavailableAt(retryAt time.Time) error {
+	ctx.ResponseData.Header().Set("Retry-After", retryAt.UTC().Format(http.TimeFormat))
+	ctx.ResponseData.WriteHeader(503)
+	return nil
+}
+`
+
+	filteredResp = `
+// OKFiltered sends a HTTP response with status code 200 after removing the fields of r that are
+// not visible to any of the given roles.
+func (ctx *ListBottleContext) OKFiltered(r *Bottle, roles []string) error {
+	if r != nil {
+		filtered := *r
+		if !goa.HasAnyRole(roles, "admin", "support") {
+			filtered.InternalNotes = nil
+		}
+		r = &filtered
+	}
+	return ctx.OK(r)
+}
+`
+
+	payloadObjUnmarshal = `
+func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	if err := checkJSONBody(req); err != nil {
+		return err
+	}
+	payload := &listBottlePayload{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
+`
+	payloadNoValidationsObjUnmarshal = `
+func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	if err := checkJSONBody(req); err != nil {
+		return err
+	}
+	payload := &listBottlePayload{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
+`
+	payloadSchemaUnmarshal = `
+// unmarshalListBottlePayloadSchema is the JSON schema of the List action payload.
+var unmarshalListBottlePayloadSchema = compileSchema("{\"type\":\"object\",\"properties\":{\"id\":{\"type\":\"string\"}}}")
+
+// unmarshalListBottlePayload unmarshals the request body into the context request data Payload field.
+func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	if err := checkJSONBody(req); err != nil {
+		return err
+	}
+	if err := validateSchema(req, unmarshalListBottlePayloadSchema); err != nil {
+		return err
+	}
+	payload := &listBottlePayload{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}
+`
+
+	overlayContextT = `// {{ .Name }} is generated from a user template.
+type {{ .Name }} struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Custom bool
 }
 `
 
-	resContext = `
+	overlayContext = `// ListBottleContext is generated from a user template.
 type ListBottleContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
-	Int *int
+	Custom bool
 }
 `
 
-	resContextFactory = `
-func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
-	var err error
-	resp := goa.ContextResponse(ctx)
-	resp.Service = service
-	req := goa.ContextRequest(ctx)
-	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	paramInt := req.Params["int"]
-	if len(paramInt) > 0 {
-		rawInt := paramInt[0]
-		if int_, err2 := strconv.Atoi(rawInt); err2 == nil {
-			tmp2 := int_
-			tmp1 := &tmp2
-			rctx.Int = tmp1
-		} else {
-			err = goa.MergeErrors(err, goa.InvalidParamTypeError("int", rawInt, "integer"))
-		}
+	unionResult = `
+// ListBottleResult is the result of the bottles list action, it is implemented by the types of the
+// action success responses.
+type ListBottleResult interface {
+	listBottleResultMarker()
+}
+
+func (*GoaBottle) listBottleResultMarker() {}
+
+func (*GoaBox) listBottleResultMarker() {}
+
+// Respond sends the response whose type matches the type of r. A nil result indicates that the
+// action already sent the response.
+func (ctx *ListBottleContext) Respond(r ListBottleResult) error {
+	switch v := r.(type) {
+	case nil:
+		return nil
+	case *GoaBottle:
+		return ctx.OK(v)
+	case *GoaBox:
+		return ctx.Accepted(v)
+	default:
+		return fmt.Errorf("invalid list result type %T", r)
 	}
-	return &rctx, err
 }
 `
 
-	requiredContext = `
-type ListBottleContext struct {
-	context.Context
-	*goa.ResponseData
-	*goa.RequestData
-	Int int
+	longPollContext = `
+// OKChunk sends data encoded in JSON and followed by a newline as a chunk of the streamed 200
+// response and flushes the response so that the client receives the chunk right away.
+func (ctx *ListBottleContext) OKChunk(data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return ctx.writeChunk(append(b, '\n'))
+}
+
+// OKDone ends the streamed response by sending the {"done":true} sentinel line, the action must
+// not send any other chunk once OKDone is called. The method is not named Done so that the context
+// keeps implementing context.Context.
+func (ctx *ListBottleContext) OKDone() error {
+	return ctx.writeChunk([]byte("{\"done\":true}\n"))
+}
+
+// writeChunk writes the response status and headers on the first call, then writes b and flushes
+// the response.
+func (ctx *ListBottleContext) writeChunk(b []byte) error {
+	if !ctx.ResponseData.Written() {
+		ctx.ResponseData.Header().Set("Content-Type", "application/x-ndjson")
+		ctx.ResponseData.Header().Set("Cache-Control", "no-cache")
+		ctx.ResponseData.WriteHeader(200)
+	}
+	if _, err := ctx.ResponseData.Write(b); err != nil {
+		return err
+	}
+	if f, ok := ctx.ResponseData.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
 }
 `
 
-	requiredContextFactory = `
-func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
-	var err error
-	resp := goa.ContextResponse(ctx)
-	resp.Service = service
-	req := goa.ContextRequest(ctx)
-	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	paramInt := req.Params["int"]
-	if len(paramInt) == 0 {
-		err = goa.MergeErrors(err, goa.MissingParamError("int"))
-	} else {
-		rawInt := paramInt[0]
-		if int_, err2 := strconv.Atoi(rawInt); err2 == nil {
-			rctx.Int = int_
-		} else {
-			err = goa.MergeErrors(err, goa.InvalidParamTypeError("int", rawInt, "integer"))
+	auditMount = `
+		// Record the audit event once the action completes successfully
+		snapshot := AuditUpdateBottleBefore(rctx)
+		if err := ctrl.Update(rctx); err != nil {
+			return err
 		}
+		AuditHandler(ctx, AuditUpdateBottleAfter(rctx, snapshot))
+		return nil
 	}
-	return &rctx, err
+`
+
+	methodNotAllowedMount = `
+	h = handleMethodNotAllowed("GET, HEAD, PATCH", "application/merge-patch+json")
+	service.Mux.Handle("DELETE", "/bottles/:id", chainMuxHandler(service, ctrl.MuxHandler("method not allowed", h, nil), chain))
+	service.Mux.Handle("POST", "/bottles/:id", chainMuxHandler(service, ctrl.MuxHandler("method not allowed", h, nil), chain))
+	service.Mux.Handle("PUT", "/bottles/:id", chainMuxHandler(service, ctrl.MuxHandler("method not allowed", h, nil), chain))
 }
 `
 
-	customContext = `
-type ListBottleContext struct {
-	context.Context
-	*goa.ResponseData
-	*goa.RequestData
-	Custom *int
+	staticAssetsMount = `
+	h = handleStaticAssets("/ui", "public", "no-cache")
+	service.Mux.Handle("GET", "/ui/*filepath", chainMuxHandler(service, ctrl.MuxHandler("static assets", h, nil), chain))
+	service.Mux.Handle("HEAD", "/ui/*filepath", chainMuxHandler(service, ctrl.MuxHandler("static assets", h, nil), chain))
+	service.LogInfo("mount", "ctrl", "UI", "assets", "public", "route", "GET /ui/*filepath")
 }
 `
 
-	customContextFactory = `
-func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
-	var err error
-	resp := goa.ContextResponse(ctx)
-	resp.Service = service
-	req := goa.ContextRequest(ctx)
-	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	paramInt := req.Params["int"]
-	if len(paramInt) > 0 {
-		rawInt := paramInt[0]
-		if int_, err2 := strconv.Atoi(rawInt); err2 == nil {
-			tmp2 := int_
-			tmp1 := &tmp2
-			rctx.Custom = tmp1
-		} else {
-			err = goa.MergeErrors(err, goa.InvalidParamTypeError("int", rawInt, "integer"))
+	unionResultMount = `
+		res, err := ctrl.Show(rctx)
+		if err != nil {
+			return err
 		}
+		return rctx.Respond(res)
 	}
-	return &rctx, err
+`
+
+	flattenLinksCode = `func (mt *Bottle) MarshalJSON() ([]byte, error) {
+	type noMethods Bottle
+	v := noMethods(*mt)
+	links := v.Links
+	v.Links = nil
+	b, err := json.Marshal(&v)
+	if err != nil || links == nil {
+		return b, err
+	}
+	lb, err := json.Marshal(links)
+	if err != nil {
+		return nil, err
+	}
+	return mergeJSONObjects(b, lb), nil
+}
+
+// UnmarshalJSON deserializes a Bottle media type whose link fields are at the top level
+// of the JSON object.
+func (mt *Bottle) UnmarshalJSON(data []byte) error {
+	type noMethods Bottle
+	var v noMethods
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var links BottleLinks
+	if err := json.Unmarshal(data, &links); err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(links, BottleLinks{}) {
+		v.Links = &links
+	}
+	*mt = Bottle(v)
+	return nil
 }
 `
 
-	payloadContext = `
-type ListBottleContext struct {
-	context.Context
-	*goa.ResponseData
-	*goa.RequestData
-	Payload ListBottlePayload
+	mergePatchMethod = `// MergePatch returns a copy of ut with the changes of the JSON merge patch (RFC 7396) applied: the
+// attributes absent from patch keep their value and the attributes set to null are removed.
+func (ut *Wine) MergePatch(patch map[string]json.RawMessage) (*Wine, error) {
+	var res Wine
+	if err := goa.MergePatch(&res, ut, patch); err != nil {
+		return nil, goa.ErrBadRequest(err)
+	}
+	if err := res.Validate(); err != nil {
+		return nil, err
+	}
+	return &res, nil
 }
 `
 
-	payloadContextFactory = `
-func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
-	var err error
-	resp := goa.ContextResponse(ctx)
-	resp.Service = service
-	req := goa.ContextRequest(ctx)
-	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
-	return &rctx, err
+	sqlScanner = `// Scan implements the database/sql Scanner interface, it decodes the JSON read from jsonb
+// columns.
+func (ut *Address) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Address", src)
+	}
+	return json.Unmarshal(data, ut)
+}
+
+// Value implements the database/sql/driver Valuer interface, it encodes the instance as JSON.
+func (ut Address) Value() (driver.Value, error) {
+	return json.Marshal(ut)
 }
 `
-	payloadObjContext = `
-type ListBottleContext struct {
-	context.Context
-	*goa.ResponseData
-	*goa.RequestData
-	Payload *ListBottlePayload
+
+	strEnumCoerce = `		if !IsValidListBottleContextParam(rawParam) {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `param` + "`" + `, rawParam, []interface{}{ListBottleContextParamRed, ListBottleContextParamWhite}))
+		}
+`
+
+	enumConstants = `// WineColor is the type of the values of the Wine color attribute.
+type WineColor = string
+
+// Values allowed for WineColor.
+const (
+	WineColorRed WineColor = "red"
+	WineColorWhite WineColor = "white"
+)
+
+// IsValidWineColor returns true if v is one of the values allowed for WineColor.
+func IsValidWineColor(v WineColor) bool {
+	switch v {
+	case WineColorRed, WineColorWhite:
+		return true
+	}
+	return false
 }
 `
 
-	payloadObjUnmarshal = `
-func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
-	payload := &listBottlePayload{}
-	if err := service.DecodeRequest(req, payload); err != nil {
+	payloadDiscriminatedUnmarshal = `
+func unmarshalCreatePetPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	var probe struct {
+		Type string ` + "`" + `json:"type"` + "`" + `
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return err
+	}
+	switch probe.Type {
+	case "cat":
+		payload := &cat{}
+		if err := json.Unmarshal(body, payload); err != nil {
+			return err
+		}
+		goa.ContextRequest(ctx).Payload = payload.Publicize()
+	case "dog":
+		payload := &dog{}
+		if err := json.Unmarshal(body, payload); err != nil {
+			return err
+		}
+		goa.ContextRequest(ctx).Payload = payload.Publicize()
+	default:
+		return goa.InvalidDiscriminatorError("type", probe.Type, []string{"cat", "dog"})
+	}
+	return nil
+}
+`
+
+	payloadFormDataUnmarshal = `
+func unmarshalUploadBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	if err := req.ParseMultipartForm(32 << 20); err != nil {
+		return goa.ErrBadRequest(err)
+	}
+	payload := &UploadBottlePayload{}
+	var err error
+	if rawCaption := req.FormValue("caption"); rawCaption != "" {
+		payload.Caption = &rawCaption
+	}
+	if file, header, err2 := req.FormFile("label"); err2 == nil {
+		file.Close()
+		payload.Label = header
+	} else if err2 != http.ErrMissingFile {
+		err = goa.MergeErrors(err, goa.ErrBadRequest(err2))
+	}
+	if err != nil {
 		return err
 	}
 	if err := payload.Validate(); err != nil {
-		// Initialize payload with private data structure so it can be logged
 		goa.ContextRequest(ctx).Payload = payload
 		return err
 	}
-	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	goa.ContextRequest(ctx).Payload = payload
 	return nil
 }
 `
-	payloadNoValidationsObjUnmarshal = `
-func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
-	payload := &listBottlePayload{}
-	if err := service.DecodeRequest(req, payload); err != nil {
+
+	payloadMultipartIngestUnmarshal = `
+func unmarshalIngestBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return goa.ErrBadRequest(err)
+	}
+	payload := &ingestBottlePayload{}
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return goa.ErrBadRequest(err)
+		}
+		var part string
+		err = service.Decoder.Decode(&part, p, p.Header.Get("Content-Type"))
+		p.Close()
+		if err != nil {
+			return goa.ErrBadRequest(fmt.Errorf("failed to decode part %d: %s", len(payload.Parts)+1, err))
+		}
+		payload.Parts = append(payload.Parts, part)
+	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
 		return err
 	}
 	goa.ContextRequest(ctx).Payload = payload.Publicize()
@@ -1385,7 +3676,7 @@ type PublicController interface {
 }
 `
 
-	fileServerOptionsHandler = `service.Mux.Handle("OPTIONS", "/public/*filepath", ctrl.MuxHandler("preflight", handlePublicOrigin(cors.HandlePreflight()), nil))`
+	fileServerOptionsHandler = `service.Mux.Handle("OPTIONS", "/public/*filepath", chainMuxHandler(service, ctrl.MuxHandler("preflight", handlePublicOrigin(cors.HandlePreflight()), nil), chain))`
 
 	simpleController = `// BottlesController is the controller interface for the Bottles actions.
 type BottlesController interface {
@@ -1395,8 +3686,9 @@ type BottlesController interface {
 `
 
 	originsIntegration = `}
+	h = handleRecover(h)
 	h = handleBottlesOrigin(h)
-	service.Mux.Handle`
+	service.Mux.Handle("GET", "/accounts", chainMuxHandler(service, ctrl.MuxHandler("List", h, nil), chain))`
 
 	originsHandler = `// handleBottlesOrigin applies the CORS response headers corresponding to the origin.
 func handleBottlesOrigin(h goa.Handler) goa.Handler {
@@ -1481,7 +3773,8 @@ func handleBottlesOrigin(h goa.Handler) goa.Handler {
 
 	encoderController = `
 // MountBottlesController "mounts" a Bottles resource controller on the given service.
-func MountBottlesController(service *goa.Service, ctrl BottlesController) {
+// The chain middleware wrap the handler of each route, the first middleware is the outermost.
+func MountBottlesController(service *goa.Service, ctrl BottlesController, chain ...func(http.Handler) http.Handler) {
 	initService(service)
 	var h goa.Handler
 
@@ -1497,12 +3790,35 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 		}
 		return ctrl.List(rctx)
 	}
-	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("List", h, nil))
+	h = handleRecover(h)
+	service.Mux.Handle("GET", "/accounts/:accountID/bottles", chainMuxHandler(service, ctrl.MuxHandler("List", h, nil), chain))
 	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
+	service.Mux.Handle("HEAD", "/accounts/:accountID/bottles", chainMuxHandler(service, headHandler(ctrl.MuxHandler("List", h, nil)), chain))
+	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "HEAD /accounts/:accountID/bottles")
+}
+`
+
+	headHandler = `
+// headHandler returns a handler that serves HEAD requests with the GET handler h: the response
+// status and headers written by h are sent and the response body is discarded.
+func headHandler(h goa.MuxHandler) goa.MuxHandler {
+	return func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+		h(headResponseWriter{rw}, req, params)
+	}
+}
+
+// headResponseWriter is the response writer given to the GET handlers serving HEAD requests.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write discards the response body.
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
 }
 `
 
-	simpleMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController) {
+	simpleMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController, chain ...func(http.Handler) http.Handler) {
 	initService(service)
 	var h goa.Handler
 
@@ -1518,11 +3834,147 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 		}
 		return ctrl.List(rctx)
 	}
-	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("List", h, nil))
+	h = handleRecover(h)
+	service.Mux.Handle("GET", "/accounts/:accountID/bottles", chainMuxHandler(service, ctrl.MuxHandler("List", h, nil), chain))
 	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
+	service.Mux.Handle("HEAD", "/accounts/:accountID/bottles", chainMuxHandler(service, headHandler(ctrl.MuxHandler("List", h, nil)), chain))
+	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "HEAD /accounts/:accountID/bottles")
+}
+`
+
+	describedController = `// BottlesController is the controller interface for the Bottles actions.
+type BottlesController interface {
+	goa.Muxer
+	// Show: Show a bottle
+	//
+	// Retrieve the bottle with the given id.
+	//
+	// Tags: bottle, cellar
+	Show(*ShowBottleContext) error
+}
+`
+
+	standaloneService = `// BottlesService is a http.Handler that serves the Bottles resource actions so that
+// the controller can be mounted on any net/http server without running the goa service, e.g.:
+//
+//	http.Handle("/", NewBottlesService(service, ctrl))
+type BottlesService struct {
+	service *goa.Service
+}
+
+// NewBottlesService mounts the controller on the service mux and returns the handler dispatching
+// the requests to it. The service provides the encoders, decoders, middleware and mux used to
+// handle the requests, it must be the service that created the controller. chain lists the
+// middleware wrapping the handler of each route.
+func NewBottlesService(service *goa.Service, ctrl BottlesController, chain ...func(http.Handler) http.Handler) *BottlesService {
+	MountBottlesController(service, ctrl, chain...)
+	return &BottlesService{service: service}
+}
+
+// ServeHTTP dispatches the requests to the service mux.
+func (s *BottlesService) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.service.Mux.ServeHTTP(rw, req)
+}
+`
+
+	expvarMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController, chain ...func(http.Handler) http.Handler) {
+	initService(service)
+	mountExpvar(service)
+	var h goa.Handler
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewListBottleContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.List(rctx)
+	}
+	h = handleRecover(h)
+	h = handleExpvar("Bottles.List", h)
+	service.Mux.Handle("GET", "/accounts/:accountID/bottles", chainMuxHandler(service, ctrl.MuxHandler("List", h, nil), chain))
+`
+
+	sunsetHandler = `// sunsetDate is the date after which the API version is no longer served.
+var sunsetDate = time.Unix(1577836800, 0).UTC()
+
+// handleSunset sets the Deprecation and Sunset headers on the responses written by h.
+// Requests made after the sunset date get a 410 Gone response.
+func handleSunset(h goa.Handler) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		rw.Header().Set("Deprecation", "true")
+		rw.Header().Set("Sunset", sunsetDate.Format(http.TimeFormat))
+		if time.Now().Before(sunsetDate) {
+			return h(ctx, rw, req)
+		}
+		rw.WriteHeader(http.StatusGone)
+		return nil
+	}
+}
+`
+
+	sunsetMount = `		return ctrl.List(rctx)
+	}
+	h = handleRecover(h)
+	h = handleSunset(h)
+	service.Mux.Handle("GET", "/accounts/:accountID/bottles", chainMuxHandler(service, ctrl.MuxHandler("List", h, nil), chain))
+`
+
+	compressMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController, chain ...func(http.Handler) http.Handler) {
+	initService(service)
+	service.SetCompressor("gzip", goa.NewGzipCompressor)
+	service.SetCompressor("zstd", func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) })
+	var h goa.Handler
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewListBottleContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.List(rctx)
+	}
+	h = handleRecover(h)
+	h = compressHandler(service, h)
+	service.Mux.Handle("GET", "/accounts/:accountID/bottles", chainMuxHandler(service, ctrl.MuxHandler("List", h, nil), chain))
+`
+
+	webSocketController = `// ChatWebSocketController is the controller interface for the Chat WebSocket
+// actions.
+type ChatWebSocketController interface {
+	Echo(*EchoChatWebSocketContext) error
+}
+
+// ChatController is the controller interface for the Chat actions.
+type ChatController interface {
+	goa.Muxer
+	ChatWebSocketController
 }
 `
 
+	webSocketMount = `		// Upgrade the connection, the websocket handler writes the error response if the handshake fails
+		resp := goa.ContextResponse(ctx)
+		resp.Status = http.StatusSwitchingProtocols
+		websocket.Handler(func(conn *websocket.Conn) {
+			// The connection is hijacked, errors can only be logged
+			if err := ctrl.Echo(&EchoChatWebSocketContext{EchoChatContext: rctx, Conn: conn}); err != nil {
+				goa.LogError(ctx, "websocket", "action", "Echo", "err", err)
+			}
+		}).ServeHTTP(resp.ResponseWriter, req)
+		return nil
+	}
+	h = handleRecover(h)
+	service.Mux.Handle("GET", "/chat/echo", chainMuxHandler(service, ctrl.MuxHandler("Echo", h, nil), chain))
+`
+
 	multiController = `// BottlesController is the controller interface for the Bottles actions.
 type BottlesController interface {
 	goa.Muxer
@@ -1531,7 +3983,7 @@ type BottlesController interface {
 }
 `
 
-	multiMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController) {
+	multiMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController, chain ...func(http.Handler) http.Handler) {
 	initService(service)
 	var h goa.Handler
 
@@ -1547,8 +3999,11 @@ type BottlesController interface {
 		}
 		return ctrl.List(rctx)
 	}
-	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("List", h, nil))
+	h = handleRecover(h)
+	service.Mux.Handle("GET", "/accounts/:accountID/bottles", chainMuxHandler(service, ctrl.MuxHandler("List", h, nil), chain))
 	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
+	service.Mux.Handle("HEAD", "/accounts/:accountID/bottles", chainMuxHandler(service, headHandler(ctrl.MuxHandler("List", h, nil)), chain))
+	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "HEAD /accounts/:accountID/bottles")
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -1562,8 +4017,11 @@ type BottlesController interface {
 		}
 		return ctrl.Show(rctx)
 	}
-	service.Mux.Handle("GET", "/accounts/:accountID/bottles/:id", ctrl.MuxHandler("Show", h, nil))
+	h = handleRecover(h)
+	service.Mux.Handle("GET", "/accounts/:accountID/bottles/:id", chainMuxHandler(service, ctrl.MuxHandler("Show", h, nil), chain))
 	service.LogInfo("mount", "ctrl", "Bottles", "action", "Show", "route", "GET /accounts/:accountID/bottles/:id")
+	service.Mux.Handle("HEAD", "/accounts/:accountID/bottles/:id", chainMuxHandler(service, headHandler(ctrl.MuxHandler("Show", h, nil)), chain))
+	service.LogInfo("mount", "ctrl", "Bottles", "action", "Show", "route", "HEAD /accounts/:accountID/bottles/:id")
 }
 `
 
@@ -1575,5 +4033,26 @@ type BottlesController interface {
 	noParamHref = `func BottleHref() string {
 	return "/bottles"
 }
+`
+
+	optionalSegmentHref = `func BottleHref(id interface{}, opts ...map[string]interface{}) string {
+	options := make(map[string]interface{})
+	for _, o := range opts {
+		for k, v := range o {
+			options[k] = v
+		}
+	}
+	var href string
+	if options["accountID"] != nil {
+		paramaccountID := strings.TrimLeftFunc(fmt.Sprintf("%v", options["accountID"]), func(r rune) bool { return r == '/' })
+		href += fmt.Sprintf("/accounts/%v", paramaccountID)
+	}
+	paramid := strings.TrimLeftFunc(fmt.Sprintf("%v", id), func(r rune) bool { return r == '/' })
+	href += fmt.Sprintf("/bottles/%v", paramid)
+	if href == "" {
+		return "/"
+	}
+	return href
+}
 `
 )
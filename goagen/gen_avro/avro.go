@@ -0,0 +1,135 @@
+package genavro
+
+import (
+	"encoding/json"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// Record represents an Avro record schema.
+	Record struct {
+		Type      string   `json:"type"`
+		Name      string   `json:"name"`
+		Namespace string   `json:"namespace,omitempty"`
+		Doc       string   `json:"doc,omitempty"`
+		Fields    []*Field `json:"fields"`
+	}
+
+	// Field represents a single field of an Avro record.
+	Field struct {
+		Name string      `json:"name"`
+		Doc  string      `json:"doc,omitempty"`
+		Type interface{} `json:"type"`
+		// Default is the field default value, optional fields default to null.
+		Default json.RawMessage `json:"default,omitempty"`
+	}
+
+	// builder keeps track of the records already defined in a schema so that subsequent
+	// occurrences refer to them by name as mandated by the Avro specification.
+	builder struct {
+		namespace string
+		defined   map[string]bool
+	}
+)
+
+// MediaTypeSchema returns the Avro record schema that describes the given media type.
+// It returns nil if the media type is not an object.
+func MediaTypeSchema(api *design.APIDefinition, mt *design.MediaTypeDefinition) *Record {
+	if !mt.IsObject() {
+		return nil
+	}
+	b := &builder{namespace: Namespace(api), defined: make(map[string]bool)}
+	r, _ := b.userTypeSchema(mt.UserTypeDefinition).(*Record)
+	return r
+}
+
+// Namespace returns the Avro namespace used for the records generated for the given API.
+func Namespace(api *design.APIDefinition) string {
+	return codegen.SnakeCase(codegen.Goify(api.Name, true))
+}
+
+// typeSchema returns the Avro type corresponding to the type of the given attribute.
+func (b *builder) typeSchema(name string, att *design.AttributeDefinition) interface{} {
+	switch t := att.Type.(type) {
+	case design.Primitive:
+		return primitiveSchema(t)
+	case *design.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": b.typeSchema(name+"Item", t.ElemType),
+		}
+	case *design.Hash:
+		return map[string]interface{}{
+			"type":   "map",
+			"values": b.typeSchema(name+"Value", t.ElemType),
+		}
+	case design.Object:
+		return b.recordSchema(codegen.Goify(name, true), "", att)
+	case *design.MediaTypeDefinition:
+		return b.userTypeSchema(t.UserTypeDefinition)
+	case *design.UserTypeDefinition:
+		return b.userTypeSchema(t)
+	}
+	panic("unknown type") // bug
+}
+
+// userTypeSchema returns the Avro type corresponding to the given user type, it returns the
+// record name if the corresponding record was already defined.
+func (b *builder) userTypeSchema(ut *design.UserTypeDefinition) interface{} {
+	name := codegen.Goify(ut.TypeName, true)
+	if !ut.IsObject() {
+		return b.typeSchema(name, ut.AttributeDefinition)
+	}
+	if b.defined[name] {
+		return name
+	}
+	return b.recordSchema(name, ut.Description, ut.AttributeDefinition)
+}
+
+// recordSchema builds the Avro record with the given name from the given object attribute.
+// Optional fields are represented as unions with null.
+func (b *builder) recordSchema(name, doc string, att *design.AttributeDefinition) *Record {
+	b.defined[name] = true
+	r := &Record{Type: "record", Name: name, Namespace: b.namespace, Doc: doc, Fields: []*Field{}}
+	att.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		f := &Field{Name: n, Doc: at.Description, Type: b.typeSchema(name+codegen.Goify(n, true), at)}
+		if !att.IsRequired(n) {
+			if _, ok := f.Type.([]interface{}); !ok {
+				f.Type = []interface{}{"null", f.Type}
+			}
+			f.Default = json.RawMessage("null")
+		}
+		r.Fields = append(r.Fields, f)
+		return nil
+	})
+	return r
+}
+
+// primitiveSchema returns the Avro type corresponding to the given primitive type.
+func primitiveSchema(p design.Primitive) interface{} {
+	switch p.Kind() {
+	case design.BooleanKind:
+		return "boolean"
+	case design.IntegerKind, design.UintKind:
+		return "long"
+	case design.Uint8Kind, design.Uint16Kind:
+		return "int"
+	case design.Uint32Kind:
+		return "long"
+	case design.NumberKind:
+		return "double"
+	case design.StringKind, design.IPKind, design.CIDRKind, design.LatLonKind, design.SemVerKind, design.BigIntKind:
+		return "string"
+	case design.DateTimeKind:
+		return map[string]interface{}{"type": "long", "logicalType": "timestamp-micros"}
+	case design.UUIDKind:
+		return map[string]interface{}{"type": "string", "logicalType": "uuid"}
+	case design.FileKind:
+		return "bytes"
+	case design.AnyKind:
+		return []interface{}{"null", "boolean", "long", "double", "string"}
+	}
+	panic("unknown primitive type") // bug
+}
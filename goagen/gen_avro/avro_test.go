@@ -0,0 +1,96 @@
+package genavro_test
+
+import (
+	"encoding/json"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_avro"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MediaTypeSchema", func() {
+	var mt *MediaTypeDefinition
+	var schema *genavro.Record
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("test api", nil)
+		mt = MediaType("application/vnd.bottle+json", func() {
+			TypeName("Bottle")
+			Description("A bottle of wine")
+			Attributes(func() {
+				Attribute("name", String)
+				Attribute("vintage", Integer)
+				Attribute("rating", Number)
+				Attribute("sweet", Boolean)
+				Attribute("created_at", DateTime)
+				Attribute("tags", ArrayOf(String))
+				Attribute("label", FileType)
+				Attribute("winery", func() {
+					Attribute("name", String)
+					Required("name")
+				})
+				Required("name", "vintage")
+			})
+			View("default", func() {
+				Attribute("name")
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		schema = genavro.MediaTypeSchema(Design, mt)
+	})
+
+	It("produces a record", func() {
+		Ω(schema).ShouldNot(BeNil())
+		Ω(schema.Type).Should(Equal("record"))
+		Ω(schema.Name).Should(Equal("Bottle"))
+		Ω(schema.Namespace).Should(Equal("testapi"))
+		Ω(schema.Doc).Should(Equal("A bottle of wine"))
+		Ω(schema.Fields).Should(HaveLen(8))
+	})
+
+	It("maps the attribute types", func() {
+		fields := make(map[string]interface{})
+		for _, f := range schema.Fields {
+			fields[f.Name] = f.Type
+		}
+		Ω(fields["name"]).Should(Equal("string"))
+		Ω(fields["vintage"]).Should(Equal("long"))
+		Ω(fields["rating"]).Should(Equal([]interface{}{"null", "double"}))
+		Ω(fields["sweet"]).Should(Equal([]interface{}{"null", "boolean"}))
+		Ω(fields["created_at"]).Should(Equal([]interface{}{"null",
+			map[string]interface{}{"type": "long", "logicalType": "timestamp-micros"}}))
+		Ω(fields["tags"]).Should(Equal([]interface{}{"null",
+			map[string]interface{}{"type": "array", "items": "string"}}))
+		Ω(fields["label"]).Should(Equal([]interface{}{"null", "bytes"}))
+	})
+
+	It("produces nested records for object attributes", func() {
+		var winery *genavro.Field
+		for _, f := range schema.Fields {
+			if f.Name == "winery" {
+				winery = f
+			}
+		}
+		Ω(winery).ShouldNot(BeNil())
+		Ω(winery.Type).Should(HaveLen(2))
+		r, ok := winery.Type.([]interface{})[1].(*genavro.Record)
+		Ω(ok).Should(BeTrue())
+		Ω(r.Name).Should(Equal("BottleWinery"))
+		Ω(r.Fields).Should(HaveLen(1))
+		Ω(r.Fields[0].Type).Should(Equal("string"))
+	})
+
+	It("defaults optional fields to null", func() {
+		js, err := json.Marshal(schema)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(js)).Should(ContainSubstring(`{"name":"rating","type":["null","double"],"default":null}`))
+		Ω(string(js)).Should(ContainSubstring(`{"name":"vintage","type":"long"}`))
+	})
+})
@@ -0,0 +1,7 @@
+/*
+Package genavro provides a generator for Apache Avro (https://avro.apache.org) schemas.
+The generator creates one .avsc file per media type defined in the API design under the "avro"
+directory. The schemas make it possible to publish media type payloads on systems that rely on Avro
+for serialization such as Kafka.
+*/
+package genavro
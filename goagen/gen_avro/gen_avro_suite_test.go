@@ -0,0 +1,13 @@
+package genavro_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenAvro(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenAvro Suite")
+}
@@ -0,0 +1,85 @@
+package genavro
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the Avro schema generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("avro", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces one Avro schema file per media type.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	avroDir := filepath.Join(g.OutDir, "avro")
+	os.RemoveAll(avroDir)
+	if err = os.MkdirAll(avroDir, 0755); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, avroDir)
+	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		s := MediaTypeSchema(g.API, mt)
+		if s == nil {
+			return nil
+		}
+		js, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return err
+		}
+		filename := filepath.Join(avroDir, codegen.SnakeCase(s.Name)+".avsc")
+		if err := ioutil.WriteFile(filename, js, 0644); err != nil {
+			return err
+		}
+		g.genfiles = append(g.genfiles, filename)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
@@ -0,0 +1,69 @@
+package genavro_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_avro"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("avrotest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genavro.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a media type", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			apidsl.MediaType("application/vnd.bottle+json", func() {
+				apidsl.TypeName("Bottle")
+				apidsl.Attributes(func() {
+					apidsl.Attribute("name", design.String)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("name")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("generates the schema file", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(ContainElement(filepath.Join(testPkg.Abs(), "avro", "bottle.avsc")))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "avro", "bottle.avsc"))
+			Ω(err).ShouldNot(HaveOccurred())
+			var r genavro.Record
+			Ω(json.Unmarshal(content, &r)).ShouldNot(HaveOccurred())
+			Ω(r.Name).Should(Equal("Bottle"))
+			Ω(r.Fields).Should(HaveLen(1))
+		})
+	})
+})
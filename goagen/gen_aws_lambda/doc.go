@@ -0,0 +1,8 @@
+/*
+Package genawslambda provides a generator for the AWS Lambda function handler of goa services.
+The generator creates a lambda_handler.go file in the service main package that mounts the
+controllers of all the API resources and adapts the service mux to the API Gateway proxy events
+using the github.com/awslabs/aws-lambda-go-api-proxy/httpadapter package. The generated
+StartLambda function starts the Lambda function with github.com/aws/aws-lambda-go/lambda.
+*/
+package genawslambda
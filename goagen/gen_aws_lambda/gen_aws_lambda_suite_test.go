@@ -0,0 +1,13 @@
+package genawslambda_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenAWSLambda(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenAWSLambda Suite")
+}
@@ -0,0 +1,131 @@
+package genawslambda
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the AWS Lambda function handler code generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Target   string                // Name of generated "app" package
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, target, ver string
+
+	set := flag.NewFlagSet("aws_lambda", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&target, "pkg", "app", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	target = codegen.Goify(target, false)
+	g := &Generator{OutDir: outDir, Target: target, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the lambda_handler.go file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Target == "" {
+		g.Target = "app"
+	}
+
+	outPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	handlerFile := filepath.Join(g.OutDir, "lambda_handler.go")
+	os.Remove(handlerFile)
+	g.genfiles = append(g.genfiles, handlerFile)
+	file, err := codegen.SourceFileFor(handlerFile)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("github.com/aws/aws-lambda-go/events"),
+		codegen.SimpleImport("github.com/aws/aws-lambda-go/lambda"),
+		codegen.SimpleImport("github.com/awslabs/aws-lambda-go-api-proxy/httpadapter"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport(path.Join(filepath.ToSlash(outPkg), g.Target)),
+	}
+	title := fmt.Sprintf("%s: AWS Lambda Function Handler", g.API.Context())
+	if err = file.WriteHeader(title, "main", imports); err != nil {
+		return nil, err
+	}
+	var resources []*design.ResourceDefinition
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		resources = append(resources, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{
+		"targetPkg": func() string { return g.Target },
+	}
+	if err = file.ExecuteTemplate("handler", handlerT, funcs, resources); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// handlerT generates the Lambda function handler.
+// template input: []*design.ResourceDefinition
+const handlerT = `
+// NewLambdaHandler mounts the controllers of all the API resources onto the service and returns
+// the Lambda function handler that converts the API Gateway proxy events to HTTP requests served
+// by the service mux and the HTTP responses back to proxy responses.
+func NewLambdaHandler(service *goa.Service) func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+{{ range . }}{{ $ctrlName := printf "%s%s" (goify .Name true) "Controller" }}	{{ targetPkg }}.Mount{{ $ctrlName }}(service, New{{ $ctrlName }}(service))
+{{ end }}	return httpadapter.New(service.Mux).ProxyWithContext
+}
+
+// StartLambda starts the Lambda function serving the API, call it from the main function of the
+// function binary in place of service.ListenAndServe:
+//
+//	func main() {
+//		StartLambda(goa.New("cellar"))
+//	}
+func StartLambda(service *goa.Service) {
+	lambda.Start(NewLambdaHandler(service))
+}
+`
@@ -0,0 +1,95 @@
+package genawslambda_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_aws_lambda"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("lambdatest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genawslambda.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a dummy API", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name:        "test api",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+			}
+		})
+
+		It("generates the Lambda function handler", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(1))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "lambda_handler.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("package main"))
+			Ω(string(content)).Should(ContainSubstring("lambda.Start(NewLambdaHandler(service))"))
+		})
+	})
+
+	Context("with resources", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			apidsl.Resource("account", func() {
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET(""))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("mounts all the controllers", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(1))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "lambda_handler.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(lambdaHandler))
+		})
+	})
+})
+
+const lambdaHandler = `func NewLambdaHandler(service *goa.Service) func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	app.MountAccountController(service, NewAccountController(service))
+	app.MountBottleController(service, NewBottleController(service))
+	return httpadapter.New(service.Mux).ProxyWithContext
+}`
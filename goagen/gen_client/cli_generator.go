@@ -26,6 +26,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		codegen.SimpleImport(cliPkg),
 		codegen.SimpleImport("github.com/spf13/cobra"),
 		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
+		codegen.SimpleImport("net"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 
@@ -127,6 +128,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 		codegen.SimpleImport(clientPkg),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.SimpleImport("net"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	if len(g.API.Resources) > 0 {
@@ -349,9 +351,9 @@ func flagTypeVal(a *design.AttributeDefinition, key string, field string) string
 	switch a.Type {
 	case design.Integer:
 		return `intFlagVal("` + key + `", ` + field + ")"
-	case design.String:
+	case design.String, design.SemVer:
 		return `stringFlagVal("` + key + `", ` + field + ")"
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.DateTime, design.IP, design.CIDR, design.LatLon, design.BigIntType, design.Any:
 		return "%s"
 	default:
 		return "&" + field
@@ -364,7 +366,7 @@ func flagTypeVal(a *design.AttributeDefinition, key string, field string) string
 // %s maps to specialTypeResult.Temps
 func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
 	switch a.Type {
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.DateTime, design.IP, design.CIDR, design.LatLon, design.BigIntType, design.Any:
 		return "*%s"
 	default:
 		return field
@@ -376,7 +378,9 @@ func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
 // %s maps to specialTypeResult.Temps
 func flagTypeArrayVal(a *design.AttributeDefinition, field string) string {
 	switch a.Type.ToArray().ElemType.Type {
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.DateTime, design.IP, design.CIDR, design.LatLon, design.BigIntType, design.Any:
+		return "%s"
+	case design.UintType, design.Uint8Type, design.Uint16Type, design.Uint32Type:
 		return "%s"
 	}
 	return field
@@ -437,6 +441,14 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 					typeHandler = "boolVal"
 				case design.UUID:
 					typeHandler = "uuidVal"
+				case design.IP:
+					typeHandler = "ipVal"
+				case design.CIDR:
+					typeHandler = "cidrVal"
+				case design.LatLon:
+					typeHandler = "latLonVal"
+				case design.BigIntType:
+					typeHandler = "bigIntVal"
 				case design.DateTime:
 					typeHandler = "timeVal"
 				case design.Any:
@@ -451,10 +463,26 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 					typeHandler = "boolArray"
 				case design.UUID:
 					typeHandler = "uuidArray"
+				case design.IP:
+					typeHandler = "ipArray"
+				case design.CIDR:
+					typeHandler = "cidrArray"
+				case design.LatLon:
+					typeHandler = "latLonArray"
+				case design.BigIntType:
+					typeHandler = "bigIntArray"
 				case design.DateTime:
 					typeHandler = "timeArray"
 				case design.Any:
 					typeHandler = "jsonArray"
+				case design.UintType:
+					typeHandler = "uintArray"
+				case design.Uint8Type:
+					typeHandler = "uint8Array"
+				case design.Uint16Type:
+					typeHandler = "uint16Array"
+				case design.Uint32Type:
+					typeHandler = "uint32Array"
 				}
 			}
 			if typeHandler != "" {
@@ -546,16 +574,26 @@ func flagType(att *design.AttributeDefinition) string {
 	switch att.Type.Kind() {
 	case design.IntegerKind:
 		return "Int"
+	case design.UintKind:
+		return "Uint"
+	case design.Uint8Kind:
+		return "Uint8"
+	case design.Uint16Kind:
+		return "Uint16"
+	case design.Uint32Kind:
+		return "Uint32"
 	case design.NumberKind:
 		return "String"
 	case design.BooleanKind:
 		return "String"
-	case design.StringKind:
+	case design.StringKind, design.SemVerKind:
 		return "String"
 	case design.DateTimeKind:
 		return "String"
 	case design.UUIDKind:
 		return "String"
+	case design.IPKind, design.CIDRKind, design.LatLonKind, design.BigIntKind:
+		return "String"
 	case design.AnyKind:
 		return "String"
 	case design.ArrayKind:
@@ -564,6 +602,8 @@ func flagType(att *design.AttributeDefinition) string {
 			return "StringSlice"
 		case design.BooleanKind:
 			return "StringSlice"
+		case design.UintKind, design.Uint8Kind, design.Uint16Kind, design.Uint32Kind:
+			return "StringSlice"
 		default:
 			return flagType(att.Type.(*design.Array).ElemType) + "Slice"
 		}
@@ -813,7 +853,7 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 	resp, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{ if .Action.Payload }}, {{/*
 	*/}}{{ if or .Action.Payload.Type.IsObject .Action.Payload.IsPrimitive }}&{{ end }}payload{{ else }}{{ end }}{{/*
 	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }}{{/*
-	*/}}{{ if .Action.Payload }}, cmd.ContentType{{ end }})
+	*/}}{{ if and .Action.Payload (not .Action.FormData) }}, cmd.ContentType{{ end }})
 	if err != nil {
 		goa.LogError(ctx, "failed", "err", err)
 		return err
@@ -968,6 +1008,158 @@ func uuidArray(ins []string) ([]uuid.UUID, error) {
 	return vals, nil
 }
 
+func ipVal(val string) (*net.IP, error) {
+	t := net.ParseIP(val)
+	if t == nil {
+		return nil, fmt.Errorf("invalid IP address %#v", val)
+	}
+	return &t, nil
+}
+
+func ipArray(ins []string) ([]net.IP, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []net.IP
+	for _, id := range ins {
+		val, err := ipVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func cidrVal(val string) (*goa.CIDR, error) {
+	t, err := goa.ParseCIDR(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func cidrArray(ins []string) ([]goa.CIDR, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []goa.CIDR
+	for _, id := range ins {
+		val, err := cidrVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func latLonVal(val string) (*goa.LatLon, error) {
+	t, err := goa.ParseLatLon(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func latLonArray(ins []string) ([]goa.LatLon, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []goa.LatLon
+	for _, id := range ins {
+		val, err := latLonVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func bigIntVal(val string) (*goa.BigInt, error) {
+	t, err := goa.ParseBigInt(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func bigIntArray(ins []string) ([]goa.BigInt, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []goa.BigInt
+	for _, id := range ins {
+		val, err := bigIntVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func uintArray(ins []string) ([]uint, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	vals := make([]uint, len(ins))
+	for i, in := range ins {
+		val, err := strconv.ParseUint(in, 10, 0)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = uint(val)
+	}
+	return vals, nil
+}
+
+func uint8Array(ins []string) ([]uint8, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	vals := make([]uint8, len(ins))
+	for i, in := range ins {
+		val, err := strconv.ParseUint(in, 10, 8)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = uint8(val)
+	}
+	return vals, nil
+}
+
+func uint16Array(ins []string) ([]uint16, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	vals := make([]uint16, len(ins))
+	for i, in := range ins {
+		val, err := strconv.ParseUint(in, 10, 16)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = uint16(val)
+	}
+	return vals, nil
+}
+
+func uint32Array(ins []string) ([]uint32, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	vals := make([]uint32, len(ins))
+	for i, in := range ins {
+		val, err := strconv.ParseUint(in, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = uint32(val)
+	}
+	return vals, nil
+}
+
 func float64Val(val string) (*float64, error) {
 	t, err := strconv.ParseFloat(val, 64)
 	if err != nil {
@@ -27,6 +27,7 @@ type Generator struct {
 	ToolDirName    string                // Name of tool directory where CLI main is generated once
 	Tool           string                // Name of CLI tool
 	NoTool         bool                  // Whether to skip tool generation
+	OrderedAttrs   string                // Order of the generated struct fields, "alpha" or "design"
 	genfiles       []string
 	encoders       []*genapp.EncoderTemplateData
 	decoders       []*genapp.EncoderTemplateData
@@ -36,8 +37,8 @@ type Generator struct {
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, target, toolDir, tool, ver string
-		notool                             bool
+		outDir, target, toolDir, tool, ver, ordered string
+		notool                                      bool
 	)
 	dtool := defaultToolName(design.Design)
 
@@ -49,6 +50,7 @@ func Generate() (files []string, err error) {
 	set.StringVar(&tool, "tool", dtool, "")
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&notool, "notool", false, "")
+	set.StringVar(&ordered, "ordered-attrs", "alpha", "")
 	set.Parse(os.Args[1:])
 
 	// First check compatibility
@@ -58,7 +60,7 @@ func Generate() (files []string, err error) {
 
 	// Now proceed
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, Target: target, ToolDirName: toolDir, Tool: tool, NoTool: notool, API: design.Design}
+	g := &Generator{OutDir: outDir, Target: target, ToolDirName: toolDir, Tool: tool, NoTool: notool, OrderedAttrs: ordered, API: design.Design}
 
 	return g.Generate()
 }
@@ -82,6 +84,9 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if g.Tool == "" {
 		g.Tool = defaultToolName(g.API)
 	}
+	if err = codegen.SetOrderedFields(g.OrderedAttrs); err != nil {
+		return nil, err
+	}
 
 	codegen.Reserved[g.Target] = true
 
@@ -230,9 +235,16 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 
 	// Setup codegen
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("crypto/tls"),
+		codegen.SimpleImport("errors"),
+		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
+		codegen.SimpleImport("net"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	for _, packagePath := range packagePaths {
@@ -294,6 +306,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("io"),
 		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("mime/multipart"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("net/url"),
 		codegen.SimpleImport("os"),
@@ -303,6 +316,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.SimpleImport("net"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	if err := file.WriteHeader("", g.Target, imports); err != nil {
@@ -399,6 +413,7 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		names         []string
 		queryParams   []*paramData
 		headers       []*paramData
+		formFields    []*paramData
 		signer        string
 		clientsTmpl   = template.Must(template.New("clients").Funcs(funcs).Parse(clientsTmpl))
 		requestsTmpl  = template.Must(template.New("requests").Funcs(funcs).Parse(requestsTmpl))
@@ -465,6 +480,9 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 	}
 	queryParams = initParams(action.QueryParams)
 	headers = initParams(action.Headers)
+	if action.FormData && action.Payload != nil {
+		formFields = initFormFields(action.Payload)
+	}
 	if action.Security != nil {
 		signer = codegen.Goify(action.Security.Scheme.SchemeName, true)
 	}
@@ -474,24 +492,28 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Description     string
 		Routes          []*design.RouteDefinition
 		HasPayload      bool
+		FormData        bool
 		Params          string
 		ParamNames      string
 		CanonicalScheme string
 		Signer          string
 		QueryParams     []*paramData
 		Headers         []*paramData
+		FormFields      []*paramData
 	}{
 		Name:            action.Name,
 		ResourceName:    action.Parent.Name,
 		Description:     action.Description,
 		Routes:          action.Routes,
 		HasPayload:      action.Payload != nil,
+		FormData:        action.FormData && action.Payload != nil,
 		Params:          strings.Join(params, ", "),
 		ParamNames:      strings.Join(names, ", "),
 		CanonicalScheme: action.CanonicalScheme(),
 		Signer:          signer,
 		QueryParams:     queryParams,
 		Headers:         headers,
+		FormFields:      formFields,
 	}
 	if action.WebSocket() {
 		return clientsWSTmpl.Execute(file, data)
@@ -502,6 +524,33 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 	return requestsTmpl.Execute(file, data)
 }
 
+// initFormFields returns the data used to write the fields of a multipart/form-data payload into
+// the parts of the request body.
+func initFormFields(payload *design.UserTypeDefinition) []*paramData {
+	obj := payload.Type.ToObject()
+	fields := make([]*paramData, 0, len(obj))
+	for n, att := range obj {
+		ref := "payload." + codegen.GoifyAtt(att, n, true)
+		field := &paramData{
+			Name:         n,
+			VarName:      ref,
+			ValueName:    ref,
+			Attribute:    att,
+			MustToString: att.Type.Kind() != design.StringKind,
+			IsFile:       att.Type.Kind() == design.FileKind,
+		}
+		if field.IsFile {
+			field.CheckNil = true
+		} else if payload.IsPrimitivePointer(n) {
+			field.ValueName = "*" + ref
+			field.CheckNil = true
+		}
+		fields = append(fields, field)
+	}
+	sort.Sort(byParamName(fields))
+	return fields
+}
+
 // fileServerMethod returns the name of the client method for downloading assets served by the given
 // file server.
 // Note: the implementation opts for generating good names rather than names that are guaranteed to
@@ -546,10 +595,13 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) er
 	title := fmt.Sprintf("%s: Application Media Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("math/big"),
+		codegen.SimpleImport("net"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	mtWr.WriteHeader(title, g.Target, imports)
@@ -589,9 +641,11 @@ func (g *Generator) generateUserTypes(pkgDir string) error {
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("math/big"),
 	}
 	utWr.WriteHeader(title, g.Target, imports)
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
@@ -695,9 +749,15 @@ func cmdFieldTypeString(t design.DataType, point bool) string {
 	if point && !t.IsArray() {
 		pointer = "*"
 	}
-	if t.Kind() == design.UUIDKind || t.Kind() == design.DateTimeKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind {
+	if t.Kind() == design.UUIDKind || t.Kind() == design.DateTimeKind || t.Kind() == design.IPKind || t.Kind() == design.CIDRKind || t.Kind() == design.LatLonKind || t.Kind() == design.BigIntKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind {
 		suffix = "string"
-	} else if isArrayOfType(t, design.UUIDKind, design.DateTimeKind, design.AnyKind, design.NumberKind, design.BooleanKind) {
+	} else if isArrayOfType(t, design.UUIDKind, design.DateTimeKind, design.IPKind, design.CIDRKind, design.LatLonKind, design.BigIntKind, design.AnyKind, design.NumberKind, design.BooleanKind) {
+		suffix = "[]string"
+	} else if t.Kind() == design.SemVerKind {
+		suffix = "string"
+	} else if isArrayOfType(t, design.SemVerKind) {
+		suffix = "[]string"
+	} else if isArrayOfType(t, design.UintKind, design.Uint8Kind, design.Uint16Kind, design.Uint32Kind) {
 		suffix = "[]string"
 	} else {
 		suffix = codegen.GoNativeType(t)
@@ -729,13 +789,15 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 		switch actual.Kind() {
 		case design.IntegerKind:
 			return fmt.Sprintf("%s := strconv.Itoa(%s)", target, name)
+		case design.UintKind, design.Uint8Kind, design.Uint16Kind, design.Uint32Kind:
+			return fmt.Sprintf("%s := strconv.FormatUint(uint64(%s), 10)", target, name)
 		case design.BooleanKind:
 			return fmt.Sprintf("%s := strconv.FormatBool(%s)", target, name)
 		case design.NumberKind:
 			return fmt.Sprintf("%s := strconv.FormatFloat(%s, 'f', -1, 64)", target, name)
-		case design.StringKind:
+		case design.StringKind, design.SemVerKind:
 			return fmt.Sprintf("%s := %s", target, name)
-		case design.DateTimeKind, design.UUIDKind:
+		case design.DateTimeKind, design.UUIDKind, design.IPKind, design.CIDRKind, design.LatLonKind, design.BigIntKind:
 			return fmt.Sprintf("%s := %s.String()", target, strings.Replace(name, "*", "", -1)) // remove pointer if present
 		case design.AnyKind:
 			return fmt.Sprintf("%s := fmt.Sprintf(\"%%v\", %s)", target, name)
@@ -823,6 +885,7 @@ type paramData struct {
 	ElemAttribute *design.AttributeDefinition
 	MustToString  bool
 	IsArray       bool
+	IsFile        bool
 	CheckNil      bool
 }
 
@@ -862,12 +925,12 @@ func {{ $funcName }}({{ pathParams . }}) string {
 	clientsTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ $desc := .Description }}{{/*
 */}}{{ if $desc }}{{ multiComment $desc }}{{ else }}{{/*
 */}}// {{ $funcName }} makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}
-func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params}},  {{ .Params }}{{ end }}{{ if .HasPayload }}, contentType string{{ end }}) (*http.Response, error) {
-	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }}{{ if .HasPayload }}, contentType{{ end }})
+func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params}},  {{ .Params }}{{ end }}{{ if and .HasPayload (not .FormData) }}, contentType string{{ end }}) (*http.Response, error) {
+	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }}{{ if and .HasPayload (not .FormData) }}, contentType{{ end }})
 	if err != nil {
 		return nil, err
 	}
-	return c.Client.Do(ctx, req)
+	return c.doWithContext(ctx, req)
 }
 `
 
@@ -915,7 +978,7 @@ func (c * Client) {{ .Name }}(ctx context.Context, {{ if .DirName }}filename, {{
 	if err != nil {
 		return 0, err
 	}
-	resp, err := c.Client.Do(ctx, req)
+	resp, err := c.doWithContext(ctx, req)
 	if err != nil {
 		return 0, err
 	}
@@ -940,8 +1003,32 @@ func (c * Client) {{ .Name }}(ctx context.Context, {{ if .DirName }}filename, {{
 
 	requestsTmpl = `{{ $funcName := goify (printf "New%s%sRequest" (title .Name) (title .ResourceName)) true }}{{/*
 */}}// {{ $funcName }} create the request corresponding to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource.
-func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if .HasPayload }}, contentType string{{ end }}) (*http.Request, error) {
-{{ if .HasPayload }}	var body bytes.Buffer
+func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if and .HasPayload (not .FormData) }}, contentType string{{ end }}) (*http.Request, error) {
+{{ if .FormData }}	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+{{ range .FormFields }}{{ if .CheckNil }}	if {{ .VarName }} != nil {
+{{ end }}{{ if .IsFile }}		f, err := {{ .VarName }}.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode body: %s", err)
+		}
+		part, err := w.CreateFormFile("{{ .Name }}", {{ .VarName }}.Filename)
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode body: %s", err)
+		}
+{{ else }}{{ if .MustToString }}{{ $tmp := tempvar }}	{{ toString .ValueName $tmp .Attribute }}
+	if err := w.WriteField("{{ .Name }}", {{ $tmp }}); err != nil {
+{{ else }}	if err := w.WriteField("{{ .Name }}", {{ .ValueName }}); err != nil {
+{{ end }}		return nil, fmt.Errorf("failed to encode body: %s", err)
+	}
+{{ end }}{{ if .CheckNil }}	}
+{{ end }}{{ end }}	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode body: %s", err)
+	}
+{{ else if .HasPayload }}	var body bytes.Buffer
 	if contentType == "" {
 		contentType = "*/*" // Use default encoder
 	}
@@ -983,7 +1070,8 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 		return nil, err
 	}
 {{ if or .Headers .HasPayload }}	header := req.Header
-{{ if .HasPayload }}	if contentType != "*/*" {
+{{ if .FormData }}	header.Set("Content-Type", w.FormDataContentType())
+{{ else if .HasPayload }}	if contentType != "*/*" {
 		header.Set("Content-Type", contentType)
 	}
 {{ end }}{{ range .Headers }}{{ if .CheckNil }}	if {{ .VarName }} != nil { {{ end }}{{ if .MustToString }}{{ $tmp := tempvar }}	{{ toString .ValueName $tmp .Attribute }}
@@ -997,12 +1085,95 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 }
 `
 
-	clientTmpl = `// Client is the {{ .API.Name }} service client.
+	clientTmpl = `// ErrTimeout is the error returned by the client methods when the request context deadline is
+// exceeded before the response is received.
+var ErrTimeout = errors.New("request timed out")
+
+// Client is the {{ .API.Name }} service client.
 type Client struct {
 	*goaclient.Client{{range $security := .API.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}
 	{{ goify $security.SchemeName true }}Signer goaclient.Signer{{ end }}{{ end }}
 	Encoder *goa.HTTPEncoder
 	Decoder *goa.HTTPDecoder
+
+	// httpClient is the HTTP client created by New{{ goify .API.Name true }}Client if any.
+	httpClient *http.Client
+}
+
+type (
+	// ClientOption configures the HTTP transport of the client created by
+	// New{{ goify .API.Name true }}Client.
+	ClientOption func(*clientOptions)
+
+	// clientOptions holds the transport settings.
+	clientOptions struct {
+		transport       http.RoundTripper
+		maxIdleConns    int
+		idleConnTimeout time.Duration
+		tlsConfig       *tls.Config
+	}
+)
+
+// WithMaxIdleConns sets the maximum number of idle connections kept in the pool, both in total
+// and per host.
+func WithMaxIdleConns(n int) ClientOption {
+	return func(o *clientOptions) {
+		o.maxIdleConns = n
+	}
+}
+
+// WithIdleConnTimeout sets the time after which idle connections are closed.
+func WithIdleConnTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.idleConnTimeout = d
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the service.
+func WithTLSConfig(c *tls.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.tlsConfig = c
+	}
+}
+
+// WithTransport sets the transport used to send the requests. The other options are ignored
+// when the transport is set.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(o *clientOptions) {
+		o.transport = rt
+	}
+}
+
+// New{{ goify .API.Name true }}Client instantiates a client that sends the requests to the service
+// located at baseURL, e.g. "https://api.example.com". The client uses a dedicated transport that
+// keeps up to 100 idle connections per host for 90 seconds unless configured otherwise with opts.
+// New{{ goify .API.Name true }}Client panics if baseURL is not a valid absolute URL.
+func New{{ goify .API.Name true }}Client(baseURL string, opts ...ClientOption) *Client {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		panic(fmt.Sprintf("invalid base URL %q", baseURL))
+	}
+	o := &clientOptions{maxIdleConns: 100, idleConnTimeout: 90 * time.Second}
+	for _, opt := range opts {
+		opt(o)
+	}
+	transport := o.transport
+	if transport == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.MaxIdleConns = o.maxIdleConns
+		t.MaxIdleConnsPerHost = o.maxIdleConns
+		t.IdleConnTimeout = o.idleConnTimeout
+		if o.tlsConfig != nil {
+			t.TLSClientConfig = o.tlsConfig
+		}
+		transport = t
+	}
+	hc := &http.Client{Transport: transport}
+	client := New(goaclient.HTTPClientDoer(hc))
+	client.httpClient = hc
+	client.Scheme = u.Scheme
+	client.Host = u.Host
+	return client
 }
 
 // New instantiates the client.
@@ -1035,6 +1206,37 @@ func New(c goaclient.Doer) *Client {
 func (c *Client) Set{{ $name }}(signer goaclient.Signer) {
 	c.{{ $name }} = signer
 }
-{{ end }}{{ end }}
+{{ end }}{{ end }}{{ if .API.LoadBalancing }}
+// NewGateway creates a client Doer that distributes the requests across the servers located at
+// the given base URLs using the {{ printf "%q" .API.LoadBalancing }} strategy. The gateway wraps
+// http.DefaultClient if c is nil. Create the client with New(gateway).
+func NewGateway(c goaclient.Doer, urls ...string) (*goaclient.Gateway, error) {
+	return goaclient.NewGateway(c, {{ printf "%q" .API.LoadBalancing }}, urls...)
+}
+{{ end }}
+// doWithContext sends the request using ctx as the request context so that cancellation and
+// deadlines set by the caller are honored. It returns ErrTimeout if the context deadline is
+// exceeded and does not send the request at all if ctx is already done.
+func (c *Client) doWithContext(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, contextError(err)
+	}
+	resp, err := c.Client.Do(ctx, req.WithContext(ctx))
+	if err != nil {
+		if cerr := ctx.Err(); cerr != nil {
+			return nil, contextError(cerr)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// contextError translates context.DeadlineExceeded into ErrTimeout.
+func contextError(err error) error {
+	if err == context.DeadlineExceeded {
+		return ErrTimeout
+	}
+	return err
+}
 `
 )
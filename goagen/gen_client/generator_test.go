@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_client"
 	"github.com/goadesign/goa/version"
@@ -135,6 +136,44 @@ var _ = Describe("Generate", func() {
 			Ω(strings.Count(string(content), "func ShowFooPath2(")).Should(Equal(1))
 		})
 
+		It("sends the requests using the caller context", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("return c.doWithContext(ctx, req)"))
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`var ErrTimeout = errors.New("request timed out")`))
+			Ω(content).Should(ContainSubstring("func (c *Client) doWithContext(ctx context.Context, req *http.Request) (*http.Response, error) {"))
+			Ω(content).Should(ContainSubstring("resp, err := c.Client.Do(ctx, req.WithContext(ctx))"))
+		})
+
+		It("generates the constructor configuring the HTTP transport", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("func NewTestapiClient(baseURL string, opts ...ClientOption) *Client {"))
+			Ω(content).Should(ContainSubstring("func WithMaxIdleConns(n int) ClientOption {"))
+			Ω(content).Should(ContainSubstring("func WithIdleConnTimeout(d time.Duration) ClientOption {"))
+			Ω(content).Should(ContainSubstring("func WithTLSConfig(c *tls.Config) ClientOption {"))
+			Ω(content).Should(ContainSubstring("func WithTransport(rt http.RoundTripper) ClientOption {"))
+			Ω(content).Should(ContainSubstring("o := &clientOptions{maxIdleConns: 100, idleConnTimeout: 90 * time.Second}"))
+		})
+
+		Context("with a load balancing strategy", func() {
+			BeforeEach(func() {
+				design.Design.LoadBalancing = "round-robin"
+			})
+
+			It("generates the NewGateway function", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(content).Should(ContainSubstring("func NewGateway(c goaclient.Doer, urls ...string) (*goaclient.Gateway, error) {"))
+				Ω(content).Should(ContainSubstring(`return goaclient.NewGateway(c, "round-robin", urls...)`))
+			})
+		})
+
 		Context("with a file server", func() {
 			BeforeEach(func() {
 				res := design.Design.Resources["foo"]
@@ -158,6 +197,53 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with a FormData payload", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			payload := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"caption": &design.AttributeDefinition{Type: design.String},
+						"image":   &design.AttributeDefinition{Type: design.FileType},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"caption"}},
+				},
+				TypeName: "UploadFooPayload",
+			}
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"upload": {
+								Name:     "upload",
+								Routes:   []*design.RouteDefinition{{Verb: "POST", Path: "/foo"}},
+								Payload:  payload,
+								FormData: true,
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			uploadAct := fooRes.Actions["upload"]
+			uploadAct.Parent = fooRes
+			uploadAct.Routes[0].Parent = uploadAct
+		})
+
+		It("encodes the payload as multipart/form-data", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("func (c *Client) NewUploadFooRequest(ctx context.Context, path string, payload *UploadFooPayload) (*http.Request, error) {"))
+			Ω(content).Should(ContainSubstring(`w.WriteField("caption", payload.Caption)`))
+			Ω(content).Should(ContainSubstring(`w.CreateFormFile("image", payload.Image.Filename)`))
+			Ω(content).Should(ContainSubstring(`header.Set("Content-Type", w.FormDataContentType())`))
+			Ω(content).ShouldNot(ContainSubstring("c.Encoder.Encode(payload"))
+		})
+	})
+
 	Context("with an action with security configured", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -0,0 +1,249 @@
+package gencompat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
+)
+
+// Change classifications of the compatibility matrix cells.
+const (
+	// Added indicates an action that does not exist in the previous version.
+	Added = "added"
+	// Unchanged indicates an action whose signature is the same in the previous version.
+	Unchanged = "unchanged"
+	// Modified indicates an action whose signature differs from the previous version.
+	Modified = "modified"
+	// Removed indicates an action that exists in the previous version only.
+	Removed = "removed"
+)
+
+type (
+	// Snapshot records the signatures of the actions of one API version.
+	Snapshot struct {
+		// Version is the API version.
+		Version string `json:"version"`
+		// Actions maps the "resource/action" keys to the action signatures.
+		Actions map[string]string `json:"actions"`
+	}
+
+	// Matrix is the compatibility matrix of a list of API versions.
+	Matrix struct {
+		// Versions lists the API versions in ascending order.
+		Versions []string
+		// Rows lists the matrix rows sorted by resource and action name.
+		Rows []*Row
+	}
+
+	// Row is a compatibility matrix row.
+	Row struct {
+		// Resource is the name of the resource.
+		Resource string
+		// Action is the name of the action.
+		Action string
+		// Changes lists the change classifications of the action indexed by version, the
+		// classification is empty if the action exists in neither the version nor the
+		// previous version.
+		Changes []string
+	}
+)
+
+// NewSnapshot returns the snapshot of the actions defined by the given API.
+func NewSnapshot(api *design.APIDefinition) *Snapshot {
+	s := &Snapshot{Version: api.Version, Actions: make(map[string]string)}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			headers := r.Headers.Merge(a.Headers)
+			if headers != nil && len(headers.Type.ToObject()) == 0 {
+				headers = nil
+			}
+			params := a.AllParams()
+			if params != nil && len(params.Type.ToObject()) == 0 {
+				params = nil
+			}
+			ctx := &genapp.ContextTemplateData{
+				Name:         codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + "Context",
+				ResourceName: r.Name,
+				ActionName:   a.Name,
+				Params:       params,
+				Payload:      a.Payload,
+				Headers:      headers,
+				Routes:       a.Routes,
+				Responses:    a.Responses,
+				API:          api,
+				Security:     a.Security,
+				WebSocket:    a.WebSocket(),
+			}
+			s.Actions[r.Name+"/"+a.Name] = Signature(ctx)
+			return nil
+		})
+	})
+	return s
+}
+
+// Signature computes the signature of the action described by the given context data. Two
+// versions of an action are compatible if they have the same signature.
+func Signature(ctx *genapp.ContextTemplateData) string {
+	var b bytes.Buffer
+	for _, r := range ctx.Routes {
+		fmt.Fprintf(&b, "route %s %s\n", r.Verb, r.FullPath())
+	}
+	if ctx.Params != nil {
+		fmt.Fprintf(&b, "params %s\n", codegen.GoTypeDef(ctx.Params, 0, true, false))
+	}
+	if ctx.Headers != nil {
+		fmt.Fprintf(&b, "headers %s\n", codegen.GoTypeDef(ctx.Headers, 0, true, false))
+	}
+	if ctx.Payload != nil {
+		fmt.Fprintf(&b, "payload %s\n", codegen.GoTypeDef(ctx.Payload, 0, true, false))
+	}
+	names := make([]string, 0, len(ctx.Responses))
+	for n := range ctx.Responses {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		resp := ctx.Responses[n]
+		fmt.Fprintf(&b, "response %s %d %s %s\n", n, resp.Status, resp.MediaType, resp.ViewName)
+		if mt := ctx.API.MediaTypeWithIdentifier(resp.MediaType); mt != nil {
+			fmt.Fprintf(&b, "body %s\n", codegen.GoTypeDef(mt, 0, true, false))
+		}
+		if resp.Headers != nil {
+			fmt.Fprintf(&b, "headers %s\n", codegen.GoTypeDef(resp.Headers, 0, true, false))
+		}
+	}
+	if ctx.Security != nil && ctx.Security.Scheme != nil {
+		fmt.Fprintf(&b, "security %s %s\n", ctx.Security.Scheme.SchemeName, strings.Join(ctx.Security.Scopes, " "))
+	}
+	if ctx.WebSocket {
+		b.WriteString("websocket\n")
+	}
+	return b.String()
+}
+
+// LoadSnapshots reads the snapshots recorded in dir and returns them sorted by version.
+func LoadSnapshots(dir string) ([]*Snapshot, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]*Snapshot, 0, len(files))
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var s Snapshot
+		if err := json.Unmarshal(b, &s); err != nil {
+			return nil, fmt.Errorf("invalid snapshot %s: %s", f, err)
+		}
+		snapshots = append(snapshots, &s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return compareVersions(snapshots[i].Version, snapshots[j].Version) < 0
+	})
+	return snapshots, nil
+}
+
+// NewMatrix computes the compatibility matrix of the given snapshots by comparing the action
+// signatures of adjacent versions. The snapshots must be sorted by version.
+func NewMatrix(snapshots []*Snapshot) *Matrix {
+	m := &Matrix{}
+	keys := make(map[string]bool)
+	for _, s := range snapshots {
+		m.Versions = append(m.Versions, s.Version)
+		for k := range s.Actions {
+			keys[k] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		elems := strings.SplitN(k, "/", 2)
+		row := &Row{Resource: elems[0], Action: elems[1], Changes: make([]string, len(snapshots))}
+		for i, s := range snapshots {
+			sig, ok := s.Actions[k]
+			var prev string
+			var prevOK bool
+			if i > 0 {
+				prev, prevOK = snapshots[i-1].Actions[k]
+			}
+			switch {
+			case ok && !prevOK:
+				row.Changes[i] = Added
+			case ok && sig == prev:
+				row.Changes[i] = Unchanged
+			case ok:
+				row.Changes[i] = Modified
+			case prevOK:
+				row.Changes[i] = Removed
+			}
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	return m
+}
+
+// Markdown renders the matrix as a Markdown document.
+func (m *Matrix) Markdown(title string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s Compatibility Matrix\n\n", title)
+	b.WriteString("| Resource | Action |")
+	for _, v := range m.Versions {
+		fmt.Fprintf(&b, " %s |", v)
+	}
+	b.WriteString("\n| --- | --- |")
+	for range m.Versions {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	for _, r := range m.Rows {
+		fmt.Fprintf(&b, "| %s | %s |", r.Resource, r.Action)
+		for _, c := range r.Changes {
+			if c == "" {
+				c = "-"
+			}
+			fmt.Fprintf(&b, " %s |", c)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// compareVersions compares two versions made of dot separated elements. Numeric elements are
+// compared numerically, other elements lexically. A leading "v" is ignored.
+func compareVersions(v1, v2 string) int {
+	e1 := strings.Split(strings.TrimPrefix(v1, "v"), ".")
+	e2 := strings.Split(strings.TrimPrefix(v2, "v"), ".")
+	for i := 0; i < len(e1) && i < len(e2); i++ {
+		n1, err1 := strconv.Atoi(e1[i])
+		n2, err2 := strconv.Atoi(e2[i])
+		switch {
+		case err1 == nil && err2 == nil:
+			if n1 != n2 {
+				if n1 < n2 {
+					return -1
+				}
+				return 1
+			}
+		case e1[i] != e2[i]:
+			if e1[i] < e2[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(e1) - len(e2)
+}
@@ -0,0 +1,11 @@
+/*
+Package gencompat provides a generator for the API version compatibility matrix. Each run records a
+snapshot of the actions defined by the design under compat/<version>.json where version is the API
+version set with the Version DSL. The snapshot maps each resource action to a signature computed
+from the action context data: routes, parameters, headers, payload and responses. The generator
+then loads the snapshots of all the recorded versions and writes compat/COMPATIBILITY.md, a
+Markdown table with one column per version and one row per resource action. The cells indicate
+whether the action was "added", "unchanged", "modified" or "removed" compared to the previous
+version.
+*/
+package gencompat
@@ -0,0 +1,13 @@
+package gencompat_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenCompat(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenCompat Suite")
+}
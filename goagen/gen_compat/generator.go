@@ -0,0 +1,105 @@
+package gencompat
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the API version compatibility matrix generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+
+	set := flag.NewFlagSet("compat", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate records the snapshot of the current API version and produces the compatibility
+// matrix of all the recorded versions.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.API.Version == "" {
+		return nil, fmt.Errorf("missing API version, use the Version DSL to set it")
+	}
+
+	// The snapshots of the previous versions must survive across runs, only the files produced
+	// by this run are cleaned up on error.
+	compatDir := filepath.Join(g.OutDir, "compat")
+	if err = os.MkdirAll(compatDir, 0755); err != nil {
+		return nil, err
+	}
+
+	snapshot := NewSnapshot(g.API)
+	js, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	snapshotFile := filepath.Join(compatDir, snapshotFilename(snapshot.Version))
+	if err = ioutil.WriteFile(snapshotFile, js, 0644); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, snapshotFile)
+
+	snapshots, err := LoadSnapshots(compatDir)
+	if err != nil {
+		return nil, err
+	}
+	title := g.API.Title
+	if title == "" {
+		title = g.API.Name
+	}
+	matrix := NewMatrix(snapshots).Markdown(title)
+	matrixFile := filepath.Join(compatDir, "COMPATIBILITY.md")
+	if err = ioutil.WriteFile(matrixFile, []byte(matrix), 0644); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, matrixFile)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// snapshotFilename returns the name of the file recording the snapshot of the given version.
+func snapshotFilename(version string) string {
+	return strings.Replace(version, string(filepath.Separator), "_", -1) + ".json"
+}
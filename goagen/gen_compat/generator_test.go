@@ -0,0 +1,111 @@
+package gencompat_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_compat"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	// generate runs the generator on the design of the given API version. The show action is
+	// modified in version 3.0, the list action in version 2.0, the delete action is removed in
+	// version 2.0 and the create action is added in version 2.0.
+	generate := func(ver string) ([]string, error) {
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Title("dummy API")
+			apidsl.Version(ver)
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.BasePath("/bottles")
+			apidsl.Action("show", func() {
+				apidsl.Routing(apidsl.GET("/:id"))
+				apidsl.Params(func() {
+					apidsl.Param("id", design.Integer)
+				})
+				apidsl.Response(design.OK)
+				if ver == "3.0" {
+					apidsl.Response(design.NotFound)
+				}
+			})
+			apidsl.Action("list", func() {
+				apidsl.Routing(apidsl.GET(""))
+				if ver != "1.0" {
+					apidsl.Params(func() {
+						apidsl.Param("vintage", design.Integer)
+					})
+				}
+				apidsl.Response(design.OK)
+			})
+			if ver == "1.0" {
+				apidsl.Action("delete", func() {
+					apidsl.Routing(apidsl.DELETE("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+					})
+					apidsl.Response(design.NoContent)
+				})
+			} else {
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String)
+					})
+					apidsl.Response(design.Created)
+				})
+			}
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		return gencompat.Generate()
+	}
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("compattest")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("classifies the changes between adjacent versions", func() {
+		for _, ver := range []string{"2.0", "1.0", "3.0"} {
+			files, err := generate(ver)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(files).Should(HaveLen(2))
+		}
+		content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "compat", "COMPATIBILITY.md"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(Equal(`# dummy API Compatibility Matrix
+
+| Resource | Action | 1.0 | 2.0 | 3.0 |
+| --- | --- | --- | --- | --- |
+| bottle | create | - | added | unchanged |
+| bottle | delete | added | removed | - |
+| bottle | list | added | modified | unchanged |
+| bottle | show | added | unchanged | modified |
+`))
+	})
+
+	It("fails if the API version is not set", func() {
+		_, err := generate("")
+		Ω(err).Should(HaveOccurred())
+	})
+})
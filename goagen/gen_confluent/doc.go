@@ -0,0 +1,10 @@
+/*
+Package genconfluent provides a generator that registers the Apache Avro schemas produced by the
+"avro" command with a Confluent Schema Registry (https://docs.confluent.io/platform/current/schema-registry).
+The generator reads the .avsc files located under the "avro" directory and registers each schema
+under the subject named after the file with the "-value" suffix, e.g. "bottle-value". Registering a
+schema that differs from the latest version of the subject creates a new version. The generator
+prints the schema ID assigned by the registry for each subject. The --dry-run flag prints the
+schemas without registering them.
+*/
+package genconfluent
@@ -0,0 +1,13 @@
+package genconfluent_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenConfluent(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenConfluent Suite")
+}
@@ -0,0 +1,159 @@
+package genconfluent
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// ContentType is the content type of the Confluent Schema Registry API requests.
+const ContentType = "application/vnd.schemaregistry.v1+json"
+
+type (
+	// Generator registers the Avro schemas with a Confluent Schema Registry.
+	Generator struct {
+		API         *design.APIDefinition // The API definition
+		OutDir      string                // Path to output directory containing the "avro" directory
+		RegistryURL string                // Base URL of the schema registry
+		DryRun      bool                  // Print the schemas without registering them if true
+		Client      *http.Client          // HTTP client used to make requests, defaults to http.DefaultClient
+		Out         io.Writer             // Writer used to print the results, defaults to os.Stdout
+		SchemaIDs   map[string]int        // Schema IDs assigned by the registry indexed by subject
+	}
+
+	// RegisterRequest is the body of the requests made to register a schema.
+	RegisterRequest struct {
+		Schema string `json:"schema"`
+	}
+
+	// RegisterResponse is the body of the responses to the requests made to register a schema.
+	RegisterResponse struct {
+		ID int `json:"id"`
+	}
+
+	// RegistryError is the body of the error responses returned by the schema registry.
+	RegistryError struct {
+		ErrorCode int    `json:"error_code"`
+		Message   string `json:"message"`
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, registryURL, ver string
+	var dryRun bool
+
+	set := flag.NewFlagSet("confluent", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&registryURL, "registry-url", "", "")
+	set.BoolVar(&dryRun, "dry-run", false, "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, RegistryURL: registryURL, DryRun: dryRun, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate registers the schemas generated by the "avro" command. It does not produce any file.
+func (g *Generator) Generate() ([]string, error) {
+	if g.RegistryURL == "" && !g.DryRun {
+		return nil, fmt.Errorf("missing schema registry URL, use --registry-url or --dry-run")
+	}
+	if g.Client == nil {
+		g.Client = http.DefaultClient
+	}
+	if g.Out == nil {
+		g.Out = os.Stdout
+	}
+	g.SchemaIDs = make(map[string]int)
+
+	schemas, err := filepath.Glob(filepath.Join(g.OutDir, "avro", "*.avsc"))
+	if err != nil {
+		return nil, err
+	}
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("no Avro schema found in %s, run the avro command first", filepath.Join(g.OutDir, "avro"))
+	}
+	sort.Strings(schemas)
+	for _, filename := range schemas {
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		var schema bytes.Buffer
+		if err := json.Compact(&schema, content); err != nil {
+			return nil, fmt.Errorf("invalid Avro schema %s: %s", filename, err)
+		}
+		subject := Subject(filename)
+		if g.DryRun {
+			fmt.Fprintf(g.Out, "%s: %s\n", subject, schema.String())
+			continue
+		}
+		id, err := g.Register(subject, schema.String())
+		if err != nil {
+			return nil, err
+		}
+		g.SchemaIDs[subject] = id
+		fmt.Fprintf(g.Out, "%s: schema ID %d\n", subject, id)
+	}
+
+	return nil, nil
+}
+
+// Register registers the given schema under the given subject and returns the schema ID assigned
+// by the registry. The registry returns the ID of the existing schema if it is already registered
+// under the subject.
+func (g *Generator) Register(subject, schema string) (int, error) {
+	body, err := json.Marshal(&RegisterRequest{Schema: schema})
+	if err != nil {
+		return 0, err
+	}
+	u := strings.TrimRight(g.RegistryURL, "/") + "/subjects/" + url.PathEscape(subject) + "/versions"
+	req, err := http.NewRequest("POST", u, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("Accept", ContentType)
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var rerr RegistryError
+		if err := json.NewDecoder(resp.Body).Decode(&rerr); err != nil || rerr.Message == "" {
+			return 0, fmt.Errorf("failed to register schema for subject %s: %s", subject, resp.Status)
+		}
+		return 0, fmt.Errorf("failed to register schema for subject %s: %s (error code %d)", subject, rerr.Message, rerr.ErrorCode)
+	}
+	var res RegisterResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return 0, fmt.Errorf("failed to decode schema registry response for subject %s: %s", subject, err)
+	}
+	return res.ID, nil
+}
+
+// Subject returns the schema registry subject used to register the schema stored in the given
+// file, e.g. "bottle-value" for "bottle.avsc".
+func Subject(filename string) string {
+	return strings.TrimSuffix(filepath.Base(filename), ".avsc") + "-value"
+}
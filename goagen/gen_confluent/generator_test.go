@@ -0,0 +1,135 @@
+package genconfluent_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_avro"
+	"github.com/goadesign/goa/goagen/gen_confluent"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const schemaID = 42
+
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+	var server *httptest.Server
+	var requests []*http.Request
+	var bodies [][]byte
+	var out bytes.Buffer
+	var dryRun bool
+	var gen *genconfluent.Generator
+	var genErr error
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("confluenttest")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		dslengine.Reset()
+		apidsl.API("test api", nil)
+		apidsl.MediaType("application/vnd.bottle+json", func() {
+			apidsl.TypeName("Bottle")
+			apidsl.Attributes(func() {
+				apidsl.Attribute("name", design.String)
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("name")
+			})
+		})
+		dslengine.Run()
+
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+		_, err = genavro.Generate()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		requests = nil
+		bodies = nil
+		out.Reset()
+		dryRun = false
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			requests = append(requests, r)
+			bodies = append(bodies, body)
+			w.Header().Set("Content-Type", genconfluent.ContentType)
+			json.NewEncoder(w).Encode(&genconfluent.RegisterResponse{ID: schemaID})
+		}))
+	})
+
+	JustBeforeEach(func() {
+		gen = &genconfluent.Generator{
+			API:         design.Design,
+			OutDir:      testPkg.Abs(),
+			RegistryURL: server.URL,
+			DryRun:      dryRun,
+			Out:         &out,
+		}
+		_, genErr = gen.Generate()
+	})
+
+	AfterEach(func() {
+		server.Close()
+		workspace.Delete()
+	})
+
+	It("registers the schemas", func() {
+		Ω(genErr).ShouldNot(HaveOccurred())
+		Ω(requests).Should(HaveLen(1))
+		Ω(requests[0].Method).Should(Equal("POST"))
+		Ω(requests[0].URL.Path).Should(Equal("/subjects/bottle-value/versions"))
+		Ω(requests[0].Header.Get("Content-Type")).Should(Equal(genconfluent.ContentType))
+
+		var body genconfluent.RegisterRequest
+		Ω(json.Unmarshal(bodies[0], &body)).ShouldNot(HaveOccurred())
+		var r genavro.Record
+		Ω(json.Unmarshal([]byte(body.Schema), &r)).ShouldNot(HaveOccurred())
+		Ω(r.Name).Should(Equal("Bottle"))
+	})
+
+	It("records the schema IDs", func() {
+		Ω(genErr).ShouldNot(HaveOccurred())
+		Ω(gen.SchemaIDs).Should(Equal(map[string]int{"bottle-value": schemaID}))
+		Ω(out.String()).Should(Equal("bottle-value: schema ID 42\n"))
+	})
+
+	Context("in dry-run mode", func() {
+		BeforeEach(func() {
+			dryRun = true
+		})
+
+		It("prints the schemas without registering them", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(requests).Should(BeEmpty())
+			Ω(gen.SchemaIDs).Should(BeEmpty())
+			Ω(out.String()).Should(HavePrefix("bottle-value: {"))
+			Ω(out.String()).Should(ContainSubstring(`"name":"Bottle"`))
+		})
+	})
+
+	Context("with a registry returning an error", func() {
+		BeforeEach(func() {
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error_code":409,"message":"Schema being registered is incompatible with an earlier schema"}`))
+			})
+		})
+
+		It("returns the registry error", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(genErr.Error()).Should(ContainSubstring("incompatible"))
+		})
+	})
+})
@@ -0,0 +1,10 @@
+/*
+Package genconnect provides a generator for Connect (https://connectrpc.com) service handlers.
+The generator creates a connect.go file in the service main package that exposes each API action
+as a unary Connect procedure named "/<api>.<Resource>Service/<Action>". The request message of a
+procedure holds the action parameters and the raw JSON payload, the response message holds the raw
+JSON response body. The generated MountConnectHandlers function registers the procedures on the
+service mux, calls are dispatched to the action handlers mounted by the app package so that the
+same middleware, validations and controllers serve both the HTTP and the Connect transports.
+*/
+package genconnect
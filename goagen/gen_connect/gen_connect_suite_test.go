@@ -0,0 +1,13 @@
+package genconnect_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenConnect(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenConnect Suite")
+}
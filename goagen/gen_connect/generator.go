@@ -0,0 +1,315 @@
+package genconnect
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the Connect handlers code generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+
+	set := flag.NewFlagSet("connect", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the connect.go file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	connectFile := filepath.Join(g.OutDir, "connect.go")
+	os.Remove(connectFile)
+	g.genfiles = append(g.genfiles, connectFile)
+	file, err := codegen.SourceFileFor(connectFile)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("errors"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("net"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/httptest"),
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("connectrpc.com/connect"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+	title := fmt.Sprintf("%s: Connect Handlers", g.API.Context())
+	if err = file.WriteHeader(title, "main", imports); err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"Procedures": Procedures(g.API),
+	}
+	if err = file.ExecuteTemplate("connect", connectT, nil, data); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Procedures returns the data used to generate the Connect procedures of the API actions. There is
+// one procedure per action built from the same context data as the action context in the app
+// package. WebSocket actions and actions that read multipart/form-data payloads cannot be exposed
+// as unary procedures and are skipped.
+func Procedures(api *design.APIDefinition) []map[string]interface{} {
+	service := codegen.Goify(api.Name, false)
+	var procs []map[string]interface{}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 || a.WebSocket() || a.FormData {
+				return nil
+			}
+			params := a.AllParams()
+			if params != nil && len(params.Type.ToObject()) == 0 {
+				params = nil
+			}
+			ctx := &genapp.ContextTemplateData{
+				Name:         codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + "Context",
+				ResourceName: r.Name,
+				ActionName:   a.Name,
+				Params:       params,
+				Payload:      a.Payload,
+				Routes:       a.Routes,
+				API:          api,
+			}
+			procs = append(procs, map[string]interface{}{
+				"Const":     codegen.Goify(r.Name, true) + codegen.Goify(a.Name, true) + "Procedure",
+				"Procedure": fmt.Sprintf("/%s.%sService/%s", service, codegen.Goify(r.Name, true), codegen.Goify(a.Name, true)),
+				"Request":   codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + "Request",
+				"Method":    a.Routes[0].Verb,
+				"Path":      a.Routes[0].FullPath(),
+				"Context":   ctx,
+				"Fields":    fields(ctx.Params, a.Routes[0].Params()),
+			})
+			return nil
+		})
+	})
+	return procs
+}
+
+// fields returns the request message fields generated for the given action parameters. The
+// fields of optional query string parameters are pointers so that unset parameters are not sent
+// to the action.
+func fields(params *design.AttributeDefinition, wildcards []string) []map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+	var fs []map[string]interface{}
+	params.Type.ToObject().IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		pointer := !att.Type.IsArray() && !params.IsRequired(n)
+		for _, w := range wildcards {
+			if w == n {
+				pointer = false
+				break
+			}
+		}
+		typ := codegen.GoNativeType(att.Type)
+		if pointer {
+			typ = "*" + typ
+		}
+		fs = append(fs, map[string]interface{}{
+			"Name":        n,
+			"Field":       codegen.Goify(n, true),
+			"Type":        typ,
+			"Pointer":     pointer,
+			"Array":       att.Type.IsArray(),
+			"Description": att.Description,
+		})
+		return nil
+	})
+	return fs
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// connectT generates the Connect request messages and handlers.
+// template input: map[string]interface{}
+const connectT = `
+// Connect procedures of the API actions.
+const (
+{{ range .Procedures }}	// {{ .Const }} is the Connect procedure of the {{ .Context.ResourceName }} {{ .Context.ActionName }} action.
+	{{ .Const }} = {{ printf "%q" .Procedure }}
+{{ end }})
+{{ range .Procedures }}
+// {{ .Request }} is the Connect request message of the {{ .Context.ResourceName }} {{ .Context.ActionName }} action.
+type {{ .Request }} struct {
+{{ range .Fields }}{{ if .Description }}	{{ comment .Description }}
+{{ end }}	{{ .Field }} {{ .Type }} ` + "`" + `json:"{{ .Name }}{{ if or .Pointer .Array }},omitempty{{ end }}"` + "`" + `
+{{ end }}{{ if .Context.Payload }}	// Payload is the JSON representation of the action payload.
+	Payload json.RawMessage ` + "`" + `json:"payload,omitempty"` + "`" + `
+{{ end }}}
+{{ end }}
+// ConnectCodec is the codec of the Connect procedures. The request and response messages are
+// serialized with encoding/json, clients must use the codec as well:
+//
+//	connect.NewClient[ShowBottleRequest, json.RawMessage](http.DefaultClient, url, connect.WithCodec(ConnectCodec{}))
+type ConnectCodec struct{}
+
+// Name returns the name of the codec.
+func (ConnectCodec) Name() string { return "json" }
+
+// Marshal serializes v.
+func (ConnectCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal deserializes data into v.
+func (ConnectCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// MountConnectHandlers registers the Connect procedures on the service mux. The procedures dispatch
+// the calls to the action handlers so the app package controllers must be mounted first.
+func MountConnectHandlers(service *goa.Service, opts ...connect.HandlerOption) {
+	opts = append([]connect.HandlerOption{connect.WithCodec(ConnectCodec{})}, opts...)
+{{ range .Procedures }}
+	service.Mux.Handle("POST", {{ .Const }}, connectMuxHandler(connect.NewUnaryHandler({{ .Const }}, func(ctx context.Context, req *connect.Request[{{ .Request }}]) (*connect.Response[json.RawMessage], error) {
+		params := url.Values{}
+{{ range .Fields }}{{ if .Array }}		for _, v := range req.Msg.{{ .Field }} {
+			params.Add({{ printf "%q" .Name }}, connectParam(v))
+		}
+{{ else if .Pointer }}		if req.Msg.{{ .Field }} != nil {
+			params.Set({{ printf "%q" .Name }}, connectParam(*req.Msg.{{ .Field }}))
+		}
+{{ else }}		params.Set({{ printf "%q" .Name }}, connectParam(req.Msg.{{ .Field }}))
+{{ end }}{{ end }}		return serveConnect(ctx, service, {{ printf "%q" .Method }}, {{ printf "%q" .Path }}, req.Header(), params, {{ if .Context.Payload }}req.Msg.Payload{{ else }}nil{{ end }})
+	}, opts...)))
+	service.LogInfo("mount", "procedure", {{ .Const }}, "route", {{ printf "%q" (printf "%s %s" .Method .Path) }})
+{{ end }}}
+
+// connectMuxHandler adapts a Connect handler to the service mux.
+func connectMuxHandler(h http.Handler) goa.MuxHandler {
+	return func(rw http.ResponseWriter, req *http.Request, _ url.Values) {
+		h.ServeHTTP(rw, req)
+	}
+}
+
+// serveConnect runs the action handler mounted on the service mux for the given method and path
+// and returns the response body as the Connect response message. Error responses are translated
+// to Connect errors.
+func serveConnect(ctx context.Context, service *goa.Service, method, path string, header http.Header, params url.Values, body []byte) (*connect.Response[json.RawMessage], error) {
+	handle := service.Mux.Lookup(method, path)
+	if handle == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("%s %s is not mounted", method, path))
+	}
+	req, err := http.NewRequest(method, connectPath(path, params), bytes.NewReader(body))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	req = req.WithContext(ctx)
+	req.Header = header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handle(rec, req, params)
+	if rec.Code >= 400 {
+		return nil, connect.NewError(connectCode(rec.Code), errors.New(strings.TrimSpace(rec.Body.String())))
+	}
+	var msg json.RawMessage
+	if rec.Body.Len() > 0 {
+		msg = json.RawMessage(rec.Body.Bytes())
+	}
+	resp := connect.NewResponse(&msg)
+	for k, v := range rec.Header() {
+		if k != "Content-Type" && k != "Content-Length" {
+			resp.Header()[k] = v
+		}
+	}
+	return resp, nil
+}
+
+// connectPath replaces the wildcards of the route path with the values of the corresponding
+// parameters.
+func connectPath(path string, params url.Values) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = url.PathEscape(params.Get(s[1:]))
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// connectParam returns the string representation of a request message parameter value as parsed
+// by the action context.
+func connectParam(v interface{}) string {
+	switch actual := v.(type) {
+	case time.Time:
+		return actual.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// connectCode maps HTTP response status codes to Connect error codes.
+func connectCode(status int) connect.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return connect.CodeInvalidArgument
+	case http.StatusUnauthorized:
+		return connect.CodeUnauthenticated
+	case http.StatusForbidden:
+		return connect.CodePermissionDenied
+	case http.StatusNotFound:
+		return connect.CodeNotFound
+	case http.StatusConflict:
+		return connect.CodeAlreadyExists
+	case http.StatusPreconditionFailed:
+		return connect.CodeFailedPrecondition
+	case http.StatusTooManyRequests:
+		return connect.CodeResourceExhausted
+	case http.StatusNotImplemented:
+		return connect.CodeUnimplemented
+	case http.StatusServiceUnavailable:
+		return connect.CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return connect.CodeDeadlineExceeded
+	}
+	if status >= 500 {
+		return connect.CodeInternal
+	}
+	return connect.CodeUnknown
+}
+`
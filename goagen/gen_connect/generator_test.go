@@ -0,0 +1,97 @@
+package genconnect_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_connect"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	JustBeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("connecttest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+
+		dslengine.Reset()
+		apidsl.API("cellar", func() {
+			apidsl.Title("dummy API")
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.BasePath("/bottles")
+			apidsl.Action("show", func() {
+				apidsl.Routing(apidsl.GET("/:id"))
+				apidsl.Params(func() {
+					apidsl.Param("id", design.Integer, "Bottle ID")
+					apidsl.Param("tags", apidsl.ArrayOf(design.String))
+					apidsl.Param("vintage", design.Integer)
+				})
+				apidsl.Response(design.OK)
+			})
+			apidsl.Action("create", func() {
+				apidsl.Routing(apidsl.POST(""))
+				apidsl.Payload(func() {
+					apidsl.Attribute("name", design.String)
+				})
+				apidsl.Response(design.Created)
+			})
+			apidsl.Action("upload", func() {
+				apidsl.Routing(apidsl.POST("/:id/label"))
+				apidsl.FormData(func() {
+					apidsl.Attribute("label", design.FileType)
+				})
+				apidsl.Response(design.NoContent)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+
+		files, genErr = genconnect.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("generates one unary procedure per action", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(1))
+		filename := filepath.Join(testPkg.Abs(), "connect.go")
+		content, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		f, err := parser.ParseFile(token.NewFileSet(), filename, content, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(f.Name.Name).Should(Equal("main"))
+		Ω(f.Scope.Lookup("MountConnectHandlers")).ShouldNot(BeNil())
+		Ω(f.Scope.Lookup("ShowBottleRequest")).ShouldNot(BeNil())
+		Ω(f.Scope.Lookup("CreateBottleRequest")).ShouldNot(BeNil())
+		Ω(f.Scope.Lookup("UploadBottleRequest")).Should(BeNil())
+
+		Ω(string(content)).Should(ContainSubstring(`BottleShowProcedure = "/cellar.BottleService/Show"`))
+		Ω(string(content)).Should(MatchRegexp(`ID\s+int\s+` + "`" + `json:"id"`))
+		Ω(string(content)).Should(MatchRegexp(`Tags\s+\[\]string\s+` + "`" + `json:"tags,omitempty"`))
+		Ω(string(content)).Should(MatchRegexp(`Vintage\s+\*int\s+` + "`" + `json:"vintage,omitempty"`))
+		Ω(string(content)).Should(ContainSubstring("Payload json.RawMessage `json:\"payload,omitempty\"`"))
+		Ω(string(content)).Should(ContainSubstring(`return serveConnect(ctx, service, "GET", "/bottles/:id", req.Header(), params, nil)`))
+		Ω(string(content)).Should(ContainSubstring(`return serveConnect(ctx, service, "POST", "/bottles", req.Header(), params, req.Msg.Payload)`))
+	})
+})
@@ -0,0 +1,11 @@
+/*
+Package gencontract provides a generator for contract compliance tests. The generator creates a
+contract_test.go file in the service main package that defines the TestContractCompliance function.
+The test mounts the service controllers onto a test server, loads the OpenAPI specification produced
+by the swagger generator and replays one example request per action against the server. Each
+response must have the success status declared in the design and a body that validates against the
+response schema of the specification. The schemas are validated with the
+github.com/getkin/kin-openapi/openapi3 package. Actions that require security credentials or headers
+are skipped.
+*/
+package gencontract
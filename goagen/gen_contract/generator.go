@@ -0,0 +1,363 @@
+package gencontract
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+type (
+	// Generator is the contract compliance test generator.
+	Generator struct {
+		API      *design.APIDefinition // The API definition
+		OutDir   string                // Path to output directory
+		Target   string                // Name of generated "app" package
+		Spec     string                // Path to the OpenAPI specification relative to OutDir
+		genfiles []string              // Generated files
+	}
+
+	// ContractCase describes the example request replayed against the service for one action.
+	ContractCase struct {
+		Name    string // Name of the case, e.g. "bottle show"
+		Method  string // HTTP method of the request
+		Pattern string // Path of the operation in the OpenAPI specification, e.g. "/bottles/{id}"
+		Path    string // Path of the request including the example parameters
+		Body    string // JSON encoded example payload if any
+		Status  int    // Expected response status
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, target, spec, ver string
+
+	set := flag.NewFlagSet("contract", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&target, "pkg", "app", "")
+	set.StringVar(&spec, "spec", "swagger/swagger.json", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	target = codegen.Goify(target, false)
+	g := &Generator{OutDir: outDir, Target: target, Spec: spec, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the contract_test.go file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Target == "" {
+		g.Target = "app"
+	}
+	if g.Spec == "" {
+		g.Spec = "swagger/swagger.json"
+	}
+
+	outPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	filename := filepath.Join(g.OutDir, "contract_test.go")
+	os.Remove(filename)
+	g.genfiles = append(g.genfiles, filename)
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("mime"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/httptest"),
+		codegen.SimpleImport("os"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("testing"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport(path.Join(filepath.ToSlash(outPkg), g.Target)),
+		codegen.SimpleImport("github.com/getkin/kin-openapi/openapi2"),
+		codegen.SimpleImport("github.com/getkin/kin-openapi/openapi2conv"),
+		codegen.SimpleImport("github.com/getkin/kin-openapi/openapi3"),
+	}
+	title := fmt.Sprintf("%s: Contract Compliance Tests", g.API.Context())
+	if err = file.WriteHeader(title, "main", imports); err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{
+		"targetPkg": func() string { return g.Target },
+	}
+	data := map[string]interface{}{
+		"API":   g.API,
+		"Spec":  g.Spec,
+		"Cases": Cases(g.API),
+	}
+	if err = file.ExecuteTemplate("contract", contractT, funcs, data); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// Cases returns the example requests replayed against the service, one per action. The request
+// uses the first action route, the parameters and the payload are initialized with the design
+// examples. Actions that define no success response, that require security credentials or
+// headers or that upgrade the connection to the WebSocket protocol are skipped.
+func Cases(api *design.APIDefinition) []*ContractCase {
+	var cases []*ContractCase
+	rand := api.RandomGenerator()
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 || a.WebSocket() || a.Security != nil {
+				return nil
+			}
+			if codegen.HasRequired(a.Headers) || codegen.HasRequired(r.Headers) {
+				return nil
+			}
+			status := codegen.SuccessStatus(a)
+			if status == 0 {
+				return nil
+			}
+			route := a.Routes[0]
+			params := a.AllParams().Type.ToObject()
+			wildcards := route.Params()
+			p := design.WildcardRegex.ReplaceAllStringFunc(route.FullPath(), func(w string) string {
+				name := design.WildcardRegex.FindStringSubmatch(w)[1]
+				att, ok := params[name]
+				if !ok {
+					return "/" + name
+				}
+				return "/" + url.PathEscape(fmt.Sprintf("%v", att.GenerateExample(rand, nil)))
+			})
+			if all := a.AllParams(); all.Validation != nil {
+				query := url.Values{}
+				for _, n := range all.Validation.Required {
+					if att, ok := params[n]; ok && !contains(wildcards, n) {
+						query.Set(n, fmt.Sprintf("%v", att.GenerateExample(rand, nil)))
+					}
+				}
+				if len(query) > 0 {
+					p += "?" + query.Encode()
+				}
+			}
+			c := &ContractCase{
+				Name:    r.Name + " " + a.Name,
+				Method:  route.Verb,
+				Pattern: specPath(api, route),
+				Path:    p,
+				Status:  status,
+			}
+			if a.Payload != nil {
+				b, err := json.Marshal(a.Payload.GenerateExample(rand, nil))
+				if err != nil {
+					return nil
+				}
+				c.Body = string(b)
+			}
+			cases = append(cases, c)
+			return nil
+		})
+	})
+	return cases
+}
+
+// specPath returns the key of the route in the paths of the OpenAPI specification produced by the
+// swagger generator: the full path with the wildcards written "{name}" and without the API base
+// path.
+func specPath(api *design.APIDefinition, route *design.RouteDefinition) string {
+	toSpec := func(p string) string {
+		return design.WildcardRegex.ReplaceAllStringFunc(p, func(w string) string {
+			return fmt.Sprintf("/{%s}", w[2:])
+		})
+	}
+	key := toSpec(route.FullPath())
+	bp := toSpec(api.BasePath)
+	if bp != "/" && !hasAbsoluteRoutes(api) {
+		key = strings.TrimPrefix(key, bp)
+	}
+	if key == "" {
+		key = "/"
+	}
+	return key
+}
+
+// hasAbsoluteRoutes returns true if any action of the API uses an absolute route or if the API
+// defines file servers, the swagger generator does not strip the base path in this case.
+func hasAbsoluteRoutes(api *design.APIDefinition) bool {
+	for _, res := range api.Resources {
+		if len(res.FileServers) > 0 {
+			return true
+		}
+		for _, a := range res.Actions {
+			for _, ro := range a.Routes {
+				if ro.IsAbsolute() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// contains returns true if names contains name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// contractT generates the TestContractCompliance function.
+// template input: map[string]interface{}
+const contractT = `
+// contractSpec is the path to the OpenAPI specification the service must comply with.
+const contractSpec = {{ printf "%q" .Spec }}
+
+// contractCase describes the example request replayed against the service for one action.
+type contractCase struct {
+	Name    string
+	Method  string
+	Pattern string
+	Path    string
+	Body    string
+	Status  int
+}
+
+// contractCases lists the example requests, one per action.
+var contractCases = []contractCase{
+{{ range .Cases }}	{ {{- printf "%q" .Name }}, {{ printf "%q" .Method }}, {{ printf "%q" .Pattern }}, {{ printf "%q" .Path }}, {{ printf "%q" .Body }}, {{ .Status -}} },
+{{ end }}}
+
+// TestContractCompliance replays the example request of each action against the service and
+// checks that the responses comply with the OpenAPI specification.
+func TestContractCompliance(t *testing.T) {
+	b, err := os.ReadFile(contractSpec)
+	if err != nil {
+		t.Fatalf("failed to read OpenAPI specification: %s", err)
+	}
+	doc, err := loadContract(b)
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI specification: %s", err)
+	}
+
+	service := goa.New({{ printf "%q" .API.Name }})
+{{ range $name, $res := .API.Resources }}{{ $name := goify $res.Name true }}	{{ targetPkg }}.Mount{{ $name }}Controller(service, New{{ $name }}Controller(service))
+{{ end }}	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	for _, tc := range contractCases {
+		var body io.Reader
+		if tc.Body != "" {
+			body = strings.NewReader(tc.Body)
+		}
+		req, err := http.NewRequest(tc.Method, server.URL+tc.Path, body)
+		if err != nil {
+			t.Fatalf("%s: failed to create request: %s", tc.Name, err)
+		}
+		if tc.Body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("%s: failed to send request: %s", tc.Name, err)
+			continue
+		}
+		if err := checkContract(doc, tc, resp); err != nil {
+			t.Errorf("%s: %s", tc.Name, err)
+		}
+	}
+}
+
+// loadContract loads the OpenAPI 2.0 specification produced by the swagger generator and converts
+// it to OpenAPI 3.
+func loadContract(b []byte) (*openapi3.T, error) {
+	var doc2 openapi2.T
+	if err := json.Unmarshal(b, &doc2); err != nil {
+		return nil, err
+	}
+	doc, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, err
+	}
+	if err := openapi3.NewLoader().ResolveRefsIn(doc, nil); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// checkContract checks that resp has the status expected by tc and that the response body
+// validates against the schema of the corresponding response in doc. checkContract closes the
+// response body.
+func checkContract(doc *openapi3.T, tc contractCase, resp *http.Response) error {
+	defer resp.Body.Close()
+	if resp.StatusCode != tc.Status {
+		return fmt.Errorf("expected status %d, got %d", tc.Status, resp.StatusCode)
+	}
+	item := doc.Paths.Find(tc.Pattern)
+	if item == nil {
+		return fmt.Errorf("path %s is not defined in the specification", tc.Pattern)
+	}
+	op := item.GetOperation(tc.Method)
+	if op == nil {
+		return fmt.Errorf("operation %s %s is not defined in the specification", tc.Method, tc.Pattern)
+	}
+	ref := op.Responses.Status(resp.StatusCode)
+	if ref == nil || ref.Value == nil {
+		return fmt.Errorf("status %d of %s %s is not defined in the specification", resp.StatusCode, tc.Method, tc.Pattern)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %s", err)
+	}
+	ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	mt := ref.Value.Content.Get(ct)
+	if mt == nil || mt.Schema == nil || mt.Schema.Value == nil || len(b) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("invalid response body: %s", err)
+	}
+	if err := mt.Schema.Value.VisitJSON(v); err != nil {
+		return fmt.Errorf("response body does not match the schema: %s", err)
+	}
+	return nil
+}
+`
@@ -0,0 +1,116 @@
+package gencontract_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_contract"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("contracttest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--spec=spec/openapi.json", "--version=" + version.String()}
+
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Title("dummy API")
+			apidsl.BasePath("/api")
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.BasePath("/bottles")
+			apidsl.Action("show", func() {
+				apidsl.Routing(apidsl.GET("/:id"))
+				apidsl.Params(func() {
+					apidsl.Param("id", design.Integer, func() {
+						apidsl.Enum(42)
+					})
+				})
+				apidsl.Response(design.OK)
+				apidsl.Response(design.NotFound)
+			})
+			apidsl.Action("list", func() {
+				apidsl.Routing(apidsl.GET(""))
+				apidsl.Params(func() {
+					apidsl.Param("vintage", design.Integer, func() {
+						apidsl.Enum(2015)
+					})
+					apidsl.Required("vintage")
+				})
+				apidsl.Response(design.OK)
+			})
+			apidsl.Action("create", func() {
+				apidsl.Routing(apidsl.POST(""))
+				apidsl.Payload(func() {
+					apidsl.Attribute("name", design.String, func() {
+						apidsl.Enum("Number 8")
+					})
+					apidsl.Required("name")
+				})
+				apidsl.Response(design.Created)
+			})
+			apidsl.Action("delete", func() {
+				apidsl.Routing(apidsl.DELETE("/:id"))
+				apidsl.Headers(func() {
+					apidsl.Header("X-Token")
+					apidsl.Required("X-Token")
+				})
+				apidsl.Response(design.NoContent)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+
+		files, genErr = gencontract.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("generates a TestContractCompliance function replaying the action examples", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(1))
+		filename := filepath.Join(testPkg.Abs(), "contract_test.go")
+		content, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		f, err := parser.ParseFile(token.NewFileSet(), filename, content, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(f.Name.Name).Should(Equal("main"))
+		var imports []string
+		for _, imp := range f.Imports {
+			imports = append(imports, imp.Path.Value)
+		}
+		Ω(imports).Should(ContainElement(`"github.com/getkin/kin-openapi/openapi3"`))
+		Ω(f.Scope.Lookup("TestContractCompliance")).ShouldNot(BeNil())
+		Ω(f.Scope.Lookup("checkContract")).ShouldNot(BeNil())
+
+		Ω(string(content)).Should(ContainSubstring(`const contractSpec = "spec/openapi.json"`))
+		Ω(string(content)).Should(ContainSubstring(`app.MountBottleController(service, NewBottleController(service))`))
+		Ω(string(content)).Should(ContainSubstring(`{"bottle show", "GET", "/bottles/{id}", "/api/bottles/42", "", 200},`))
+		Ω(string(content)).Should(ContainSubstring(`{"bottle list", "GET", "/bottles", "/api/bottles?vintage=2015", "", 200},`))
+		Ω(string(content)).Should(ContainSubstring(`{"bottle create", "POST", "/bottles", "/api/bottles", "{\"name\":\"Number 8\"}", 201},`))
+		Ω(string(content)).ShouldNot(ContainSubstring(`"bottle delete"`))
+		Ω(string(content)).Should(ContainSubstring(`return fmt.Errorf("expected status %d, got %d", tc.Status, resp.StatusCode)`))
+	})
+})
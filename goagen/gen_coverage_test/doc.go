@@ -0,0 +1,10 @@
+/*
+Package gencoveragetest provides a generator for the test that detects the drift between the design
+and the generated controller interfaces. The generator creates the api_version_test.go file in the
+service main package. The file contains the TestAPIVersion function which uses reflection to check
+that the method set of each XxxController interface of the app package has one method per action
+defined in the design. The test fails with a descriptive message for each missing method, for
+example when the design gained an action but the app package was not regenerated. The --test-pkg
+flag sets the name of the package of the generated file when the tests do not live in package main.
+*/
+package gencoveragetest
@@ -0,0 +1,13 @@
+package gencoveragetest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenCoverageTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenCoverageTest Suite")
+}
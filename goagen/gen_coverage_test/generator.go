@@ -0,0 +1,161 @@
+package gencoveragetest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the design coverage test generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Target   string                // Name of generated "app" package
+	TestPkg  string                // Name of the package of the generated test file, "main" if empty
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, target, testPkg, ver string
+
+	set := flag.NewFlagSet("coverage", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&target, "pkg", "app", "")
+	set.StringVar(&testPkg, "test-pkg", "main", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	target = codegen.Goify(target, false)
+	g := &Generator{OutDir: outDir, Target: target, TestPkg: testPkg, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the api_version_test.go file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Target == "" {
+		g.Target = "app"
+	}
+	if g.TestPkg == "" {
+		g.TestPkg = "main"
+	}
+
+	controllers := Controllers(g.API)
+	if len(controllers) == 0 {
+		return nil, nil
+	}
+	outPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	filename := filepath.Join(g.OutDir, "api_version_test.go")
+	os.Remove(filename)
+	g.genfiles = append(g.genfiles, filename)
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("reflect"),
+		codegen.SimpleImport("testing"),
+		codegen.SimpleImport(path.Join(filepath.ToSlash(outPkg), g.Target)),
+	}
+	title := fmt.Sprintf("%s: API Version Tests", g.API.Context())
+	if err = file.WriteHeader(title, g.TestPkg, imports); err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{
+		"targetPkg": func() string { return g.Target },
+	}
+	data := map[string]interface{}{
+		"API":         g.API,
+		"Controllers": controllers,
+	}
+	if err = file.ExecuteTemplate("coverage", coverageT, funcs, data); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// Controllers returns the controllers of the API resources that define actions sorted by resource
+// name. The "Name" key of the actions holds the name of the corresponding controller method.
+func Controllers(api *design.APIDefinition) []*genapp.ControllerTemplateData {
+	var controllers []*genapp.ControllerTemplateData
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		data := &genapp.ControllerTemplateData{API: api, Resource: codegen.Goify(r.Name, true)}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			data.Actions = append(data.Actions, map[string]interface{}{
+				"Name": codegen.Goify(a.Name, true),
+			})
+			return nil
+		})
+		if len(data.Actions) > 0 {
+			controllers = append(controllers, data)
+		}
+		return nil
+	})
+	return controllers
+}
+
+// coverageT generates the test that checks that the controller interfaces have one method per
+// design action.
+// template input: map[string]interface{}
+const coverageT = `
+// designActions lists the controller methods of the actions defined in the design indexed by
+// controller interface.
+var designActions = []struct {
+	Controller reflect.Type
+	Methods    []string
+}{
+{{ range .Controllers }}	{
+		Controller: reflect.TypeOf((*{{ targetPkg }}.{{ .Resource }}Controller)(nil)).Elem(),
+		Methods:    []string{ {{- range $i, $a := .Actions }}{{ if $i }}, {{ end }}{{ printf "%q" $a.Name }}{{ end -}} },
+	},
+{{ end }}}
+
+// TestAPIVersion checks that the controller interfaces handle all the actions defined in the
+// design{{ with .API.Version }} of version {{ . }} of the API{{ end }}.
+func TestAPIVersion(t *testing.T) {
+	for _, d := range designActions {
+		for _, m := range d.Methods {
+			if _, ok := d.Controller.MethodByName(m); !ok {
+				t.Errorf("%s does not handle the %s action defined in the design, regenerate the %s package", d.Controller, m, {{ printf "%q" targetPkg }})
+			}
+		}
+	}
+}
+`
@@ -0,0 +1,134 @@
+package gencoveragetest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_coverage_test"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("coveragetest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gencoveragetest.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a dummy API", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API with no resource")
+				apidsl.Description("I told you it's dummy")
+			})
+			dslengine.Run()
+		})
+
+		It("does not generate any file", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+
+	Context("with resources defining actions", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Version("1.0")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.OK)
+				})
+				apidsl.Action("rate_bottle", func() {
+					apidsl.Routing(apidsl.PUT("/:id/rate"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			apidsl.Resource("health", func() {
+				apidsl.Action("check", func() {
+					apidsl.Routing(apidsl.GET("/health"))
+					apidsl.Response(design.OK)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the controller coverage test", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(1))
+			Ω(files[0]).Should(Equal(filepath.Join(testPkg.Abs(), "api_version_test.go")))
+			content, err := ioutil.ReadFile(files[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("package main\n"))
+			Ω(string(content)).Should(ContainSubstring(coverageCode))
+		})
+
+		Context("with a test package name", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--test-pkg=service")
+			})
+
+			It("generates the test file in that package", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(HaveLen(1))
+				content, err := ioutil.ReadFile(files[0])
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("package service\n"))
+			})
+		})
+	})
+})
+
+const coverageCode = `var designActions = []struct {
+	Controller reflect.Type
+	Methods    []string
+}{
+	{
+		Controller: reflect.TypeOf((*app.BottleController)(nil)).Elem(),
+		Methods:    []string{"RateBottle", "Show"},
+	},
+	{
+		Controller: reflect.TypeOf((*app.HealthController)(nil)).Elem(),
+		Methods:    []string{"Check"},
+	},
+}
+
+// TestAPIVersion checks that the controller interfaces handle all the actions defined in the
+// design of version 1.0 of the API.
+func TestAPIVersion(t *testing.T) {
+	for _, d := range designActions {
+		for _, m := range d.Methods {
+			if _, ok := d.Controller.MethodByName(m); !ok {
+				t.Errorf("%s does not handle the %s action defined in the design, regenerate the %s package", d.Controller, m, "app")
+			}
+		}
+	}
+}
+`
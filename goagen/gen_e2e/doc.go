@@ -0,0 +1,9 @@
+/*
+Package gene2e provides a generator for end-to-end tests that exercise the complete service. The
+generated e2e_test.go file defines a TestE2E function that builds the service binary, runs it in a
+Docker container started with the testcontainers-go package, waits for the health endpoint to
+respond with 200 and then sends one request per action checking that the response status is the
+success status declared in the design. Actions that require a payload, security credentials or
+non path parameters are skipped. The file is built only when the "e2e" build tag is set.
+*/
+package gene2e
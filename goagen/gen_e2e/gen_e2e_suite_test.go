@@ -0,0 +1,13 @@
+package gene2e_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenE2E(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenE2E Suite")
+}
@@ -0,0 +1,241 @@
+package gene2e
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the end-to-end test generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Health   string                // Path of the endpoint polled until the service is ready
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, health, ver string
+
+	set := flag.NewFlagSet("e2e", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&health, "health", "/health", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Health: health, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the e2e_test.go file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Health == "" {
+		g.Health = "/health"
+	}
+	filename := filepath.Join(g.OutDir, "e2e_test.go")
+	g.genfiles = append(g.genfiles, filename)
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("os"),
+		codegen.SimpleImport("os/exec"),
+		codegen.SimpleImport("path/filepath"),
+		codegen.SimpleImport("testing"),
+		codegen.NewImport("testcontainers", "github.com/testcontainers/testcontainers-go"),
+		codegen.SimpleImport("github.com/testcontainers/testcontainers-go/wait"),
+	}
+	file.Write([]byte("//go:build e2e\n// +build e2e\n\n"))
+	title := fmt.Sprintf("%s: End-to-End Tests", g.API.Context())
+	if err = file.WriteHeader(title, "main", imports); err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"Port":        Port(g.API),
+		"Health":      g.Health,
+		"Controllers": Controllers(g.API),
+	}
+	if err = file.ExecuteTemplate("e2e", e2eT, nil, data); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Port returns the port the service generated by the main generator listens on.
+func Port(api *design.APIDefinition) string {
+	_, port, err := net.SplitHostPort(api.Host)
+	if err != nil {
+		return "8080"
+	}
+	return port
+}
+
+// Controllers returns the controller data of the API resources that define actions that can be
+// tested end-to-end. Each action has the keys "Name", "Method", "Path" and "Status" where Path is
+// the first action route with the wildcards replaced by example values and Status is the lowest
+// success status declared by the action responses. Actions that require a payload, security
+// credentials, headers or query string parameters are skipped.
+func Controllers(api *design.APIDefinition) []*genapp.ControllerTemplateData {
+	var controllers []*genapp.ControllerTemplateData
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		data := &genapp.ControllerTemplateData{API: api, Resource: codegen.Goify(r.Name, true)}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 || a.WebSocket() || a.Security != nil {
+				return nil
+			}
+			if a.Payload != nil && !a.PayloadOptional {
+				return nil
+			}
+			status := codegen.SuccessStatus(a)
+			if status == 0 {
+				return nil
+			}
+			route := a.Routes[0]
+			params := a.AllParams()
+			wildcards := route.Params()
+			if codegen.HasRequired(params, wildcards...) || codegen.HasRequired(a.Headers) || codegen.HasRequired(r.Headers) {
+				return nil
+			}
+			data.Actions = append(data.Actions, map[string]interface{}{
+				"Name":   codegen.Goify(a.Name, true),
+				"Method": route.Verb,
+				"Path":   examplePath(api, route.FullPath(), params),
+				"Status": status,
+			})
+			return nil
+		})
+		if len(data.Actions) > 0 {
+			controllers = append(controllers, data)
+		}
+		return nil
+	})
+	return controllers
+}
+
+// examplePath replaces the wildcards of path with example values of the corresponding params.
+func examplePath(api *design.APIDefinition, path string, params *design.AttributeDefinition) string {
+	obj := params.Type.ToObject()
+	return design.WildcardRegex.ReplaceAllStringFunc(path, func(w string) string {
+		name := design.WildcardRegex.FindStringSubmatch(w)[1]
+		att, ok := obj[name]
+		if !ok {
+			return "/" + name
+		}
+		return "/" + url.PathEscape(fmt.Sprintf("%v", att.GenerateExample(api.RandomGenerator(), nil)))
+	})
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// e2eT generates the TestE2E function.
+// template input: map[string]interface{}
+const e2eT = `
+// TestE2E builds the service binary, runs it in a container, waits for {{ printf "%q" .Health }} to
+// respond with 200 and checks the response status of one request per action.
+func TestE2E(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "e2e")
+	if err != nil {
+		t.Fatalf("failed to create build directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Build the service binary and the image Dockerfile
+	build := exec.Command("go", "build", "-o", filepath.Join(dir, "service"), ".")
+	build.Env = append(os.Environ(), "CGO_ENABLED=0", "GOOS=linux")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build service: %s\n%s", err, out)
+	}
+	dockerfile := "FROM scratch\nCOPY service /service\nEXPOSE {{ .Port }}\nENTRYPOINT [\"/service\"]\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+		t.Fatalf("failed to write Dockerfile: %s", err)
+	}
+
+	// Start the service container
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			FromDockerfile: testcontainers.FromDockerfile{Context: dir, Dockerfile: "Dockerfile"},
+			ExposedPorts:   []string{"{{ .Port }}/tcp"},
+			WaitingFor: wait.ForHTTP({{ printf "%q" .Health }}).WithPort("{{ .Port }}/tcp").WithStatusCodeMatcher(func(status int) bool {
+				return status == http.StatusOK
+			}),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start service container: %s", err)
+	}
+	defer container.Terminate(ctx)
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve service container host: %s", err)
+	}
+	port, err := container.MappedPort(ctx, "{{ .Port }}/tcp")
+	if err != nil {
+		t.Fatalf("failed to retrieve service container port: %s", err)
+	}
+	url := fmt.Sprintf("http://%s:%s", host, port.Port())
+
+	cases := []struct {
+		Name   string
+		Method string
+		Path   string
+		Status int
+	}{
+{{ range .Controllers }}{{ $res := .Resource }}{{ range .Actions }}		{ {{- printf "%q" (printf "%s %s" $res .Name) }}, {{ printf "%q" .Method }}, {{ printf "%q" .Path }}, {{ .Status -}} },
+{{ end }}{{ end }}	}
+	for _, tc := range cases {
+		req, err := http.NewRequest(tc.Method, url+tc.Path, nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create request: %s", tc.Name, err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("%s: failed to send request: %s", tc.Name, err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.Status {
+			t.Errorf("%s: expected status %d, got %d", tc.Name, tc.Status, resp.StatusCode)
+		}
+	}
+}
+`
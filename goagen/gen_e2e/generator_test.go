@@ -0,0 +1,102 @@
+package gene2e_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_e2e"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	JustBeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("e2etest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--health=/healthz", "--version=" + version.String()}
+
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Title("dummy API")
+			apidsl.Host("localhost:8081")
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.BasePath("/bottles")
+			apidsl.Action("show", func() {
+				apidsl.Routing(apidsl.GET("/:id"))
+				apidsl.Params(func() {
+					apidsl.Param("id", design.Integer, func() {
+						apidsl.Enum(42)
+					})
+				})
+				apidsl.Response(design.NoContent)
+				apidsl.Response(design.NotFound)
+			})
+			apidsl.Action("create", func() {
+				apidsl.Routing(apidsl.POST(""))
+				apidsl.Payload(func() {
+					apidsl.Attribute("name", design.String)
+					apidsl.Required("name")
+				})
+				apidsl.Response(design.Created)
+			})
+			apidsl.Action("list", func() {
+				apidsl.Routing(apidsl.GET(""))
+				apidsl.Params(func() {
+					apidsl.Param("vintage", design.Integer)
+					apidsl.Required("vintage")
+				})
+				apidsl.Response(design.OK)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+
+		files, genErr = gene2e.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("generates a TestE2E function asserting the success status of the testable actions", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(1))
+		filename := filepath.Join(testPkg.Abs(), "e2e_test.go")
+		content, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(HavePrefix("//go:build e2e\n// +build e2e\n"))
+
+		f, err := parser.ParseFile(token.NewFileSet(), filename, content, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		var imports []string
+		for _, imp := range f.Imports {
+			imports = append(imports, imp.Path.Value)
+		}
+		Ω(imports).Should(ContainElement(`"github.com/testcontainers/testcontainers-go"`))
+		Ω(imports).Should(ContainElement(`"os/exec"`))
+		Ω(f.Scope.Lookup("TestE2E")).ShouldNot(BeNil())
+
+		Ω(string(content)).Should(ContainSubstring(`exec.Command("go", "build", "-o", filepath.Join(dir, "service"), ".")`))
+		Ω(string(content)).Should(ContainSubstring(`wait.ForHTTP("/healthz").WithPort("8081/tcp")`))
+		Ω(string(content)).Should(ContainSubstring(`{"Bottle Show", "GET", "/bottles/42", 204},`))
+		Ω(string(content)).ShouldNot(ContainSubstring(`"Bottle Create"`))
+		Ω(string(content)).ShouldNot(ContainSubstring(`"Bottle List"`))
+	})
+})
@@ -0,0 +1,66 @@
+// explorer.js makes the API explorer page interactive: the [data-toggle] buttons show and hide
+// the [data-panel] element of their [data-collapsible] parent and the [data-try] forms send the
+// request to the base URL entered in the [data-base-url] input.
+document.addEventListener("DOMContentLoaded", function () {
+	var base = document.querySelector("[data-base-url]");
+	base.value = document.body.dataset.base;
+
+	document.querySelectorAll("[data-toggle]").forEach(function (button) {
+		button.addEventListener("click", function () {
+			var parent = button.closest("[data-collapsible]");
+			parent.querySelector(":scope > [data-panel]").classList.toggle("hidden");
+		});
+	});
+
+	document.querySelectorAll("[data-try]").forEach(function (form) {
+		var submit = form.querySelector("[type=submit]");
+		var status = form.querySelector("[data-status]");
+		var response = form.querySelector("[data-response]");
+
+		function show(s, r) {
+			status.textContent = s;
+			status.classList.toggle("hidden", s === "");
+			response.textContent = r;
+			response.classList.toggle("hidden", r === "");
+		}
+
+		form.addEventListener("submit", async function (event) {
+			event.preventDefault();
+			var route = form.querySelector("[data-route]").value.split(" ");
+			var verb = route[0], path = route[1], query = new URLSearchParams();
+			form.querySelectorAll("[data-param]").forEach(function (input) {
+				if (input.value === "") {
+					return;
+				}
+				var segment = new RegExp("[:*]" + input.name + "(?=/|$)");
+				if (segment.test(path)) {
+					path = path.replace(segment, encodeURIComponent(input.value));
+				} else {
+					query.append(input.name, input.value);
+				}
+			});
+			var url = base.value.replace(new RegExp("/+$"), "") + path;
+			if (query.toString() !== "") {
+				url += "?" + query.toString();
+			}
+			var init = { method: verb, headers: {} };
+			var body = form.querySelector("[data-body]");
+			if (body && body.value !== "") {
+				init.body = body.value;
+				init.headers["Content-Type"] = "application/json";
+			}
+			submit.disabled = true;
+			try {
+				var resp = await fetch(url, init);
+				var text = await resp.text();
+				try {
+					text = JSON.stringify(JSON.parse(text), null, 2);
+				} catch (e) {}
+				show(resp.status + " " + resp.statusText, text);
+			} catch (e) {
+				show("request failed", e.message);
+			}
+			submit.disabled = false;
+		});
+	});
+});
@@ -0,0 +1,12 @@
+/*
+Package genexplorer provides a generator for an interactive HTML API explorer. The generator
+creates a single "explorer/index.html" file that lists the API resources in collapsible sections.
+Each action expands to show its description, its routes and a table describing its parameters
+together with a "Try it" form that sends the request with fetch to the base URL entered at the
+top of the page and displays the response.
+
+The page is self-contained: the stylesheet and the script that implement the Tailwind CSS classes
+used by the page and the "Try it" forms are inlined so that the file can be served as is or opened
+directly in a browser without network access.
+*/
+package genexplorer
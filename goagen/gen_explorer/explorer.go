@@ -0,0 +1,218 @@
+package genexplorer
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
+)
+
+type (
+	// Data is the data used to render the explorer page.
+	Data struct {
+		// Title is the page title, the API title or name.
+		Title string
+		// Description is the API description.
+		Description string
+		// BaseURL is the initial value of the base URL the requests are sent to, empty if
+		// the API does not define a host in which case the requests are sent to the origin
+		// serving the page.
+		BaseURL string
+		// Controllers describes the API resources, see Controllers.
+		Controllers []*genapp.ControllerTemplateData
+	}
+
+	// Param is a row of the action parameter tables.
+	Param struct {
+		// Name is the name of the parameter.
+		Name string
+		// In is "path" if the parameter is a path parameter of one of the action routes,
+		// "query" otherwise.
+		In string
+		// Type is the name of the parameter type.
+		Type string
+		// Required is true if the parameter is required.
+		Required bool
+		// Description is the description of the parameter.
+		Description string
+	}
+)
+
+// Controllers returns the controller data of the API resources that define actions. Each action
+// has the keys "Name", "Description", "Routes", "Params" listing the action parameters as
+// []*Param and "Payload" holding an example of the request body in JSON or the empty string if
+// the action has no payload.
+func Controllers(api *design.APIDefinition) []*genapp.ControllerTemplateData {
+	var controllers []*genapp.ControllerTemplateData
+	for _, r := range api.SortedResources() {
+		data := &genapp.ControllerTemplateData{API: api, Resource: codegen.Goify(r.Name, true)}
+		for _, a := range r.SortedActions() {
+			if len(a.Routes) == 0 {
+				continue
+			}
+			data.Actions = append(data.Actions, map[string]interface{}{
+				"Name":        a.Name,
+				"Description": a.Description,
+				"Routes":      a.Routes,
+				"Params":      Params(a),
+				"Payload":     payloadExample(api, a),
+			})
+		}
+		if len(data.Actions) > 0 {
+			controllers = append(controllers, data)
+		}
+	}
+	return controllers
+}
+
+// Params returns the rows of the table describing the parameters of the given action sorted by
+// name.
+func Params(a *design.ActionDefinition) []*Param {
+	params := a.AllParams()
+	if params == nil {
+		return nil
+	}
+	var rows []*Param
+	params.Type.ToObject().IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		row := &Param{
+			Name:        n,
+			In:          "query",
+			Type:        att.Type.Name(),
+			Required:    params.IsRequired(n),
+			Description: att.Description,
+		}
+		for _, r := range a.Routes {
+			for _, p := range r.Params() {
+				if p == n {
+					row.In = "path"
+					row.Required = true
+				}
+			}
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	return rows
+}
+
+// Explorer renders the HTML page of the API explorer.
+func Explorer(api *design.APIDefinition, controllers []*genapp.ControllerTemplateData) (string, error) {
+	data := &Data{
+		Title:       api.Title,
+		Description: api.Description,
+		Controllers: controllers,
+	}
+	if data.Title == "" {
+		data.Title = api.Name
+	}
+	if api.Host != "" {
+		scheme := "http"
+		if len(api.Schemes) > 0 {
+			scheme = api.Schemes[0]
+		}
+		data.BaseURL = scheme + "://" + api.Host
+	}
+	var b bytes.Buffer
+	if err := explorerTmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// payloadExample returns an example of the action payload in JSON, the empty string if the action
+// has no payload.
+func payloadExample(api *design.APIDefinition, a *design.ActionDefinition) string {
+	if a.Payload == nil {
+		return ""
+	}
+	js, err := json.MarshalIndent(a.Payload.GenerateExample(api.RandomGenerator(), nil), "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(js)
+}
+
+var (
+	// explorerCSS styles the explorer page with the Tailwind CSS utility classes it uses.
+	//go:embed assets/explorer.css
+	explorerCSS string
+
+	// explorerJS implements the collapsible sections and the "Try it" forms of the
+	// explorer page.
+	//go:embed assets/explorer.js
+	explorerJS string
+)
+
+var explorerTmpl = template.Must(template.New("explorer").Funcs(template.FuncMap{
+	"style":  func() template.CSS { return template.CSS(explorerCSS) },
+	"script": func() template.JS { return template.JS(explorerJS) },
+}).Parse(explorerT))
+
+// explorerT renders the explorer page.
+// template input: *Data
+const explorerT = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }} - API Explorer</title>
+<style>{{ style }}</style>
+<script>{{ script }}</script>
+</head>
+<body class="bg-gray-50 text-gray-900" data-base="{{ .BaseURL }}">
+<header class="bg-white border-b px-8 py-6">
+<h1 class="text-3xl font-bold">{{ .Title }}</h1>
+{{ if .Description }}<p class="mt-2 text-gray-600">{{ .Description }}</p>
+{{ end }}<label class="mt-4 flex items-center gap-2 text-sm">
+<span class="font-semibold">Base URL</span>
+<input type="text" class="flex-1 rounded border px-2 py-1 font-mono" placeholder="same origin" data-base-url>
+</label>
+</header>
+<main class="mx-auto max-w-5xl space-y-6 px-8 py-6">
+{{ range .Controllers }}<section class="rounded-lg border bg-white shadow-sm" data-collapsible>
+<h2>
+<button type="button" class="w-full px-4 py-3 text-left text-xl font-semibold" data-toggle>{{ .Resource }}</button>
+</h2>
+<div class="divide-y border-t" data-panel>
+{{ range .Actions }}<article class="px-4 py-3" data-collapsible>
+<button type="button" class="flex w-full items-center gap-3 text-left" data-toggle>
+{{ range .Routes }}<span class="rounded bg-indigo-100 px-2 py-0.5 font-mono text-xs font-bold text-indigo-700">{{ .Verb }}</span>
+<code class="font-mono text-sm">{{ .FullPath }}</code>
+{{ end }}<span class="ml-auto text-sm text-gray-500">{{ .Name }}</span>
+</button>
+<div class="mt-3 space-y-4 hidden" data-panel>
+{{ if .Description }}<p class="text-gray-700">{{ .Description }}</p>
+{{ end }}{{ if .Params }}<table class="w-full text-left text-sm">
+<thead>
+<tr class="border-b"><th class="py-1">Name</th><th>In</th><th>Type</th><th>Required</th><th>Description</th></tr>
+</thead>
+<tbody>
+{{ range .Params }}<tr class="border-b"><td class="py-1 font-mono">{{ .Name }}</td><td>{{ .In }}</td><td>{{ .Type }}</td><td>{{ if .Required }}yes{{ else }}no{{ end }}</td><td>{{ .Description }}</td></tr>
+{{ end }}</tbody>
+</table>
+{{ end }}<form class="space-y-2 rounded bg-gray-50 p-3" data-try>
+<h3 class="font-semibold">Try it</h3>
+<select class="rounded border px-2 py-1 font-mono text-sm" data-route>
+{{ range .Routes }}<option value="{{ .Verb }} {{ .FullPath }}">{{ .Verb }} {{ .FullPath }}</option>
+{{ end }}</select>
+{{ range .Params }}<label class="flex items-center gap-2 text-sm">
+<span class="w-40 font-mono">{{ .Name }}</span>
+<input type="text" class="flex-1 rounded border px-2 py-1" name="{{ .Name }}" placeholder="{{ .Type }}" data-param{{ if eq .In "path" }} required{{ end }}>
+</label>
+{{ end }}{{ if .Payload }}<textarea class="h-40 w-full rounded border p-2 font-mono text-sm" data-body>{{ .Payload }}</textarea>
+{{ end }}<button type="submit" class="rounded bg-indigo-600 px-4 py-1 text-white">Send</button>
+<p class="font-mono text-sm hidden" data-status></p>
+<pre class="overflow-x-auto rounded bg-gray-900 p-3 text-sm text-gray-100 hidden" data-response></pre>
+</form>
+</div>
+</article>
+{{ end }}</div>
+</section>
+{{ end }}</main>
+</body>
+</html>
+`
@@ -0,0 +1,13 @@
+package genexplorer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenExplorer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenExplorer Suite")
+}
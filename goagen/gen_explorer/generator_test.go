@@ -0,0 +1,181 @@
+package genexplorer_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_explorer"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/html"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("explorertest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genexplorer.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with resources", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+				apidsl.Host("localhost:8080")
+				apidsl.Scheme("https")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Description("Retrieve a bottle <by ID>")
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer, "Bottle ID")
+						apidsl.Param("view", design.String)
+					})
+					apidsl.Response(design.NoContent)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String)
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.Created)
+				})
+			})
+			apidsl.Resource("account", func() {
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET("/accounts"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("generates a valid HTML page with one section per resource", func() {
+			Ω(genErr).Should(BeNil())
+			filename := filepath.Join(testPkg.Abs(), "explorer", "index.html")
+			Ω(files).Should(ContainElement(filename))
+			content, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			doc, err := html.Parse(bytes.NewReader(content))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(text(find(doc, "title")[0])).Should(Equal("dummy API - API Explorer"))
+			Ω(attr(find(doc, "body")[0], "data-base")).Should(Equal("https://localhost:8080"))
+			var resources []string
+			for _, s := range find(doc, "section") {
+				resources = append(resources, strings.TrimSpace(text(find(s, "h2")[0])))
+			}
+			Ω(resources).Should(Equal([]string{"Account", "Bottle"}))
+			Ω(find(doc, "article")).Should(HaveLen(3))
+			Ω(find(doc, "form")).Should(HaveLen(3))
+		})
+
+		It("inlines the stylesheet and the script", func() {
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "explorer", "index.html"))
+			Ω(err).ShouldNot(HaveOccurred())
+			doc, err := html.Parse(bytes.NewReader(content))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			for _, s := range find(doc, "script") {
+				Ω(attr(s, "src")).Should(BeEmpty())
+			}
+			Ω(find(doc, "link")).Should(BeEmpty())
+			Ω(text(find(doc, "style")[0])).Should(ContainSubstring(".space-y-2>:not([hidden])~:not([hidden])"))
+			Ω(text(find(doc, "script")[0])).Should(ContainSubstring(`document.querySelectorAll("[data-try]")`))
+		})
+
+		It("describes the action parameters and payload", func() {
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "explorer", "index.html"))
+			Ω(err).ShouldNot(HaveOccurred())
+			doc, err := html.Parse(bytes.NewReader(content))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var show *html.Node
+			for _, a := range find(doc, "article") {
+				if strings.Contains(text(a), "Retrieve a bottle <by ID>") {
+					show = a
+				}
+			}
+			Ω(show).ShouldNot(BeNil())
+			var rows []string
+			for _, tr := range find(find(show, "tbody")[0], "tr") {
+				var cells []string
+				for _, td := range find(tr, "td") {
+					cells = append(cells, text(td))
+				}
+				rows = append(rows, strings.Join(cells, "|"))
+			}
+			Ω(rows).Should(Equal([]string{"id|path|integer|yes|Bottle ID", "view|query|string|no|"}))
+			Ω(attr(find(show, "option")[0], "value")).Should(Equal("GET /bottles/:id"))
+			Ω(find(show, "textarea")).Should(BeEmpty())
+
+			for _, a := range find(doc, "article") {
+				if strings.Contains(text(a), "POST") {
+					Ω(text(find(a, "textarea")[0])).Should(ContainSubstring(`"name":`))
+				}
+			}
+		})
+	})
+})
+
+// find returns the descendant elements of n with the given tag name in document order.
+func find(n *html.Node, tag string) []*html.Node {
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag {
+			nodes = append(nodes, c)
+		}
+		nodes = append(nodes, find(c, tag)...)
+	}
+	return nodes
+}
+
+// text returns the concatenated text content of n.
+func text(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var s string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		s += text(c)
+	}
+	return s
+}
+
+// attr returns the value of the attribute of n with the given key.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
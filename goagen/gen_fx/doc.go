@@ -0,0 +1,8 @@
+/*
+Package genfx provides a generator for Uber fx (https://godoc.org/go.uber.org/fx) modules.
+The generator creates a fx.go file in the service main package that defines one fx module per
+resource. Each module provides the resource controller and mounts it onto the goa service. The
+generated ServiceModule function supplies the service to the fx application and registers the
+lifecycle hooks that start and stop the HTTP server.
+*/
+package genfx
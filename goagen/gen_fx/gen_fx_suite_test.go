@@ -0,0 +1,13 @@
+package genfx_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenFx(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenFx Suite")
+}
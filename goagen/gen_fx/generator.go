@@ -0,0 +1,190 @@
+package genfx
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the fx modules code generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Target   string                // Name of generated "app" package
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, target, ver string
+
+	set := flag.NewFlagSet("fx", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&target, "pkg", "app", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	target = codegen.Goify(target, false)
+	g := &Generator{OutDir: outDir, Target: target, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the fx.go file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Target == "" {
+		g.Target = "app"
+	}
+
+	outPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	fxFile := filepath.Join(g.OutDir, "fx.go")
+	os.Remove(fxFile)
+	g.genfiles = append(g.genfiles, fxFile)
+	file, err := codegen.SourceFileFor(fxFile)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("net"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport(path.Join(filepath.ToSlash(outPkg), g.Target)),
+		codegen.SimpleImport("go.uber.org/fx"),
+	}
+	title := fmt.Sprintf("%s: fx Modules", g.API.Context())
+	if err = file.WriteHeader(title, "main", imports); err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{
+		"targetPkg": func() string { return g.Target },
+	}
+	if err = file.ExecuteTemplate("service", serviceT, funcs, g.API); err != nil {
+		return nil, err
+	}
+	var resources []*design.ResourceDefinition
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		resources = append(resources, r)
+		return file.ExecuteTemplate("module", moduleT, funcs, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = file.ExecuteTemplate("modules", modulesT, funcs, resources); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+const (
+	// serviceT generates the module that supplies the service and manages the server lifecycle.
+	// template input: *design.APIDefinition
+	serviceT = `
+// ServiceModule provides the given service to the fx application and registers the lifecycle hooks
+// that start and stop the HTTP server listening on addr. The service uses the middlewares provided
+// with Middleware.
+func ServiceModule(service *goa.Service, addr string) fx.Option {
+	return fx.Options(
+		fx.Provide(func(p middlewareParams) *goa.Service {
+			for _, m := range p.Middleware {
+				service.Use(m)
+			}
+			return service
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, service *goa.Service) {
+			srv := &http.Server{Addr: addr, Handler: service.Mux}
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					ln, err := net.Listen("tcp", addr)
+					if err != nil {
+						return err
+					}
+					service.LogInfo("listen", "transport", "http", "addr", ln.Addr().String())
+					go srv.Serve(ln)
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					service.CancelAll()
+					return srv.Shutdown(ctx)
+				},
+			})
+		}),
+	)
+}
+
+// Middleware provides a service middleware to the fx application. fx value groups are unordered so
+// middlewares that depend on each other must be combined into a single middleware.
+func Middleware(m goa.Middleware) fx.Option {
+	return fx.Provide(fx.Annotated{
+		Group:  "middleware",
+		Target: func() goa.Middleware { return m },
+	})
+}
+
+// middlewareParams collects the middlewares provided with Middleware.
+type middlewareParams struct {
+	fx.In
+
+	Middleware []goa.Middleware ` + "`" + `group:"middleware"` + "`" + `
+}
+`
+
+	// moduleT generates the module of a single resource.
+	// template input: *design.ResourceDefinition
+	moduleT = `{{ $ctrlName := printf "%s%s" (goify .Name true) "Controller" }}
+// {{ goify .Name true }}Module provides the {{ .Name }} controller and mounts it onto the service.
+var {{ goify .Name true }}Module = fx.Module({{ printf "%q" .Name }},
+	fx.Provide(New{{ $ctrlName }}),
+	fx.Invoke(func(service *goa.Service, ctrl *{{ $ctrlName }}) {
+		{{ targetPkg }}.Mount{{ $ctrlName }}(service, ctrl)
+	}),
+)
+`
+
+	// modulesT generates the module that groups all the resource modules.
+	// template input: []*design.ResourceDefinition
+	modulesT = `
+// Modules groups the modules of all the API resources. Use it together with ServiceModule to build
+// the fx application:
+//
+//	fx.New(ServiceModule(service, ":8080"), Middleware(middleware.RequestID()), Modules).Run()
+var Modules = fx.Options({{ range . }}
+	{{ goify .Name true }}Module,{{ end }}
+)
+`
+)
@@ -0,0 +1,106 @@
+package genfx_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_fx"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("fxtest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genfx.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a dummy API", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name:        "test api",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+			}
+		})
+
+		It("generates the service module", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(1))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "fx.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("func ServiceModule(service *goa.Service, addr string) fx.Option {"))
+			Ω(string(content)).Should(ContainSubstring("return srv.Shutdown(ctx)"))
+			Ω(string(content)).Should(ContainSubstring("func Middleware(m goa.Middleware) fx.Option {"))
+			Ω(string(content)).Should(ContainSubstring("Middleware []goa.Middleware `group:\"middleware\"`"))
+		})
+	})
+
+	Context("with resources", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			apidsl.Resource("account", func() {
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET(""))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("generates one module per resource", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(1))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "fx.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(bottleModule))
+			Ω(string(content)).Should(ContainSubstring(modules))
+		})
+	})
+})
+
+const (
+	bottleModule = `var BottleModule = fx.Module("bottle",
+	fx.Provide(NewBottleController),
+	fx.Invoke(func(service *goa.Service, ctrl *BottleController) {
+		app.MountBottleController(service, ctrl)
+	}),
+)`
+
+	modules = `var Modules = fx.Options(
+	AccountModule,
+	BottleModule,
+)`
+)
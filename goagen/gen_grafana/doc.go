@@ -0,0 +1,12 @@
+/*
+Package gengrafana provides a generator for Grafana (https://grafana.com) dashboards.
+The generator creates a single dashboard.json file under the "grafana" directory. The dashboard
+contains one row per resource and each row contains the request rate, the latency 99th percentile
+and the error rate panels of the resource actions.
+
+The panels query the Prometheus "<prefix>_requests_total" counter and the
+"<prefix>_request_duration_seconds" histogram. Both metrics must be labeled with the controller
+name ("ctrl"), the action name ("action") and the response status code ("code") using the names
+that appear in the generated mount functions, e.g. ctrl="Bottle" and action="Show".
+*/
+package gengrafana
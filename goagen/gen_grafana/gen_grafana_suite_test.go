@@ -0,0 +1,13 @@
+package gengrafana_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenGrafana(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenGrafana Suite")
+}
@@ -0,0 +1,88 @@
+package gengrafana
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the Grafana dashboard generator.
+type Generator struct {
+	API        *design.APIDefinition // The API definition
+	OutDir     string                // Path to output directory
+	Prefix     string                // Prefix of the Prometheus metric names
+	Datasource string                // Name of the Grafana Prometheus data source
+	genfiles   []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, prefix, datasource, ver string
+	set := flag.NewFlagSet("grafana", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&prefix, "prefix", "goa", "")
+	set.StringVar(&datasource, "datasource", "Prometheus", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Prefix: prefix, Datasource: datasource, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Grafana dashboard file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Prefix == "" {
+		g.Prefix = "goa"
+	}
+	if g.Datasource == "" {
+		g.Datasource = "Prometheus"
+	}
+
+	grafanaDir := filepath.Join(g.OutDir, "grafana")
+	os.RemoveAll(grafanaDir)
+	if err = os.MkdirAll(grafanaDir, 0755); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, grafanaDir)
+
+	d := NewDashboard(g.API, Controllers(g.API), g.Prefix, g.Datasource)
+	js, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	filename := filepath.Join(grafanaDir, "dashboard.json")
+	if err := ioutil.WriteFile(filename, js, 0644); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, filename)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
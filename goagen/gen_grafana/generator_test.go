@@ -0,0 +1,114 @@
+package gengrafana_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_grafana"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("grafanatest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gengrafana.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with resources", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.NoContent)
+				})
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET(""))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			apidsl.Resource("account", func() {
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST("/accounts"))
+					apidsl.Response(design.Created)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("generates a dashboard with one row per resource", func() {
+			Ω(genErr).Should(BeNil())
+			filename := filepath.Join(testPkg.Abs(), "grafana", "dashboard.json")
+			Ω(files).Should(ContainElement(filename))
+			content, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			var d gengrafana.Dashboard
+			Ω(json.Unmarshal(content, &d)).ShouldNot(HaveOccurred())
+			Ω(d.Title).Should(Equal("dummy API"))
+
+			var rows, titles []string
+			for _, p := range d.Panels {
+				if p.Type == "row" {
+					rows = append(rows, p.Title)
+					continue
+				}
+				titles = append(titles, p.Title)
+				Ω(p.Targets).Should(HaveLen(1))
+			}
+			Ω(rows).Should(Equal([]string{"Account", "Bottle"}))
+			Ω(titles).Should(ConsistOf(
+				"Account Create request rate",
+				"Account Create latency P99",
+				"Account Create error rate",
+				"Bottle List request rate",
+				"Bottle List latency P99",
+				"Bottle List error rate",
+				"Bottle Show request rate",
+				"Bottle Show latency P99",
+				"Bottle Show error rate",
+			))
+		})
+
+		It("queries the action metrics", func() {
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "grafana", "dashboard.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			var d gengrafana.Dashboard
+			Ω(json.Unmarshal(content, &d)).ShouldNot(HaveOccurred())
+			for _, p := range d.Panels {
+				if p.Title == "Bottle Show request rate" {
+					Ω(p.Description).Should(Equal("GET /bottles/:id"))
+					Ω(p.Targets[0].Expr).Should(Equal(`sum(rate(goa_requests_total{ctrl="Bottle",action="Show"}[5m]))`))
+				}
+			}
+		})
+	})
+})
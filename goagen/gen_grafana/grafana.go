@@ -0,0 +1,156 @@
+package gengrafana
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
+)
+
+type (
+	// Dashboard represents a Grafana dashboard.
+	Dashboard struct {
+		Title         string   `json:"title"`
+		Description   string   `json:"description,omitempty"`
+		Tags          []string `json:"tags"`
+		Timezone      string   `json:"timezone"`
+		SchemaVersion int      `json:"schemaVersion"`
+		Refresh       string   `json:"refresh"`
+		Time          *Range   `json:"time"`
+		Panels        []*Panel `json:"panels"`
+	}
+
+	// Range is the time range displayed by a dashboard.
+	Range struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+
+	// Panel represents a dashboard panel, rows are panels of type "row".
+	Panel struct {
+		ID          int         `json:"id"`
+		Type        string      `json:"type"`
+		Title       string      `json:"title"`
+		Description string      `json:"description,omitempty"`
+		Datasource  string      `json:"datasource,omitempty"`
+		GridPos     *GridPos    `json:"gridPos"`
+		Collapsed   bool        `json:"collapsed,omitempty"`
+		Targets     []*Target   `json:"targets,omitempty"`
+		FieldConfig interface{} `json:"fieldConfig,omitempty"`
+	}
+
+	// GridPos is the position and size of a panel in the dashboard grid.
+	GridPos struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+		W int `json:"w"`
+		H int `json:"h"`
+	}
+
+	// Target is a panel Prometheus query.
+	Target struct {
+		RefID        string `json:"refId"`
+		Expr         string `json:"expr"`
+		LegendFormat string `json:"legendFormat,omitempty"`
+	}
+)
+
+// Controllers returns the controller data of the API resources that define actions. Each action
+// has the keys "Name" and "Routes".
+func Controllers(api *design.APIDefinition) []*genapp.ControllerTemplateData {
+	var controllers []*genapp.ControllerTemplateData
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		data := &genapp.ControllerTemplateData{API: api, Resource: codegen.Goify(r.Name, true)}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			data.Actions = append(data.Actions, map[string]interface{}{
+				"Name":   codegen.Goify(a.Name, true),
+				"Routes": a.Routes,
+			})
+			return nil
+		})
+		if len(data.Actions) > 0 {
+			controllers = append(controllers, data)
+		}
+		return nil
+	})
+	return controllers
+}
+
+// NewDashboard returns the dashboard that displays the request rate, the latency 99th percentile
+// and the error rate of each action of the given controllers. prefix is the prefix of the
+// Prometheus metric names and datasource the name of the Grafana Prometheus data source.
+func NewDashboard(api *design.APIDefinition, controllers []*genapp.ControllerTemplateData, prefix, datasource string) *Dashboard {
+	title := api.Title
+	if title == "" {
+		title = api.Name
+	}
+	d := &Dashboard{
+		Title:         title,
+		Description:   api.Description,
+		Tags:          []string{"goa", api.Name},
+		Timezone:      "browser",
+		SchemaVersion: 16,
+		Refresh:       "30s",
+		Time:          &Range{From: "now-6h", To: "now"},
+		Panels:        []*Panel{},
+	}
+	id, y := 1, 0
+	for _, c := range controllers {
+		d.Panels = append(d.Panels, &Panel{
+			ID:      id,
+			Type:    "row",
+			Title:   c.Resource,
+			GridPos: &GridPos{X: 0, Y: y, W: 24, H: 1},
+		})
+		id++
+		y++
+		for _, a := range c.Actions {
+			name := a["Name"].(string)
+			selector := fmt.Sprintf(`ctrl=%q,action=%q`, c.Resource, name)
+			desc := routesDescription(a["Routes"].([]*design.RouteDefinition))
+			panels := []struct{ title, expr, unit string }{
+				{
+					"request rate",
+					fmt.Sprintf(`sum(rate(%s_requests_total{%s}[5m]))`, prefix, selector),
+					"reqps",
+				},
+				{
+					"latency P99",
+					fmt.Sprintf(`histogram_quantile(0.99, sum(rate(%s_request_duration_seconds_bucket{%s}[5m])) by (le))`, prefix, selector),
+					"s",
+				},
+				{
+					"error rate",
+					fmt.Sprintf(`sum(rate(%s_requests_total{%s,code=~"5.."}[5m])) / sum(rate(%s_requests_total{%s}[5m]))`, prefix, selector, prefix, selector),
+					"percentunit",
+				},
+			}
+			for i, p := range panels {
+				d.Panels = append(d.Panels, &Panel{
+					ID:          id,
+					Type:        "timeseries",
+					Title:       fmt.Sprintf("%s %s %s", c.Resource, name, p.title),
+					Description: desc,
+					Datasource:  datasource,
+					GridPos:     &GridPos{X: i * 8, Y: y, W: 8, H: 8},
+					Targets:     []*Target{{RefID: "A", Expr: p.expr, LegendFormat: name}},
+					FieldConfig: map[string]interface{}{"defaults": map[string]interface{}{"unit": p.unit}},
+				})
+				id++
+			}
+			y += 8
+		}
+	}
+	return d
+}
+
+// routesDescription returns the list of routes of an action, e.g. "GET /bottles/:id".
+func routesDescription(routes []*design.RouteDefinition) string {
+	descs := make([]string, len(routes))
+	for i, r := range routes {
+		descs[i] = fmt.Sprintf("%s %s", r.Verb, r.FullPath())
+	}
+	return strings.Join(descs, ", ")
+}
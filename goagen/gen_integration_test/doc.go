@@ -0,0 +1,17 @@
+/*
+Package genintegrationtest provides a generator for integration tests that exercise the service
+over real HTTP round-trips. The generator creates one <resource>_integration_test.go file per
+resource in the service main package. Each file defines a TestIntegration_<Resource>Resource
+function that starts the service with all the controllers mounted using httptest.NewServer and
+runs one sub-test per action. A sub-test sends a request built from the design examples with
+http.DefaultClient, checks that the response status is the success status declared in the design
+and decodes the response body into the generated media type, validating it when the type defines
+validations. The sub-tests of actions that require security credentials, request headers or a
+non JSON payload are skipped.
+
+The tests mount the controllers created by the New<Resource>Controller functions that "goagen main"
+generates. Each function must accept the *goa.Service and return a value implementing the
+<Resource>Controller interface of the app package. The files belong to package main by default, the
+--test-pkg flag sets the name of the package defining the constructors when it is not main.
+*/
+package genintegrationtest
@@ -0,0 +1,13 @@
+package genintegrationtest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenIntegrationTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenIntegrationTest Suite")
+}
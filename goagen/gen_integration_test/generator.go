@@ -0,0 +1,305 @@
+package genintegrationtest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+type (
+	// Generator is the integration tests generator.
+	Generator struct {
+		API      *design.APIDefinition // The API definition
+		OutDir   string                // Path to output directory
+		Target   string                // Name of generated "app" package
+		TestPkg  string                // Name of the package of the generated test files, "main" if empty
+		genfiles []string              // Generated files
+	}
+
+	// ResourceTemplateData contains the information required to generate the integration
+	// test of a resource.
+	ResourceTemplateData struct {
+		API      *design.APIDefinition      // API definition
+		Resource *design.ResourceDefinition // Tested resource
+		Actions  []*ActionTemplateData      // Sub-tests, one per action
+	}
+
+	// ActionTemplateData describes the request sent by the sub-test of an action and the
+	// expected response.
+	ActionTemplateData struct {
+		Name   string // Action name
+		Skip   string // Reason why the sub-test is skipped if any
+		Method string // Request method
+		Path   string // Request path and query string built from the parameter examples
+		Body   string // JSON request body built from the payload example if any
+		Status int    // Expected response status
+		Result string // Name of the type the response body decodes into if any
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, target, testPkg, ver string
+
+	set := flag.NewFlagSet("integration_test", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&target, "pkg", "app", "")
+	set.StringVar(&testPkg, "test-pkg", "main", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	target = codegen.Goify(target, false)
+	g := &Generator{OutDir: outDir, Target: target, TestPkg: testPkg, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces one integration test file per resource.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Target == "" {
+		g.Target = "app"
+	}
+	if g.TestPkg == "" {
+		g.TestPkg = "main"
+	}
+
+	outPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{
+		"targetPkg": func() string { return g.Target },
+	}
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		data := &ResourceTemplateData{API: g.API, Resource: r, Actions: NewActions(g.API, r, g.Target)}
+		if len(data.Actions) == 0 {
+			return nil
+		}
+		filename := filepath.Join(g.OutDir, codegen.SnakeCase(r.Name)+"_integration_test.go")
+		os.Remove(filename)
+		g.genfiles = append(g.genfiles, filename)
+		file, err := codegen.SourceFileFor(filename)
+		if err != nil {
+			return err
+		}
+		imports := []*codegen.ImportSpec{
+			codegen.SimpleImport("encoding/json"),
+			codegen.SimpleImport("net/http"),
+			codegen.SimpleImport("net/http/httptest"),
+			codegen.SimpleImport("strings"),
+			codegen.SimpleImport("testing"),
+			codegen.SimpleImport("github.com/goadesign/goa"),
+			codegen.SimpleImport("github.com/goadesign/goa/middleware"),
+			codegen.SimpleImport(path.Join(filepath.ToSlash(outPkg), g.Target)),
+		}
+		title := fmt.Sprintf("%s: %s Integration Tests", g.API.Context(), r.Name)
+		if err := file.WriteHeader(title, g.TestPkg, imports); err != nil {
+			return err
+		}
+		if err := file.ExecuteTemplate("integration", integrationT, funcs, data); err != nil {
+			return err
+		}
+		return file.FormatCode()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// NewActions returns the sub-tests of the given resource, one per action sorted by name. target
+// is the name of the generated app package used to qualify the result type names.
+func NewActions(api *design.APIDefinition, r *design.ResourceDefinition, target string) []*ActionTemplateData {
+	var actions []*ActionTemplateData
+	for _, a := range r.SortedActions() {
+		if len(a.Routes) == 0 {
+			continue
+		}
+		data := &ActionTemplateData{Name: a.Name}
+		actions = append(actions, data)
+		route := a.Routes[0]
+		params := a.AllParams()
+		switch {
+		case a.WebSocket():
+			data.Skip = "WebSocket actions are not supported"
+		case a.Security != nil:
+			data.Skip = "the action requires security credentials"
+		case codegen.HasRequired(a.Headers) || codegen.HasRequired(r.Headers):
+			data.Skip = "the action requires request headers"
+		case a.FormData || a.MultipartIngest:
+			data.Skip = "the action payload is not JSON"
+		}
+		if data.Skip != "" {
+			continue
+		}
+		resp := successResponse(a)
+		if resp == nil {
+			data.Skip = "the action does not define a success response"
+			continue
+		}
+		if a.Payload != nil {
+			js, err := json.Marshal(a.Payload.GenerateExample(api.RandomGenerator(), nil))
+			if err != nil {
+				data.Skip = "the payload example cannot be serialized to JSON"
+				continue
+			}
+			data.Body = string(js)
+		}
+		data.Method = route.Verb
+		data.Path = examplePath(api, route, params)
+		data.Status = resp.Status
+		data.Result = resultType(api, resp, target)
+	}
+	return actions
+}
+
+// successResponse returns the response of the action with the lowest 2xx status, nil if there is
+// none.
+func successResponse(a *design.ActionDefinition) *design.ResponseDefinition {
+	var success []*design.ResponseDefinition
+	for _, resp := range a.Responses {
+		if resp.Status >= 200 && resp.Status < 300 {
+			success = append(success, resp)
+		}
+	}
+	if len(success) == 0 {
+		return nil
+	}
+	sort.Slice(success, func(i, j int) bool { return success[i].Status < success[j].Status })
+	return success[0]
+}
+
+// resultType returns the qualified name of the projected media type the body of the given
+// response decodes into, the empty string if the response has no JSON body.
+func resultType(api *design.APIDefinition, resp *design.ResponseDefinition, target string) string {
+	if resp.MediaType == "" {
+		return ""
+	}
+	mt := api.MediaTypeWithIdentifier(resp.MediaType)
+	if mt == nil || !strings.Contains(mt.Identifier, "json") {
+		return ""
+	}
+	view := resp.ViewName
+	if view == "" {
+		view = design.DefaultView
+	}
+	p, _, err := mt.Project(view)
+	if err != nil {
+		return ""
+	}
+	return target + "." + codegen.GoTypeName(p, nil, 0, false)
+}
+
+// examplePath replaces the wildcards of the route path with example values of the corresponding
+// params and appends the examples of the required query string params.
+func examplePath(api *design.APIDefinition, route *design.RouteDefinition, params *design.AttributeDefinition) string {
+	obj := params.Type.ToObject()
+	example := func(att *design.AttributeDefinition) string {
+		return fmt.Sprintf("%v", att.GenerateExample(api.RandomGenerator(), nil))
+	}
+	p := design.WildcardRegex.ReplaceAllStringFunc(route.FullPath(), func(w string) string {
+		name := design.WildcardRegex.FindStringSubmatch(w)[1]
+		att, ok := obj[name]
+		if !ok {
+			return "/" + name
+		}
+		return "/" + url.PathEscape(example(att))
+	})
+	wildcards := route.Params()
+	query := url.Values{}
+	for n, att := range obj {
+		if !params.IsRequired(n) {
+			continue
+		}
+		found := false
+		for _, w := range wildcards {
+			if w == n {
+				found = true
+				break
+			}
+		}
+		if !found {
+			query.Set(n, example(att))
+		}
+	}
+	if len(query) > 0 {
+		p += "?" + query.Encode()
+	}
+	return p
+}
+
+// integrationT generates the integration test of a resource. The test package must define one
+// New<Resource>Controller(*goa.Service) constructor per resource as generated by "goagen main".
+// template input: *ResourceTemplateData
+const integrationT = `{{ $res := goify .Resource.Name true }}
+// TestIntegration_{{ $res }}Resource starts the service with all the controllers mounted and sends
+// one request per action of the {{ .Resource.Name }} resource checking the response status and body.
+func TestIntegration_{{ $res }}Resource(t *testing.T) {
+	service := goa.New({{ printf "%q" .API.Name }})
+	service.Use(middleware.ErrorHandler(service, true))
+	service.Use(middleware.Recover())
+{{ range $name, $r := .API.Resources }}{{ $name := goify $r.Name true }}	{{ targetPkg }}.Mount{{ $name }}Controller(service, New{{ $name }}Controller(service))
+{{ end }}	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+{{ range .Actions }}
+	t.Run({{ printf "%q" .Name }}, func(t *testing.T) {
+{{ if .Skip }}		t.Skip({{ printf "%q" .Skip }})
+{{ else }}{{ if .Body }}		body := strings.NewReader({{ printf "%q" .Body }})
+{{ end }}		req, err := http.NewRequest({{ printf "%q" .Method }}, server.URL+{{ printf "%q" .Path }}, {{ if .Body }}body{{ else }}nil{{ end }})
+		if err != nil {
+			t.Fatalf("failed to create request: %s", err)
+		}
+{{ if .Body }}		req.Header.Set("Content-Type", "application/json")
+{{ end }}		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != {{ .Status }} {
+			t.Fatalf("expected status {{ .Status }}, got %d", resp.StatusCode)
+		}
+{{ if .Result }}		res := new({{ .Result }})
+		if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+			t.Fatalf("failed to decode response body: %s", err)
+		}
+		if v, ok := interface{}(res).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				t.Errorf("invalid response body: %s", err)
+			}
+		}
+{{ end }}{{ end }}	})
+{{ end }}}
+`
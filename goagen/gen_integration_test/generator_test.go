@@ -0,0 +1,128 @@
+package genintegrationtest_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_integration_test"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("integrationtest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genintegrationtest.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a two-action resource", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			bottle := apidsl.MediaType("application/vnd.bottle+json", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("name", design.String)
+					apidsl.Required("name")
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("name")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer, func() {
+							apidsl.Enum(42)
+						})
+					})
+					apidsl.Response(design.OK, bottle)
+					apidsl.Response(design.NotFound)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String, func() {
+							apidsl.Enum("Number 8")
+						})
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.Created)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("generates a test function with one sub-test per action", func() {
+			Ω(genErr).Should(BeNil())
+			filename := filepath.Join(testPkg.Abs(), "bottle_integration_test.go")
+			Ω(files).Should(Equal([]string{filename}))
+			content, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			f, err := parser.ParseFile(token.NewFileSet(), filename, content, 0)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(f.Scope.Lookup("TestIntegration_BottleResource")).ShouldNot(BeNil())
+			var runs []string
+			ast.Inspect(f, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Run" {
+						runs = append(runs, call.Args[0].(*ast.BasicLit).Value)
+					}
+				}
+				return true
+			})
+			Ω(runs).Should(Equal([]string{`"create"`, `"show"`}))
+
+			Ω(string(content)).Should(ContainSubstring("app.MountBottleController(service, NewBottleController(service))"))
+			Ω(string(content)).Should(ContainSubstring("server := httptest.NewServer(service.Mux)"))
+			Ω(string(content)).Should(ContainSubstring(`http.NewRequest("GET", server.URL+"/bottles/42", nil)`))
+			Ω(string(content)).Should(ContainSubstring(`body := strings.NewReader("{\"name\":\"Number 8\"}")`))
+			Ω(string(content)).Should(ContainSubstring("http.DefaultClient.Do(req)"))
+			Ω(string(content)).Should(ContainSubstring("if resp.StatusCode != 201 {"))
+			Ω(string(content)).Should(ContainSubstring("res := new(app.Bottle)"))
+			Ω(f.Name.Name).Should(Equal("main"))
+		})
+
+		Context("with a test package name", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--test-pkg=service")
+			})
+
+			It("generates the test files in that package", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(HaveLen(1))
+				f, err := parser.ParseFile(token.NewFileSet(), files[0], nil, parser.PackageClauseOnly)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(f.Name.Name).Should(Equal("service"))
+			})
+		})
+	})
+})
@@ -22,6 +22,7 @@ type Generator struct {
 	DesignPkg string                // Path to design package, only used to mark generated files.
 	Target    string                // Name of generated "app" package
 	Force     bool                  // Whether to override existing files
+	WireGen   bool                  // Whether to override an existing wire_gen.go file
 	genfiles  []string              // Generated files
 }
 
@@ -29,7 +30,7 @@ type Generator struct {
 func Generate() (files []string, err error) {
 	var (
 		outDir, designPkg, target, ver string
-		force                          bool
+		force, wireGen                 bool
 	)
 
 	set := flag.NewFlagSet("main", flag.PanicOnError)
@@ -38,6 +39,7 @@ func Generate() (files []string, err error) {
 	set.StringVar(&target, "pkg", "app", "")
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&force, "force", false, "")
+	set.BoolVar(&wireGen, "wire-gen", false, "")
 	set.Parse(os.Args[1:])
 
 	if err := codegen.CheckVersion(ver); err != nil {
@@ -45,7 +47,7 @@ func Generate() (files []string, err error) {
 	}
 
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, DesignPkg: designPkg, Target: target, Force: force, API: design.Design}
+	g := &Generator{OutDir: outDir, DesignPkg: designPkg, Target: target, Force: force, WireGen: wireGen, API: design.Design}
 
 	return g.Generate()
 }
@@ -90,12 +92,11 @@ func (g *Generator) Generate() (_ []string, err error) {
 		codegen.SimpleImport("io"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport(imp),
-		codegen.SimpleImport("golang.org/x/net/websocket"),
 	}
 	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
 		filename := filepath.Join(g.OutDir, codegen.SnakeCase(r.Name)+".go")
 		if g.Force {
-			if err2 := os.Remove(filename); err2 != nil {
+			if err2 := os.Remove(filename); err2 != nil && !os.IsNotExist(err2) {
 				return err2
 			}
 		}
@@ -113,6 +114,9 @@ func (g *Generator) Generate() (_ []string, err error) {
 				if a.WebSocket() {
 					return file.ExecuteTemplate("actionWS", actionWST, funcs, a)
 				}
+				if a.UnionResult {
+					return file.ExecuteTemplate("actionResult", actionResultT, funcs, a)
+				}
 				return file.ExecuteTemplate("action", actionT, funcs, a)
 			})
 			if err2 != nil {
@@ -127,6 +131,11 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err != nil {
 		return
 	}
+	if g.API.WireInject {
+		if err = g.createWireFiles(funcs); err != nil {
+			return
+		}
+	}
 
 	return g.genfiles, nil
 }
@@ -187,6 +196,50 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) erro
 	return file.FormatCode()
 }
 
+// createWireFiles generates the wire.go file containing the google/wire injectors and the
+// wire_gen.go file containing placeholder implementations used until the wire tool is run.
+// An existing wire_gen.go file holds the injectors generated by wire so it is only overwritten
+// when WireGen is set, Force does not apply to it.
+func (g *Generator) createWireFiles(funcs template.FuncMap) error {
+	files := []struct {
+		name, tag, tmpl string
+		overwrite       bool
+		imports         []*codegen.ImportSpec
+	}{
+		{"wire.go", "wireinject", wireT, g.Force, []*codegen.ImportSpec{
+			codegen.SimpleImport("github.com/goadesign/goa"),
+			codegen.SimpleImport("github.com/google/wire"),
+		}},
+		{"wire_gen.go", "!wireinject", wireGenT, g.WireGen, []*codegen.ImportSpec{
+			codegen.SimpleImport("errors"),
+			codegen.SimpleImport("github.com/goadesign/goa"),
+		}},
+	}
+	for _, f := range files {
+		filename := filepath.Join(g.OutDir, f.name)
+		if f.overwrite {
+			os.Remove(filename)
+		}
+		if _, err := os.Stat(filename); err == nil {
+			continue
+		}
+		g.genfiles = append(g.genfiles, filename)
+		file, err := codegen.SourceFileFor(filename)
+		if err != nil {
+			return err
+		}
+		file.Write([]byte("//go:build " + f.tag + "\n// +build " + f.tag + "\n\n"))
+		file.WriteHeader("", "main", f.imports)
+		if err = file.ExecuteTemplate("wire", f.tmpl, funcs, g.API); err != nil {
+			return err
+		}
+		if err = file.FormatCode(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (g *Generator) okResp(a *design.ActionDefinition) map[string]interface{} {
 	var ok *design.ResponseDefinition
 	for _, resp := range a.Responses {
@@ -261,6 +314,26 @@ func main() {
 }
 `
 
+const wireT = `{{ range $name, $res := .Resources }}{{ $ctrlName := printf "%s%s" (goify $res.Name true) "Controller" }}
+// Initialize{{ $ctrlName }} creates a {{ $res.Name }} controller, its implementation is generated
+// by wire.
+func Initialize{{ $ctrlName }}(service *goa.Service) (*{{ $ctrlName }}, error) {
+	wire.Build(New{{ $ctrlName }})
+	return nil, nil
+}
+{{ end }}`
+
+const wireGenT = `
+// ErrNotImplemented is the error returned by the placeholder injectors, run the wire tool to
+// generate the actual implementations.
+var ErrNotImplemented = errors.New("injector not implemented, run wire to generate it")
+{{ range $name, $res := .Resources }}{{ $ctrlName := printf "%s%s" (goify $res.Name true) "Controller" }}
+// Initialize{{ $ctrlName }} is a placeholder for the injector generated by wire.
+func Initialize{{ $ctrlName }}(service *goa.Service) (*{{ $ctrlName }}, error) {
+	return nil, ErrNotImplemented
+}
+{{ end }}`
+
 const ctrlT = `// {{ $ctrlName := printf "%s%s" (goify .Name true) "Controller" }}{{ $ctrlName }} implements the {{ .Name }} resource.
 type {{ $ctrlName }} struct {
 	*goa.Controller
@@ -284,23 +357,27 @@ func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .
 }
 `
 
-const actionWST = `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
-func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
-	c.{{ goify .Name true }}WSHandler(ctx).ServeHTTP(ctx.ResponseWriter, ctx.Request)
-	return nil
+const actionResultT = `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) ({{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Result, error) {
+	// {{ $ctrlName }}_{{ goify .Name true }}: start_implement
+
+	// Put your logic here
+
+	// {{ $ctrlName }}_{{ goify .Name true }}: end_implement
+{{ $ok := okResp . }}{{ if $ok }} res := {{ $ok.TypeRef }}
+	return res, nil{{ else }} return nil, nil{{ end }}
 }
+`
 
-// {{ goify .Name true }}WSHandler establishes a websocket connection to run the {{ .Name }} action.
-func (c *{{ $ctrlName }}) {{ goify .Name true }}WSHandler(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) websocket.Handler {
-	return func(ws *websocket.Conn) {
-		// {{ $ctrlName }}_{{ goify .Name true }}: start_implement
+const actionWST = `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action over the upgraded WebSocket connection.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}WebSocketContext) error {
+	// {{ $ctrlName }}_{{ goify .Name true }}: start_implement
 
-		// Put your logic here
+	// Put your logic here
 
-		// {{ $ctrlName }}_{{ goify .Name true }}: end_implement
-		ws.Write([]byte("{{ .Name }} {{ .Parent.Name }}"))
-		// Dummy echo websocket server
-		io.Copy(ws, ws)
-	}
+	// {{ $ctrlName }}_{{ goify .Name true }}: end_implement
+	// Dummy echo websocket server
+	_, err := io.Copy(ctx.Conn, ctx.Conn)
+	return err
 }
 `
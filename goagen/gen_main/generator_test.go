@@ -56,4 +56,64 @@ var _ = Describe("Generate", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 	})
+
+	Context("with WireInject", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name:       "test api",
+				WireInject: true,
+			}
+			design.Design.Resources = map[string]*design.ResourceDefinition{
+				"bottle": {Name: "bottle"},
+			}
+		})
+
+		It("generates the wire injectors and placeholders", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(4))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "wire.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(HavePrefix("//go:build wireinject\n// +build wireinject\n"))
+			Ω(string(content)).Should(ContainSubstring("wire.Build(NewBottleController)"))
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "wire_gen.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(HavePrefix("//go:build !wireinject\n// +build !wireinject\n"))
+			Ω(string(content)).Should(ContainSubstring("return nil, ErrNotImplemented"))
+		})
+
+		Context("with an existing wire_gen.go file", func() {
+			const generated = "package main\n\n// generated by wire\n"
+
+			BeforeEach(func() {
+				err := ioutil.WriteFile(filepath.Join(outDir, "wire_gen.go"), []byte(generated), 0644)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			Context("and --force", func() {
+				BeforeEach(func() {
+					os.Args = append(os.Args, "--force")
+				})
+
+				It("keeps the file", func() {
+					Ω(genErr).Should(BeNil())
+					content, err := ioutil.ReadFile(filepath.Join(outDir, "wire_gen.go"))
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(string(content)).Should(Equal(generated))
+				})
+			})
+
+			Context("and --wire-gen", func() {
+				BeforeEach(func() {
+					os.Args = append(os.Args, "--wire-gen")
+				})
+
+				It("overwrites the file", func() {
+					Ω(genErr).Should(BeNil())
+					content, err := ioutil.ReadFile(filepath.Join(outDir, "wire_gen.go"))
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(string(content)).Should(ContainSubstring("return nil, ErrNotImplemented"))
+				})
+			})
+		})
+	})
 })
@@ -0,0 +1,16 @@
+/*
+Package genmarkdown provides a generator for the API documentation in Markdown format. The
+generator creates one <resource>.md file per resource under the "docs" directory where resource is
+the snake case resource name. Each file contains the resource description, a table listing the
+resource actions with their HTTP method, path and description and one section per action.
+
+The action sections describe the action parameters, request headers, payload and responses. The
+attributes are listed in tables indicating their type, whether they are required and their
+validation rules. The response attributes are the attributes of the media type view rendered by
+the response.
+
+Each generated file starts with a comment marking it as generated. Running the generator again
+replaces the files that carry the marker and leaves the other files of the "docs" directory
+untouched.
+*/
+package genmarkdown
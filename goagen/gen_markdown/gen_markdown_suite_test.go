@@ -0,0 +1,13 @@
+package genmarkdown_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenMarkdown(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenMarkdown Suite")
+}
@@ -0,0 +1,109 @@
+package genmarkdown
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// generatedMarker starts the content of the generated files. It tells them apart from the files
+// written by users in the same directory.
+const generatedMarker = "<!-- The content of this file is auto-generated by goagen, DO NOT MODIFY -->\n\n"
+
+// Generator is the Markdown documentation generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("markdown", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces one Markdown file per resource.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	docsDir := filepath.Join(g.OutDir, "docs")
+	if err = removeGenerated(docsDir); err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(docsDir, 0755); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, docsDir)
+
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		doc, err := ResourceDoc(NewResourceData(g.API, r))
+		if err != nil {
+			return err
+		}
+		filename := filepath.Join(docsDir, codegen.SnakeCase(r.Name)+".md")
+		if err := ioutil.WriteFile(filename, []byte(generatedMarker+doc), 0644); err != nil {
+			return err
+		}
+		g.genfiles = append(g.genfiles, filename)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// removeGenerated deletes the Markdown files produced by a previous run of the generator in dir.
+// It leaves the other files untouched.
+func removeGenerated(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(string(content), generatedMarker) {
+			if err := os.Remove(f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
@@ -0,0 +1,167 @@
+package genmarkdown_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_markdown"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("markdowntest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genmarkdown.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with resources", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("cellar", func() {
+				apidsl.Title("The virtual wine cellar")
+			})
+			bottle := apidsl.MediaType("application/vnd.goa.example.bottle+json", func() {
+				apidsl.Description("A bottle of wine")
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer, "ID of bottle")
+					apidsl.Attribute("name", design.String, "Name of wine", func() {
+						apidsl.MinLength(2)
+						apidsl.MaxLength(64)
+					})
+					apidsl.Attribute("color", design.String, "Color of wine", func() {
+						apidsl.Enum("red", "white", "rose")
+					})
+					apidsl.Attribute("vintage", design.Integer, "Vintage of wine", func() {
+						apidsl.Minimum(1900)
+					})
+					apidsl.Required("id", "name")
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+					apidsl.Attribute("color")
+					apidsl.Attribute("vintage")
+				})
+				apidsl.View("tiny", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Description("A wine bottle | stored in a cellar")
+				apidsl.BasePath("/bottles")
+				apidsl.DefaultMedia(bottle)
+				apidsl.Action("list", func() {
+					apidsl.Description("List all bottles")
+					apidsl.Routing(apidsl.GET(""))
+					apidsl.Params(func() {
+						apidsl.Param("color", design.String, "Filter by color", func() {
+							apidsl.Enum("red", "white", "rose")
+						})
+					})
+					apidsl.Response(design.OK, func() {
+						apidsl.Media(apidsl.CollectionOf(bottle), "tiny")
+					})
+				})
+				apidsl.Action("show", func() {
+					apidsl.Description("Retrieve bottle with given ID")
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer, "Bottle ID")
+					})
+					apidsl.Response(design.OK)
+					apidsl.Response(design.NotFound)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Description("Record a new bottle")
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Member("name")
+						apidsl.Member("vintage")
+						apidsl.Attribute("tags", apidsl.ArrayOf(design.String), "Tags")
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.Created, func() {
+						apidsl.Headers(func() {
+							apidsl.Header("Location", design.String, "Href to created bottle", func() {
+								apidsl.Pattern("^/bottles/[0-9]+$")
+							})
+						})
+					})
+					apidsl.Response(design.BadRequest, design.ErrorMedia)
+				})
+			})
+			apidsl.Resource("health", func() {
+				apidsl.Action("check", func() {
+					apidsl.Routing(apidsl.GET("//health"))
+					apidsl.Headers(func() {
+						apidsl.Header("X-Request-Id", design.String, "Request ID", func() {
+							apidsl.Format("hostname")
+						})
+					})
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates one file per resource matching the golden files", func() {
+			Ω(genErr).Should(BeNil())
+			for _, name := range []string{"bottle", "health"} {
+				filename := filepath.Join(testPkg.Abs(), "docs", name+".md")
+				Ω(files).Should(ContainElement(filename))
+				content, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				golden, err := ioutil.ReadFile(filepath.Join("testdata", name+".golden"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(Equal(string(golden)))
+			}
+		})
+
+		Context("with existing files in the docs directory", func() {
+			var docsDir string
+
+			BeforeEach(func() {
+				docsDir = filepath.Join(testPkg.Abs(), "docs")
+				Ω(os.MkdirAll(docsDir, 0755)).Should(Succeed())
+				stale := "<!-- The content of this file is auto-generated by goagen, DO NOT MODIFY -->\n\n# cellar\n"
+				Ω(ioutil.WriteFile(filepath.Join(docsDir, "cellar.md"), []byte(stale), 0644)).Should(Succeed())
+				Ω(ioutil.WriteFile(filepath.Join(docsDir, "notes.md"), []byte("# Notes\n"), 0644)).Should(Succeed())
+			})
+
+			It("removes the previously generated files only", func() {
+				Ω(genErr).Should(BeNil())
+				_, err := os.Stat(filepath.Join(docsDir, "cellar.md"))
+				Ω(os.IsNotExist(err)).Should(BeTrue())
+				content, err := ioutil.ReadFile(filepath.Join(docsDir, "notes.md"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(Equal("# Notes\n"))
+			})
+		})
+	})
+})
@@ -0,0 +1,294 @@
+package genmarkdown
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
+)
+
+type (
+	// ResourceData contains the information required to render the documentation of a
+	// resource.
+	ResourceData struct {
+		// Name is the name of the resource.
+		Name string
+		// Description is the description of the resource.
+		Description string
+		// Controller describes the resource actions, each action has the keys "Name",
+		// "Routes" and "Description".
+		Controller *genapp.ControllerTemplateData
+		// Contexts lists the action contexts in the order of the controller actions.
+		Contexts []*genapp.ContextTemplateData
+	}
+
+	// Attribute is a row of the attribute tables.
+	Attribute struct {
+		// Name is the name of the attribute.
+		Name string
+		// Type is the name of the attribute type.
+		Type string
+		// Required is true if the attribute is required.
+		Required bool
+		// Validations lists the validation rules of the attribute.
+		Validations string
+		// Description is the description of the attribute.
+		Description string
+	}
+)
+
+// NewResourceData returns the documentation data of the given resource.
+func NewResourceData(api *design.APIDefinition, r *design.ResourceDefinition) *ResourceData {
+	data := &ResourceData{
+		Name:        r.Name,
+		Description: r.Description,
+		Controller:  &genapp.ControllerTemplateData{API: api, Resource: codegen.Goify(r.Name, true)},
+	}
+	r.IterateActions(func(a *design.ActionDefinition) error {
+		data.Controller.Actions = append(data.Controller.Actions, map[string]interface{}{
+			"Name":        a.Name,
+			"Routes":      a.Routes,
+			"Description": a.Description,
+		})
+		headers := r.Headers.Merge(a.Headers)
+		if headers != nil && len(headers.Type.ToObject()) == 0 {
+			headers = nil
+		}
+		params := a.AllParams()
+		if params != nil && len(params.Type.ToObject()) == 0 {
+			params = nil
+		}
+		data.Contexts = append(data.Contexts, &genapp.ContextTemplateData{
+			Name:         codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + "Context",
+			ResourceName: r.Name,
+			ActionName:   a.Name,
+			Params:       params,
+			Payload:      a.Payload,
+			Headers:      headers,
+			Routes:       a.Routes,
+			Responses:    a.Responses,
+			API:          api,
+		})
+		return nil
+	})
+	return data
+}
+
+// ResourceDoc renders the Markdown documentation of a resource.
+func ResourceDoc(data *ResourceData) (string, error) {
+	var b bytes.Buffer
+	if err := resourceTmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Attributes returns the rows of the table describing the attributes of the given object
+// attribute sorted by name.
+func Attributes(att *design.AttributeDefinition) []*Attribute {
+	if att == nil {
+		return nil
+	}
+	obj := att.Type.ToObject()
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	rows := make([]*Attribute, len(names))
+	for i, n := range names {
+		catt := obj[n]
+		rows[i] = &Attribute{
+			Name:        n,
+			Type:        TypeName(catt.Type),
+			Required:    att.IsRequired(n),
+			Validations: Validations(catt),
+			Description: catt.Description,
+		}
+	}
+	return rows
+}
+
+// Params returns the rows of the table describing the parameters of the given action context,
+// the path parameters are always required.
+func Params(ctx *genapp.ContextTemplateData) []*Attribute {
+	rows := Attributes(ctx.Params)
+	for _, row := range rows {
+		for _, r := range ctx.Routes {
+			for _, p := range r.Params() {
+				if p == row.Name {
+					row.Required = true
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// TypeName returns the name of the given type as it appears in the documentation.
+func TypeName(t design.DataType) string {
+	switch actual := t.(type) {
+	case *design.MediaTypeDefinition:
+		return actual.TypeName
+	case *design.UserTypeDefinition:
+		return actual.TypeName
+	case *design.Array:
+		return "array of " + TypeName(actual.ElemType.Type)
+	case *design.Hash:
+		return fmt.Sprintf("map of %s to %s", TypeName(actual.KeyType.Type), TypeName(actual.ElemType.Type))
+	default:
+		return t.Name()
+	}
+}
+
+// Validations describes the validation rules of the given attribute.
+func Validations(att *design.AttributeDefinition) string {
+	v := att.Validation
+	if v == nil {
+		return ""
+	}
+	var rules []string
+	if len(v.Values) > 0 {
+		values := make([]string, len(v.Values))
+		for i, val := range v.Values {
+			values[i] = fmt.Sprintf("%#v", val)
+		}
+		rules = append(rules, "one of "+strings.Join(values, ", "))
+	}
+	if v.Format != "" {
+		rules = append(rules, "format "+v.Format)
+	}
+	if v.Pattern != "" {
+		rules = append(rules, fmt.Sprintf("pattern `%s`", v.Pattern))
+	}
+	if v.Minimum != nil {
+		rules = append(rules, fmt.Sprintf("minimum %v", *v.Minimum))
+	}
+	if v.Maximum != nil {
+		rules = append(rules, fmt.Sprintf("maximum %v", *v.Maximum))
+	}
+	if v.MinLength != nil {
+		rules = append(rules, fmt.Sprintf("minimum length %d", *v.MinLength))
+	}
+	if v.MaxLength != nil {
+		rules = append(rules, fmt.Sprintf("maximum length %d", *v.MaxLength))
+	}
+	return strings.Join(rules, ", ")
+}
+
+// responseBody returns the attribute describing the body of the response rendered with the
+// response media type view, nil if the response has no media type or if the media type is not an
+// object or a collection of objects.
+func responseBody(api *design.APIDefinition, resp *design.ResponseDefinition) *design.AttributeDefinition {
+	mt := api.MediaTypeWithIdentifier(resp.MediaType)
+	if mt == nil {
+		return nil
+	}
+	view := resp.ViewName
+	if view == "" {
+		view = design.DefaultView
+	}
+	if _, ok := mt.Views[view]; !ok && !mt.IsArray() {
+		return nil
+	}
+	projected, _, err := mt.Project(view)
+	if err != nil {
+		return nil
+	}
+	att := projected.AttributeDefinition
+	if projected.IsArray() {
+		att = projected.ToArray().ElemType
+		if elem, ok := att.Type.(*design.MediaTypeDefinition); ok {
+			att = elem.AttributeDefinition
+		}
+	}
+	if !att.Type.IsObject() {
+		return nil
+	}
+	return att
+}
+
+// sortedResponses returns the responses sorted by status code.
+func sortedResponses(responses map[string]*design.ResponseDefinition) []*design.ResponseDefinition {
+	sorted := make([]*design.ResponseDefinition, 0, len(responses))
+	for _, r := range responses {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Status == sorted[j].Status {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Status < sorted[j].Status
+	})
+	return sorted
+}
+
+// anchor returns the anchor of the section with the given title as computed by GitHub.
+func anchor(title string) string {
+	return strings.Replace(strings.ToLower(title), " ", "-", -1)
+}
+
+// cell escapes the text so that it can be written in a Markdown table cell.
+func cell(text string) string {
+	text = strings.Replace(text, "|", `\|`, -1)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+var resourceTmpl = template.Must(template.New("resource").Funcs(template.FuncMap{
+	"attributes":      Attributes,
+	"params":          Params,
+	"typeName":        TypeName,
+	"responseBody":    responseBody,
+	"sortedResponses": sortedResponses,
+	"cell":            cell,
+	"anchor":          anchor,
+}).Parse(resourceT))
+
+const resourceT = `{{ define "Attributes" }}| Name | Type | Required | Validations | Description |
+| --- | --- | --- | --- | --- |
+{{ range . }}| {{ .Name }} | {{ .Type }} | {{ if .Required }}yes{{ else }}no{{ end }} | {{ cell .Validations }} | {{ cell .Description }} |
+{{ end }}{{ end }}# {{ .Name }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+## Actions
+
+| Action | Method | Path | Description |
+| --- | --- | --- | --- |
+{{ range .Controller.Actions }}{{ $action := . }}{{ range .Routes }}| [{{ $action.Name }}](#{{ anchor $action.Name }}) | {{ .Verb }} | {{ .FullPath }} | {{ cell $action.Description }} |
+{{ end }}{{ end }}{{ range $i, $action := .Controller.Actions }}{{ $ctx := index $.Contexts $i }}
+## {{ .Name }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}{{ range .Routes }}
+    {{ .Verb }} {{ .FullPath }}
+{{ end }}{{ if $ctx.Params }}
+### Parameters
+
+{{ template "Attributes" (params $ctx) }}{{ end }}{{ if $ctx.Headers }}
+### Headers
+
+{{ template "Attributes" (attributes $ctx.Headers) }}{{ end }}{{ if $ctx.Payload }}
+### Payload
+
+Type: {{ typeName $ctx.Payload }}{{ if $ctx.Payload.IsObject }}
+
+{{ template "Attributes" (attributes $ctx.Payload.AttributeDefinition) }}{{ else }}
+{{ end }}{{ end }}{{ if $ctx.Responses }}
+### Responses
+
+| Response | Status | Media Type | Description |
+| --- | --- | --- | --- |
+{{ range sortedResponses $ctx.Responses }}| {{ .Name }} | {{ .Status }} | {{ .MediaType }} | {{ cell .Description }} |
+{{ end }}{{ range sortedResponses $ctx.Responses }}{{ $body := responseBody $ctx.API . }}{{ if $body }}
+#### {{ .Name }}
+
+{{ template "Attributes" (attributes $body) }}{{ end }}{{ if .Headers }}
+#### {{ .Name }} Headers
+
+{{ template "Attributes" (attributes .Headers) }}{{ end }}{{ end }}{{ end }}{{ end }}`
@@ -0,0 +1,9 @@
+/*
+Package genmiddleware provides a generator for the service request logging middleware. The generator
+creates a log_middleware.go file in the service main package that defines the LogRequest middleware.
+The middleware logs one "request" entry per request with the request method and path, the response
+status and the request latency. The --logger flag selects the logging package used by the generated
+code: "slog" (the default) uses the log/slog package, "zerolog" uses github.com/rs/zerolog and
+retrieves the logger from the request context with zerolog.Ctx.
+*/
+package genmiddleware
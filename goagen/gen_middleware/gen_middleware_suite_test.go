@@ -0,0 +1,13 @@
+package genmiddleware_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenMiddleware(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenMiddleware Suite")
+}
@@ -0,0 +1,151 @@
+package genmiddleware
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the request logging middleware generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Logger   string                // Logging package used by the middleware, "slog" or "zerolog"
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, logger, ver string
+
+	set := flag.NewFlagSet("middleware", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&logger, "logger", "slog", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Logger: logger, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the log_middleware.go file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Logger == "" {
+		g.Logger = "slog"
+	}
+	var tmpl string
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	switch g.Logger {
+	case "slog":
+		tmpl = slogT
+		imports = append(imports, codegen.SimpleImport("log/slog"))
+	case "zerolog":
+		tmpl = zerologT
+		imports = append(imports, codegen.SimpleImport("github.com/rs/zerolog"))
+	default:
+		return nil, fmt.Errorf(`invalid logger %q, must be one of "slog" or "zerolog"`, g.Logger)
+	}
+
+	filename := filepath.Join(g.OutDir, "log_middleware.go")
+	os.Remove(filename)
+	g.genfiles = append(g.genfiles, filename)
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	title := fmt.Sprintf("%s: Request Logging Middleware", g.API.Context())
+	if err = file.WriteHeader(title, "main", imports); err != nil {
+		return nil, err
+	}
+	if err = file.ExecuteTemplate("middleware", tmpl, nil, nil); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// slogT generates the LogRequest middleware using log/slog.
+// template input: nil
+const slogT = `
+// LogRequest creates a request logger middleware that logs one "request" entry per request with
+// the request method and path, the response status and the request latency using slog.Default().
+func LogRequest() goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			startedAt := time.Now()
+			err := h(ctx, rw, req)
+			var status int
+			if resp := goa.ContextResponse(ctx); resp != nil {
+				status = resp.Status
+			}
+			slog.Default().InfoContext(ctx, "request",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(startedAt)),
+			)
+			return err
+		}
+	}
+}
+`
+
+// zerologT generates the LogRequest middleware using zerolog.
+// template input: nil
+const zerologT = `
+// LogRequest creates a request logger middleware that logs one "request" entry per request with
+// the request method and path, the response status and the request latency. The logger is
+// retrieved from the request context with zerolog.Ctx, use zerolog.Logger.WithContext to set it
+// before the request reaches the service mux.
+func LogRequest() goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			startedAt := time.Now()
+			err := h(ctx, rw, req)
+			var status int
+			if resp := goa.ContextResponse(ctx); resp != nil {
+				status = resp.Status
+			}
+			duration := time.Since(startedAt)
+			log := zerolog.Ctx(req.Context())
+			log.Info().Str("method", req.Method).Str("path", req.URL.Path).Int("status", status).Dur("latency", duration).Msg("request")
+			return err
+		}
+	}
+}
+`
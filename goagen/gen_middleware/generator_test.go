@@ -0,0 +1,107 @@
+package genmiddleware_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_middleware"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var logger string
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		logger = ""
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("middlewaretest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+		if logger != "" {
+			os.Args = append(os.Args, "--logger="+logger)
+		}
+
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Title("dummy API")
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+
+		files, genErr = genmiddleware.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	// parse returns the content of the generated file and the paths of the packages it imports.
+	parse := func() (string, []string) {
+		filename := filepath.Join(testPkg.Abs(), "log_middleware.go")
+		content, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		f, err := parser.ParseFile(token.NewFileSet(), filename, content, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(f.Name.Name).Should(Equal("main"))
+		Ω(f.Scope.Lookup("LogRequest")).ShouldNot(BeNil())
+		var imports []string
+		for _, imp := range f.Imports {
+			imports = append(imports, imp.Path.Value)
+		}
+		return string(content), imports
+	}
+
+	It("generates a slog middleware by default", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(1))
+		content, imports := parse()
+		Ω(imports).Should(ContainElement(`"log/slog"`))
+		Ω(imports).ShouldNot(ContainElement(`"github.com/rs/zerolog"`))
+		Ω(content).Should(ContainSubstring(`slog.Default().InfoContext(ctx, "request",`))
+		Ω(content).Should(ContainSubstring(`slog.Duration("latency", time.Since(startedAt)),`))
+	})
+
+	Context("with the zerolog logger", func() {
+		BeforeEach(func() {
+			logger = "zerolog"
+		})
+
+		It("generates a zerolog middleware", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(1))
+			content, imports := parse()
+			Ω(imports).Should(ContainElement(`"github.com/rs/zerolog"`))
+			Ω(imports).ShouldNot(ContainElement(`"log/slog"`))
+			Ω(content).Should(ContainSubstring(`log := zerolog.Ctx(req.Context())`))
+			Ω(content).Should(ContainSubstring(`log.Info().Str("method", req.Method).Str("path", req.URL.Path).Int("status", status).Dur("latency", duration).Msg("request")`))
+		})
+	})
+
+	Context("with an unknown logger", func() {
+		BeforeEach(func() {
+			logger = "logrus"
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+})
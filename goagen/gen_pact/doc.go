@@ -0,0 +1,8 @@
+/*
+Package genpact provides a generator for Pact (https://docs.pact.io) provider verification tests.
+The generator creates one _test.go file per resource in the service main package. Each test mounts
+the resource controller onto a test server and verifies it against the contracts published by the
+consumers to the Pact broker. The provider states are derived from the resource actions and the
+interaction descriptions from the action responses.
+*/
+package genpact
@@ -0,0 +1,175 @@
+package genpact
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+type (
+	// Generator is the Pact provider verification tests generator.
+	Generator struct {
+		API      *design.APIDefinition // The API definition
+		OutDir   string                // Path to output directory
+		Target   string                // Name of generated "app" package
+		genfiles []string              // Generated files
+	}
+
+	// ProviderTemplateData contains the information required to generate the provider
+	// verification test of a resource.
+	ProviderTemplateData struct {
+		API      *design.APIDefinition      // API definition
+		Resource *design.ResourceDefinition // Verified resource
+		States   []*StateTemplateData       // Provider states, one per action
+	}
+
+	// StateTemplateData describes a provider state.
+	StateTemplateData struct {
+		Name         string   // Provider state name, e.g. "bottle show"
+		Interactions []string // Descriptions of the interactions, one per action response
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, target, ver string
+
+	set := flag.NewFlagSet("pact", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&target, "pkg", "app", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	target = codegen.Goify(target, false)
+	g := &Generator{OutDir: outDir, Target: target, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces one provider verification test file per resource.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Target == "" {
+		g.Target = "app"
+	}
+
+	outPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{
+		"targetPkg": func() string { return g.Target },
+	}
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		data := &ProviderTemplateData{API: g.API, Resource: r, States: NewStates(r)}
+		filename := filepath.Join(g.OutDir, codegen.SnakeCase(r.Name)+"_pact_test.go")
+		os.Remove(filename)
+		g.genfiles = append(g.genfiles, filename)
+		file, err := codegen.SourceFileFor(filename)
+		if err != nil {
+			return err
+		}
+		imports := []*codegen.ImportSpec{
+			codegen.SimpleImport("net/http/httptest"),
+			codegen.SimpleImport("os"),
+			codegen.SimpleImport("testing"),
+			codegen.SimpleImport("github.com/goadesign/goa"),
+			codegen.SimpleImport(path.Join(filepath.ToSlash(outPkg), g.Target)),
+			codegen.SimpleImport("github.com/pact-foundation/pact-go/dsl"),
+			codegen.SimpleImport("github.com/pact-foundation/pact-go/types"),
+		}
+		title := fmt.Sprintf("%s: %s Pact Provider Verification", g.API.Context(), r.Name)
+		if err := file.WriteHeader(title, "main", imports); err != nil {
+			return err
+		}
+		if err := file.ExecuteTemplate("provider", providerT, funcs, data); err != nil {
+			return err
+		}
+		return file.FormatCode()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// NewStates returns the provider states of the given resource: one state per action named after
+// the resource and the action, e.g. "bottle show". The descriptions of the interactions covered
+// by a state are built from the action responses, e.g. "show bottle responds with OK".
+func NewStates(r *design.ResourceDefinition) []*StateTemplateData {
+	var states []*StateTemplateData
+	r.IterateActions(func(a *design.ActionDefinition) error {
+		state := &StateTemplateData{Name: fmt.Sprintf("%s %s", r.Name, a.Name)}
+		a.IterateResponses(func(resp *design.ResponseDefinition) error {
+			desc := fmt.Sprintf("%s %s responds with %s", a.Name, r.Name, resp.Name)
+			state.Interactions = append(state.Interactions, desc)
+			return nil
+		})
+		states = append(states, state)
+		return nil
+	})
+	return states
+}
+
+const (
+	// providerT generates the provider verification test of a resource.
+	// template input: *ProviderTemplateData
+	providerT = `{{ $res := goify .Resource.Name true }}
+// Test{{ $res }}PactProvider verifies the {{ .Resource.Name }} controller against the contracts
+// published by the API consumers to the Pact broker located at PACT_BROKER_URL.
+func Test{{ $res }}PactProvider(t *testing.T) {
+	brokerURL := os.Getenv("PACT_BROKER_URL")
+	if brokerURL == "" {
+		t.Skip("PACT_BROKER_URL is not set")
+	}
+	service := goa.New({{ printf "%q" .API.Name }})
+	{{ targetPkg }}.Mount{{ $res }}Controller(service, New{{ $res }}Controller(service))
+	server := httptest.NewServer(service.Mux)
+	defer server.Close()
+
+	pact := &dsl.Pact{Provider: {{ printf "%q" .API.Name }}}
+	_, err := pact.VerifyProvider(t, types.VerifyRequest{
+		ProviderBaseURL: server.URL,
+		BrokerURL:       brokerURL,
+		StateHandlers: types.StateHandlers{
+{{ range .States }}{{ range .Interactions }}			// {{ . }}
+{{ end }}			{{ printf "%q" .Name }}: func() error {
+				// Set up the state required by the interactions.
+				return nil
+			},
+{{ end }}		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+`
+)
@@ -0,0 +1,100 @@
+package genpact_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_pact"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("pacttest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genpact.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a dummy API", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API with no resource")
+				apidsl.Description("I told you it's dummy")
+			})
+			dslengine.Run()
+		})
+
+		It("does not generate any file", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+
+	Context("with resources", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.NoContent)
+					apidsl.Response(design.NotFound)
+				})
+			})
+			apidsl.Resource("account", func() {
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET(""))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("generates one provider test per resource", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(2))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "bottle_pact_test.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("func TestBottlePactProvider(t *testing.T) {"))
+			Ω(string(content)).Should(ContainSubstring("app.MountBottleController(service, NewBottleController(service))"))
+			Ω(string(content)).Should(ContainSubstring(bottleStates))
+			_, err = os.Stat(filepath.Join(testPkg.Abs(), "account_pact_test.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+})
+
+const bottleStates = `		StateHandlers: types.StateHandlers{
+			// show bottle responds with NoContent
+			// show bottle responds with NotFound
+			"bottle show": func() error {
+				// Set up the state required by the interactions.
+				return nil
+			},
+		},`
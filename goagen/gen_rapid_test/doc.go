@@ -0,0 +1,10 @@
+/*
+Package genrapidtest provides a generator for property-based tests of the action context
+constructors using the rapid package (https://pkg.go.dev/pgregory.net/rapid). The generator
+creates one _test.go file per resource in the service main package with one
+TestProperty_NewXxxContext function per action that accepts parameters. Each test draws arbitrary
+strings for the action parameters, biased towards values that satisfy the parameter types and enum
+validations, and asserts that the context constructor either succeeds with field values that
+satisfy the design validations or fails with a goa bad request error.
+*/
+package genrapidtest
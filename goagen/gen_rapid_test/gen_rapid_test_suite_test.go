@@ -0,0 +1,13 @@
+package genrapidtest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenRapidTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenRapidTest Suite")
+}
@@ -0,0 +1,239 @@
+package genrapidtest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+type (
+	// Generator is the context constructor property-based tests generator.
+	Generator struct {
+		API      *design.APIDefinition // The API definition
+		OutDir   string                // Path to output directory
+		Target   string                // Name of generated "app" package
+		genfiles []string              // Generated files
+	}
+
+	// ActionTemplateData contains the information required to generate the property test of
+	// an action context constructor.
+	ActionTemplateData struct {
+		Context string               // Name of the action context type, e.g. "ShowBottleContext"
+		Verb    string               // HTTP method of the first action route
+		Path    string               // Full path of the first action route
+		Params  []*ParamTemplateData // Action parameters sorted by name
+		// Validation is the code validating the coerced context fields if any.
+		Validation string
+	}
+
+	// ParamTemplateData describes a parameter of an action.
+	ParamTemplateData struct {
+		Name       string // Parameter name
+		Generator  string // Code of the rapid generator drawing the raw parameter values
+		IsArray    bool   // IsArray is true if the parameter accepts multiple values
+		IsRequired bool   // IsRequired is true if the parameter must be set
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, target, ver string
+
+	set := flag.NewFlagSet("rapid", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&target, "pkg", "app", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	target = codegen.Goify(target, false)
+	g := &Generator{OutDir: outDir, Target: target, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces one property test file per resource whose actions accept parameters.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Target == "" {
+		g.Target = "app"
+	}
+
+	outPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{
+		"targetPkg": func() string { return g.Target },
+	}
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		actions := NewActions(r)
+		if len(actions) == 0 {
+			return nil
+		}
+		filename := filepath.Join(g.OutDir, codegen.SnakeCase(r.Name)+"_rapid_test.go")
+		os.Remove(filename)
+		g.genfiles = append(g.genfiles, filename)
+		file, err := codegen.SourceFileFor(filename)
+		if err != nil {
+			return err
+		}
+		imports := []*codegen.ImportSpec{
+			codegen.SimpleImport("net/http"),
+			codegen.SimpleImport("net/http/httptest"),
+			codegen.SimpleImport("net/url"),
+			codegen.SimpleImport("strconv"),
+			codegen.SimpleImport("testing"),
+			codegen.SimpleImport("time"),
+			codegen.SimpleImport("unicode/utf8"),
+			codegen.SimpleImport("github.com/goadesign/goa"),
+			codegen.SimpleImport("golang.org/x/net/context"),
+			codegen.SimpleImport("pgregory.net/rapid"),
+			codegen.SimpleImport(path.Join(filepath.ToSlash(outPkg), g.Target)),
+		}
+		title := fmt.Sprintf("%s: %s Context Property Tests", g.API.Context(), r.Name)
+		if err := file.WriteHeader(title, "main", imports); err != nil {
+			return err
+		}
+		for _, a := range actions {
+			if err := file.ExecuteTemplate("property", propertyT, funcs, a); err != nil {
+				return err
+			}
+		}
+		return file.FormatCode()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// NewActions returns the template data of the resource actions that accept parameters.
+func NewActions(r *design.ResourceDefinition) []*ActionTemplateData {
+	var actions []*ActionTemplateData
+	r.IterateActions(func(a *design.ActionDefinition) error {
+		if a.Params == nil || len(a.Params.Type.ToObject()) == 0 || len(a.Routes) == 0 {
+			return nil
+		}
+		data := &ActionTemplateData{
+			Context: fmt.Sprintf("%s%sContext", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true)),
+			Verb:    a.Routes[0].Verb,
+			Path:    a.Routes[0].FullPath(),
+		}
+		var validations []string
+		a.Params.Type.ToObject().IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+			elem := att
+			if att.Type.IsArray() {
+				elem = att.Type.ToArray().ElemType
+			}
+			data.Params = append(data.Params, &ParamTemplateData{
+				Name:       n,
+				Generator:  rawGenerator(elem),
+				IsArray:    att.Type.IsArray(),
+				IsRequired: a.Params.IsRequired(n),
+			})
+			target := "rctx." + codegen.GoifyAtt(att, n, true)
+			if v := codegen.ValidationChecker(att, a.Params.IsNonZero(n), a.Params.IsRequired(n),
+				a.Params.HasDefaultValue(n), target, n, 2, false); v != "" {
+				validations = append(validations, v)
+			}
+			return nil
+		})
+		data.Validation = strings.Join(validations, "\n")
+		actions = append(actions, data)
+		return nil
+	})
+	return actions
+}
+
+// rawGenerator returns the code of the rapid generator that draws the raw values of parameters
+// described by att. The generator draws arbitrary strings as well as values that are valid for
+// the parameter type and enum validation so that both the success and failure paths of the
+// context constructor get exercised.
+func rawGenerator(att *design.AttributeDefinition) string {
+	gens := []string{"rapid.String()"}
+	if att.Validation != nil && len(att.Validation.Values) > 0 {
+		vals := make([]string, len(att.Validation.Values))
+		for i, v := range att.Validation.Values {
+			vals[i] = fmt.Sprintf("%q", fmt.Sprint(v))
+		}
+		gens = append(gens, fmt.Sprintf("rapid.SampledFrom([]string{%s})", strings.Join(vals, ", ")))
+	}
+	switch att.Type.Kind() {
+	case design.BooleanKind:
+		gens = append(gens, `rapid.SampledFrom([]string{"true", "false"})`)
+	case design.IntegerKind:
+		gens = append(gens, "rapid.Map(rapid.Int(), strconv.Itoa)")
+	case design.UintKind, design.Uint8Kind, design.Uint16Kind, design.Uint32Kind:
+		gens = append(gens, "rapid.Map(rapid.Int(), strconv.Itoa)")
+	case design.NumberKind:
+		gens = append(gens, "rapid.Map(rapid.Float64(), func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) })")
+	case design.DateTimeKind:
+		gens = append(gens, "rapid.Map(rapid.Int64Range(0, 1<<40), func(s int64) string { return time.Unix(s, 0).UTC().Format(time.RFC3339) })")
+	}
+	if len(gens) == 1 {
+		return gens[0]
+	}
+	return fmt.Sprintf("rapid.OneOf(%s)", strings.Join(gens, ", "))
+}
+
+// propertyT generates the property test of an action context constructor.
+// template input: *ActionTemplateData
+const propertyT = `
+// TestProperty_New{{ .Context }} checks that New{{ .Context }} either returns a context whose
+// fields satisfy the design validations or a bad request error for arbitrary parameter values.
+func TestProperty_New{{ .Context }}(t *testing.T) {
+	service := goa.New("rapid")
+	rapid.Check(t, func(t *rapid.T) {
+		params := url.Values{}
+{{ range .Params }}{{ if .IsArray }}		params[{{ printf "%q" .Name }}] = rapid.SliceOf({{ .Generator }}).Draw(t, {{ printf "%q" .Name }})
+{{ else }}{{ if not .IsRequired }}		if rapid.Bool().Draw(t, {{ printf "%q" (printf "%s set" .Name) }}) {
+	{{ end }}		params.Set({{ printf "%q" .Name }}, {{ .Generator }}.Draw(t, {{ printf "%q" .Name }}))
+{{ if not .IsRequired }}		}
+{{ end }}{{ end }}{{ end }}		req, err := http.NewRequest({{ printf "%q" .Verb }}, {{ printf "%q" .Path }}, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %s", err)
+		}
+		ctx := goa.NewContext(context.Background(), httptest.NewRecorder(), req, params)
+		{{ if .Validation }}rctx, err :={{ else }}_, err ={{ end }} {{ targetPkg }}.New{{ .Context }}(ctx, service)
+		if err != nil {
+			if serr, ok := err.(goa.ServiceError); !ok || serr.ResponseStatus() != http.StatusBadRequest {
+				t.Fatalf("invalid error %T for params %v: %s", err, params, err)
+			}
+			return
+		}
+{{ if .Validation }}{{ .Validation }}
+		if err != nil {
+			t.Fatalf("context built from params %v violates the design validations: %s", params, err)
+		}
+{{ end }}	})
+}
+`
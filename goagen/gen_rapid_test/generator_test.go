@@ -0,0 +1,114 @@
+package genrapidtest_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_rapid_test"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("rapidtest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genrapidtest.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a dummy API", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API with no resource")
+				apidsl.Description("I told you it's dummy")
+			})
+			dslengine.Run()
+		})
+
+		It("does not generate any file", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+
+	Context("with an action accepting parameters", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET("/bottles"))
+					apidsl.Params(func() {
+						apidsl.Param("sort", design.String, func() {
+							apidsl.Enum("asc", "desc")
+						})
+						apidsl.Param("limit", design.Integer, func() {
+							apidsl.Minimum(1)
+						})
+						apidsl.Required("sort")
+					})
+					apidsl.Response(design.NoContent)
+				})
+				apidsl.Action("delete", func() {
+					apidsl.Routing(apidsl.DELETE("/bottles"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates a property test per context constructor", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(1))
+			filename := filepath.Join(testPkg.Abs(), "bottle_rapid_test.go")
+			content, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			f, err := parser.ParseFile(token.NewFileSet(), filename, content, 0)
+			Ω(err).ShouldNot(HaveOccurred())
+			var imports []string
+			for _, imp := range f.Imports {
+				imports = append(imports, imp.Path.Value)
+			}
+			Ω(imports).Should(ContainElement(`"pgregory.net/rapid"`))
+			Ω(f.Scope.Lookup("TestProperty_NewListBottleContext")).ShouldNot(BeNil())
+			Ω(f.Scope.Lookup("TestProperty_NewDeleteBottleContext")).Should(BeNil())
+
+			code := string(content)
+			Ω(code).Should(ContainSubstring(`params.Set("sort", rapid.OneOf(rapid.String(), rapid.SampledFrom([]string{"asc", "desc"})).Draw(t, "sort"))`))
+			Ω(code).Should(ContainSubstring(`if rapid.Bool().Draw(t, "limit set") {`))
+			Ω(code).Should(ContainSubstring(`params.Set("limit", rapid.OneOf(rapid.String(), rapid.Map(rapid.Int(), strconv.Itoa)).Draw(t, "limit"))`))
+			Ω(code).Should(ContainSubstring("rctx, err := app.NewListBottleContext(ctx, service)"))
+			Ω(code).Should(ContainSubstring("goa.InvalidRangeError(`limit`, *rctx.Limit, 1, true)"))
+			Ω(code).Should(ContainSubstring("serr.ResponseStatus() != http.StatusBadRequest"))
+		})
+	})
+})
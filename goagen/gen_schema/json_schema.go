@@ -235,15 +235,23 @@ func GenerateResourceDefinition(api *design.APIDefinition, r *design.ResourceDef
 }
 
 // MediaTypeRef produces the JSON reference to the media type definition with the given view.
+// Each media type view is defined once and referenced by all the schemas that use it.
 func MediaTypeRef(api *design.APIDefinition, mt *design.MediaTypeDefinition, view string) string {
-	if _, ok := Definitions[mt.TypeName]; !ok {
+	name := MediaTypeDefinitionName(mt, view)
+	if _, ok := Definitions[name]; !ok {
 		GenerateMediaTypeDefinition(api, mt, view)
 	}
-	ref := fmt.Sprintf("#/definitions/%s", mt.TypeName)
-	if view != "default" {
-		ref += codegen.Goify(view, true)
+	return fmt.Sprintf("#/definitions/%s", name)
+}
+
+// MediaTypeDefinitionName returns the name of the definition of the given media type view. The
+// name is the name of the Go type generated for the media type projected with the view.
+func MediaTypeDefinitionName(mt *design.MediaTypeDefinition, view string) string {
+	name := mt.TypeName
+	if view != "" && view != "default" {
+		name += codegen.Goify(view, true)
 	}
-	return ref
+	return name
 }
 
 // TypeRef produces the JSON reference to the type definition.
@@ -257,12 +265,13 @@ func TypeRef(api *design.APIDefinition, ut *design.UserTypeDefinition) string {
 // GenerateMediaTypeDefinition produces the JSON schema corresponding to the given media type and
 // given view.
 func GenerateMediaTypeDefinition(api *design.APIDefinition, mt *design.MediaTypeDefinition, view string) {
-	if _, ok := Definitions[mt.TypeName]; ok {
+	name := MediaTypeDefinitionName(mt, view)
+	if _, ok := Definitions[name]; ok {
 		return
 	}
 	s := NewJSONSchema()
 	s.Title = fmt.Sprintf("Mediatype identifier: %s", mt.Identifier)
-	Definitions[mt.TypeName] = s
+	Definitions[name] = s
 	buildMediaTypeSchema(api, mt, view, s)
 }
 
@@ -288,6 +297,14 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 			s.Format = "uuid"
 		case design.DateTimeKind:
 			s.Format = "date-time"
+		case design.IPKind:
+			s.Format = "ip"
+		case design.CIDRKind:
+			s.Format = "cidr"
+		case design.SemVerKind:
+			s.Format = "semver"
+		case design.BigIntKind:
+			s.Format = "bigint"
 		case design.NumberKind:
 			s.Format = "double"
 		case design.IntegerKind:
@@ -450,6 +467,12 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	}
 	s.Enum = val.Values
 	s.Format = val.Format
+	if val.Format == "e164" {
+		s.Format = "phone"
+	}
+	if val.IPVersion != 0 {
+		s.Format = fmt.Sprintf("ipv%d", val.IPVersion)
+	}
 	s.Pattern = val.Pattern
 	if val.Minimum != nil {
 		s.Minimum = val.Minimum
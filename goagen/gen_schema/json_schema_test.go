@@ -78,4 +78,23 @@ var _ = Describe("TypeSchema", func() {
 		})
 
 	})
+
+	Context("with an attribute using the e164 format", func() {
+		BeforeEach(func() {
+			Type("Contact", func() {
+				Attribute("phone", design.String, func() {
+					Format("e164")
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Contact"].Type
+		})
+
+		It("uses the phone JSON schema format", func() {
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.Properties).Should(HaveKey("phone"))
+			Ω(s.Properties["phone"].Format).Should(Equal("phone"))
+		})
+	})
 })
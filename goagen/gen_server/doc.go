@@ -0,0 +1,8 @@
+/*
+Package genserver provides a generator for the service HTTP server runner. The generator creates a
+server.go file in the service main package that defines the RunServer function. RunServer serves the
+service mux until the process receives SIGTERM or SIGINT, it then stops accepting new connections
+and waits for the in-flight requests to complete before returning. The time given to the requests
+to complete defaults to 30 seconds and can be configured with the WithDrainTimeout option.
+*/
+package genserver
@@ -0,0 +1,13 @@
+package genserver_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenServer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenServer Suite")
+}
@@ -0,0 +1,148 @@
+package genserver
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the graceful HTTP server runner generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+
+	set := flag.NewFlagSet("server", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the server.go file.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	filename := filepath.Join(g.OutDir, "server.go")
+	os.Remove(filename)
+	g.genfiles = append(g.genfiles, filename)
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("os"),
+		codegen.SimpleImport("os/signal"),
+		codegen.SimpleImport("syscall"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	title := fmt.Sprintf("%s: Graceful Server", g.API.Context())
+	if err = file.WriteHeader(title, "main", imports); err != nil {
+		return nil, err
+	}
+	if err = file.ExecuteTemplate("server", serverT, nil, nil); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// serverT generates the RunServer function and its options.
+// template input: nil
+const serverT = `
+// DefaultDrainTimeout is the time given to the in-flight requests to complete on shutdown when
+// the WithDrainTimeout option is not used.
+const DefaultDrainTimeout = 30 * time.Second
+
+type (
+	// ServerOption configures the server run by RunServer.
+	ServerOption func(*serverOptions)
+
+	// serverOptions holds the RunServer settings.
+	serverOptions struct {
+		drainTimeout time.Duration
+	}
+)
+
+// WithDrainTimeout sets the maximum time given to the in-flight requests to complete once
+// shutdown starts. RunServer returns context.DeadlineExceeded if the requests do not complete in
+// time.
+func WithDrainTimeout(d time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		o.drainTimeout = d
+	}
+}
+
+// RunServer serves the service requests on addr until the process receives SIGTERM or SIGINT.
+// RunServer then stops accepting new connections and returns once all the in-flight requests have
+// completed or the drain timeout has elapsed.
+func RunServer(svc *goa.Service, addr string, opts ...ServerOption) error {
+	o := &serverOptions{drainTimeout: DefaultDrainTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+	srv := &http.Server{Addr: addr, Handler: svc.Mux}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigc)
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errc:
+		return err
+	case sig := <-sigc:
+		svc.LogInfo("shutdown", "signal", sig.String(), "drain", o.drainTimeout.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.drainTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		return err
+	}
+	if err := <-errc; err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+`
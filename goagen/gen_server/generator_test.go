@@ -0,0 +1,61 @@
+package genserver_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_server"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("servertest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Title("dummy API")
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+
+		files, genErr = genserver.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("generates the RunServer function", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(1))
+		filename := filepath.Join(testPkg.Abs(), "server.go")
+		content, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		f, err := parser.ParseFile(token.NewFileSet(), filename, content, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(f.Name.Name).Should(Equal("main"))
+		Ω(f.Scope.Lookup("RunServer")).ShouldNot(BeNil())
+		Ω(f.Scope.Lookup("WithDrainTimeout")).ShouldNot(BeNil())
+		Ω(string(content)).Should(ContainSubstring("signal.Notify(sigc, syscall.SIGTERM, syscall.SIGINT)"))
+		Ω(string(content)).Should(ContainSubstring("if err := srv.Shutdown(ctx); err != nil {"))
+	})
+})
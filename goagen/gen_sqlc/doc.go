@@ -0,0 +1,10 @@
+/*
+Package gensqlc provides a generator for sqlc (https://sqlc.dev) schema and query files.
+The generator creates a "sqlc" directory containing a schema.sql file with the definition of the
+tables backing the API resources and one query file per resource. Only the resources whose design
+specifies a database table via the Table DSL are taken into account. The table columns are derived
+from the top level attributes of the resource default media type. The list query of resources
+with an action using the KeysetPage DSL returns one page of rows following a given key, sqlc
+generates a List<Resource>Params struct with LastKey and Limit fields for it.
+*/
+package gensqlc
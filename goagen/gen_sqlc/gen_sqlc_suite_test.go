@@ -0,0 +1,13 @@
+package gensqlc_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenSqlc(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenSqlc Suite")
+}
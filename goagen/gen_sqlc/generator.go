@@ -0,0 +1,250 @@
+package gensqlc
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the sqlc files generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+type (
+	// TableData contains the data used to render the schema and queries of a single table.
+	TableData struct {
+		// Name is the table name.
+		Name string
+		// Resource is the name of the resource used to build the query names.
+		Resource string
+		// Key is the primary key column if any.
+		Key *Column
+		// Columns lists the table columns other than the primary key.
+		Columns []*Column
+		// Keyset describes the keyset pagination of the list query if any.
+		Keyset *Keyset
+	}
+
+	// Keyset describes the keyset pagination of the list query, see the KeysetPage DSL.
+	Keyset struct {
+		// Column is the name of the key column.
+		Column string
+		// Desc is true if the rows are listed in descending key order.
+		Desc bool
+	}
+
+	// Column describes a single table column.
+	Column struct {
+		// Name is the column name.
+		Name string
+		// Type is the column SQL type.
+		Type string
+		// NotNull is true if the corresponding attribute is required.
+		NotNull bool
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("sqlc", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the schema and query files.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	var tables []*TableData
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		t, err := NewTableData(g.API, r)
+		if err != nil {
+			return err
+		}
+		if t != nil {
+			tables = append(tables, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+
+	sqlcDir := filepath.Join(g.OutDir, "sqlc")
+	os.RemoveAll(sqlcDir)
+	if err = os.MkdirAll(sqlcDir, 0755); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, sqlcDir)
+	schemaFile := filepath.Join(sqlcDir, "schema.sql")
+	file, err := codegen.SourceFileFor(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, schemaFile)
+	for _, t := range tables {
+		if err = file.ExecuteTemplate("schema", schemaT, nil, t); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range tables {
+		queryFile := filepath.Join(sqlcDir, t.Name+".sql")
+		file, err := codegen.SourceFileFor(queryFile)
+		if err != nil {
+			return nil, err
+		}
+		g.genfiles = append(g.genfiles, queryFile)
+		if err = file.ExecuteTemplate("queries", queriesT, nil, t); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// NewTableData builds the table data for the given resource. It returns nil if the resource does
+// not define a table.
+func NewTableData(api *design.APIDefinition, r *design.ResourceDefinition) (*TableData, error) {
+	table, ok := r.Metadata["sql:table"]
+	if !ok || len(table) == 0 {
+		return nil, nil
+	}
+	mt := api.MediaTypeWithIdentifier(r.MediaType)
+	if mt == nil || !mt.IsObject() {
+		return nil, fmt.Errorf("resource %#v defines table %#v but has no default media type object", r.Name, table[0])
+	}
+	t := &TableData{Name: table[0], Resource: r.Name}
+	obj := mt.Type.ToObject()
+	err := r.IterateActions(func(a *design.ActionDefinition) error {
+		keyset, ok := a.Metadata["sql:keyset"]
+		if !ok || len(keyset) != 2 {
+			return nil
+		}
+		if obj[keyset[0]] == nil {
+			return fmt.Errorf("keyset page key %#v of action %#v is not an attribute of the resource %#v media type", keyset[0], a.Name, r.Name)
+		}
+		t.Keyset = &Keyset{Column: codegen.SnakeCase(keyset[0]), Desc: keyset[1] == "desc"}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	obj.IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		c := &Column{Name: codegen.SnakeCase(n), Type: sqlType(at.Type), NotNull: mt.IsRequired(n)}
+		if n == "id" {
+			t.Key = c
+			return nil
+		}
+		t.Columns = append(t.Columns, c)
+		return nil
+	})
+	return t, nil
+}
+
+// sqlType returns the PostgreSQL type of the column that stores values of the given type.
+func sqlType(dt design.DataType) string {
+	if ut, ok := dt.(*design.UserTypeDefinition); ok && ut.SQLType() != "" {
+		return ut.SQLType()
+	}
+	switch dt.Kind() {
+	case design.BooleanKind:
+		return "boolean"
+	case design.IntegerKind, design.UintKind, design.Uint32Kind:
+		return "bigint"
+	case design.Uint8Kind, design.Uint16Kind:
+		return "integer"
+	case design.NumberKind:
+		return "double precision"
+	case design.BigIntKind:
+		return "numeric"
+	case design.StringKind, design.SemVerKind:
+		return "text"
+	case design.DateTimeKind:
+		return "timestamptz"
+	case design.UUIDKind:
+		return "uuid"
+	case design.IPKind:
+		return "inet"
+	case design.CIDRKind:
+		return "cidr"
+	default:
+		return "jsonb"
+	}
+}
+
+const (
+	// schemaT generates the table definitions.
+	// template input: *TableData
+	schemaT = `CREATE TABLE {{ .Name }} (
+{{ if .Key }}  {{ .Key.Name }} {{ if eq .Key.Type "bigint" }}bigserial{{ else }}{{ .Key.Type }}{{ end }} PRIMARY KEY{{ if .Columns }},{{ end }}
+{{ end }}{{ $last := len .Columns }}{{ range $i, $c := .Columns }}  {{ $c.Name }} {{ $c.Type }}{{ if $c.NotNull }} NOT NULL{{ end }}{{ if ne (add $i 1) $last }},{{ end }}
+{{ end }});
+
+`
+
+	// queriesT generates the CRUD queries of a single table.
+	// template input: *TableData
+	queriesT = `{{ $name := goify .Resource true }}-- name: Create{{ $name }} :one
+INSERT INTO {{ .Name }}{{ if .Columns }} ({{ range $i, $c := .Columns }}{{ if $i }}, {{ end }}{{ $c.Name }}{{ end }})
+VALUES ({{ range $i, $c := .Columns }}{{ if $i }}, {{ end }}${{ add $i 1 }}{{ end }}){{ else }}
+DEFAULT VALUES{{ end }}
+RETURNING *;
+{{ if .Key }}
+-- name: Get{{ $name }} :one
+SELECT * FROM {{ .Name }}
+WHERE {{ .Key.Name }} = $1 LIMIT 1;
+{{ end }}
+-- name: List{{ $name }} :many
+SELECT * FROM {{ .Name }}{{ with .Keyset }}
+WHERE {{ .Column }} {{ if .Desc }}<{{ else }}>{{ end }} @last_key
+ORDER BY {{ .Column }}{{ if .Desc }} DESC{{ end }}
+LIMIT @limit{{ else }}{{ if .Key }}
+ORDER BY {{ .Key.Name }}{{ end }}{{ end }};
+{{ if .Key }}{{ if .Columns }}
+-- name: Update{{ $name }} :one
+UPDATE {{ .Name }}
+SET {{ range $i, $c := .Columns }}{{ if $i }}, {{ end }}{{ $c.Name }} = ${{ add $i 2 }}{{ end }}
+WHERE {{ .Key.Name }} = $1
+RETURNING *;
+{{ end }}
+-- name: Delete{{ $name }} :exec
+DELETE FROM {{ .Name }}
+WHERE {{ .Key.Name }} = $1;
+{{ end }}`
+)
@@ -0,0 +1,226 @@
+package gensqlc_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_sqlc"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("sqlctest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gensqlc.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a resource with no table", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("does not generate any file", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+
+	Context("with a resource backed by a table", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			bottle := apidsl.MediaType("application/vnd.bottle+json", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("name", design.String)
+					apidsl.Attribute("vintage", design.Integer)
+					apidsl.Required("id", "name")
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.DefaultMedia(bottle)
+				apidsl.Table("bottles")
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.OK)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("generates the schema and queries", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(3))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "sqlc", "schema.sql"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(Equal(schema))
+			content, err = ioutil.ReadFile(filepath.Join(testPkg.Abs(), "sqlc", "bottles.sql"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(Equal(queries))
+		})
+	})
+
+	Context("with a table that only has a key", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			ticket := apidsl.MediaType("application/vnd.ticket+json", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("ticket", func() {
+				apidsl.DefaultMedia(ticket)
+				apidsl.Table("tickets")
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.OK)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("inserts the default values and does not generate an update query", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "sqlc", "tickets.sql"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(keyOnlyInsert))
+			Ω(string(content)).ShouldNot(ContainSubstring("UPDATE"))
+			Ω(string(content)).Should(ContainSubstring("-- name: DeleteTicket :exec"))
+		})
+	})
+
+	Context("with a keyset paginated list action", func() {
+		// keysetDesign defines a resource whose list action is paginated using the given key.
+		keysetDesign := func(key string) {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			bottle := apidsl.MediaType("application/vnd.bottle+json", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("vintage", design.Integer)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.DefaultMedia(bottle)
+				apidsl.Table("bottles")
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET(""))
+					apidsl.KeysetPage(key, "desc")
+					apidsl.Response(design.OK)
+				})
+			})
+			dslengine.Run()
+		}
+
+		BeforeEach(func() {
+			keysetDesign("vintage")
+		})
+
+		It("generates the keyset list query", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "sqlc", "bottles.sql"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(keysetQuery))
+		})
+
+		Context("using an unknown key", func() {
+			BeforeEach(func() {
+				keysetDesign("year")
+			})
+
+			It("fails", func() {
+				Ω(genErr).Should(HaveOccurred())
+			})
+		})
+	})
+})
+
+const (
+	schema = `CREATE TABLE bottles (
+  id bigserial PRIMARY KEY,
+  name text NOT NULL,
+  vintage bigint
+);
+
+`
+
+	keysetQuery = `-- name: ListBottle :many
+SELECT * FROM bottles
+WHERE vintage < @last_key
+ORDER BY vintage DESC
+LIMIT @limit;
+`
+
+	queries = `-- name: CreateBottle :one
+INSERT INTO bottles (name, vintage)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetBottle :one
+SELECT * FROM bottles
+WHERE id = $1 LIMIT 1;
+
+-- name: ListBottle :many
+SELECT * FROM bottles
+ORDER BY id;
+
+-- name: UpdateBottle :one
+UPDATE bottles
+SET name = $2, vintage = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteBottle :exec
+DELETE FROM bottles
+WHERE id = $1;
+`
+
+	keyOnlyInsert = `-- name: CreateTicket :one
+INSERT INTO tickets
+DEFAULT VALUES
+RETURNING *;
+`
+)
@@ -0,0 +1,11 @@
+/*
+Package gensqlmigration provides a generator for SQL migration files in the format used by
+golang-migrate (https://github.com/golang-migrate/migrate) and similar tools.
+The generator creates a "migrations" directory containing a pair of
+<version>_create_<table>.up.sql and <version>_create_<table>.down.sql files for each media type
+whose design specifies a database table via the Table DSL. The up migration creates the table with
+one column per top level attribute of the media type and the down migration drops it. The version
+is the UTC generation time formatted as YYYYMMDDHHMMSS. Running the generator again keeps the
+version of the migrations that already exist so that the migration history is preserved.
+*/
+package gensqlmigration
@@ -0,0 +1,13 @@
+package gensqlmigration_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenSQLMigration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenSQLMigration Suite")
+}
@@ -0,0 +1,222 @@
+package gensqlmigration
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the SQL migration files generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+type (
+	// TableData contains the data used to render the migrations of a single table.
+	TableData struct {
+		// Name is the table name.
+		Name string
+		// Version is the version of the migrations, the migration file names start with it.
+		Version string
+		// Columns lists the table columns, the primary key comes first if any.
+		Columns []*Column
+	}
+
+	// Column describes a single table column.
+	Column struct {
+		// Name is the column name.
+		Name string
+		// Type is the column SQL type.
+		Type string
+		// PrimaryKey is true if the column is the table primary key.
+		PrimaryKey bool
+		// NotNull is true if the corresponding attribute is required.
+		NotNull bool
+	}
+)
+
+// versionFormat is the layout of the migration versions.
+const versionFormat = "20060102150405"
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("sqlmigration", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the migration files.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	var tables []*TableData
+	owners := make(map[string]string)
+	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		t, err := NewTableData(mt)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return nil
+		}
+		if owner, ok := owners[t.Name]; ok {
+			return fmt.Errorf("media types %#v and %#v both define table %#v", owner, mt.Identifier, t.Name)
+		}
+		owners[t.Name] = mt.Identifier
+		tables = append(tables, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+
+	migrationsDir := filepath.Join(g.OutDir, "migrations")
+	if err = os.MkdirAll(migrationsDir, 0755); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for i, t := range tables {
+		// Give each new migration its own version so that the tables are created in a
+		// deterministic order.
+		t.Version = now.Add(time.Duration(i) * time.Second).Format(versionFormat)
+		existing, err := filepath.Glob(filepath.Join(migrationsDir, "*_create_"+t.Name+".up.sql"))
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 {
+			t.Version = strings.SplitN(filepath.Base(existing[0]), "_", 2)[0]
+		}
+		base := filepath.Join(migrationsDir, t.Version+"_create_"+t.Name)
+		if err = g.write(base+".up.sql", upT, t); err != nil {
+			return nil, err
+		}
+		if err = g.write(base+".down.sql", downT, t); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// write renders the given template into the file with the given path, replacing any existing
+// content.
+func (g *Generator) write(path, tmpl string, t *TableData) error {
+	os.Remove(path)
+	file, err := codegen.SourceFileFor(path)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, path)
+	return file.ExecuteTemplate("migration", tmpl, nil, t)
+}
+
+// NewTableData builds the table data for the given media type. It returns nil if the media type
+// does not define a table.
+func NewTableData(mt *design.MediaTypeDefinition) (*TableData, error) {
+	table, ok := mt.Metadata["sql:table"]
+	if !ok || len(table) == 0 {
+		return nil, nil
+	}
+	if !mt.IsObject() {
+		return nil, fmt.Errorf("media type %#v defines table %#v but is not an object", mt.Identifier, table[0])
+	}
+	t := &TableData{Name: table[0]}
+	var key *Column
+	mt.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		c := &Column{Name: codegen.SnakeCase(n), Type: sqlType(at.Type), NotNull: mt.IsRequired(n)}
+		if n == "id" {
+			c.PrimaryKey = true
+			key = c
+			return nil
+		}
+		t.Columns = append(t.Columns, c)
+		return nil
+	})
+	if key != nil {
+		t.Columns = append([]*Column{key}, t.Columns...)
+	}
+	return t, nil
+}
+
+// sqlType returns the type of the column that stores values of the given type.
+func sqlType(dt design.DataType) string {
+	if ut, ok := dt.(*design.UserTypeDefinition); ok && ut.SQLType() != "" {
+		return strings.ToUpper(ut.SQLType())
+	}
+	switch dt.Kind() {
+	case design.BooleanKind:
+		return "BOOLEAN"
+	case design.IntegerKind, design.Uint8Kind, design.Uint16Kind:
+		return "INTEGER"
+	case design.UintKind, design.Uint32Kind:
+		return "BIGINT"
+	case design.NumberKind:
+		return "DOUBLE PRECISION"
+	case design.BigIntKind:
+		return "NUMERIC"
+	case design.StringKind, design.SemVerKind:
+		return "TEXT"
+	case design.DateTimeKind:
+		return "TIMESTAMPTZ"
+	case design.UUIDKind:
+		return "UUID"
+	case design.IPKind:
+		return "INET"
+	case design.CIDRKind:
+		return "CIDR"
+	default:
+		return "JSONB"
+	}
+}
+
+const (
+	// upT generates the migration that creates a table.
+	// template input: *TableData
+	upT = `CREATE TABLE {{ .Name }} (
+{{ range $i, $c := .Columns }}{{ if $i }},
+{{ end }}  {{ $c.Name }} {{ $c.Type }}{{ if $c.PrimaryKey }} PRIMARY KEY{{ else if $c.NotNull }} NOT NULL{{ end }}{{ end }}
+);
+`
+
+	// downT generates the migration that drops a table.
+	// template input: *TableData
+	downT = `DROP TABLE {{ .Name }};
+`
+)
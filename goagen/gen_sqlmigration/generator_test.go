@@ -0,0 +1,142 @@
+package gensqlmigration_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_sqlmigration"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("sqlmigrationtest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gensqlmigration.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a media type with no table", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			apidsl.MediaType("application/vnd.bottle+json", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("does not generate any file", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+
+	Context("with a media type backed by a table", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			apidsl.MediaType("application/vnd.bottle+json", func() {
+				apidsl.Table("bottles")
+				apidsl.Attributes(func() {
+					apidsl.Attribute("name", design.String)
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("vintage", design.Integer)
+					apidsl.Attribute("createdAt", design.DateTime)
+					apidsl.Required("id", "name")
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("generates the up and down migrations", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(2))
+			Ω(filepath.Base(files[0])).Should(MatchRegexp(`^\d{14}_create_bottles\.up\.sql$`))
+			Ω(filepath.Base(files[1])).Should(MatchRegexp(`^\d{14}_create_bottles\.down\.sql$`))
+			content, err := ioutil.ReadFile(files[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(Equal(up))
+			content, err = ioutil.ReadFile(files[1])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(Equal(down))
+		})
+
+		It("keeps the version of existing migrations", func() {
+			Ω(genErr).Should(BeNil())
+			again, err := gensqlmigration.Generate()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(again).Should(Equal(files))
+			content, err := ioutil.ReadFile(again[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(Equal(up))
+		})
+	})
+
+	Context("with two media types using the same table", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			for _, id := range []string{"application/vnd.bottle+json", "application/vnd.wine+json"} {
+				apidsl.MediaType(id, func() {
+					apidsl.Table("bottles")
+					apidsl.Attributes(func() {
+						apidsl.Attribute("id", design.Integer)
+					})
+					apidsl.View("default", func() {
+						apidsl.Attribute("id")
+					})
+				})
+			}
+			dslengine.Run()
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(HaveOccurred())
+		})
+	})
+})
+
+const (
+	up = `CREATE TABLE bottles (
+  id INTEGER PRIMARY KEY,
+  created_at TIMESTAMPTZ,
+  name TEXT NOT NULL,
+  vintage INTEGER
+);
+`
+
+	down = `DROP TABLE bottles;
+`
+)
@@ -0,0 +1,9 @@
+/*
+Package genstubs provides a generator for stub controller implementations used for rapid
+prototyping. The generator creates a stubs.go file in the service main package that defines one
+XxxControllerImpl struct per resource implementing the corresponding controller interface. The
+action methods panic with a "not implemented" message until replaced with actual implementations.
+The file is built only when the "stubs" build tag is set so that it can be excluded from production
+builds. The generator does not overwrite an existing stubs.go file.
+*/
+package genstubs
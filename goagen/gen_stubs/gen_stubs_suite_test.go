@@ -0,0 +1,13 @@
+package genstubs_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenStubs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenStubs Suite")
+}
@@ -0,0 +1,133 @@
+package genstubs
+
+import (
+	"flag"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the stub controllers code generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Target   string                // Name of generated "app" package
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, target, ver string
+
+	set := flag.NewFlagSet("stubs", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&target, "pkg", "app", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	target = codegen.Goify(target, false)
+	g := &Generator{OutDir: outDir, Target: target, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the stubs.go file unless it already exists.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Target == "" {
+		g.Target = "app"
+	}
+
+	stubsFile := filepath.Join(g.OutDir, "stubs.go")
+	if _, err = os.Stat(stubsFile); err == nil {
+		return nil, nil
+	}
+	outPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, stubsFile)
+	file, err := codegen.SourceFileFor(stubsFile)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport(path.Join(filepath.ToSlash(outPkg), g.Target)),
+	}
+	file.Write([]byte("//go:build stubs\n// +build stubs\n\n"))
+	if err = file.WriteHeader("", "main", imports); err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{
+		"targetPkg": func() string { return g.Target },
+	}
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		if err := file.ExecuteTemplate("controller", ctrlT, funcs, r); err != nil {
+			return err
+		}
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			return file.ExecuteTemplate("action", actionT, funcs, a)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+const (
+	// ctrlT generates the stub controller of a resource.
+	// template input: *design.ResourceDefinition
+	ctrlT = `{{ $ctrl := printf "%sController" (goify .Name true) }}
+// {{ $ctrl }}Impl is a stub implementation of the {{ targetPkg }}.{{ $ctrl }} interface.
+type {{ $ctrl }}Impl struct {
+	*goa.Controller
+}
+
+var _ {{ targetPkg }}.{{ $ctrl }} = (*{{ $ctrl }}Impl)(nil)
+
+// New{{ $ctrl }}Impl creates a stub {{ .Name }} controller.
+func New{{ $ctrl }}Impl(service *goa.Service) *{{ $ctrl }}Impl {
+	return &{{ $ctrl }}Impl{Controller: service.NewController("{{ $ctrl }}")}
+}
+`
+
+	// actionT generates the stub method of an action.
+	// template input: *design.ActionDefinition
+	actionT = `{{ $ctrl := printf "%sController" (goify .Parent.Name true) }}{{ $action := goify .Name true }}
+// {{ $action }} runs the {{ .Name }} action.
+func (c *{{ $ctrl }}Impl) {{ $action }}(ctx *{{ targetPkg }}.{{ $action }}{{ goify .Parent.Name true }}{{ if .WebSocket }}WebSocket{{ end }}Context) error {
+	panic("not implemented: {{ $ctrl }}.{{ $action }}")
+}
+`
+)
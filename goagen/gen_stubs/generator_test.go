@@ -0,0 +1,86 @@
+package genstubs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_stubs"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("stubstest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Title("dummy API")
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.Action("show", func() {
+				apidsl.Routing(apidsl.GET("/:id"))
+				apidsl.Response(design.NoContent)
+			})
+			apidsl.Action("list", func() {
+				apidsl.Routing(apidsl.GET(""))
+				apidsl.Response(design.NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genstubs.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("generates the stub controllers", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(1))
+		content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "stubs.go"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(HavePrefix("//go:build stubs\n// +build stubs\n"))
+		Ω(string(content)).Should(ContainSubstring("type BottleControllerImpl struct {"))
+		Ω(string(content)).Should(ContainSubstring("var _ app.BottleController = (*BottleControllerImpl)(nil)"))
+		Ω(string(content)).Should(ContainSubstring("func (c *BottleControllerImpl) Show(ctx *app.ShowBottleContext) error {"))
+		Ω(string(content)).Should(ContainSubstring(`panic("not implemented: BottleController.Show")`))
+		Ω(string(content)).Should(ContainSubstring(`panic("not implemented: BottleController.List")`))
+	})
+
+	Context("with an existing stubs file", func() {
+		const custom = "//go:build stubs\n\npackage main\n\n// custom implementation\n"
+
+		BeforeEach(func() {
+			err := ioutil.WriteFile(filepath.Join(testPkg.Abs(), "stubs.go"), []byte(custom), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("does not overwrite it", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(BeEmpty())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "stubs.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(Equal(custom))
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package genswagger_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	JustBeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("swaggertest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+		apidsl.API("test api", func() {
+			apidsl.Title("dummy API")
+		})
+		bottle := apidsl.MediaType("application/vnd.goa.example.bottle", func() {
+			apidsl.TypeName("Bottle")
+			apidsl.Attributes(func() {
+				apidsl.Attribute("id", design.Integer)
+				apidsl.Attribute("name", design.String)
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("id")
+				apidsl.Attribute("name")
+			})
+			apidsl.View("tiny", func() {
+				apidsl.Attribute("id")
+			})
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.Action("show", func() {
+				apidsl.Routing(apidsl.GET("/bottles/:id"))
+				apidsl.Response(design.OK, bottle)
+			})
+			apidsl.Action("create", func() {
+				apidsl.Routing(apidsl.POST("/bottles"))
+				apidsl.Response(design.Created, bottle)
+			})
+			apidsl.Action("peek", func() {
+				apidsl.Routing(apidsl.GET("/bottles/:id/peek"))
+				apidsl.Response(design.OK, func() {
+					apidsl.Media(bottle, "tiny")
+				})
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+
+		files, genErr = genswagger.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("defines the media types used by multiple responses once", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(3))
+		content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "swagger", "swagger.yaml"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(strings.Count(string(content), "\n  Bottle:\n")).Should(Equal(1))
+
+		var spec struct {
+			Paths map[string]map[string]struct {
+				Responses map[string]struct {
+					Schema map[string]string `yaml:"schema"`
+				} `yaml:"responses"`
+			} `yaml:"paths"`
+			Definitions map[string]interface{} `yaml:"definitions"`
+		}
+		Ω(yaml.Unmarshal(content, &spec)).Should(Succeed())
+		Ω(spec.Definitions).Should(HaveLen(2))
+		Ω(spec.Definitions).Should(HaveKey("Bottle"))
+		Ω(spec.Definitions).Should(HaveKey("BottleTiny"))
+		Ω(spec.Paths["/bottles/{id}"]["get"].Responses["200"].Schema["$ref"]).Should(Equal("#/definitions/Bottle"))
+		Ω(spec.Paths["/bottles"]["post"].Responses["201"].Schema["$ref"]).Should(Equal("#/definitions/Bottle"))
+		Ω(spec.Paths["/bottles/{id}/peek"]["get"].Responses["200"].Schema["$ref"]).Should(Equal("#/definitions/BottleTiny"))
+	})
+})
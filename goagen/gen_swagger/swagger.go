@@ -144,6 +144,9 @@ type (
 		UniqueItems      bool          `json:"uniqueItems,omitempty"`
 		Enum             []interface{} `json:"enum,omitempty"`
 		MultipleOf       float64       `json:"multipleOf,omitempty"`
+		// Example is the example value defined in the design if any. Swagger 2.0 does not
+		// support examples on non-body parameters so the value is emitted as an extension.
+		Example interface{} `json:"x-example,omitempty"`
 	}
 
 	// Response describes an operation response.
@@ -555,6 +558,7 @@ func paramFor(at *design.AttributeDefinition, name, in string, required bool) *P
 		Description: at.Description,
 		Required:    required,
 		Type:        at.Type.Name(),
+		Example:     toStringMap(at.Example),
 	}
 	if at.Type.IsArray() {
 		p.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
@@ -612,7 +616,12 @@ func responseSpecFromDefinition(s *Swagger, api *design.APIDefinition, r *design
 	var schema *genschema.JSONSchema
 	if r.MediaType != "" {
 		if mt, ok := api.MediaTypes[design.CanonicalIdentifier(r.MediaType)]; ok {
-			schema = genschema.TypeSchema(api, mt)
+			view := r.ViewName
+			if view == "" {
+				view = design.DefaultView
+			}
+			schema = genschema.NewJSONSchema()
+			schema.Ref = genschema.MediaTypeRef(api, mt, view)
 		}
 	}
 	headers, err := headersFromDefinition(r.Headers)
@@ -755,7 +764,14 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		responses[strconv.Itoa(r.Status)] = resp
 	}
 
-	if action.Payload != nil {
+	var consumes []string
+	if action.Payload != nil && action.FormData {
+		action.Payload.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+			params = append(params, paramFor(at, n, "formData", action.Payload.IsRequired(n)))
+			return nil
+		})
+		consumes = []string{"multipart/form-data"}
+	} else if action.Payload != nil {
 		payloadSchema := genschema.TypeSchema(api, action.Payload)
 		pp := &Parameter{
 			Name:        "payload",
@@ -790,6 +806,7 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		Summary:      summaryFromDefinition(action.Name+" "+action.Parent.Name, action.Metadata),
 		ExternalDocs: docsFromDefinition(action.Docs),
 		OperationID:  operationID,
+		Consumes:     consumes,
 		Parameters:   params,
 		Responses:    responses,
 		Schemes:      schemes,
@@ -976,6 +993,9 @@ func initValidations(attr *design.AttributeDefinition, def interface{}) {
 	}
 	initEnumValidation(def, val.Values)
 	initFormatValidation(def, val.Format)
+	if val.IPVersion != 0 {
+		initFormatValidation(def, fmt.Sprintf("ipv%d", val.IPVersion))
+	}
 	initPatternValidation(def, val.Pattern)
 	if val.Minimum != nil {
 		initMinimumValidation(def, val.Minimum)
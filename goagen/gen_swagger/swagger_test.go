@@ -214,6 +214,49 @@ var _ = Describe("New", func() {
 			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
 		})
 
+		Context("with examples", func() {
+			const (
+				strParam   = "strParam"
+				strExample = "example-param"
+				attExample = "example-attribute"
+			)
+
+			BeforeEach(func() {
+				PayloadWithExample := Type("PayloadWithExample", func() {
+					Attribute("name", String, func() {
+						Example(attExample)
+					})
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/examples"),
+						)
+						Params(func() {
+							Param(strParam, String, func() {
+								Example(strExample)
+							})
+						})
+						Payload(PayloadWithExample)
+					})
+				})
+			})
+
+			It("sets the parameter and payload examples", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				params := swagger.Paths["/examples"].Put.Parameters
+				Ω(params).ShouldNot(BeEmpty())
+				Ω(params[0].Example).Should(Equal(strExample))
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"x-example":"` + strExample + `"`),
+					[]byte(`"example":"` + attExample + `"`),
+				})
+			})
+		})
+
 		Context("with zero value validations", func() {
 			const (
 				intParam = "intParam"
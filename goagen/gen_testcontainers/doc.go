@@ -0,0 +1,10 @@
+/*
+Package gentestcontainers provides a generator for the TestMain function of integration tests that
+depend on external services such as databases. The services are declared in the API design with the
+Requires DSL. The generated testcontainers_test.go file starts one Docker container per service
+using the testcontainers-go package, waits until the services accept connections and exports their
+connection strings in environment variables named after the services (e.g. POSTGRES_URL) before
+running the tests. The containers are terminated once the tests complete. The file is built only
+when the "integration" build tag is set.
+*/
+package gentestcontainers
@@ -0,0 +1,13 @@
+package gentestcontainers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenTestcontainers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenTestcontainers Suite")
+}
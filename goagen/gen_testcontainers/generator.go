@@ -0,0 +1,233 @@
+package gentestcontainers
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the testcontainers TestMain code generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Service describes how to run and connect to an external service.
+type Service struct {
+	// Name is the service name used in the Requires DSL, e.g. "postgres".
+	Name string
+	// Image is the Docker image running the service.
+	Image string
+	// Port is the container port the service listens on, e.g. "5432/tcp".
+	Port string
+	// Env contains the container environment variables.
+	Env map[string]string
+	// Ready is the log line written by the service once it accepts connections.
+	Ready string
+	// Occurrence is the number of times Ready must be logged.
+	Occurrence int
+	// EnvVar is the name of the environment variable holding the connection string.
+	EnvVar string
+	// URL is the connection string format, it takes the host and the mapped port as arguments.
+	URL string
+}
+
+// Services lists the supported external services indexed by name.
+var Services = map[string]*Service{
+	"postgres": {
+		Name:       "postgres",
+		Port:       "5432/tcp",
+		Env:        map[string]string{"POSTGRES_USER": "goa", "POSTGRES_PASSWORD": "goa", "POSTGRES_DB": "goa"},
+		Ready:      "database system is ready to accept connections",
+		Occurrence: 2,
+		EnvVar:     "POSTGRES_URL",
+		URL:        "postgres://goa:goa@%s:%s/goa?sslmode=disable",
+	},
+	"mysql": {
+		Name:       "mysql",
+		Port:       "3306/tcp",
+		Env:        map[string]string{"MYSQL_USER": "goa", "MYSQL_PASSWORD": "goa", "MYSQL_DATABASE": "goa", "MYSQL_ROOT_PASSWORD": "goa"},
+		Ready:      "ready for connections",
+		Occurrence: 2,
+		EnvVar:     "MYSQL_URL",
+		URL:        "goa:goa@tcp(%s:%s)/goa",
+	},
+	"redis": {
+		Name:       "redis",
+		Port:       "6379/tcp",
+		Ready:      "Ready to accept connections",
+		Occurrence: 1,
+		EnvVar:     "REDIS_URL",
+		URL:        "redis://%s:%s",
+	},
+	"mongo": {
+		Name:       "mongo",
+		Port:       "27017/tcp",
+		Ready:      "Waiting for connections",
+		Occurrence: 1,
+		EnvVar:     "MONGO_URL",
+		URL:        "mongodb://%s:%s",
+	},
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+
+	set := flag.NewFlagSet("testcontainers", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the testcontainers_test.go file if the API requires external services.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	services, err := RequiredServices(g.API)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, nil
+	}
+	filename := filepath.Join(g.OutDir, "testcontainers_test.go")
+	g.genfiles = append(g.genfiles, filename)
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("log"),
+		codegen.SimpleImport("os"),
+		codegen.SimpleImport("testing"),
+		codegen.NewImport("testcontainers", "github.com/testcontainers/testcontainers-go"),
+		codegen.SimpleImport("github.com/testcontainers/testcontainers-go/wait"),
+	}
+	file.Write([]byte("//go:build integration\n// +build integration\n\n"))
+	title := fmt.Sprintf("%s: Integration Test Containers", g.API.Context())
+	if err = file.WriteHeader(title, "main", imports); err != nil {
+		return nil, err
+	}
+	if err = file.ExecuteTemplate("testMain", testMainT, nil, services); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// RequiredServices returns the external services declared with the Requires DSL in order of
+// declaration.
+func RequiredServices(api *design.APIDefinition) ([]*Service, error) {
+	var services []*Service
+	for _, req := range api.Metadata["testcontainers:requires"] {
+		elems := strings.SplitN(req, "=", 2)
+		if len(elems) != 2 {
+			return nil, fmt.Errorf(`invalid testcontainers:requires metadata value "%s", must be of the form "service=image"`, req)
+		}
+		known, ok := Services[elems[0]]
+		if !ok {
+			var names []string
+			for n := range Services {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf(`unsupported service "%s", must be one of %s`, elems[0], strings.Join(names, ", "))
+		}
+		svc := *known
+		svc.Image = elems[1]
+		services = append(services, &svc)
+	}
+	return services, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// testMainT generates the TestMain function starting the service containers.
+// template input: []*Service
+const testMainT = `
+// TestMain starts the containers of the external services required by the API, exports their
+// connection strings in the environment, runs the tests and terminates the containers.
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	var containers []testcontainers.Container
+	terminate := func() {
+		for _, c := range containers {
+			c.Terminate(ctx)
+		}
+	}
+	fail := func(msg string, err error) {
+		terminate()
+		log.Fatalf("%s: %s", msg, err)
+	}
+{{ range . }}
+	// Start the {{ .Name }} container and export {{ .EnvVar }}
+	{
+		c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:        {{ printf "%q" .Image }},
+				ExposedPorts: []string{ {{- printf "%q" .Port -}} },
+{{ if .Env }}				Env: map[string]string{
+{{ range $k, $v := .Env }}					{{ printf "%q" $k }}: {{ printf "%q" $v }},
+{{ end }}				},
+{{ end }}				WaitingFor: wait.ForAll(
+					wait.ForListeningPort({{ printf "%q" .Port }}),
+					wait.ForLog({{ printf "%q" .Ready }}){{ if gt .Occurrence 1 }}.WithOccurrence({{ .Occurrence }}){{ end }},
+				),
+			},
+			Started: true,
+		})
+		if err != nil {
+			fail("failed to start {{ .Name }} container", err)
+		}
+		containers = append(containers, c)
+		host, err := c.Host(ctx)
+		if err != nil {
+			fail("failed to retrieve {{ .Name }} container host", err)
+		}
+		port, err := c.MappedPort(ctx, {{ printf "%q" .Port }})
+		if err != nil {
+			fail("failed to retrieve {{ .Name }} container port", err)
+		}
+		os.Setenv({{ printf "%q" .EnvVar }}, fmt.Sprintf({{ printf "%q" .URL }}, host, port.Port()))
+	}
+{{ end }}
+	code := m.Run()
+	terminate()
+	os.Exit(code)
+}
+`
@@ -0,0 +1,108 @@
+package gentestcontainers_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_testcontainers"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var requires [][2]string
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		requires = [][2]string{{"postgres", "postgres:16-alpine"}, {"redis", "redis:7"}}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("containerstest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Title("dummy API")
+			for _, r := range requires {
+				apidsl.Requires(r[0], r[1])
+			}
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+
+		files, genErr = gentestcontainers.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("generates a TestMain that compiles with testcontainers-go imported", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(1))
+		filename := filepath.Join(testPkg.Abs(), "testcontainers_test.go")
+		content, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(HavePrefix("//go:build integration\n// +build integration\n"))
+
+		f, err := parser.ParseFile(token.NewFileSet(), filename, content, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		var imports []string
+		for _, imp := range f.Imports {
+			imports = append(imports, imp.Path.Value)
+		}
+		Ω(imports).Should(ContainElement(`"github.com/testcontainers/testcontainers-go"`))
+		Ω(imports).Should(ContainElement(`"github.com/testcontainers/testcontainers-go/wait"`))
+		Ω(f.Scope.Lookup("TestMain")).ShouldNot(BeNil())
+
+		Ω(string(content)).Should(ContainSubstring(`Image:        "postgres:16-alpine",`))
+		Ω(string(content)).Should(ContainSubstring(`wait.ForLog("database system is ready to accept connections").WithOccurrence(2),`))
+		Ω(string(content)).Should(ContainSubstring(`os.Setenv("POSTGRES_URL", fmt.Sprintf("postgres://goa:goa@%s:%s/goa?sslmode=disable", host, port.Port()))`))
+		Ω(string(content)).Should(ContainSubstring(`Image:        "redis:7",`))
+		Ω(string(content)).Should(ContainSubstring(`os.Setenv("REDIS_URL", fmt.Sprintf("redis://%s:%s", host, port.Port()))`))
+		Ω(string(content)).Should(ContainSubstring("code := m.Run()"))
+
+		cmd := exec.Command("go", "vet", "-tags", "integration", ".")
+		cmd.Dir = testPkg.Abs()
+		out, err := cmd.CombinedOutput()
+		Ω(err).ShouldNot(HaveOccurred(), string(out))
+	})
+
+	Context("with no required service", func() {
+		BeforeEach(func() {
+			requires = nil
+		})
+
+		It("does not generate any file", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+
+	Context("with an unsupported service", func() {
+		BeforeEach(func() {
+			requires = [][2]string{{"cassandra", "cassandra:4"}}
+		})
+
+		It("returns an error", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(genErr.Error()).Should(ContainSubstring(`unsupported service "cassandra"`))
+		})
+	})
+})
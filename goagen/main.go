@@ -61,8 +61,8 @@ package and tool and the Swagger specification for the API.
 
 	// appCmd implements the "app" command.
 	var (
-		pkg    string
-		notest bool
+		pkg, router, orderedAttrs, overlay, tracer       string
+		notest, expvar, schema, standalone, flattenLinks bool
 	)
 	appCmd := &cobra.Command{
 		Use:   "app",
@@ -71,11 +71,19 @@ package and tool and the Swagger specification for the API.
 	}
 	appCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
 	appCmd.Flags().BoolVar(&notest, "notest", false, "Prevent generation of test helpers")
+	appCmd.Flags().StringVar(&router, "router", "httptreemux", `Router used by the generated code: "httptreemux", "stdlib" (requires Go 1.22) or "chi"`)
+	appCmd.Flags().BoolVar(&expvar, "expvar", false, "Record request counts and latencies with expvar and serve them on /debug/vars")
+	appCmd.Flags().BoolVar(&schema, "schema-validate", false, "Validate the JSON request bodies against the payload JSON schemas before decoding them")
+	appCmd.Flags().BoolVar(&standalone, "standalone", false, "Generate a <Resource>Service http.Handler for each resource so that controllers can be mounted on any net/http server")
+	appCmd.Flags().BoolVar(&flattenLinks, "flatten-links", false, "Marshal the links of media types at the top level of the JSON objects instead of nesting them under \"links\"")
+	appCmd.Flags().StringVar(&orderedAttrs, "ordered-attrs", "alpha", `Order of the generated struct fields: "alpha" sorts them by name, "design" uses the design declaration order`)
+	appCmd.Flags().StringVar(&tracer, "tracer", "", `APM tracer instrumenting the generated service: "datadog" generates tracing.go using dd-trace-go`)
+	appCmd.Flags().StringVar(&overlay, "overlay", "", "Directory containing <name>.tmpl files that override the built-in templates with the same constant name, e.g. ctxT.tmpl")
 	rootCmd.AddCommand(appCmd)
 
 	// mainCmd implements the "main" command.
 	var (
-		force bool
+		force, wireGen bool
 	)
 	mainCmd := &cobra.Command{
 		Use:   "main",
@@ -83,6 +91,7 @@ package and tool and the Swagger specification for the API.
 		Run:   func(c *cobra.Command, _ []string) { files, err = run("genmain", c) },
 	}
 	mainCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
+	mainCmd.Flags().BoolVar(&wireGen, "wire-gen", false, "overwrite an existing wire_gen.go file, --force leaves it untouched")
 	rootCmd.AddCommand(mainCmd)
 
 	// clientCmd implements the "client" command.
@@ -99,6 +108,7 @@ package and tool and the Swagger specification for the API.
 	clientCmd.Flags().StringVar(&toolDir, "tooldir", "tool", "Name of generated tool directory")
 	clientCmd.Flags().StringVar(&tool, "tool", "[API-name]-cli", "Name of generated tool")
 	clientCmd.Flags().BoolVar(&notool, "notool", false, "Prevent generation of cli tool")
+	clientCmd.Flags().StringVar(&orderedAttrs, "ordered-attrs", "alpha", `Order of the generated struct fields: "alpha" sorts them by name, "design" uses the design declaration order`)
 	rootCmd.AddCommand(clientCmd)
 
 	// swaggerCmd implements the "swagger" command.
@@ -134,6 +144,208 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(schemaCmd)
 
+	// avroCmd implements the "avro" command.
+	avroCmd := &cobra.Command{
+		Use:   "avro",
+		Short: "Generate Avro schemas",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genavro", c) },
+	}
+	rootCmd.AddCommand(avroCmd)
+
+	// confluentCmd implements the "confluent" command.
+	var (
+		registryURL string
+		dryRun      bool
+	)
+	confluentCmd := &cobra.Command{
+		Use:   "confluent",
+		Short: "Register Avro schemas with a Confluent Schema Registry",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genconfluent", c) },
+	}
+	confluentCmd.Flags().StringVar(&registryURL, "registry-url", "", "Base URL of the Confluent Schema Registry, e.g. http://localhost:8081")
+	confluentCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the schemas without registering them")
+	rootCmd.AddCommand(confluentCmd)
+
+	// fxCmd implements the "fx" command.
+	fxCmd := &cobra.Command{
+		Use:   "fx",
+		Short: "Generate fx modules",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genfx", c) },
+	}
+	fxCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
+	rootCmd.AddCommand(fxCmd)
+
+	// awsLambdaCmd implements the "aws_lambda" command.
+	awsLambdaCmd := &cobra.Command{
+		Use:   "aws_lambda",
+		Short: "Generate AWS Lambda function handler",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genaws_lambda", c) },
+	}
+	awsLambdaCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
+	rootCmd.AddCommand(awsLambdaCmd)
+
+	// stubsCmd implements the "stubs" command.
+	stubsCmd := &cobra.Command{
+		Use:   "stubs",
+		Short: "Generate stub controller implementations",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genstubs", c) },
+	}
+	stubsCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
+	rootCmd.AddCommand(stubsCmd)
+
+	// testcontainersCmd implements the "testcontainers" command.
+	testcontainersCmd := &cobra.Command{
+		Use:   "testcontainers",
+		Short: "Generate the TestMain function starting the external services containers",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gentestcontainers", c) },
+	}
+	rootCmd.AddCommand(testcontainersCmd)
+
+	// e2eCmd implements the "e2e" command.
+	var health string
+	e2eCmd := &cobra.Command{
+		Use:   "e2e",
+		Short: "Generate end-to-end tests running the service in a container",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gene2e", c) },
+	}
+	e2eCmd.Flags().StringVar(&health, "health", "/health", "Path of the endpoint polled until the service is ready")
+	rootCmd.AddCommand(e2eCmd)
+
+	// connectCmd implements the "connect" command.
+	connectCmd := &cobra.Command{
+		Use:   "connect",
+		Short: "Generate Connect handlers exposing the API actions as unary procedures",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genconnect", c) },
+	}
+	rootCmd.AddCommand(connectCmd)
+
+	// middlewareCmd implements the "middleware" command.
+	var logger string
+	middlewareCmd := &cobra.Command{
+		Use:   "middleware",
+		Short: "Generate the request logging middleware",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genmiddleware", c) },
+	}
+	middlewareCmd.Flags().StringVar(&logger, "logger", "slog", `Logging package used by the middleware, one of "slog" or "zerolog"`)
+	rootCmd.AddCommand(middlewareCmd)
+
+	// compatCmd implements the "compat" command.
+	compatCmd := &cobra.Command{
+		Use:   "compat",
+		Short: "Record the API version snapshot and generate the version compatibility matrix",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gencompat", c) },
+	}
+	rootCmd.AddCommand(compatCmd)
+
+	// markdownCmd implements the "markdown" command.
+	markdownCmd := &cobra.Command{
+		Use:   "markdown",
+		Short: "Generate the API documentation in Markdown format",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genmarkdown", c) },
+	}
+	rootCmd.AddCommand(markdownCmd)
+
+	// explorerCmd implements the "explorer" command.
+	explorerCmd := &cobra.Command{
+		Use:   "explorer",
+		Short: "Generate an interactive HTML API explorer",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genexplorer", c) },
+	}
+	rootCmd.AddCommand(explorerCmd)
+
+	// serverCmd implements the "server" command.
+	serverCmd := &cobra.Command{
+		Use:   "server",
+		Short: "Generate the server runner draining the in-flight requests on shutdown",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genserver", c) },
+	}
+	rootCmd.AddCommand(serverCmd)
+
+	// contractCmd implements the "contract" command.
+	var spec string
+	contractCmd := &cobra.Command{
+		Use:   "contract",
+		Short: "Generate the tests checking the service responses against the OpenAPI specification",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gencontract", c) },
+	}
+	contractCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
+	contractCmd.Flags().StringVar(&spec, "spec", "swagger/swagger.json", "Path to the OpenAPI specification relative to the output directory")
+	rootCmd.AddCommand(contractCmd)
+
+	// rapidCmd implements the "rapid" command.
+	rapidCmd := &cobra.Command{
+		Use:   "rapid",
+		Short: "Generate property-based tests of the action context constructors",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genrapid_test", c) },
+	}
+	rapidCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
+	rootCmd.AddCommand(rapidCmd)
+
+	// coverageCmd implements the "coverage" command.
+	var testPkg string
+	coverageCmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Generate the test checking that the controllers handle all the design actions",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gencoverage_test", c) },
+	}
+	coverageCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
+	coverageCmd.Flags().StringVar(&testPkg, "test-pkg", "main", "Name of the Go package of the generated test file")
+	rootCmd.AddCommand(coverageCmd)
+
+	// pactCmd implements the "pact" command.
+	pactCmd := &cobra.Command{
+		Use:   "pact",
+		Short: "Generate Pact provider verification tests",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genpact", c) },
+	}
+	pactCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
+	rootCmd.AddCommand(pactCmd)
+
+	// integrationTestCmd implements the "integration_test" command.
+	integrationTestCmd := &cobra.Command{
+		Use:   "integration_test",
+		Short: "Generate integration tests sending HTTP requests to the running service",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genintegration_test", c) },
+	}
+	integrationTestCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
+	integrationTestCmd.Flags().StringVar(&testPkg, "test-pkg", "main", "Name of the Go package of the generated test files, it must define the New<Resource>Controller functions")
+	rootCmd.AddCommand(integrationTestCmd)
+
+	// grafanaCmd implements the "grafana" command.
+	var prefix, datasource string
+	grafanaCmd := &cobra.Command{
+		Use:   "grafana",
+		Short: "Generate a Grafana dashboard",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gengrafana", c) },
+	}
+	grafanaCmd.Flags().StringVar(&prefix, "prefix", "goa", "Prefix of the Prometheus metric names")
+	grafanaCmd.Flags().StringVar(&datasource, "datasource", "Prometheus", "Name of the Grafana Prometheus data source")
+	rootCmd.AddCommand(grafanaCmd)
+
+	// sqlcCmd implements the "sqlc" command.
+	sqlcCmd := &cobra.Command{
+		Use:   "sqlc",
+		Short: "Generate sqlc schema and queries",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gensqlc", c) },
+	}
+	rootCmd.AddCommand(sqlcCmd)
+
+	// sqlmigrationCmd implements the "sqlmigration" command.
+	sqlmigrationCmd := &cobra.Command{
+		Use:   "sqlmigration",
+		Short: "Generate SQL migrations creating the media type tables",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gensqlmigration", c) },
+	}
+	rootCmd.AddCommand(sqlmigrationCmd)
+
+	// aiToolCmd implements the "ai_tool" command.
+	aiToolCmd := &cobra.Command{
+		Use:   "ai_tool",
+		Short: "Generate OpenAI function calling tool definitions",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genai_tool", c) },
+	}
+	rootCmd.AddCommand(aiToolCmd)
+
 	// genCmd implements the "gen" command.
 	var (
 		pkgPath string
@@ -267,6 +479,11 @@ func generate(pkgName, pkgPath string, c *cobra.Command) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	if overlay, ok := m["overlay"]; ok {
+		if m["overlay"], err = filepath.Abs(overlay); err != nil {
+			return nil, err
+		}
+	}
 
 	gen, err := meta.NewGenerator(
 		pkgName+".Generate",
@@ -0,0 +1,13 @@
+package goatest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGoatest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Goatest Suite")
+}
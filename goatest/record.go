@@ -0,0 +1,109 @@
+package goatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+)
+
+type (
+	// RecordedRequest is the serializable representation of a request sent to a handler.
+	RecordedRequest struct {
+		// Method is the request HTTP method.
+		Method string `json:"method"`
+		// URL is the request URL.
+		URL string `json:"url"`
+		// Header contains the request headers.
+		Header http.Header `json:"header,omitempty"`
+		// Body is the request body.
+		Body string `json:"body,omitempty"`
+	}
+
+	// RecordedResponse is the serializable representation of a response written by a handler.
+	RecordedResponse struct {
+		// Status is the response HTTP status code.
+		Status int `json:"status"`
+		// Header contains the response headers.
+		Header http.Header `json:"header,omitempty"`
+		// Body is the response body.
+		Body string `json:"body,omitempty"`
+	}
+)
+
+// SnapshotDir is the directory where MatchesSnapshot reads and writes the golden files.
+var SnapshotDir = "testdata"
+
+// Record sends req to handler and captures both the request and the response written by the
+// handler so that they can be compared against snapshots.
+func Record(handler http.Handler, req *http.Request) (*RecordedRequest, *RecordedResponse, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	recReq := &RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header,
+		Body:   string(body),
+	}
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	recResp := &RecordedResponse{
+		Status: rw.Code,
+		Header: rw.HeaderMap,
+		Body:   rw.Body.String(),
+	}
+	return recReq, recResp, nil
+}
+
+// MatchesSnapshot compares the request with the golden file "<name>.request.golden" stored in
+// SnapshotDir. The golden file is created if it does not exist yet.
+func (r *RecordedRequest) MatchesSnapshot(t TInterface, name string) {
+	matchSnapshot(t, name+".request.golden", r)
+}
+
+// MatchesSnapshot compares the response with the golden file "<name>.response.golden" stored in
+// SnapshotDir. The golden file is created if it does not exist yet.
+func (r *RecordedResponse) MatchesSnapshot(t TInterface, name string) {
+	matchSnapshot(t, name+".response.golden", r)
+}
+
+// matchSnapshot serializes v and compares the result with the content of the given golden file,
+// it writes the file if it does not exist.
+func matchSnapshot(t TInterface, filename string, v interface{}) {
+	actual, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		t.Fatalf("failed to serialize snapshot %s: %s", filename, err)
+		return
+	}
+	actual = append(actual, '\n')
+	path := filepath.Join(SnapshotDir, filename)
+	expected, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(SnapshotDir, 0755); err != nil {
+			t.Fatalf("failed to create snapshot directory: %s", err)
+			return
+		}
+		if err := ioutil.WriteFile(path, actual, 0644); err != nil {
+			t.Fatalf("failed to write snapshot %s: %s", path, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("failed to read snapshot %s: %s", path, err)
+		return
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("snapshot %s does not match:\n--- expected\n%s\n--- actual\n%s", path, expected, actual)
+	}
+}
@@ -0,0 +1,97 @@
+package goatest_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goadesign/goa/goatest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeT records the failures reported by the snapshot helpers.
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+var _ = Describe("Record", func() {
+	var handler http.Handler
+	var dir string
+	var t *fakeT
+
+	BeforeEach(func() {
+		handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			body, _ := ioutil.ReadAll(req.Body)
+			rw.Header().Set("Content-Type", "text/plain")
+			rw.WriteHeader(http.StatusCreated)
+			rw.Write([]byte("echo: " + string(body)))
+		})
+		var err error
+		dir, err = ioutil.TempDir("", "goatest")
+		Ω(err).ShouldNot(HaveOccurred())
+		goatest.SnapshotDir = dir
+		t = &fakeT{}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+		goatest.SnapshotDir = "testdata"
+	})
+
+	record := func(body string) (*goatest.RecordedRequest, *goatest.RecordedResponse) {
+		req, err := http.NewRequest("POST", "/bottles?sort=name", strings.NewReader(body))
+		Ω(err).ShouldNot(HaveOccurred())
+		req.Header.Set("X-Test", "true")
+		recReq, recResp, err := goatest.Record(handler, req)
+		Ω(err).ShouldNot(HaveOccurred())
+		return recReq, recResp
+	}
+
+	It("captures the request and response", func() {
+		recReq, recResp := record("hello")
+		Ω(recReq.Method).Should(Equal("POST"))
+		Ω(recReq.URL).Should(Equal("/bottles?sort=name"))
+		Ω(recReq.Header.Get("X-Test")).Should(Equal("true"))
+		Ω(recReq.Body).Should(Equal("hello"))
+		Ω(recResp.Status).Should(Equal(http.StatusCreated))
+		Ω(recResp.Header.Get("Content-Type")).Should(Equal("text/plain"))
+		Ω(recResp.Body).Should(Equal("echo: hello"))
+	})
+
+	It("creates the snapshots on first run and matches them on second run", func() {
+		recReq, recResp := record("hello")
+		recReq.MatchesSnapshot(t, "create")
+		recResp.MatchesSnapshot(t, "create")
+		Ω(t.errors).Should(BeEmpty())
+		_, err := os.Stat(filepath.Join(dir, "create.request.golden"))
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = os.Stat(filepath.Join(dir, "create.response.golden"))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		recReq, recResp = record("hello")
+		recReq.MatchesSnapshot(t, "create")
+		recResp.MatchesSnapshot(t, "create")
+		Ω(t.errors).Should(BeEmpty())
+	})
+
+	It("reports snapshot mismatches", func() {
+		_, recResp := record("hello")
+		recResp.MatchesSnapshot(t, "create")
+		_, recResp = record("bye")
+		recResp.MatchesSnapshot(t, "create")
+		Ω(t.errors).Should(HaveLen(1))
+		Ω(t.errors[0]).Should(ContainSubstring("does not match"))
+	})
+})
@@ -0,0 +1,72 @@
+package goa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LatLon is a geographic coordinate expressed in decimal degrees. Its text representation is
+// "lat,lon", e.g. "48.8583,2.2945".
+type LatLon struct {
+	// Lat is the latitude, between -90 and 90.
+	Lat float64
+	// Lon is the longitude, between -180 and 180.
+	Lon float64
+}
+
+// ParseLatLon parses a coordinate formatted as "lat,lon". It returns an error if the value is
+// malformed or if the latitude or longitude is out of range.
+func ParseLatLon(s string) (LatLon, error) {
+	var ll LatLon
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return ll, fmt.Errorf("invalid coordinate %#v, must be formatted as \"lat,lon\"", s)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return ll, fmt.Errorf("invalid latitude in coordinate %#v", s)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return ll, fmt.Errorf("invalid longitude in coordinate %#v", s)
+	}
+	if lat < -90 || lat > 90 {
+		return ll, fmt.Errorf("latitude %v is out of range [-90, 90]", lat)
+	}
+	if lon < -180 || lon > 180 {
+		return ll, fmt.Errorf("longitude %v is out of range [-180, 180]", lon)
+	}
+	ll.Lat, ll.Lon = lat, lon
+	return ll, nil
+}
+
+// String returns the "lat,lon" representation of the coordinate.
+func (ll LatLon) String() string {
+	return strconv.FormatFloat(ll.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(ll.Lon, 'f', -1, 64)
+}
+
+// MarshalText implements encoding.TextMarshaler so that coordinates are serialized as
+// "lat,lon" strings.
+func (ll LatLon) MarshalText() ([]byte, error) {
+	return []byte(ll.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (ll *LatLon) UnmarshalText(text []byte) error {
+	parsed, err := ParseLatLon(string(text))
+	if err != nil {
+		return err
+	}
+	*ll = parsed
+	return nil
+}
+
+// ValidateBoundingBox returns an error if ll lies outside of the box delimited by the given
+// latitudes and longitudes.
+func ValidateBoundingBox(ll LatLon, minLat, maxLat, minLon, maxLon float64) error {
+	if ll.Lat < minLat || ll.Lat > maxLat || ll.Lon < minLon || ll.Lon > maxLon {
+		return fmt.Errorf("%s is outside of the bounding box [%v,%v]-[%v,%v]", ll, minLat, minLon, maxLat, maxLon)
+	}
+	return nil
+}
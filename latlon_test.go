@@ -0,0 +1,94 @@
+package goa_test
+
+import (
+	"encoding/json"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseLatLon", func() {
+	var val string
+	var ll goa.LatLon
+	var err error
+
+	JustBeforeEach(func() {
+		ll, err = goa.ParseLatLon(val)
+	})
+
+	Context("with a valid coordinate", func() {
+		BeforeEach(func() {
+			val = "48.8583,2.2945"
+		})
+
+		It("parses", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ll).Should(Equal(goa.LatLon{Lat: 48.8583, Lon: 2.2945}))
+		})
+	})
+
+	Context("with spaces around the values", func() {
+		BeforeEach(func() {
+			val = "-33.8568, 151.2153"
+		})
+
+		It("parses", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ll).Should(Equal(goa.LatLon{Lat: -33.8568, Lon: 151.2153}))
+		})
+	})
+
+	Context("with an out of range latitude", func() {
+		BeforeEach(func() {
+			val = "90.5,2"
+		})
+
+		It("fails", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an out of range longitude", func() {
+		BeforeEach(func() {
+			val = "48,-180.1"
+		})
+
+		It("fails", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a malformed value", func() {
+		BeforeEach(func() {
+			val = "48.8583"
+		})
+
+		It("fails", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("LatLon", func() {
+	It("serializes to JSON as a \"lat,lon\" string", func() {
+		b, err := json.Marshal(goa.LatLon{Lat: 48.8583, Lon: 2.2945})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`"48.8583,2.2945"`))
+
+		var ll goa.LatLon
+		Ω(json.Unmarshal(b, &ll)).Should(Succeed())
+		Ω(ll).Should(Equal(goa.LatLon{Lat: 48.8583, Lon: 2.2945}))
+	})
+})
+
+var _ = Describe("ValidateBoundingBox", func() {
+	It("accepts coordinates inside the box", func() {
+		Ω(goa.ValidateBoundingBox(goa.LatLon{Lat: 45, Lon: 5}, 40, 50, 0, 10)).Should(Succeed())
+	})
+
+	It("rejects coordinates outside of the box", func() {
+		Ω(goa.ValidateBoundingBox(goa.LatLon{Lat: 55, Lon: 5}, 40, 50, 0, 10)).ShouldNot(Succeed())
+		Ω(goa.ValidateBoundingBox(goa.LatLon{Lat: 45, Lon: -5}, 40, 50, 0, 10)).ShouldNot(Succeed())
+	})
+})
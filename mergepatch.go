@@ -0,0 +1,69 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch applies the JSON merge patch (RFC 7396) patch to the JSON representation of existing
+// and unmarshals the result into v. The members absent from patch keep their value, the members
+// set to null are removed and the members holding objects are merged recursively. existing is not
+// modified.
+func MergePatch(v, existing interface{}, patch map[string]json.RawMessage) error {
+	doc, err := json.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	var target map[string]interface{}
+	if err := decodeJSON(doc, &target); err != nil {
+		return fmt.Errorf("cannot merge patch into %s: %s", doc, err)
+	}
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+	for name, raw := range patch {
+		var value interface{}
+		if err := decodeJSON(raw, &value); err != nil {
+			return fmt.Errorf("invalid value for patch member %#v: %s", name, err)
+		}
+		if value == nil {
+			delete(target, name)
+			continue
+		}
+		target[name] = mergeValue(target[name], value)
+	}
+	merged, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(merged, v)
+}
+
+// mergeValue returns the result of merging the patch value into target as described in RFC 7396.
+func mergeValue(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+			continue
+		}
+		t[name] = mergeValue(t[name], value)
+	}
+	return t
+}
+
+// decodeJSON unmarshals data into v keeping numbers as json.Number so that large integers do not
+// lose precision.
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -0,0 +1,78 @@
+package goa_test
+
+import (
+	"encoding/json"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MergePatch", func() {
+	type origin struct {
+		Country *string `json:"country,omitempty"`
+		Region  *string `json:"region,omitempty"`
+	}
+	type bottle struct {
+		Name    string  `json:"name"`
+		Vintage *int    `json:"vintage,omitempty"`
+		Origin  *origin `json:"origin,omitempty"`
+	}
+
+	var existing *bottle
+	var patch map[string]json.RawMessage
+	var res bottle
+	var err error
+
+	BeforeEach(func() {
+		vintage, country, region := 2012, "France", "Bordeaux"
+		existing = &bottle{Name: "Number 8", Vintage: &vintage, Origin: &origin{Country: &country, Region: &region}}
+		res = bottle{}
+	})
+
+	JustBeforeEach(func() {
+		err = goa.MergePatch(&res, existing, patch)
+	})
+
+	Context("with a patch omitting fields", func() {
+		BeforeEach(func() {
+			patch = map[string]json.RawMessage{"name": json.RawMessage(`"Number 9"`)}
+		})
+
+		It("leaves the omitted fields unchanged", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Name).Should(Equal("Number 9"))
+			Ω(*res.Vintage).Should(Equal(2012))
+			Ω(*res.Origin.Country).Should(Equal("France"))
+			Ω(existing.Name).Should(Equal("Number 8"))
+		})
+	})
+
+	Context("with null members", func() {
+		BeforeEach(func() {
+			patch = map[string]json.RawMessage{
+				"vintage": json.RawMessage(`null`),
+				"origin":  json.RawMessage(`{"region":null}`),
+			}
+		})
+
+		It("removes the members and merges the objects", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Name).Should(Equal("Number 8"))
+			Ω(res.Vintage).Should(BeNil())
+			Ω(*res.Origin.Country).Should(Equal("France"))
+			Ω(res.Origin.Region).Should(BeNil())
+			Ω(*existing.Origin.Region).Should(Equal("Bordeaux"))
+		})
+	})
+
+	Context("with an invalid member value", func() {
+		BeforeEach(func() {
+			patch = map[string]json.RawMessage{"vintage": json.RawMessage(`"2012"`)}
+		})
+
+		It("fails", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
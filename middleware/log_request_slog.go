@@ -0,0 +1,72 @@
+//go:build go1.21
+// +build go1.21
+
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa"
+
+	"golang.org/x/net/context"
+)
+
+// LogRequestSlog creates a request logger middleware that uses the log/slog package introduced in
+// Go 1.21. Each request produces one "completed" entry made of a "request" group containing the
+// request method, path, correlation ID and params and a "response" group containing the response
+// status and the request duration. The query string is not logged. The values of the params
+// listed in sensitive are omitted. The middleware is aware of the RequestID middleware and if
+// registered after it uses the request ID as correlation ID. logger defaults to slog.Default() if
+// nil.
+func LogRequestSlog(logger *slog.Logger, sensitive ...string) goa.Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	omit := make(map[string]bool, len(sensitive))
+	for _, s := range sensitive {
+		omit[s] = true
+	}
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			reqID := ContextRequestID(ctx)
+			if reqID == "" {
+				reqID = shortID()
+			}
+			startedAt := time.Now()
+			err := h(ctx, rw, req)
+			r := goa.ContextRequest(ctx)
+			reqAttrs := []any{
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.String("correlation_id", reqID),
+			}
+			if r != nil {
+				keys := make([]string, 0, len(r.Params))
+				for k := range r.Params {
+					if !omit[k] {
+						keys = append(keys, k)
+					}
+				}
+				sort.Strings(keys)
+				var paramAttrs []any
+				for _, k := range keys {
+					paramAttrs = append(paramAttrs, slog.String(k, strings.Join(r.Params[k], ", ")))
+				}
+				reqAttrs = append(reqAttrs, slog.Group("params", paramAttrs...))
+			}
+			respAttrs := []any{slog.Duration("duration", time.Since(startedAt))}
+			if resp := goa.ContextResponse(ctx); resp != nil {
+				respAttrs = append([]any{slog.Int("status", resp.Status)}, respAttrs...)
+			}
+			logger.InfoContext(ctx, "completed",
+				slog.Group("request", reqAttrs...),
+				slog.Group("response", respAttrs...),
+			)
+			return err
+		}
+	}
+}
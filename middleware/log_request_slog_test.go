@@ -0,0 +1,57 @@
+//go:build go1.21
+// +build go1.21
+
+package middleware_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LogRequestSlog", func() {
+	var ctx context.Context
+	var rw *testResponseWriter
+	var req *http.Request
+	var service *goa.Service
+	var buf *bytes.Buffer
+	var logger *slog.Logger
+
+	BeforeEach(func() {
+		service = newService(nil)
+		var err error
+		req, err = http.NewRequest("GET", "/bottles/1?token=secret", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		rw = new(testResponseWriter)
+		params := url.Values{"id": []string{"1"}, "token": []string{"secret"}}
+		ctrl := service.NewController("test")
+		ctx = goa.NewContext(ctrl.Context, rw, req, params)
+		buf = new(bytes.Buffer)
+		logger = slog.New(slog.NewTextHandler(buf, nil))
+	})
+
+	It("logs the request and response groups", func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			return service.Send(ctx, 200, "ok")
+		}
+		lg := middleware.LogRequestSlog(logger, "token")(h)
+		Ω(lg(ctx, rw, req)).ShouldNot(HaveOccurred())
+		out := buf.String()
+		Ω(out).Should(ContainSubstring("msg=completed"))
+		Ω(out).Should(ContainSubstring("request.method=GET"))
+		Ω(out).Should(ContainSubstring("request.path=/bottles/1"))
+		Ω(out).Should(ContainSubstring("request.correlation_id="))
+		Ω(out).Should(ContainSubstring("request.params.id=1"))
+		Ω(out).Should(ContainSubstring("response.status=200"))
+		Ω(out).Should(ContainSubstring("response.duration="))
+		Ω(out).ShouldNot(ContainSubstring("secret"))
+	})
+})
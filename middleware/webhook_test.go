@@ -0,0 +1,84 @@
+package middleware_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VerifyWebhookSignature", func() {
+	const (
+		sigHeader = "X-Hub-Signature-256"
+		secret    = "shhh"
+		payload   = `{"action":"opened"}`
+	)
+
+	var req *http.Request
+	var rw *testResponseWriter
+	var called bool
+	var readBody string
+	var h goa.MuxHandler
+
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest("POST", "/hooks", strings.NewReader(payload))
+		Ω(err).ShouldNot(HaveOccurred())
+		rw = newTestResponseWriter()
+		called = false
+		readBody = ""
+		h = func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+			called = true
+			b, _ := ioutil.ReadAll(req.Body)
+			readBody = string(b)
+		}
+	})
+
+	JustBeforeEach(func() {
+		middleware.VerifyWebhookSignature(sigHeader, secret, h)(rw, req, nil)
+	})
+
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	Context("with a valid signature", func() {
+		BeforeEach(func() {
+			req.Header.Set(sigHeader, "sha256="+sign(payload))
+		})
+
+		It("calls the handler with the original body", func() {
+			Ω(called).Should(BeTrue())
+			Ω(readBody).Should(Equal(payload))
+		})
+	})
+
+	Context("with an invalid signature", func() {
+		BeforeEach(func() {
+			req.Header.Set(sigHeader, sign("tampered"))
+		})
+
+		It("responds with 403", func() {
+			Ω(called).Should(BeFalse())
+			Ω(rw.Status).Should(Equal(http.StatusForbidden))
+			Ω(string(rw.Body)).Should(ContainSubstring("invalid signature"))
+		})
+	})
+
+	Context("with a missing signature header", func() {
+		It("responds with 403", func() {
+			Ω(called).Should(BeFalse())
+			Ω(rw.Status).Should(Equal(http.StatusForbidden))
+		})
+	})
+})
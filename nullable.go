@@ -0,0 +1,30 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// DecodeJSONNulls unmarshals the JSON object data into v and returns the names of the object
+// members whose value is null. The private types generated for request payloads use it to tell
+// required nullable attributes set to null from missing attributes as both leave the struct field
+// nil.
+func DecodeJSONNulls(data []byte, v interface{}) (map[string]bool, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	var nulls map[string]bool
+	for name, raw := range members {
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			if nulls == nil {
+				nulls = make(map[string]bool)
+			}
+			nulls[name] = true
+		}
+	}
+	return nulls, nil
+}
@@ -0,0 +1,57 @@
+package goa_test
+
+import (
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecodeJSONNulls", func() {
+	type payload struct {
+		Name   *string `json:"name"`
+		Rating *int    `json:"rating"`
+	}
+	var data string
+	var v payload
+	var nulls map[string]bool
+	var err error
+
+	JustBeforeEach(func() {
+		v = payload{}
+		nulls, err = goa.DecodeJSONNulls([]byte(data), &v)
+	})
+
+	Context("with a member set to null", func() {
+		BeforeEach(func() {
+			data = `{"name": "merlot", "rating": null}`
+		})
+
+		It("decodes the value and records the null member", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*v.Name).Should(Equal("merlot"))
+			Ω(v.Rating).Should(BeNil())
+			Ω(nulls).Should(Equal(map[string]bool{"rating": true}))
+		})
+	})
+
+	Context("with a missing member", func() {
+		BeforeEach(func() {
+			data = `{"name": "merlot"}`
+		})
+
+		It("does not record it", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(nulls).Should(BeEmpty())
+		})
+	})
+
+	Context("with invalid JSON", func() {
+		BeforeEach(func() {
+			data = `{"name": 1}`
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -27,6 +27,20 @@ func WithRequiredScopes(ctx context.Context, scopes []string) context.Context {
 	return context.WithValue(ctx, securityScopesKey, scopes)
 }
 
+// HasAnyRole returns true if roles contains at least one of the allowed roles. The generated
+// "Filtered" response helpers use it to remove the attributes that are only visible to specific
+// roles, see the VisibleTo DSL.
+func HasAnyRole(roles []string, allowed ...string) bool {
+	for _, r := range roles {
+		for _, a := range allowed {
+			if r == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // OAuth2Security represents the `oauth2` security scheme. It is instantiated by the generated code
 // accordingly to the use of the different `*Security()` DSL functions and `Security()` in the
 // design.
@@ -0,0 +1,7 @@
+package goa
+
+// SemVerString is the Go representation of the SemVer design type: a semantic version string
+// such as "v1.2.3" as handled by the golang.org/x/mod/semver package. Note that the leading "v"
+// is required. Values read from request params are validated with semver.IsValid and may be
+// compared with the CompareSemVer function generated in the application package.
+type SemVerString = string
@@ -35,8 +35,10 @@ type (
 		// Response body encoder
 		Encoder *HTTPEncoder
 
-		middleware []Middleware       // Middleware chain
-		cancel     context.CancelFunc // Service context cancel signal trigger
+		middleware  []Middleware              // Middleware chain
+		cancel      context.CancelFunc        // Service context cancel signal trigger
+		compressors map[string]CompressorFunc // Response compressors indexed by content encoding
+		encodings   []string                  // Content encodings in order of registration
 	}
 
 	// Controller defines the common fields and behavior of generated controllers.
@@ -76,22 +78,29 @@ func New(name string) *Service {
 		stdlog       = log.New(os.Stderr, "", log.LstdFlags)
 		ctx          = WithLogger(context.Background(), NewLogger(stdlog))
 		cctx, cancel = context.WithCancel(ctx)
-		mux          = NewMux()
 		service      = &Service{
 			Name:    name,
 			Context: cctx,
-			Mux:     mux,
 			Decoder: NewHTTPDecoder(),
 			Encoder: NewHTTPEncoder(),
 
 			cancel: cancel,
 		}
-		notFoundHandler Handler
 	)
+	service.UseMux(NewMux())
+
+	return service
+}
+
+// UseMux sets the service request mux and registers the service NotFound handler with it.
+// Generated code calls UseMux to switch to a different mux implementation (see NewStdMux), it
+// must be called before any controller is mounted.
+func (service *Service) UseMux(mux ServeMux) {
+	var notFoundHandler Handler
 
 	// Setup default NotFound handler
 	mux.HandleNotFound(func(rw http.ResponseWriter, req *http.Request, params url.Values) {
-		if resp := ContextResponse(ctx); resp != nil && resp.Written() {
+		if resp := ContextResponse(service.Context); resp != nil && resp.Written() {
 			return
 		}
 		// Use closure to do lazy computation of middleware chain so all middlewares are
@@ -112,8 +121,7 @@ func New(name string) *Service {
 			service.Send(ctx, 404, err)
 		}
 	})
-
-	return service
+	service.Mux = mux
 }
 
 // CancelAll sends a cancel signals to all request handlers via the context.
@@ -267,7 +275,9 @@ func (ctrl *Controller) MuxHandler(name string, hdlr Handler, unm Unmarshaler) M
 				if err.Error() == "http: request body too large" {
 					msg := fmt.Sprintf("request body length exceeds %d bytes", ctrl.MaxRequestBodyLength)
 					err = ErrRequestBodyTooLarge(msg)
-				} else {
+				} else if se, ok := err.(ServiceError); !ok || se.ResponseStatus() == http.StatusBadRequest {
+					// Errors with a more specific status such as
+					// ErrUnsupportedMediaType keep it.
 					err = ErrBadRequest(err)
 				}
 				ctx = WithError(ctx, err)
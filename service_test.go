@@ -186,6 +186,20 @@ var _ = Describe("Service", func() {
 				Ω(tw.Body).Should(Equal(respContent))
 			})
 
+			Context("with an unmarshaler returning an unsupported media type error", func() {
+				BeforeEach(func() {
+					r.Body = ioutil.NopCloser(bytes.NewBuffer([]byte("<xml/>")))
+					r.ContentLength = 6
+					unmarshaler = func(c context.Context, service *goa.Service, req *http.Request) error {
+						return goa.ErrUnsupportedMediaType("request body is not valid JSON")
+					}
+				})
+
+				It("keeps the error status", func() {
+					Ω(string(rw.(*TestResponseWriter).Body)).Should(MatchRegexp(`\[.*\] 415 unsupported_media_type: request body is not valid JSON`))
+				})
+			})
+
 			Context("with an invalid payload", func() {
 				BeforeEach(func() {
 					r.Body = ioutil.NopCloser(bytes.NewBuffer([]byte("not json")))
@@ -0,0 +1,77 @@
+//go:build go1.22
+// +build go1.22
+
+package goa
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// StdMux is a ServeMux implementation that relies on the method and wildcard routing of the
+// standard library http.ServeMux. Paths given to Handle use the http.ServeMux wildcard syntax,
+// e.g. "/bottles/{id}" or "/public/{filepath...}". Note that the enhanced patterns are only
+// enabled when the main module targets Go 1.22 or later (see the httpmuxgo121 GODEBUG setting).
+type StdMux struct {
+	mux      *http.ServeMux
+	handles  map[string]MuxHandler
+	notFound MuxHandler
+}
+
+// wildcardRegex captures the names of the wildcards of http.ServeMux patterns.
+var wildcardRegex = regexp.MustCompile(`{([a-zA-Z0-9_]+)(?:\.\.\.)?}`)
+
+// NewStdMux returns a ServeMux backed by the standard library http.ServeMux.
+func NewStdMux() *StdMux {
+	m := &StdMux{
+		mux:     http.NewServeMux(),
+		handles: make(map[string]MuxHandler),
+	}
+	m.mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
+		if m.notFound == nil {
+			http.NotFound(rw, req)
+			return
+		}
+		m.notFound(rw, req, nil)
+	})
+	return m
+}
+
+// Handle sets the handler for the given verb and path. Paths ending with a slash only match
+// requests made to that exact path, unlike http.ServeMux patterns that match all the paths
+// under them.
+func (m *StdMux) Handle(method, path string, handle MuxHandler) {
+	var names []string
+	for _, match := range wildcardRegex.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+	m.handles[method+path] = handle
+	pattern := path
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "{$}"
+	}
+	m.mux.HandleFunc(method+" "+pattern, func(rw http.ResponseWriter, req *http.Request) {
+		params := req.URL.Query()
+		for _, n := range names {
+			params.Set(n, req.PathValue(n))
+		}
+		handle(rw, req, params)
+	})
+}
+
+// HandleNotFound sets the MuxHandler invoked for requests that don't match any
+// handler registered with Handle.
+func (m *StdMux) HandleNotFound(handle MuxHandler) {
+	m.notFound = handle
+}
+
+// Lookup returns the MuxHandler associated with the given method and path.
+func (m *StdMux) Lookup(method, path string) MuxHandler {
+	return m.handles[method+path]
+}
+
+// ServeHTTP is the function called back by the underlying HTTP server to handle incoming requests.
+func (m *StdMux) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	m.mux.ServeHTTP(rw, req)
+}
@@ -0,0 +1,123 @@
+//go:build go1.22
+// +build go1.22
+
+// The enhanced http.ServeMux patterns are disabled by default for code that is not built from a
+// Go 1.22 or later module, e.g. in GOPATH mode.
+//go:debug httpmuxgo121=0
+
+package goa_test
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StdMux", func() {
+	var mux *goa.StdMux
+
+	var req *http.Request
+	var rw *TestResponseWriter
+
+	BeforeEach(func() {
+		mux = goa.NewStdMux()
+	})
+
+	JustBeforeEach(func() {
+		rw = &TestResponseWriter{ParentHeader: http.Header{}}
+		mux.ServeHTTP(rw, req)
+	})
+
+	Context("with no handler", func() {
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequest("GET", "/", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("returns 404 to all requests", func() {
+			Ω(rw.Status).Should(Equal(404))
+		})
+	})
+
+	Context("with a handler using wildcards", func() {
+		var params url.Values
+
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequest("GET", "/accounts/42/bottles/1?sort=name", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			mux.Handle("GET", "/accounts/{accountID}/bottles/{id}", func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+				params = vals
+			})
+		})
+
+		It("sets the path and querystring parameters", func() {
+			Ω(params.Get("accountID")).Should(Equal("42"))
+			Ω(params.Get("id")).Should(Equal("1"))
+			Ω(params.Get("sort")).Should(Equal("name"))
+		})
+
+		It("records the handler", func() {
+			Ω(mux.Lookup("GET", "/accounts/{accountID}/bottles/{id}")).ShouldNot(BeNil())
+		})
+	})
+
+	Context("with a handler for a path ending with a slash", func() {
+		var called bool
+
+		BeforeEach(func() {
+			called = false
+			mux.Handle("GET", "/", func(http.ResponseWriter, *http.Request, url.Values) {
+				called = true
+			})
+		})
+
+		Context("and a request made to that path", func() {
+			BeforeEach(func() {
+				var err error
+				req, err = http.NewRequest("GET", "/", nil)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("calls the handler", func() {
+				Ω(called).Should(BeTrue())
+			})
+		})
+
+		Context("and a request made to a path under it", func() {
+			BeforeEach(func() {
+				var err error
+				req, err = http.NewRequest("GET", "/other", nil)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("does not call the handler", func() {
+				Ω(called).Should(BeFalse())
+				Ω(rw.Status).Should(Equal(404))
+			})
+		})
+	})
+
+	Context("with a request using a different method", func() {
+		var notFound bool
+
+		BeforeEach(func() {
+			notFound = false
+			var err error
+			req, err = http.NewRequest("POST", "/bottles", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			mux.Handle("GET", "/bottles", func(http.ResponseWriter, *http.Request, url.Values) {})
+			mux.HandleNotFound(func(http.ResponseWriter, *http.Request, url.Values) {
+				notFound = true
+			})
+		})
+
+		It("calls the not found handler", func() {
+			Ω(notFound).Should(BeTrue())
+		})
+	})
+})
@@ -48,6 +48,9 @@ const (
 
 	// FormatRegexp Regexp defines regular expression syntax accepted by RE2.
 	FormatRegexp = "regexp"
+
+	// FormatE164 defines ITU-T E.164 international phone numbers, e.g. "+12125551234".
+	FormatE164 = "e164"
 )
 
 var (
@@ -56,6 +59,9 @@ var (
 
 	// Simple regular expression for IPv4 values, more rigorous checking is done via net.ParseIP
 	ipv4Regex = regexp.MustCompile(`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}$`)
+
+	// Regular expression used to validate E.164 phone numbers: a "+" followed by up to 15 digits.
+	e164Regex = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
 )
 
 // ValidateFormat validates a string against a standard format.
@@ -72,6 +78,7 @@ var (
 //     - "mac": IEEE 802 MAC-48, EUI-48 or EUI-64 MAC address value
 //     - "cidr": RFC4632 and RFC4291 CIDR notation IP address value
 //     - "regexp": Regular expression syntax accepted by RE2
+//     - "e164": ITU-T E.164 international phone number
 func ValidateFormat(f Format, val string) error {
 	var err error
 	switch f {
@@ -109,6 +116,8 @@ func ValidateFormat(f Format, val string) error {
 		_, _, err = net.ParseCIDR(val)
 	case FormatRegexp:
 		_, err = regexp.Compile(val)
+	case FormatE164:
+		err = ValidateE164(val)
 	default:
 		return fmt.Errorf("unknown format %#v", f)
 	}
@@ -119,6 +128,27 @@ func ValidateFormat(f Format, val string) error {
 	return nil
 }
 
+// ValidateE164 returns an error if s is not an E.164 international phone number, i.e. a "+" sign
+// followed by a country code and subscriber number totaling at most 15 digits.
+func ValidateE164(s string) error {
+	if !e164Regex.MatchString(s) {
+		return fmt.Errorf("phone number %#v does not match %s", s, e164Regex.String())
+	}
+	return nil
+}
+
+// ValidateIPVersion returns an error if ip is not an IP address of the given version (4 or 6).
+func ValidateIPVersion(version int, ip net.IP) error {
+	isV4 := ip.To4() != nil
+	if version == 4 && !isV4 {
+		return fmt.Errorf("%s is not an IPv4 address", ip)
+	}
+	if version == 6 && (isV4 || ip.To16() == nil) {
+		return fmt.Errorf("%s is not an IPv6 address", ip)
+	}
+	return nil
+}
+
 // knownPatterns records the compiled patterns.
 // TBD: refactor all this so that the generated code initializes the map on start to get rid of the
 // need for a RW mutex.
@@ -1,6 +1,8 @@
 package goa_test
 
 import (
+	"net"
+
 	"github.com/goadesign/goa"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -342,4 +344,103 @@ var _ = Describe("ValidateFormat", func() {
 		})
 
 	})
+
+	Context("E164", func() {
+		BeforeEach(func() {
+			f = goa.FormatE164
+		})
+
+		Context("with an invalid value", func() {
+			BeforeEach(func() {
+				val = "212-555-1234"
+			})
+
+			It("does not validate", func() {
+				Ω(valErr).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a value that is too long", func() {
+			BeforeEach(func() {
+				val = "+1212555123456789"
+			})
+
+			It("does not validate", func() {
+				Ω(valErr).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a valid value", func() {
+			BeforeEach(func() {
+				val = "+12125551234"
+			})
+
+			It("validates", func() {
+				Ω(valErr).ShouldNot(HaveOccurred())
+			})
+		})
+
+	})
+})
+
+var _ = Describe("ValidateIPVersion", func() {
+	var version int
+	var ip net.IP
+	var valErr error
+
+	JustBeforeEach(func() {
+		valErr = goa.ValidateIPVersion(version, ip)
+	})
+
+	Context("IPv4", func() {
+		BeforeEach(func() {
+			version = 4
+		})
+
+		Context("with an IPv4 address", func() {
+			BeforeEach(func() {
+				ip = net.ParseIP("192.168.1.1")
+			})
+
+			It("validates", func() {
+				Ω(valErr).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an IPv6 address", func() {
+			BeforeEach(func() {
+				ip = net.ParseIP("2001:db8::1")
+			})
+
+			It("does not validate", func() {
+				Ω(valErr).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("IPv6", func() {
+		BeforeEach(func() {
+			version = 6
+		})
+
+		Context("with an IPv6 address", func() {
+			BeforeEach(func() {
+				ip = net.ParseIP("2001:db8::1")
+			})
+
+			It("validates", func() {
+				Ω(valErr).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an IPv4 address", func() {
+			BeforeEach(func() {
+				ip = net.ParseIP("192.168.1.1")
+			})
+
+			It("does not validate", func() {
+				Ω(valErr).Should(HaveOccurred())
+			})
+		})
+	})
 })